@@ -43,6 +43,14 @@ type cliParams struct {
 	dsdCaptureDuration   time.Duration
 	dsdCaptureFilePath   string
 	dsdCaptureCompressed bool
+	dsdCaptureMaxSize    int64
+
+	// capture filters
+	dsdCaptureMetricNameRegex string
+	dsdCaptureTags            []string
+	dsdCaptureContainerID     string
+	dsdCapturePid             int32
+	dsdCaptureTransport       string
 }
 
 // Commands returns a slice of subcommands for the 'agent' command.
@@ -67,6 +75,12 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 	dogstatsdCaptureCmd.Flags().DurationVarP(&cliParams.dsdCaptureDuration, "duration", "d", defaultCaptureDuration, "Duration traffic capture should span.")
 	dogstatsdCaptureCmd.Flags().StringVarP(&cliParams.dsdCaptureFilePath, "path", "p", "", "Directory path to write the capture to.")
 	dogstatsdCaptureCmd.Flags().BoolVarP(&cliParams.dsdCaptureCompressed, "compressed", "z", true, "Should capture be zstd compressed.")
+	dogstatsdCaptureCmd.Flags().Int64Var(&cliParams.dsdCaptureMaxSize, "max-size", 0, "Stop the capture once this many bytes of traffic were captured, 0 means no limit.")
+	dogstatsdCaptureCmd.Flags().StringVar(&cliParams.dsdCaptureMetricNameRegex, "metric-name", "", "Only capture the metrics whose name matches this regular expression.")
+	dogstatsdCaptureCmd.Flags().StringSliceVar(&cliParams.dsdCaptureTags, "tag", nil, "Only capture the metrics carrying this tag, can be repeated.")
+	dogstatsdCaptureCmd.Flags().StringVar(&cliParams.dsdCaptureContainerID, "container-id", "", "Only capture the traffic sent from this container.")
+	dogstatsdCaptureCmd.Flags().Int32Var(&cliParams.dsdCapturePid, "pid", 0, "Only capture the traffic sent by this PID.")
+	dogstatsdCaptureCmd.Flags().StringVar(&cliParams.dsdCaptureTransport, "transport", "", "Only capture the traffic received on this transport: unix, unixgram or unixpacket.")
 
 	// shut up grpc client!
 	grpclog.SetLoggerV2(grpclog.NewLoggerV2(io.Discard, io.Discard, io.Discard))
@@ -112,9 +126,15 @@ func dogstatsdCapture(_ log.Component, config config.Component, cliParams *cliPa
 	cli := pb.NewAgentSecureClient(conn)
 
 	resp, err := cli.DogstatsdCaptureTrigger(ctx, &pb.CaptureTriggerRequest{
-		Duration:   cliParams.dsdCaptureDuration.String(),
-		Path:       cliParams.dsdCaptureFilePath,
-		Compressed: cliParams.dsdCaptureCompressed,
+		Duration:        cliParams.dsdCaptureDuration.String(),
+		Path:            cliParams.dsdCaptureFilePath,
+		Compressed:      cliParams.dsdCaptureCompressed,
+		MaxSize:         cliParams.dsdCaptureMaxSize,
+		MetricNameRegex: cliParams.dsdCaptureMetricNameRegex,
+		Tags:            cliParams.dsdCaptureTags,
+		ContainerId:     cliParams.dsdCaptureContainerID,
+		Pid:             cliParams.dsdCapturePid,
+		Transport:       cliParams.dsdCaptureTransport,
 	})
 	if err != nil {
 		return err
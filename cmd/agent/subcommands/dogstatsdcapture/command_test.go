@@ -28,3 +28,17 @@ func TestCommand(t *testing.T) {
 			require.Equal(t, false, secretParams.Enabled)
 		})
 }
+
+func TestCommandFilters(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"dogstatsd-capture", "--metric-name", "^custom\\.", "--tag", "env:prod", "--tag", "team:a", "--pid", "42", "--transport", "unixgram", "--max-size", "1048576"},
+		dogstatsdCapture,
+		func(cliParams *cliParams, _ core.BundleParams, _ secrets.Params) {
+			require.Equal(t, "^custom\\.", cliParams.dsdCaptureMetricNameRegex)
+			require.Equal(t, []string{"env:prod", "team:a"}, cliParams.dsdCaptureTags)
+			require.Equal(t, int32(42), cliParams.dsdCapturePid)
+			require.Equal(t, "unixgram", cliParams.dsdCaptureTransport)
+			require.Equal(t, int64(1048576), cliParams.dsdCaptureMaxSize)
+		})
+}
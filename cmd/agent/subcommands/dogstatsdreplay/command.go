@@ -10,6 +10,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/signal"
@@ -35,6 +36,10 @@ import (
 
 const (
 	defaultIterations = 1
+	defaultSpeed      = 1.0
+
+	exportFormatJSONL  = "jsonl"
+	exportFormatPcapng = "pcapng"
 )
 
 // cliParams are the command-line arguments for this subcommand
@@ -47,6 +52,11 @@ type cliParams struct {
 	dsdVerboseReplay    bool
 	dsdMmapReplay       bool
 	dsdReplayIterations int
+	dsdReplaySpeed      float64
+
+	// export flags
+	dsdExportFormat     string
+	dsdExportOutputPath string
 }
 
 // Commands returns a slice of subcommands for the 'agent' command.
@@ -71,12 +81,34 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 	dogstatsdReplayCmd.Flags().BoolVarP(&cliParams.dsdVerboseReplay, "verbose", "v", false, "Verbose replay.")
 	dogstatsdReplayCmd.Flags().BoolVarP(&cliParams.dsdMmapReplay, "mmap", "m", true, "Mmap file for replay. Set to false to load the entire file into memory instead")
 	dogstatsdReplayCmd.Flags().IntVarP(&cliParams.dsdReplayIterations, "loops", "l", defaultIterations, "Number of iterations to replay.")
+	dogstatsdReplayCmd.Flags().Float64VarP(&cliParams.dsdReplaySpeed, "speed", "s", defaultSpeed, "Replay speed multiplier, 2 replays the traffic twice as fast as it was captured.")
+
+	dogstatsdExportCmd := &cobra.Command{
+		Use:   "dogstatsd-export",
+		Short: "Export a dogstatsd traffic capture to JSON lines or pcapng",
+		Long:  ``,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fxutil.OneShot(dogstatsdExport,
+				fx.Supply(cliParams),
+				fx.Supply(command.GetDefaultCoreBundleParams(cliParams.GlobalParams)),
+				core.Bundle(),
+			)
+		},
+	}
+	dogstatsdExportCmd.Flags().StringVarP(&cliParams.dsdReplayFilePath, "file", "f", "", "Input file with traffic captured with dogstatsd-capture.")
+	dogstatsdExportCmd.Flags().StringVar(&cliParams.dsdExportFormat, "format", exportFormatJSONL, "Export format: jsonl or pcapng.")
+	dogstatsdExportCmd.Flags().StringVarP(&cliParams.dsdExportOutputPath, "output", "o", "", "Output file, the capture is written to stdout when not set.")
+	dogstatsdExportCmd.Flags().BoolVarP(&cliParams.dsdMmapReplay, "mmap", "m", true, "Mmap file for export. Set to false to load the entire file into memory instead")
 
-	return []*cobra.Command{dogstatsdReplayCmd}
+	return []*cobra.Command{dogstatsdReplayCmd, dogstatsdExportCmd}
 }
 
 //nolint:revive // TODO(AML) Fix revive linter
 func dogstatsdReplay(_ log.Component, config config.Component, cliParams *cliParams) error {
+	if cliParams.dsdReplaySpeed <= 0 {
+		return fmt.Errorf("invalid replay speed %v, it must be greater than 0", cliParams.dsdReplaySpeed)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -132,6 +164,7 @@ func dogstatsdReplay(_ log.Component, config config.Component, cliParams *cliPar
 		fmt.Printf("could not open: %s\n", cliParams.dsdReplayFilePath)
 		return err
 	}
+	reader.Speed = cliParams.dsdReplaySpeed
 
 	s := pkgconfigsetup.Datadog().GetString("dogstatsd_socket")
 	if s == "" {
@@ -225,3 +258,44 @@ func dogstatsdReplay(_ log.Component, config config.Component, cliParams *cliPar
 	fmt.Println("replay done")
 	return err
 }
+
+//nolint:revive // TODO(AML) Fix revive linter
+func dogstatsdExport(_ log.Component, _ config.Component, cliParams *cliParams) error {
+	var export func(*replay.TrafficCaptureReader, io.Writer) (int, error)
+	switch cliParams.dsdExportFormat {
+	case exportFormatJSONL:
+		export = replay.ExportJSONL
+	case exportFormatPcapng:
+		export = replay.ExportPcapng
+	default:
+		return fmt.Errorf("unknown export format %q, expected %s or %s", cliParams.dsdExportFormat, exportFormatJSONL, exportFormatPcapng)
+	}
+
+	reader, err := replay.NewTrafficCaptureReader(cliParams.dsdReplayFilePath, 1, cliParams.dsdMmapReplay)
+	if reader != nil {
+		defer reader.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", cliParams.dsdReplayFilePath, err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if cliParams.dsdExportOutputPath != "" {
+		f, err := os.Create(cliParams.dsdExportOutputPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	n, err := export(reader, out)
+	if err != nil {
+		return fmt.Errorf("could not export the capture: %w", err)
+	}
+
+	if cliParams.dsdExportOutputPath != "" {
+		fmt.Printf("Exported %d messages to %s\n", n, cliParams.dsdExportOutputPath)
+	}
+	return nil
+}
@@ -23,6 +23,19 @@ func TestCommand(t *testing.T) {
 		dogstatsdReplay,
 		func(cliParams *cliParams, _ core.BundleParams, secretParams secrets.Params) {
 			require.True(t, cliParams.dsdVerboseReplay)
+			require.Equal(t, 1.0, cliParams.dsdReplaySpeed)
 			require.Equal(t, false, secretParams.Enabled)
 		})
 }
+
+func TestExportCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"dogstatsd-export", "-f", "capture.dog", "--format", "pcapng", "-o", "capture.pcapng"},
+		dogstatsdExport,
+		func(cliParams *cliParams, _ core.BundleParams, _ secrets.Params) {
+			require.Equal(t, "capture.dog", cliParams.dsdReplayFilePath)
+			require.Equal(t, "pcapng", cliParams.dsdExportFormat)
+			require.Equal(t, "capture.pcapng", cliParams.dsdExportOutputPath)
+		})
+}
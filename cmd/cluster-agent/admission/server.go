@@ -52,6 +52,10 @@ type Request struct {
 	DynamicClient dynamic.Interface
 	// APIClient holds a Kubernetes client
 	APIClient kubernetes.Interface
+	// DryRun is set when the request isn't sent by the API server and its
+	// result is never persisted, the webhooks must then have no side effects
+	// such as submitting metrics
+	DryRun bool
 }
 
 // WebhookFunc is the function that runs the webhook logic.
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/preview"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/api"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// InstallAdmissionEndpoints registers endpoints for the admission controller
+func InstallAdmissionEndpoints(r *mux.Router, previewer *preview.Previewer) {
+	log.Debug("Registering admission controller endpoints")
+	r.HandleFunc("/admission/preview", api.WithTelemetryWrapper("postAdmissionPreview", postAdmissionPreview(previewer))).Methods("POST")
+}
+
+// postAdmissionPreview runs a pod through the enabled mutating webhooks and
+// returns the resulting JSON patch
+func postAdmissionPreview(previewer *preview.Previewer) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req preview.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httputils.SetJSONError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		result, err := previewer.Preview(r.Context(), req)
+		if err != nil {
+			httputils.SetJSONError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			httputils.SetJSONError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
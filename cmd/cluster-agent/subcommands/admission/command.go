@@ -0,0 +1,238 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows && kubeapiserver
+
+// Package admission implements 'cluster-agent admission'.
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/preview"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+type cliParams struct {
+	file      string
+	namespace string
+	json      bool
+}
+
+// Commands returns a slice of subcommands for the 'cluster-agent' command.
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{}
+
+	cmd := &cobra.Command{
+		Use:   "admission",
+		Short: "Admission controller related commands",
+	}
+
+	previewCmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Preview the mutations the admission controller would apply to a pod",
+		Long: `The preview command runs a pod through every enabled mutating webhook
+of the admission controller, without creating anything, and prints the
+resulting JSON patch along with the changes made or the reason for skipping
+for each webhook. The manifest can be a Pod or a workload with a pod template
+(Deployment, ReplicaSet, StatefulSet, DaemonSet, Job or CronJob).`,
+		Example: "datadog-cluster-agent admission preview -f pod.yaml",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fxutil.OneShot(runPreview,
+				fx.Supply(cliParams),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewClusterAgentParams(globalParams.ConfFilePath),
+					SecretParams: secrets.NewEnabledParams(),
+					LogParams:    log.ForOneShot(command.LoggerName, command.DefaultLogLevel, true),
+				}),
+				core.Bundle(),
+			)
+		},
+	}
+	previewCmd.Flags().StringVarP(&cliParams.file, "file", "f", "", "manifest of the pod or workload to preview, - to read it from stdin")
+	previewCmd.Flags().StringVarP(&cliParams.namespace, "namespace", "n", "", "namespace the pod would be created in, defaults to the namespace of the manifest")
+	previewCmd.Flags().BoolVarP(&cliParams.json, "json", "j", false, "print the raw JSON result")
+	_ = previewCmd.MarkFlagRequired("file")
+
+	cmd.AddCommand(previewCmd)
+
+	return []*cobra.Command{cmd}
+}
+
+func runPreview(_ log.Component, config config.Component, cliParams *cliParams) error {
+	var manifest []byte
+	var err error
+	if cliParams.file == "-" {
+		manifest, err = io.ReadAll(os.Stdin)
+	} else {
+		manifest, err = os.ReadFile(cliParams.file)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read manifest: %w", err)
+	}
+
+	pod, err := podFromManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(preview.Request{
+		Namespace: cliParams.namespace,
+		Pod:       pod,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling payload: %v", err)
+	}
+
+	c := util.GetClient(false) // FIX: get certificates right then make this true
+	urlstr := fmt.Sprintf("https://localhost:%v/api/v1/admission/preview", pkgconfigsetup.Datadog().GetInt("cluster_agent.cmd_port"))
+
+	// Set session token
+	if err = util.SetAuthToken(config); err != nil {
+		return err
+	}
+
+	r, err := util.DoPost(c, urlstr, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		var errMap = make(map[string]string)
+		json.Unmarshal(r, &errMap) //nolint:errcheck
+		// If the error has been marshalled into a json object, check it and return it properly
+		if e, found := errMap["error"]; found {
+			return errors.New(e)
+		}
+
+		fmt.Printf(`
+		Could not reach agent: %v
+		Make sure the cluster agent is running with the admission controller enabled before requesting a preview.
+		Contact support if you continue having issues.`, err)
+
+		return err
+	}
+
+	if cliParams.json {
+		var out bytes.Buffer
+		if err := json.Indent(&out, r, "", "  "); err != nil {
+			return err
+		}
+		fmt.Println(out.String())
+		return nil
+	}
+
+	var result preview.Result
+	if err := json.Unmarshal(r, &result); err != nil {
+		return fmt.Errorf("cannot decode the preview: %w", err)
+	}
+
+	return printResult(color.Output, &result)
+}
+
+// printResult prints the outcome of each webhook followed by the JSON patch
+func printResult(w io.Writer, result *preview.Result) error {
+	if len(result.Webhooks) == 0 {
+		fmt.Fprintln(w, "No mutating webhook is enabled")
+	}
+
+	for _, webhook := range result.Webhooks {
+		switch webhook.Status {
+		case preview.StatusMutated:
+			fmt.Fprintf(w, "%s: %s\n", color.BlueString(webhook.Name), color.GreenString(webhook.Status))
+			var ops []json.RawMessage
+			if err := json.Unmarshal(webhook.Patch, &ops); err != nil {
+				return fmt.Errorf("cannot decode the patch of %s: %w", webhook.Name, err)
+			}
+			for _, op := range ops {
+				fmt.Fprintf(w, "  %s\n", op)
+			}
+		case preview.StatusError:
+			fmt.Fprintf(w, "%s: %s (%s)\n", color.BlueString(webhook.Name), color.RedString(webhook.Status), webhook.Reason)
+		default:
+			fmt.Fprintf(w, "%s: %s (%s)\n", color.BlueString(webhook.Name), color.YellowString(webhook.Status), webhook.Reason)
+		}
+	}
+
+	var patch bytes.Buffer
+	if err := json.Indent(&patch, result.Patch, "", "  "); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nJSON patch:\n%s\n", patch.String())
+	return nil
+}
+
+// podTemplatePaths are the paths of the pod template in the supported workloads
+var podTemplatePaths = map[string][]string{
+	"Deployment":  {"spec", "template"},
+	"ReplicaSet":  {"spec", "template"},
+	"StatefulSet": {"spec", "template"},
+	"DaemonSet":   {"spec", "template"},
+	"Job":         {"spec", "template"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template"},
+}
+
+// podFromManifest returns the pod described by a YAML or JSON manifest of a
+// pod or of a workload with a pod template
+func podFromManifest(manifest []byte) (json.RawMessage, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(manifest, &obj); err != nil {
+		return nil, fmt.Errorf("cannot parse manifest: %w", err)
+	}
+	if obj == nil {
+		return nil, errors.New("empty manifest")
+	}
+
+	kind, _ := obj["kind"].(string)
+	if kind == "Pod" {
+		return json.Marshal(obj)
+	}
+
+	path, found := podTemplatePaths[kind]
+	if !found {
+		return nil, fmt.Errorf("unsupported kind %q, expected a Pod or a workload with a pod template", kind)
+	}
+
+	template := obj
+	for _, key := range path {
+		template, _ = template[key].(map[string]interface{})
+		if template == nil {
+			return nil, fmt.Errorf("%s has no pod template", kind)
+		}
+	}
+
+	metadata, _ := template["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	if workloadMetadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		if ns, ok := workloadMetadata["namespace"]; ok {
+			metadata["namespace"] = ns
+		}
+		if name, ok := workloadMetadata["name"].(string); ok && metadata["name"] == nil {
+			metadata["generateName"] = name + "-"
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   metadata,
+		"spec":       template["spec"],
+	})
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows && kubeapiserver
+
+package admission
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/command"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestPreviewCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"admission", "preview", "-f", "pod.yaml", "-n", "apps"},
+		runPreview,
+		func(cliParams *cliParams) {
+			require.Equal(t, "pod.yaml", cliParams.file)
+			require.Equal(t, "apps", cliParams.namespace)
+			require.False(t, cliParams.json)
+		})
+}
+
+func TestPodFromManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     string
+		wantErr  string
+	}{
+		{
+			name: "pod",
+			manifest: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: foo
+spec:
+  containers:
+    - name: app
+      image: foo
+`,
+			want: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"foo"},"spec":{"containers":[{"name":"app","image":"foo"}]}}`,
+		},
+		{
+			name: "deployment",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  namespace: apps
+spec:
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      containers:
+        - name: app
+          image: foo
+`,
+			want: `{"apiVersion":"v1","kind":"Pod","metadata":{"generateName":"foo-","namespace":"apps","labels":{"app":"foo"}},"spec":{"containers":[{"name":"app","image":"foo"}]}}`,
+		},
+		{
+			name: "cronjob",
+			manifest: `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: foo
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: app
+              image: foo
+`,
+			want: `{"apiVersion":"v1","kind":"Pod","metadata":{"generateName":"foo-"},"spec":{"containers":[{"name":"app","image":"foo"}]}}`,
+		},
+		{
+			name:     "unsupported kind",
+			manifest: `{"kind": "Service"}`,
+			wantErr:  `unsupported kind "Service", expected a Pod or a workload with a pod template`,
+		},
+		{
+			name:     "missing template",
+			manifest: `{"kind": "Deployment", "spec": {}}`,
+			wantErr:  "Deployment has no pod template",
+		},
+		{
+			name:    "empty manifest",
+			wantErr: "empty manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod, err := podFromManifest([]byte(tt.manifest))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(pod))
+		})
+	}
+}
@@ -68,6 +68,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/clusteragent"
 	admissionpkg "github.com/DataDog/datadog-agent/pkg/clusteragent/admission"
 	admissionpatch "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/patch"
+	admissionpreview "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/preview"
 	apidca "github.com/DataDog/datadog-agent/pkg/clusteragent/api"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks"
@@ -417,7 +418,7 @@ func start(log log.Component,
 			log.Error("Admission controller is disabled, vertical autoscaling requires the admission controller to be enabled. Vertical scaling will be disabled.")
 		}
 
-		if adapter, err := workload.StartWorkloadAutoscaling(mainCtx, clusterID, apiCl, rcClient, wmeta, demultiplexer); err != nil {
+		if adapter, err := workload.StartWorkloadAutoscaling(mainCtx, clusterID, apiCl, rcClient, wmeta, demultiplexer, dc); err != nil {
 			pkglog.Errorf("Error while starting workload autoscaling: %v", err)
 		} else {
 			pa = adapter
@@ -490,6 +491,12 @@ func start(log log.Component,
 				server.Register(webhookConf.Endpoint(), webhookConf.Name(), webhookConf.WebhookType(), webhookConf.WebhookFunc(), apiCl.DynamicCl, apiCl.Cl)
 			}
 
+			// Expose the mutations preview on the cluster agent API
+			previewer := admissionpreview.NewPreviewer(webhooks, apiCl.DynamicCl, apiCl.Cl)
+			api.ModifyAPIRouter(func(r *mux.Router) {
+				dcav1.InstallAdmissionEndpoints(r, previewer)
+			})
+
 			// Start the k8s admission webhook server
 			wg.Add(1)
 			go func() {
@@ -10,6 +10,7 @@ package subcommands
 
 import (
 	"github.com/DataDog/datadog-agent/cmd/cluster-agent/command"
+	cmdadmission "github.com/DataDog/datadog-agent/cmd/cluster-agent/subcommands/admission"
 	cmdcheck "github.com/DataDog/datadog-agent/cmd/cluster-agent/subcommands/check"
 	cmdclusterchecks "github.com/DataDog/datadog-agent/cmd/cluster-agent/subcommands/clusterchecks"
 	cmdcompliance "github.com/DataDog/datadog-agent/cmd/cluster-agent/subcommands/compliance"
@@ -48,5 +49,6 @@ func ClusterAgentSubcommands() []command.SubcommandFactory {
 		cmdstatus.Commands,
 		cmdworkloadlist.Commands,
 		cmdtaggerlist.Commands,
+		cmdadmission.Commands,
 	}
 }
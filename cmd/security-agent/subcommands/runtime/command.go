@@ -68,6 +68,7 @@ func commonPolicyCommands(globalParams *command.GlobalParams) []*cobra.Command {
 	}
 
 	commonPolicyCmd.AddCommand(evalCommands(globalParams)...)
+	commonPolicyCmd.AddCommand(testSuiteCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(commonCheckPoliciesCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(commonReloadPoliciesCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(downloadPolicyCommands(globalParams)...)
@@ -532,33 +533,52 @@ func eventDataFromJSON(file string) (eval.Event, error) {
 		return nil, err
 	}
 
-	kind := secconfig.ParseEvalEventType(eventData.Type)
+	event, err := newEventWithType(eventData.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setEventValues(event, eventData.Values); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// newEventWithType returns an initialized event of the given type
+func newEventWithType(eventType eval.EventType) (*model.Event, error) {
+	kind := secconfig.ParseEvalEventType(eventType)
 	if kind == model.UnknownEventType {
 		return nil, errors.New("unknown event type")
 	}
 
 	m := &model.Model{}
-	event := m.NewDefaultEventWithType(kind)
+	event := m.NewDefaultEventWithType(kind).(*model.Event)
 	event.Init()
 
-	for k, v := range eventData.Values {
+	return event, nil
+}
+
+// setEventValues sets the given field values on an event
+func setEventValues(event *model.Event, values map[string]interface{}) error {
+	for k, v := range values {
 		switch v := v.(type) {
 		case json.Number:
 			value, err := v.Int64()
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if err := event.SetFieldValue(k, int(value)); err != nil {
-				return nil, err
+				return err
 			}
 		default:
 			if err := event.SetFieldValue(k, v); err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
 
-	return event, nil
+	return nil
 }
 
 func evalRule(_ log.Component, _ config.Component, _ secrets.Component, evalArgs *evalCliParams) error {
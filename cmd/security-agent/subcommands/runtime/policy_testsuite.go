@@ -0,0 +1,730 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux || windows
+
+package runtime
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+	"gopkg.in/yaml.v3"
+
+	"github.com/DataDog/datadog-agent/cmd/security-agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/security/probe/kfilters"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+type testSuiteCliParams struct {
+	*command.GlobalParams
+
+	dir            string
+	testsDir       string
+	junitOutput    string
+	coverageOutput string
+}
+
+func testSuiteCommands(globalParams *command.GlobalParams) []*cobra.Command {
+	testSuiteArgs := &testSuiteCliParams{
+		GlobalParams: globalParams,
+	}
+
+	testSuiteCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run the test files of a tests directory against the given policies",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fxutil.OneShot(runPolicyTestSuite,
+				fx.Supply(testSuiteArgs),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewSecurityAgentParams(globalParams.ConfigFilePaths, config.WithFleetPoliciesDirPath(globalParams.FleetPoliciesDirPath)),
+					SecretParams: secrets.NewEnabledParams(),
+					LogParams:    log.ForOneShot(command.LoggerName, "off", false)}),
+				core.Bundle(),
+			)
+		},
+	}
+
+	testSuiteCmd.Flags().StringVar(&testSuiteArgs.dir, "policies-dir", pkgconfigsetup.DefaultRuntimePoliciesDir, "Path to policies directory")
+	testSuiteCmd.Flags().StringVar(&testSuiteArgs.testsDir, "tests-dir", "", "Path to the directory of the YAML test files")
+	_ = testSuiteCmd.MarkFlagRequired("tests-dir")
+	testSuiteCmd.Flags().StringVar(&testSuiteArgs.junitOutput, "junit-output", "", "Write the test results in the JUnit XML format to the given file")
+	testSuiteCmd.Flags().StringVar(&testSuiteArgs.coverageOutput, "coverage-output", "", "Write the rules, approvers and discarders coverage report in the JSON format to the given file")
+
+	return []*cobra.Command{testSuiteCmd}
+}
+
+// PolicyTestFile defines the content of a policy test file
+type PolicyTestFile struct {
+	Name  string           `yaml:"name"`
+	Tests []PolicyTestCase `yaml:"tests"`
+}
+
+// PolicyTestCase defines an ordered sequence of events evaluated against a fresh rule set,
+// variables set by the rules matching an event are visible to the following events
+type PolicyTestCase struct {
+	Name   string            `yaml:"name"`
+	Events []PolicyTestEvent `yaml:"events"`
+}
+
+// PolicyTestEvent defines a synthetic event and the expected outcome of its evaluation.
+// Events sharing the same `process.pid` value share the same process cache entry, and events
+// sharing the same `container.id` value share the same container context, so that process and
+// container scoped variables behave like they do at runtime.
+type PolicyTestEvent struct {
+	Type   eval.EventType         `yaml:"type"`
+	Values map[string]interface{} `yaml:"values"`
+	Expect PolicyTestExpectations `yaml:"expect"`
+}
+
+// PolicyTestExpectations defines the expected outcome of the evaluation of an event
+type PolicyTestExpectations struct {
+	Match   []string               `yaml:"match"`
+	NoMatch []string               `yaml:"no_match"`
+	Actions []PolicyTestActionSpec `yaml:"actions"`
+}
+
+// PolicyTestActionSpec defines an action expected to be performed by a rule. Exactly one
+// of `kill`, `set` and `hash` must be specified.
+type PolicyTestActionSpec struct {
+	Rule string `yaml:"rule"`
+	// Kill is the expected signal of a kill action, e.g. SIGKILL
+	Kill string `yaml:"kill"`
+	// Set is the name of the variable expected to be set, prefixed by its scope if any, e.g. process.my_var
+	Set  string `yaml:"set"`
+	Hash bool   `yaml:"hash"`
+}
+
+func (a PolicyTestActionSpec) String() string {
+	switch {
+	case a.Kill != "":
+		return fmt.Sprintf("%s: kill %s", a.Rule, a.Kill)
+	case a.Set != "":
+		return fmt.Sprintf("%s: set %s", a.Rule, a.Set)
+	case a.Hash:
+		return fmt.Sprintf("%s: hash", a.Rule)
+	default:
+		return fmt.Sprintf("%s: <invalid action>", a.Rule)
+	}
+}
+
+func (a PolicyTestActionSpec) validate() error {
+	count := 0
+	if a.Kill != "" {
+		count++
+	}
+	if a.Set != "" {
+		count++
+	}
+	if a.Hash {
+		count++
+	}
+	if a.Rule == "" || count != 1 {
+		return fmt.Errorf("invalid expected action `%s`, a rule and exactly one of 'kill', 'set' or 'hash' must be specified", a)
+	}
+	return nil
+}
+
+// PolicyTestResult defines the result of a test case
+type PolicyTestResult struct {
+	File     string
+	Name     string
+	Failures []string
+	// Error is set when the test case, or its whole test file, could not be run
+	Error    error
+	Duration time.Duration
+}
+
+// Succeeded returns whether the test case was run and all its expectations were met
+func (r *PolicyTestResult) Succeeded() bool {
+	return r.Error == nil && len(r.Failures) == 0
+}
+
+// PolicyCoverageReport defines the coverage of the policies by the test files
+type PolicyCoverageReport struct {
+	Rules      map[eval.RuleID]int                             `json:"rules"`
+	Uncovered  []eval.RuleID                                   `json:"uncovered_rules"`
+	Approvers  map[eval.EventType][]ApproverCoverage           `json:"approvers"`
+	Discarders map[eval.EventType]map[eval.Field][]interface{} `json:"discarders"`
+}
+
+// ApproverCoverage defines the number of test events carrying an approver value
+type ApproverCoverage struct {
+	Field  eval.Field  `json:"field"`
+	Value  interface{} `json:"value"`
+	Events int         `json:"events"`
+}
+
+// policyTestListener collects the rules matched, the actions performed and the discarders found
+// while evaluating an event
+type policyTestListener struct {
+	matched    map[eval.RuleID]bool
+	actions    []PolicyTestActionSpec
+	discarders map[eval.Field]bool
+}
+
+func newPolicyTestListener() *policyTestListener {
+	return &policyTestListener{
+		matched:    make(map[eval.RuleID]bool),
+		discarders: make(map[eval.Field]bool),
+	}
+}
+
+func (l *policyTestListener) reset() {
+	clear(l.matched)
+	clear(l.discarders)
+	l.actions = nil
+}
+
+// RuleMatch is called when a rule matches an event, set actions are reported by ruleActionPerformed
+func (l *policyTestListener) RuleMatch(rule *rules.Rule, event eval.Event) bool {
+	l.matched[rule.ID] = true
+
+	ctx := eval.NewContext(event)
+	for _, action := range rule.PolicyRule.Actions {
+		if action.Def == nil || !action.IsAccepted(ctx) {
+			continue
+		}
+
+		switch {
+		case action.Def.Kill != nil:
+			l.actions = append(l.actions, PolicyTestActionSpec{Rule: rule.ID, Kill: action.Def.Kill.Signal})
+		case action.Def.Hash != nil:
+			l.actions = append(l.actions, PolicyTestActionSpec{Rule: rule.ID, Hash: true})
+		}
+	}
+
+	return true
+}
+
+// EventDiscarderFound is called when a discarder is found for an event
+func (l *policyTestListener) EventDiscarderFound(_ *rules.RuleSet, _ eval.Event, field eval.Field, _ eval.EventType) {
+	l.discarders[field] = true
+}
+
+func (l *policyTestListener) ruleActionPerformed(rule *rules.Rule, action *rules.ActionDefinition) {
+	if action.Set == nil {
+		return
+	}
+
+	name := action.Set.Name
+	if action.Set.Scope != "" {
+		name = string(action.Set.Scope) + "." + name
+	}
+	l.actions = append(l.actions, PolicyTestActionSpec{Rule: rule.ID, Set: name})
+}
+
+// policyTestRunner runs test files against a policies directory
+type policyTestRunner struct {
+	policiesDir string
+	coverage    *PolicyCoverageReport
+	approvers   map[eval.EventType]rules.Approvers
+}
+
+func newPolicyTestRunner(policiesDir string) (*policyTestRunner, error) {
+	runner := &policyTestRunner{
+		policiesDir: policiesDir,
+		coverage: &PolicyCoverageReport{
+			Rules:      make(map[eval.RuleID]int),
+			Approvers:  make(map[eval.EventType][]ApproverCoverage),
+			Discarders: make(map[eval.EventType]map[eval.Field][]interface{}),
+		},
+	}
+
+	ruleSet, err := runner.loadRuleSet(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for id := range ruleSet.GetRules() {
+		runner.coverage.Rules[id] = 0
+	}
+
+	approvers, err := ruleSet.GetApprovers(kfilters.GetCapababilities())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute approvers: %w", err)
+	}
+	runner.approvers = approvers
+
+	for eventType, fields := range approvers {
+		for field, values := range fields {
+			for _, value := range values {
+				runner.coverage.Approvers[eventType] = append(runner.coverage.Approvers[eventType], ApproverCoverage{
+					Field: field,
+					Value: value.Value,
+				})
+			}
+		}
+		sort.Slice(runner.coverage.Approvers[eventType], func(i, j int) bool {
+			a, b := runner.coverage.Approvers[eventType][i], runner.coverage.Approvers[eventType][j]
+			if a.Field != b.Field {
+				return a.Field < b.Field
+			}
+			return fmt.Sprint(a.Value) < fmt.Sprint(b.Value)
+		})
+	}
+
+	return runner, nil
+}
+
+// loadRuleSet loads all the rules of the policies directory in a new rule set
+func (r *policyTestRunner) loadRuleSet(listener *policyTestListener) (*rules.RuleSet, error) {
+	// enabled all the rules
+	enabled := map[eval.EventType]bool{"*": true}
+
+	ruleOpts := rules.NewRuleOpts(enabled)
+	evalOpts := newEvalOpts(false)
+	ruleOpts.WithLogger(seclog.DefaultLogger)
+	if listener != nil {
+		ruleOpts.WithRuleActionPerformedCb(listener.ruleActionPerformed)
+	}
+
+	agentVersionFilter, err := newAgentVersionFilter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent version filter: %w", err)
+	}
+
+	loaderOpts := rules.PolicyLoaderOpts{
+		MacroFilters: []rules.MacroFilter{
+			agentVersionFilter,
+		},
+		RuleFilters: []rules.RuleFilter{
+			agentVersionFilter,
+		},
+	}
+
+	provider, err := rules.NewPoliciesDirProvider(r.policiesDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := rules.NewPolicyLoader(provider)
+
+	ruleSet := rules.NewRuleSet(&model.Model{}, newFakeEvent, ruleOpts, evalOpts)
+	ruleSet.SetFakeEventCtor(newFakeEvent)
+	if err := ruleSet.LoadPolicies(loader, loaderOpts); err.ErrorOrNil() != nil {
+		return nil, err
+	}
+
+	if listener != nil {
+		ruleSet.AddListener(listener)
+	}
+
+	return ruleSet, nil
+}
+
+// runFile runs all the test cases of a test file. A test file that cannot be read or parsed is
+// reported as a single failed test case named after the file.
+func (r *policyTestRunner) runFile(path string) []*PolicyTestResult {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var testFile PolicyTestFile
+	content, err := os.ReadFile(path)
+	if err == nil {
+		err = yaml.Unmarshal(content, &testFile)
+	}
+	if err != nil {
+		return []*PolicyTestResult{{
+			File:  name,
+			Name:  name,
+			Error: fmt.Errorf("failed to parse test file %s: %w", path, err),
+		}}
+	}
+
+	if testFile.Name != "" {
+		name = testFile.Name
+	}
+
+	results := make([]*PolicyTestResult, 0, len(testFile.Tests))
+	for _, testCase := range testFile.Tests {
+		result := r.runTestCase(testCase)
+		result.File = name
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// runTestCase evaluates the events of a test case, in order, against a fresh rule set
+func (r *policyTestRunner) runTestCase(testCase PolicyTestCase) *PolicyTestResult {
+	start := time.Now()
+	result := &PolicyTestResult{Name: testCase.Name}
+	result.Error = r.evaluateTestCase(testCase, result)
+	result.Duration = time.Since(start)
+	return result
+}
+
+// evaluateTestCase evaluates the events of a test case and records the unmet expectations in result.
+// It returns an error if the test case is invalid.
+func (r *policyTestRunner) evaluateTestCase(testCase PolicyTestCase, result *PolicyTestResult) error {
+	listener := newPolicyTestListener()
+	ruleSet, err := r.loadRuleSet(listener)
+	if err != nil {
+		return err
+	}
+
+	processes := make(map[interface{}]*model.ProcessCacheEntry)
+	containers := make(map[interface{}]*model.ContainerContext)
+
+	for i, testEvent := range testCase.Events {
+		for _, action := range testEvent.Expect.Actions {
+			if err := action.validate(); err != nil {
+				return err
+			}
+		}
+
+		event, err := newEventWithType(testEvent.Type)
+		if err != nil {
+			return fmt.Errorf("event #%d: %w", i, err)
+		}
+
+		if pid, ok := testEvent.Values["process.pid"]; ok {
+			entry, exists := processes[pid]
+			if !exists {
+				entry = event.ProcessCacheEntry
+				processes[pid] = entry
+			}
+			event.ProcessCacheEntry = entry
+			event.ProcessContext = &entry.ProcessContext
+		}
+		if id, ok := testEvent.Values["container.id"]; ok {
+			containerContext, exists := containers[id]
+			if !exists {
+				containerContext = event.ContainerContext
+				containers[id] = containerContext
+			}
+			event.ContainerContext = containerContext
+		}
+
+		if err := setEventValues(event, testEvent.Values); err != nil {
+			return fmt.Errorf("event #%d: %w", i, err)
+		}
+
+		listener.reset()
+		if !ruleSet.Evaluate(event) {
+			ruleSet.EvaluateDiscarders(event)
+		}
+
+		r.updateCoverage(event, listener)
+
+		for _, failure := range checkExpectations(testEvent.Expect, listener) {
+			result.Failures = append(result.Failures, fmt.Sprintf("event #%d (%s): %s", i, testEvent.Type, failure))
+		}
+	}
+
+	return nil
+}
+
+// checkExpectations returns the expectations that were not met
+func checkExpectations(expect PolicyTestExpectations, listener *policyTestListener) []string {
+	var failures []string
+
+	for _, id := range expect.Match {
+		if !listener.matched[id] {
+			failures = append(failures, fmt.Sprintf("rule `%s` was expected to match", id))
+		}
+	}
+	for _, id := range expect.NoMatch {
+		if listener.matched[id] {
+			failures = append(failures, fmt.Sprintf("rule `%s` was not expected to match", id))
+		}
+	}
+	for _, expected := range expect.Actions {
+		found := false
+		for _, action := range listener.actions {
+			if action == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			failures = append(failures, fmt.Sprintf("action `%s` was expected to be performed", expected))
+		}
+	}
+
+	return failures
+}
+
+func (r *policyTestRunner) updateCoverage(event *model.Event, listener *policyTestListener) {
+	for id := range listener.matched {
+		r.coverage.Rules[id]++
+	}
+
+	eventType := event.GetType()
+	for field := range listener.discarders {
+		value, err := event.GetFieldValue(field)
+		if err != nil {
+			continue
+		}
+		if r.coverage.Discarders[eventType] == nil {
+			r.coverage.Discarders[eventType] = make(map[eval.Field][]interface{})
+		}
+		r.coverage.Discarders[eventType][field] = append(r.coverage.Discarders[eventType][field], value)
+	}
+
+	approvers := r.coverage.Approvers[eventType]
+	for i, approver := range approvers {
+		value, err := event.GetFieldValue(approver.Field)
+		if err != nil {
+			continue
+		}
+		for _, filterValue := range r.approvers[eventType][approver.Field] {
+			if filterValue.Value == approver.Value && approverMatches(filterValue, value) {
+				approvers[i].Events++
+				break
+			}
+		}
+	}
+}
+
+// approverMatches returns whether an event field value is accepted by an approver
+func approverMatches(approver rules.FilterValue, value interface{}) bool {
+	switch approverValue := approver.Value.(type) {
+	case string:
+		matcher, err := eval.NewStringMatcher(approver.Type, approverValue, eval.StringCmpOpts{})
+		if err != nil {
+			return false
+		}
+		switch value := value.(type) {
+		case string:
+			return matcher.Matches(value)
+		case []string:
+			for _, v := range value {
+				if matcher.Matches(v) {
+					return true
+				}
+			}
+		}
+	case int:
+		v, ok := value.(int)
+		if !ok {
+			return false
+		}
+		if approver.Type == eval.BitmaskValueType {
+			return v&approverValue != 0
+		}
+		return v == approverValue
+	case bool:
+		v, ok := value.(bool)
+		return ok && v == approverValue
+	}
+	return false
+}
+
+// report finalizes and returns the coverage report
+func (r *policyTestRunner) report() *PolicyCoverageReport {
+	r.coverage.Uncovered = nil
+	for id, count := range r.coverage.Rules {
+		if count == 0 {
+			r.coverage.Uncovered = append(r.coverage.Uncovered, id)
+		}
+	}
+	sort.Strings(r.coverage.Uncovered)
+	return r.coverage
+}
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is the JUnit XML report of a test file
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is the JUnit XML report of a test case
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes the failure of a test case
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// writeJUnitReport writes the test results in the JUnit XML format
+func writeJUnitReport(results []*PolicyTestResult, writer io.Writer) error {
+	var report JUnitTestSuites
+	suites := make(map[string]int)
+
+	for _, result := range results {
+		index, exists := suites[result.File]
+		if !exists {
+			index = len(report.Suites)
+			suites[result.File] = index
+			report.Suites = append(report.Suites, JUnitTestSuite{Name: result.File})
+		}
+		suite := &report.Suites[index]
+
+		testCase := JUnitTestCase{
+			Name:      result.Name,
+			ClassName: result.File,
+			Time:      fmt.Sprintf("%.3f", result.Duration.Seconds()),
+		}
+		switch {
+		case result.Error != nil:
+			testCase.Failure = &JUnitFailure{
+				Message: "test could not be run",
+				Content: result.Error.Error(),
+			}
+			suite.Failures++
+		case !result.Succeeded():
+			testCase.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%d expectation(s) not met", len(result.Failures)),
+				Content: strings.Join(result.Failures, "\n"),
+			}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for i := range report.Suites {
+		var duration time.Duration
+		for _, result := range results {
+			if result.File == report.Suites[i].Name {
+				duration += result.Duration
+			}
+		}
+		report.Suites[i].Time = fmt.Sprintf("%.3f", duration.Seconds())
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+// policyTestFiles returns the YAML test files of a directory
+func policyTestFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if ext := filepath.Ext(entry.Name()); ext == ".yaml" || ext == ".yml" {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no test file found in %s", dir)
+	}
+	return files, nil
+}
+
+func runPolicyTests(args *testSuiteCliParams, writer io.Writer) ([]*PolicyTestResult, *PolicyCoverageReport, error) {
+	files, err := policyTestFiles(args.testsDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runner, err := newPolicyTestRunner(args.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []*PolicyTestResult
+	for _, file := range files {
+		fileResults := runner.runFile(file)
+
+		for _, result := range fileResults {
+			if result.Succeeded() {
+				fmt.Fprintf(writer, "PASS %s/%s\n", result.File, result.Name)
+				continue
+			}
+			fmt.Fprintf(writer, "FAIL %s/%s\n", result.File, result.Name)
+			if result.Error != nil {
+				fmt.Fprintf(writer, "    %s\n", result.Error)
+			}
+			for _, failure := range result.Failures {
+				fmt.Fprintf(writer, "    %s\n", failure)
+			}
+		}
+		results = append(results, fileResults...)
+	}
+
+	return results, runner.report(), nil
+}
+
+func runPolicyTestSuite(_ log.Component, _ config.Component, _ secrets.Component, args *testSuiteCliParams) error {
+	results, coverage, err := runPolicyTests(args, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if args.junitOutput != "" {
+		f, err := os.Create(args.junitOutput)
+		if err != nil {
+			return fmt.Errorf("unable to create JUnit report: %w", err)
+		}
+		defer f.Close()
+
+		if err := writeJUnitReport(results, f); err != nil {
+			return fmt.Errorf("unable to write JUnit report: %w", err)
+		}
+	}
+
+	if args.coverageOutput != "" {
+		content, _ := json.MarshalIndent(coverage, "", "\t")
+		if err := os.WriteFile(args.coverageOutput, content, 0644); err != nil {
+			return fmt.Errorf("unable to write coverage report: %w", err)
+		}
+	}
+
+	var failed int
+	for _, result := range results {
+		if !result.Succeeded() {
+			failed++
+		}
+	}
+	fmt.Printf("%d test(s), %d failure(s), %d/%d rule(s) covered\n", len(results), failed, len(coverage.Rules)-len(coverage.Uncovered), len(coverage.Rules))
+
+	if failed > 0 {
+		return errors.New("policy tests failed")
+	}
+
+	return nil
+}
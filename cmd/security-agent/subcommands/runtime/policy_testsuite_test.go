@@ -0,0 +1,197 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package runtime
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/security-agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+const testPolicy = `---
+version: 1.2.3
+rules:
+  - id: curl_download
+    expression: exec.file.path == "/usr/bin/curl"
+    actions:
+      - set:
+          name: downloader
+          value: true
+          scope: process
+  - id: shell_after_download
+    expression: exec.file.path == "/usr/bin/sh" && ${process.downloader}
+    actions:
+      - kill:
+          signal: SIGKILL
+  - id: shadow_open
+    expression: open.file.path == "/etc/shadow"
+    actions:
+      - hash: {}
+  - id: passwd_open
+    expression: open.file.path == "/etc/passwd"
+`
+
+const testSuiteFile = `name: download
+tests:
+  - name: shell spawned after a download
+    events:
+      - type: exec
+        values:
+          process.pid: 42
+          exec.file.path: /usr/bin/curl
+        expect:
+          match: [curl_download]
+          no_match: [shell_after_download]
+          actions:
+            - rule: curl_download
+              set: process.downloader
+      - type: exec
+        values:
+          process.pid: 42
+          exec.file.path: /usr/bin/sh
+        expect:
+          match: [shell_after_download]
+          actions:
+            - rule: shell_after_download
+              kill: SIGKILL
+  - name: shell spawned by another process
+    events:
+      - type: exec
+        values:
+          process.pid: 42
+          exec.file.path: /usr/bin/curl
+      - type: exec
+        values:
+          process.pid: 43
+          exec.file.path: /usr/bin/sh
+        expect:
+          no_match: [shell_after_download]
+  - name: wrong expectation
+    events:
+      - type: open
+        values:
+          open.file.path: /etc/shadow
+        expect:
+          match: [passwd_open]
+          actions:
+            - rule: shadow_open
+              hash: true
+`
+
+func writePolicyTestFiles(t *testing.T) *testSuiteCliParams {
+	policiesDir := t.TempDir()
+	testsDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(policiesDir, "test.policy"), []byte(testPolicy), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(testsDir, "download.yaml"), []byte(testSuiteFile), 0644))
+
+	return &testSuiteCliParams{
+		dir:      policiesDir,
+		testsDir: testsDir,
+	}
+}
+
+func TestPolicyTestSuiteCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		testSuiteCommands(&command.GlobalParams{}),
+		[]string{"test", "--tests-dir", "tests", "--junit-output", "junit.xml"},
+		runPolicyTestSuite,
+		func(cliParams *testSuiteCliParams, params core.BundleParams) {
+			require.Equal(t, "tests", cliParams.testsDir)
+			require.Equal(t, "junit.xml", cliParams.junitOutput)
+			require.Equal(t, command.LoggerName, params.LoggerName(), "logger name not matching")
+		},
+	)
+}
+
+func TestRunPolicyTests(t *testing.T) {
+	args := writePolicyTestFiles(t)
+
+	var output bytes.Buffer
+	results, coverage, err := runPolicyTests(args, &output)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.True(t, results[0].Succeeded(), results[0].Failures)
+	assert.True(t, results[1].Succeeded(), results[1].Failures)
+	assert.Equal(t, []string{"event #0 (open): rule `passwd_open` was expected to match"}, results[2].Failures)
+	assert.Contains(t, output.String(), "FAIL download/wrong expectation")
+
+	assert.Equal(t, 2, coverage.Rules["curl_download"])
+	assert.Equal(t, 1, coverage.Rules["shell_after_download"])
+	assert.Equal(t, []string{"passwd_open"}, coverage.Uncovered)
+
+	var covered bool
+	for _, approver := range coverage.Approvers["open"] {
+		if approver.Field == "open.file.path" && approver.Value == "/etc/shadow" {
+			covered = approver.Events == 1
+		}
+	}
+	assert.True(t, covered, "the /etc/shadow approver should be covered")
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	args := writePolicyTestFiles(t)
+
+	results, _, err := runPolicyTests(args, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, writeJUnitReport(results, &output))
+
+	var report JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(output.Bytes(), &report))
+	require.Len(t, report.Suites, 1)
+	assert.Equal(t, "download", report.Suites[0].Name)
+	assert.Equal(t, 3, report.Suites[0].Tests)
+	assert.Equal(t, 1, report.Suites[0].Failures)
+	require.NotNil(t, report.Suites[0].TestCases[2].Failure)
+	assert.Nil(t, report.Suites[0].TestCases[0].Failure)
+}
+
+func TestRunPolicyTestsInvalidFiles(t *testing.T) {
+	args := writePolicyTestFiles(t)
+	// files are run in lexical order
+	require.NoError(t, os.WriteFile(filepath.Join(args.testsDir, "broken.yaml"), []byte("tests: [\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(args.testsDir, "unknown.yaml"), []byte(`tests:
+  - name: unknown event type
+    events:
+      - type: unknown
+`), 0644))
+
+	var output bytes.Buffer
+	results, _, err := runPolicyTests(args, &output)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.Equal(t, "broken", results[0].File)
+	assert.Error(t, results[0].Error)
+	assert.False(t, results[0].Succeeded())
+	assert.True(t, results[1].Succeeded(), results[1].Failures)
+	assert.Equal(t, "unknown event type", results[4].Name)
+	assert.Error(t, results[4].Error)
+	assert.Contains(t, output.String(), "FAIL broken/broken")
+
+	var junit bytes.Buffer
+	require.NoError(t, writeJUnitReport(results, &junit))
+	var report JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(junit.Bytes(), &report))
+	require.Len(t, report.Suites, 3)
+	assert.Equal(t, 1, report.Suites[0].Failures)
+	require.NotNil(t, report.Suites[0].TestCases[0].Failure)
+	assert.Equal(t, "test could not be run", report.Suites[0].TestCases[0].Failure.Message)
+}
@@ -49,6 +49,29 @@ func TestParseParams(t *testing.T) {
 				Timeout:      1000,
 			},
 		},
+		{
+			name: "paris multipath config",
+			host: "1.2.3.4",
+			params: map[string]string{
+				"protocol":   "ICMP",
+				"num_paths":  "4",
+				"paris_mode": "true",
+			},
+			expectedConfig: tracerouteutil.Config{
+				DestHostname: "1.2.3.4",
+				Protocol:     "ICMP",
+				NumPaths:     4,
+				ParisMode:    true,
+			},
+		},
+		{
+			name: "invalid paris mode",
+			host: "1.2.3.4",
+			params: map[string]string{
+				"paris_mode": "sometimes",
+			},
+			expectedError: `invalid paris_mode: strconv.ParseBool: parsing "sometimes": invalid syntax`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(_ *testing.T) {
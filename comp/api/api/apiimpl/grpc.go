@@ -83,7 +83,18 @@ func (s *serverSecure) DogstatsdCaptureTrigger(_ context.Context, req *pb.Captur
 		return &pb.CaptureTriggerResponse{}, err
 	}
 
-	p, err := s.capture.StartCapture(req.GetPath(), d, req.GetCompressed())
+	opts := dsdReplay.CaptureOptions{
+		Filter: dsdReplay.CaptureFilter{
+			MetricNameRegex: req.GetMetricNameRegex(),
+			Tags:            req.GetTags(),
+			ContainerID:     req.GetContainerId(),
+			Pid:             req.GetPid(),
+			Transport:       req.GetTransport(),
+		},
+		MaxSize: req.GetMaxSize(),
+	}
+
+	p, err := s.capture.StartCapture(req.GetPath(), d, req.GetCompressed(), opts)
 	if err != nil {
 		return &pb.CaptureTriggerResponse{}, err
 	}
@@ -45,7 +45,8 @@ func getJMXConfigs(w http.ResponseWriter, r *http.Request) {
 	j := map[string]interface{}{}
 	configs := map[string]integration.JSONMap{}
 
-	for name, config := range jmxfetch.GetScheduledConfigs() {
+	// when JMX integrations run in several JMXFetch processes, the other processes load their configs from files
+	for name, config := range jmxfetch.GetSingleProcessScheduledConfigs() {
 		var rawInitConfig integration.RawMap
 		err := yaml.Unmarshal(config.InitConfig, &rawInitConfig)
 		if err != nil {
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build jmx
+
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/jmxfetch"
+)
+
+func TestGetJMXConfigs(t *testing.T) {
+	cfg := configmock.New(t)
+
+	getConfigs := func() map[string]map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/jmx/configs", nil)
+		rec := httptest.NewRecorder()
+		getJMXConfigs(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var payload struct {
+			Configs map[string]map[string]interface{} `json:"configs"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &payload))
+		return payload.Configs
+	}
+	checkNames := func(configs map[string]map[string]interface{}) []string {
+		names := []string{}
+		for _, c := range configs {
+			names = append(names, c["check_name"].(string))
+		}
+		return names
+	}
+
+	// the single JMXFetch process pulls the configs it runs from the API
+	jmxfetch.AddScheduledConfig(integration.Config{
+		Name:       "kafka",
+		InitConfig: integration.Data("is_jmx: true\n"),
+		Instances:  []integration.Data{integration.Data("host: localhost\nport: 9999\n")},
+	})
+	configs := getConfigs()
+	assert.Equal(t, []string{"kafka"}, checkNames(configs))
+	for _, c := range configs {
+		assert.Equal(t, map[string]interface{}{"is_jmx": true}, c["init_config"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"host": "localhost", "port": float64(9999)}}, c["instances"])
+	}
+
+	// the configs run by the other processes are loaded from their config files, they are not served by the API
+	cfg.SetWithoutSource("jmx_process_mode", "per_integration")
+	jmxfetch.AddScheduledConfig(integration.Config{
+		Name:      "tomcat",
+		Instances: []integration.Data{integration.Data("host: localhost\nport: 9012\n")},
+	})
+	assert.Equal(t, []string{"kafka"}, checkNames(getConfigs()))
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+
+package listeners
+
+import (
+	"errors"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/telemetry"
+	"github.com/DataDog/datadog-agent/comp/core/tagger"
+	taggercommon "github.com/DataDog/datadog-agent/comp/core/tagger/common"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// KubeWorkloadsListener listens to Kubernetes Jobs, CronJobs, StatefulSets
+// and DaemonSets collected from the API server through a subscription to the
+// workloadmeta store.
+type KubeWorkloadsListener struct {
+	workloadmetaListener
+}
+
+// NewKubeWorkloadsListener returns a new KubeWorkloadsListener.
+func NewKubeWorkloadsListener(_ Config, wmeta optional.Option[workloadmeta.Component], telemetryStore *telemetry.Store) (ServiceListener, error) {
+	const name = "ad-kubeworkloadslistener"
+	l := &KubeWorkloadsListener{}
+	filter := workloadmeta.NewFilterBuilder().
+		SetSource(workloadmeta.SourceAll).
+		AddKind(workloadmeta.KindKubernetesJob).
+		AddKind(workloadmeta.KindKubernetesCronJob).
+		AddKind(workloadmeta.KindKubernetesStatefulSet).
+		AddKind(workloadmeta.KindKubernetesDaemonSet).Build()
+
+	wmetaInstance, ok := wmeta.Get()
+	if !ok {
+		return nil, errors.New("workloadmeta store is not initialized")
+	}
+	var err error
+	l.workloadmetaListener, err = newWorkloadmetaListener(name, filter, l.createWorkloadService, wmetaInstance, telemetryStore)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func registerKubeWorkloadsListener(serviceListenerFactories map[string]ServiceListenerFactory, wmeta optional.Option[workloadmeta.Component]) {
+	Register(kubeWorkloadsListenerName, func(config Config, telemetryStore *telemetry.Store) (ServiceListener, error) {
+		return NewKubeWorkloadsListener(config, wmeta, telemetryStore)
+	}, serviceListenerFactories)
+}
+
+func (l *KubeWorkloadsListener) createWorkloadService(entity workloadmeta.Entity) {
+	ready := true
+	if job, ok := entity.(*workloadmeta.KubernetesJob); ok {
+		// Finished jobs are not scheduled anymore
+		ready = job.CompletionTime.IsZero()
+	}
+
+	entityID := entity.GetID()
+	svc := &service{
+		entity:        entity,
+		tagsHash:      tagger.GetEntityHash(taggercommon.BuildTaggerEntityID(entityID).String(), tagger.ChecksCardinality()),
+		adIdentifiers: []string{buildKubeWorkloadEntityName(entityID)},
+		ready:         ready,
+	}
+
+	l.AddService(buildSvcID(entityID), svc, "")
+}
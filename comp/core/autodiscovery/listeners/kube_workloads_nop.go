@@ -0,0 +1,16 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !(clusterchecks && kubeapiserver)
+
+package listeners
+
+import (
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// The kube workloads listener is only available in the Cluster Agent
+func registerKubeWorkloadsListener(map[string]ServiceListenerFactory, optional.Option[workloadmeta.Component]) {}
@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+
+package listeners
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+func TestCreateWorkloadService(t *testing.T) {
+	statefulSet := &workloadmeta.KubernetesStatefulSet{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesStatefulSet,
+			ID:   "default/redis",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:      "redis",
+			Namespace: "default",
+		},
+	}
+
+	completedJob := &workloadmeta.KubernetesJob{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesJob,
+			ID:   "default/export-28761230",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:      "export-28761230",
+			Namespace: "default",
+		},
+		CompletionTime: time.Now(),
+	}
+
+	tests := []struct {
+		name             string
+		entity           workloadmeta.Entity
+		expectedServices map[string]wlmListenerSvc
+	}{
+		{
+			name:   "statefulset",
+			entity: statefulSet,
+			expectedServices: map[string]wlmListenerSvc{
+				"kubernetes_statefulset://default/redis": {
+					service: &service{
+						entity:        statefulSet,
+						adIdentifiers: []string{"kube_statefulset://default/redis"},
+						ready:         true,
+					},
+				},
+			},
+		},
+		{
+			name:   "completed job is not ready",
+			entity: completedJob,
+			expectedServices: map[string]wlmListenerSvc{
+				"kubernetes_job://default/export-28761230": {
+					service: &service{
+						entity:        completedJob,
+						adIdentifiers: []string{"kube_job://default/export-28761230"},
+						ready:         false,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wlm := newTestWorkloadmetaListener(t)
+			listener := &KubeWorkloadsListener{workloadmetaListener: wlm}
+
+			listener.createWorkloadService(tt.entity)
+
+			wlm.assertServices(tt.expectedServices)
+		})
+	}
+}
+
+func TestKubeWorkloadServiceID(t *testing.T) {
+	svc := &service{
+		entity: &workloadmeta.KubernetesDaemonSet{
+			EntityID: workloadmeta.EntityID{
+				Kind: workloadmeta.KindKubernetesDaemonSet,
+				ID:   "kube-system/node-exporter",
+			},
+		},
+	}
+
+	assert.Equal(t, "kube_daemonset://kube-system/node-exporter", svc.GetServiceID())
+}
@@ -18,6 +18,7 @@ const (
 	environmentListenerName     = "environment"
 	kubeEndpointsListenerName   = "kube_endpoints"
 	kubeServicesListenerName    = "kube_services"
+	kubeWorkloadsListenerName   = "kube_workloads"
 	kubeletListenerName         = "kubelet"
 	snmpListenerName            = "snmp"
 	staticConfigListenerName    = "static config"
@@ -34,6 +35,7 @@ func RegisterListeners(serviceListenerFactories map[string]ServiceListenerFactor
 	Register(environmentListenerName, NewEnvironmentListener, serviceListenerFactories)
 	Register(kubeEndpointsListenerName, NewKubeEndpointsListener, serviceListenerFactories)
 	Register(kubeServicesListenerName, NewKubeServiceListener, serviceListenerFactories)
+	registerKubeWorkloadsListener(serviceListenerFactories, wmeta)
 	Register(kubeletListenerName, func(config Config, telemetryStore *telemetry.Store) (ServiceListener, error) {
 		return NewKubeletListener(config, wmeta, telemetryStore)
 	}, serviceListenerFactories)
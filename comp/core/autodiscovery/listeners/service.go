@@ -65,6 +65,8 @@ func (s *service) GetServiceID() string {
 		return containers.BuildEntityName(string(e.Runtime), e.ID)
 	case *workloadmeta.KubernetesPod:
 		return kubelet.PodUIDToEntityName(e.ID)
+	case *workloadmeta.KubernetesJob, *workloadmeta.KubernetesCronJob, *workloadmeta.KubernetesStatefulSet, *workloadmeta.KubernetesDaemonSet:
+		return buildKubeWorkloadEntityName(e.GetID())
 	default:
 		entityID := s.entity.GetID()
 		log.Errorf("cannot build AD entity ID for kind %q, ID %q", entityID.Kind, entityID.ID)
@@ -72,6 +74,20 @@ func (s *service) GetServiceID() string {
 	}
 }
 
+// kubeWorkloadEntityPrefixes maps workload controller kinds to their AD entity prefix
+var kubeWorkloadEntityPrefixes = map[workloadmeta.Kind]string{
+	workloadmeta.KindKubernetesJob:         "kube_job://",
+	workloadmeta.KindKubernetesCronJob:     "kube_cronjob://",
+	workloadmeta.KindKubernetesStatefulSet: "kube_statefulset://",
+	workloadmeta.KindKubernetesDaemonSet:   "kube_daemonset://",
+}
+
+// buildKubeWorkloadEntityName returns the AD entity of a workload controller,
+// e.g. kube_statefulset://<namespace>/<name>
+func buildKubeWorkloadEntityName(entityID workloadmeta.EntityID) string {
+	return kubeWorkloadEntityPrefixes[entityID.Kind] + entityID.ID
+}
+
 // GetADIdentifiers returns the service's AD identifiers.
 func (s *service) GetADIdentifiers(_ context.Context) ([]string, error) {
 	return s.adIdentifiers, nil
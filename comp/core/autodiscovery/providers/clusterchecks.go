@@ -36,6 +36,7 @@ type ClusterChecksConfigProvider struct {
 	heartbeat        time.Time
 	lastChange       int64
 	identifier       string
+	nodeName         string
 	flushedConfigs   bool
 }
 
@@ -50,6 +51,7 @@ func NewClusterChecksConfigProvider(providerConfig *pkgconfigsetup.Configuration
 	c := &ClusterChecksConfigProvider{
 		graceDuration:    defaultGraceDuration,
 		degradedDuration: defaultDegradedDeadline,
+		nodeName:         pkgconfigsetup.Datadog().GetString("kubernetes_kubelet_nodename"),
 	}
 
 	c.identifier = pkgconfigsetup.Datadog().GetString("clc_runner_id")
@@ -115,6 +117,7 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 
 	status := types.NodeStatus{
 		LastChange: c.lastChange,
+		NodeName:   c.nodeName,
 	}
 
 	reply, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
@@ -213,6 +216,7 @@ func (c *ClusterChecksConfigProvider) postHeartbeat(ctx context.Context) error {
 
 	status := types.NodeStatus{
 		LastChange: types.ExtraHeartbeatLastChangeValue,
+		NodeName:   c.nodeName,
 	}
 
 	_, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
@@ -27,6 +27,14 @@ func BuildTaggerEntityID(entityID workloadmeta.EntityID) types.EntityID {
 		return types.NewEntityID(types.Process, entityID.ID)
 	case workloadmeta.KindKubernetesDeployment:
 		return types.NewEntityID(types.KubernetesDeployment, entityID.ID)
+	case workloadmeta.KindKubernetesJob:
+		return types.NewEntityID(types.KubernetesJob, entityID.ID)
+	case workloadmeta.KindKubernetesCronJob:
+		return types.NewEntityID(types.KubernetesCronJob, entityID.ID)
+	case workloadmeta.KindKubernetesStatefulSet:
+		return types.NewEntityID(types.KubernetesStatefulSet, entityID.ID)
+	case workloadmeta.KindKubernetesDaemonSet:
+		return types.NewEntityID(types.KubernetesDaemonSet, entityID.ID)
 	case workloadmeta.KindHost:
 		return types.NewEntityID(types.Host, entityID.ID)
 	case workloadmeta.KindKubernetesMetadata:
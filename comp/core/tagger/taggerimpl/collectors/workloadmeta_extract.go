@@ -150,6 +150,14 @@ func (c *WorkloadMetaCollector) processEvents(evBundle workloadmeta.EventBundle)
 				// tagInfos = append(tagInfos, c.handleProcess(ev)...) No tags for now
 			case workloadmeta.KindKubernetesDeployment:
 				tagInfos = append(tagInfos, c.handleKubeDeployment(ev)...)
+			case workloadmeta.KindKubernetesJob:
+				tagInfos = append(tagInfos, c.handleKubeJob(ev)...)
+			case workloadmeta.KindKubernetesCronJob:
+				tagInfos = append(tagInfos, c.handleKubeCronJob(ev)...)
+			case workloadmeta.KindKubernetesStatefulSet:
+				tagInfos = append(tagInfos, c.handleKubeStatefulSet(ev)...)
+			case workloadmeta.KindKubernetesDaemonSet:
+				tagInfos = append(tagInfos, c.handleKubeDaemonSet(ev)...)
 			default:
 				log.Errorf("cannot handle event for entity %q with kind %q", entityID.ID, entityID.Kind)
 			}
@@ -564,6 +572,80 @@ func (c *WorkloadMetaCollector) handleKubeDeployment(ev workloadmeta.Event) []*t
 	return tagInfos
 }
 
+func (c *WorkloadMetaCollector) handleKubeJob(ev workloadmeta.Event) []*types.TagInfo {
+	job := ev.Entity.(*workloadmeta.KubernetesJob)
+
+	tagList := taglist.NewTagList()
+	if cronJob := job.CronJobOwner(); cronJob != "" {
+		tagList.AddOrchestrator(tags.KubeJob, job.Name)
+		tagList.AddLow(tags.KubeCronjob, cronJob)
+	} else {
+		tagList.AddLow(tags.KubeJob, job.Name)
+	}
+
+	return c.handleKubeWorkload(jobSource, "jobs.batch", job.EntityID, job.EntityMeta, tagList)
+}
+
+func (c *WorkloadMetaCollector) handleKubeCronJob(ev workloadmeta.Event) []*types.TagInfo {
+	cronJob := ev.Entity.(*workloadmeta.KubernetesCronJob)
+
+	tagList := taglist.NewTagList()
+	tagList.AddLow(tags.KubeCronjob, cronJob.Name)
+
+	return c.handleKubeWorkload(cronJobSource, "cronjobs.batch", cronJob.EntityID, cronJob.EntityMeta, tagList)
+}
+
+func (c *WorkloadMetaCollector) handleKubeStatefulSet(ev workloadmeta.Event) []*types.TagInfo {
+	statefulSet := ev.Entity.(*workloadmeta.KubernetesStatefulSet)
+
+	tagList := taglist.NewTagList()
+	tagList.AddLow(tags.KubeStatefulSet, statefulSet.Name)
+
+	return c.handleKubeWorkload(statefulSetSource, "statefulsets.apps", statefulSet.EntityID, statefulSet.EntityMeta, tagList)
+}
+
+func (c *WorkloadMetaCollector) handleKubeDaemonSet(ev workloadmeta.Event) []*types.TagInfo {
+	daemonSet := ev.Entity.(*workloadmeta.KubernetesDaemonSet)
+
+	tagList := taglist.NewTagList()
+	tagList.AddLow(tags.KubeDaemonSet, daemonSet.Name)
+
+	return c.handleKubeWorkload(daemonSetSource, "daemonsets.apps", daemonSet.EntityID, daemonSet.EntityMeta, tagList)
+}
+
+// handleKubeWorkload completes the tags of a workload controller (job, cronjob, statefulset, daemonset)
+// with its namespace and the labels and annotations configured as tags for its group resource.
+func (c *WorkloadMetaCollector) handleKubeWorkload(source string, groupResource string, entityID workloadmeta.EntityID, meta workloadmeta.EntityMeta, tagList *taglist.TagList) []*types.TagInfo {
+	tagList.AddLow(tags.KubeNamespace, meta.Namespace)
+
+	labelsAsTags := c.k8sResourcesLabelsAsTags[groupResource]
+	annotationsAsTags := c.k8sResourcesAnnotationsAsTags[groupResource]
+
+	globLabels := c.globK8sResourcesLabels[groupResource]
+	globAnnotations := c.globK8sResourcesAnnotations[groupResource]
+
+	for name, value := range meta.Labels {
+		k8smetadata.AddMetadataAsTags(name, value, labelsAsTags, globLabels, tagList)
+	}
+
+	for name, value := range meta.Annotations {
+		k8smetadata.AddMetadataAsTags(name, value, annotationsAsTags, globAnnotations, tagList)
+	}
+
+	low, orch, high, standard := tagList.Compute()
+
+	return []*types.TagInfo{
+		{
+			Source:               source,
+			EntityID:             common.BuildTaggerEntityID(entityID),
+			HighCardTags:         high,
+			OrchestratorCardTags: orch,
+			LowCardTags:          low,
+			StandardTags:         standard,
+		},
+	}
+}
+
 func (c *WorkloadMetaCollector) handleKubeMetadata(ev workloadmeta.Event) []*types.TagInfo {
 	kubeMetadata := ev.Entity.(*workloadmeta.KubernetesMetadata)
 
@@ -640,12 +722,14 @@ func (c *WorkloadMetaCollector) extractTagsFromPodOwner(pod *workloadmeta.Kubern
 
 	case kubernetes.DaemonSetKind:
 		tagList.AddLow(tags.KubeDaemonSet, owner.Name)
+		c.extractRolloutRevision(pod, tagList)
 
 	case kubernetes.ReplicationControllerKind:
 		tagList.AddLow(tags.KubeReplicationController, owner.Name)
 
 	case kubernetes.StatefulSetKind:
 		tagList.AddLow(tags.KubeStatefulSet, owner.Name)
+		c.extractRolloutRevision(pod, tagList)
 		if c.collectPersistentVolumeClaimsTags {
 			for _, pvc := range pod.PersistentVolumeClaimNames {
 				if pvc != "" {
@@ -655,7 +739,14 @@ func (c *WorkloadMetaCollector) extractTagsFromPodOwner(pod *workloadmeta.Kubern
 		}
 
 	case kubernetes.JobKind:
-		cronjob, _ := kubernetes.ParseCronJobForJob(owner.Name)
+		// The owner CronJob is read from the Job when it is known, the Job name
+		// does not always follow the CronJob naming convention.
+		var cronjob string
+		if job, err := c.store.GetKubernetesJob(pod.Namespace + "/" + owner.Name); err == nil {
+			cronjob = job.CronJobOwner()
+		} else {
+			cronjob, _ = kubernetes.ParseCronJobForJob(owner.Name)
+		}
 		if cronjob != "" {
 			tagList.AddOrchestrator(tags.KubeJob, owner.Name)
 			tagList.AddLow(tags.KubeCronjob, cronjob)
@@ -672,6 +763,13 @@ func (c *WorkloadMetaCollector) extractTagsFromPodOwner(pod *workloadmeta.Kubern
 	}
 }
 
+// extractRolloutRevision adds the controller revision of pods owned by a stateful set or a daemon set
+func (c *WorkloadMetaCollector) extractRolloutRevision(pod *workloadmeta.KubernetesPod, tagList *taglist.TagList) {
+	if revision := pod.Labels[kubernetes.ControllerRevisionHashLabelKey]; revision != "" {
+		tagList.AddLow(tags.KubeRolloutRevision, revision)
+	}
+}
+
 func (c *WorkloadMetaCollector) extractTagsFromPodContainer(pod *workloadmeta.KubernetesPod, podContainer workloadmeta.OrchestratorContainer, tagList *taglist.TagList) (*types.TagInfo, error) {
 	container, err := c.store.GetContainer(podContainer.ID)
 	if err != nil {
@@ -37,6 +37,10 @@ const (
 	hostSource           = workloadmetaCollectorName + "-" + string(workloadmeta.KindHost)
 	kubeMetadataSource   = workloadmetaCollectorName + "-" + string(workloadmeta.KindKubernetesMetadata)
 	deploymentSource     = workloadmetaCollectorName + "-" + string(workloadmeta.KindKubernetesDeployment)
+	jobSource            = workloadmetaCollectorName + "-" + string(workloadmeta.KindKubernetesJob)
+	cronJobSource        = workloadmetaCollectorName + "-" + string(workloadmeta.KindKubernetesCronJob)
+	statefulSetSource    = workloadmetaCollectorName + "-" + string(workloadmeta.KindKubernetesStatefulSet)
+	daemonSetSource      = workloadmetaCollectorName + "-" + string(workloadmeta.KindKubernetesDaemonSet)
 
 	clusterTagNamePrefix = "kube_cluster_name"
 )
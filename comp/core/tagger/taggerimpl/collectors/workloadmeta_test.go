@@ -863,6 +863,226 @@ func TestHandleKubePod(t *testing.T) {
 	}
 }
 
+func TestHandleKubeWorkloads(t *testing.T) {
+	store := fxutil.Test[workloadmetamock.Mock](t, fx.Options(
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		config.MockModule(),
+		fx.Supply(context.Background()),
+		workloadmetafxmock.MockModule(workloadmeta.NewParams()),
+	))
+
+	cfg := configmock.New(t)
+	collector := NewWorkloadMetaCollector(context.Background(), cfg, store, nil)
+	collector.initK8sResourcesMetaAsTags(
+		map[string]map[string]string{"statefulsets.apps": {"team": "team"}},
+		map[string]map[string]string{"cronjobs.batch": {"tier": "tier"}},
+	)
+
+	tests := []struct {
+		name     string
+		entity   workloadmeta.Entity
+		expected []*types.TagInfo
+	}{
+		{
+			name: "job owned by a cronjob",
+			entity: &workloadmeta.KubernetesJob{
+				EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesJob, ID: "default/export-manual"},
+				EntityMeta: workloadmeta.EntityMeta{Name: "export-manual", Namespace: "default"},
+				Owners:     []workloadmeta.KubernetesPodOwner{{Kind: kubernetes.CronJobKind, Name: "export"}},
+			},
+			expected: []*types.TagInfo{
+				{
+					Source:               jobSource,
+					EntityID:             types.NewEntityID(types.KubernetesJob, "default/export-manual"),
+					HighCardTags:         []string{},
+					OrchestratorCardTags: []string{"kube_job:export-manual"},
+					LowCardTags:          []string{"kube_namespace:default", "kube_cronjob:export"},
+					StandardTags:         []string{},
+				},
+			},
+		},
+		{
+			name: "cronjob with annotations as tags",
+			entity: &workloadmeta.KubernetesCronJob{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesCronJob, ID: "default/export"},
+				EntityMeta: workloadmeta.EntityMeta{
+					Name:        "export",
+					Namespace:   "default",
+					Annotations: map[string]string{"tier": "batch"},
+				},
+			},
+			expected: []*types.TagInfo{
+				{
+					Source:               cronJobSource,
+					EntityID:             types.NewEntityID(types.KubernetesCronJob, "default/export"),
+					HighCardTags:         []string{},
+					OrchestratorCardTags: []string{},
+					LowCardTags:          []string{"kube_namespace:default", "kube_cronjob:export", "tier:batch"},
+					StandardTags:         []string{},
+				},
+			},
+		},
+		{
+			name: "statefulset with labels as tags",
+			entity: &workloadmeta.KubernetesStatefulSet{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesStatefulSet, ID: "default/redis"},
+				EntityMeta: workloadmeta.EntityMeta{
+					Name:      "redis",
+					Namespace: "default",
+					Labels:    map[string]string{"team": "storage"},
+				},
+			},
+			expected: []*types.TagInfo{
+				{
+					Source:               statefulSetSource,
+					EntityID:             types.NewEntityID(types.KubernetesStatefulSet, "default/redis"),
+					HighCardTags:         []string{},
+					OrchestratorCardTags: []string{},
+					LowCardTags:          []string{"kube_namespace:default", "kube_stateful_set:redis", "team:storage"},
+					StandardTags:         []string{},
+				},
+			},
+		},
+		{
+			name: "daemonset",
+			entity: &workloadmeta.KubernetesDaemonSet{
+				EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesDaemonSet, ID: "kube-system/node-exporter"},
+				EntityMeta: workloadmeta.EntityMeta{Name: "node-exporter", Namespace: "kube-system"},
+			},
+			expected: []*types.TagInfo{
+				{
+					Source:               daemonSetSource,
+					EntityID:             types.NewEntityID(types.KubernetesDaemonSet, "kube-system/node-exporter"),
+					HighCardTags:         []string{},
+					OrchestratorCardTags: []string{},
+					LowCardTags:          []string{"kube_namespace:kube-system", "kube_daemon_set:node-exporter"},
+					StandardTags:         []string{},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			var actual []*types.TagInfo
+			ev := workloadmeta.Event{Type: workloadmeta.EventTypeSet, Entity: test.entity}
+			switch test.entity.GetID().Kind {
+			case workloadmeta.KindKubernetesJob:
+				actual = collector.handleKubeJob(ev)
+			case workloadmeta.KindKubernetesCronJob:
+				actual = collector.handleKubeCronJob(ev)
+			case workloadmeta.KindKubernetesStatefulSet:
+				actual = collector.handleKubeStatefulSet(ev)
+			case workloadmeta.KindKubernetesDaemonSet:
+				actual = collector.handleKubeDaemonSet(ev)
+			}
+
+			assertTagInfoListEqual(tt, test.expected, actual)
+		})
+	}
+}
+
+func TestHandleKubePodWithWorkloadOwners(t *testing.T) {
+	const podNamespace = "default"
+
+	podEntityID := workloadmeta.EntityID{
+		Kind: workloadmeta.KindKubernetesPod,
+		ID:   "foobar",
+	}
+	podTaggerEntityID := types.NewEntityID(types.KubernetesPodUID, podEntityID.ID)
+
+	store := fxutil.Test[workloadmetamock.Mock](t, fx.Options(
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		config.MockModule(),
+		fx.Supply(context.Background()),
+		workloadmetafxmock.MockModule(workloadmeta.NewParams()),
+	))
+	// The job name does not follow the CronJob naming convention, the owner is read from the Job entity
+	store.Set(&workloadmeta.KubernetesJob{
+		EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesJob, ID: podNamespace + "/export-manual"},
+		EntityMeta: workloadmeta.EntityMeta{Name: "export-manual", Namespace: podNamespace},
+		Owners:     []workloadmeta.KubernetesPodOwner{{Kind: kubernetes.CronJobKind, Name: "export"}},
+	})
+
+	tests := []struct {
+		name     string
+		pod      workloadmeta.KubernetesPod
+		expected []*types.TagInfo
+	}{
+		{
+			name: "pod owned by a job known in workloadmeta",
+			pod: workloadmeta.KubernetesPod{
+				EntityID:   podEntityID,
+				EntityMeta: workloadmeta.EntityMeta{Name: "export-manual-x7k2p", Namespace: podNamespace},
+				Owners:     []workloadmeta.KubernetesPodOwner{{Kind: kubernetes.JobKind, Name: "export-manual"}},
+			},
+			expected: []*types.TagInfo{
+				{
+					Source:       podSource,
+					EntityID:     podTaggerEntityID,
+					HighCardTags: []string{},
+					OrchestratorCardTags: []string{
+						"pod_name:export-manual-x7k2p",
+						"kube_ownerref_name:export-manual",
+						"kube_job:export-manual",
+					},
+					LowCardTags: []string{
+						"kube_namespace:default",
+						"kube_ownerref_kind:job",
+						"kube_cronjob:export",
+					},
+					StandardTags: []string{},
+				},
+			},
+		},
+		{
+			name: "pod owned by a statefulset with a controller revision",
+			pod: workloadmeta.KubernetesPod{
+				EntityID: podEntityID,
+				EntityMeta: workloadmeta.EntityMeta{
+					Name:      "redis-0",
+					Namespace: podNamespace,
+					Labels:    map[string]string{kubernetes.ControllerRevisionHashLabelKey: "redis-5d8f9c7b4"},
+				},
+				Owners: []workloadmeta.KubernetesPodOwner{{Kind: kubernetes.StatefulSetKind, Name: "redis"}},
+			},
+			expected: []*types.TagInfo{
+				{
+					Source:       podSource,
+					EntityID:     podTaggerEntityID,
+					HighCardTags: []string{},
+					OrchestratorCardTags: []string{
+						"pod_name:redis-0",
+						"kube_ownerref_name:redis",
+					},
+					LowCardTags: []string{
+						"kube_namespace:default",
+						"kube_ownerref_kind:statefulset",
+						"kube_stateful_set:redis",
+						"kube_rollout_revision:redis-5d8f9c7b4",
+					},
+					StandardTags: []string{},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configmock.New(t)
+			cfg.SetWithoutSource("kubernetes_persistent_volume_claims_as_tags", false)
+			collector := NewWorkloadMetaCollector(context.Background(), cfg, store, nil)
+
+			actual := collector.handleKubePod(workloadmeta.Event{
+				Type:   workloadmeta.EventTypeSet,
+				Entity: &tt.pod,
+			})
+
+			assertTagInfoListEqual(t, tt.expected, actual)
+		})
+	}
+}
+
 func TestHandleKubePodWithoutPvcAsTags(t *testing.T) {
 	const (
 		noEnvContainerID     = "foobarbaz"
@@ -71,6 +71,8 @@ const (
 	KubeJob = "kube_job"
 	// KubeCronjob is the tag for the cronjob name
 	KubeCronjob = "kube_cronjob"
+	// KubeRolloutRevision is the tag for the controller revision of pods owned by a stateful set or a daemon set
+	KubeRolloutRevision = "kube_rollout_revision"
 	// KubeService is the tag for the service name
 	KubeService = "kube_service"
 	// KubeNamespace is the tag for the namespace name
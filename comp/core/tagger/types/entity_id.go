@@ -127,6 +127,14 @@ const (
 	Host EntityIDPrefix = "host"
 	// KubernetesDeployment is the prefix `deployment`
 	KubernetesDeployment EntityIDPrefix = "deployment"
+	// KubernetesJob is the prefix `job`
+	KubernetesJob EntityIDPrefix = "job"
+	// KubernetesCronJob is the prefix `cronjob`
+	KubernetesCronJob EntityIDPrefix = "cronjob"
+	// KubernetesStatefulSet is the prefix `statefulset`
+	KubernetesStatefulSet EntityIDPrefix = "statefulset"
+	// KubernetesDaemonSet is the prefix `daemonset`
+	KubernetesDaemonSet EntityIDPrefix = "daemonset"
 	// KubernetesMetadata is the prefix `kubernetes_metadata`
 	KubernetesMetadata EntityIDPrefix = "kubernetes_metadata"
 	// KubernetesPodUID is the prefix `kubernetes_pod_uid`
@@ -143,6 +151,10 @@ func AllPrefixesSet() map[EntityIDPrefix]struct{} {
 		ECSTask:                {},
 		Host:                   {},
 		KubernetesDeployment:   {},
+		KubernetesJob:          {},
+		KubernetesCronJob:      {},
+		KubernetesStatefulSet:  {},
+		KubernetesDaemonSet:    {},
 		KubernetesMetadata:     {},
 		KubernetesPodUID:       {},
 		Process:                {},
@@ -55,6 +55,10 @@ func TestFilterBuilderOps(t *testing.T) {
 				prefixes: map[EntityIDPrefix]struct{}{
 					ContainerImageMetadata: {},
 					ECSTask:                {},
+					KubernetesJob:          {},
+					KubernetesCronJob:      {},
+					KubernetesStatefulSet:  {},
+					KubernetesDaemonSet:    {},
 					KubernetesMetadata:     {},
 					KubernetesPodUID:       {},
 					Process:                {},
@@ -69,6 +69,10 @@ func storeGenerators(cfg config.Reader) []storeGenerator {
 		generators = append(generators, newDeploymentStore)
 	}
 
+	if shouldHaveWorkloadStores(cfg) {
+		generators = append(generators, newJobStore, newCronJobStore, newStatefulSetStore, newDaemonSetStore)
+	}
+
 	return generators
 }
 
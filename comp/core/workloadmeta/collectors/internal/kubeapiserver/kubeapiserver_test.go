@@ -31,19 +31,30 @@ func TestStoreGenerators(t *testing.T) {
 	}{
 		{
 			name: "All configurations disabled",
+			cfg: map[string]interface{}{
+				"cluster_agent.collect_kubernetes_tags":                           false,
+				"cluster_agent.kubernetes_resources_collection.workloads_enabled": false,
+				"language_detection.reporting.enabled":                            false,
+				"language_detection.enabled":                                      false,
+			},
+			expectedStoresGenerator: []storeGenerator{},
+		},
+		{
+			name: "Default configuration",
 			cfg: map[string]interface{}{
 				"cluster_agent.collect_kubernetes_tags": false,
 				"language_detection.reporting.enabled":  false,
 				"language_detection.enabled":            false,
 			},
-			expectedStoresGenerator: []storeGenerator{},
+			expectedStoresGenerator: []storeGenerator{newJobStore, newCronJobStore, newStatefulSetStore, newDaemonSetStore},
 		},
 		{
 			name: "All configurations disabled",
 			cfg: map[string]interface{}{
-				"cluster_agent.collect_kubernetes_tags": false,
-				"language_detection.reporting.enabled":  false,
-				"language_detection.enabled":            true,
+				"cluster_agent.collect_kubernetes_tags":                           false,
+				"cluster_agent.kubernetes_resources_collection.workloads_enabled": false,
+				"language_detection.reporting.enabled":                            false,
+				"language_detection.enabled":                                      true,
 			},
 			expectedStoresGenerator: []storeGenerator{},
 		},
@@ -54,23 +65,25 @@ func TestStoreGenerators(t *testing.T) {
 				"language_detection.reporting.enabled":  false,
 				"language_detection.enabled":            true,
 			},
-			expectedStoresGenerator: []storeGenerator{newPodStore},
+			expectedStoresGenerator: []storeGenerator{newPodStore, newJobStore, newCronJobStore, newStatefulSetStore, newDaemonSetStore},
 		},
 		{
 			name: "Language detection enabled",
 			cfg: map[string]interface{}{
-				"cluster_agent.collect_kubernetes_tags": false,
-				"language_detection.reporting.enabled":  true,
-				"language_detection.enabled":            true,
+				"cluster_agent.collect_kubernetes_tags":                           false,
+				"cluster_agent.kubernetes_resources_collection.workloads_enabled": false,
+				"language_detection.reporting.enabled":                            true,
+				"language_detection.enabled":                                      true,
 			},
 			expectedStoresGenerator: []storeGenerator{newDeploymentStore},
 		},
 		{
 			name: "Language detection enabled",
 			cfg: map[string]interface{}{
-				"cluster_agent.collect_kubernetes_tags": false,
-				"language_detection.reporting.enabled":  true,
-				"language_detection.enabled":            false,
+				"cluster_agent.collect_kubernetes_tags":                           false,
+				"cluster_agent.kubernetes_resources_collection.workloads_enabled": false,
+				"language_detection.reporting.enabled":                            true,
+				"language_detection.enabled":                                      false,
 			},
 			expectedStoresGenerator: []storeGenerator{},
 		},
@@ -81,7 +94,7 @@ func TestStoreGenerators(t *testing.T) {
 				"language_detection.reporting.enabled":  true,
 				"language_detection.enabled":            true,
 			},
-			expectedStoresGenerator: []storeGenerator{newPodStore, newDeploymentStore},
+			expectedStoresGenerator: []storeGenerator{newPodStore, newDeploymentStore, newJobStore, newCronJobStore, newStatefulSetStore, newDaemonSetStore},
 		},
 	}
 
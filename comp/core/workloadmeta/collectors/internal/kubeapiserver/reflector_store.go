@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -150,6 +151,14 @@ func (r *reflectorStore) Delete(obj interface{}) error {
 		uid = v.UID
 	case *appsv1.Deployment:
 		uid = v.UID
+	case *appsv1.StatefulSet:
+		uid = v.UID
+	case *appsv1.DaemonSet:
+		uid = v.UID
+	case *batchv1.Job:
+		uid = v.UID
+	case *batchv1.CronJob:
+		uid = v.UID
 	case *metav1.PartialObjectMetadata:
 		uid = v.UID
 	default:
@@ -225,6 +234,26 @@ func entityFromEntityID(entityID workloadmeta.EntityID) (workloadmeta.Entity, er
 		return &workloadmeta.KubernetesMetadata{
 			EntityID: entityID,
 		}, nil
+
+	case workloadmeta.KindKubernetesJob:
+		return &workloadmeta.KubernetesJob{
+			EntityID: entityID,
+		}, nil
+
+	case workloadmeta.KindKubernetesCronJob:
+		return &workloadmeta.KubernetesCronJob{
+			EntityID: entityID,
+		}, nil
+
+	case workloadmeta.KindKubernetesStatefulSet:
+		return &workloadmeta.KubernetesStatefulSet{
+			EntityID: entityID,
+		}, nil
+
+	case workloadmeta.KindKubernetesDaemonSet:
+		return &workloadmeta.KubernetesDaemonSet{
+			EntityID: entityID,
+		}, nil
 	}
 
 	return nil, fmt.Errorf("unsupported entity kind: %s", entityID.Kind)
@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubeapiserver
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	kubernetesresourceparsers "github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/util/kubernetes_resource_parsers"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Jobs, CronJobs, StatefulSets and DaemonSets are collected to resolve the ownership of
+// the pods collected by the Cluster Agent and to let cluster checks target them. They
+// don't depend on cluster_agent.collect_kubernetes_tags, as they are needed by the
+// autodiscovery listener even when pods aren't collected.
+func shouldHaveWorkloadStores(cfg config.Reader) bool {
+	return cfg.GetBool("cluster_agent.kubernetes_resources_collection.workloads_enabled")
+}
+
+func newJobStore(ctx context.Context, wlm workloadmeta.Component, cfg config.Reader, client kubernetes.Interface) (*cache.Reflector, *reflectorStore) {
+	jobListerWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return client.BatchV1().Jobs(metav1.NamespaceAll).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return client.BatchV1().Jobs(metav1.NamespaceAll).Watch(ctx, options)
+		},
+	}
+
+	jobStore := newWorkloadReflectorStore(wlm, cfg, kubernetesresourceparsers.NewJobParser)
+	jobReflector := cache.NewNamedReflector(
+		componentName,
+		jobListerWatcher,
+		&batchv1.Job{},
+		jobStore,
+		noResync,
+	)
+	log.Debug("job reflector enabled")
+	return jobReflector, jobStore
+}
+
+func newCronJobStore(ctx context.Context, wlm workloadmeta.Component, cfg config.Reader, client kubernetes.Interface) (*cache.Reflector, *reflectorStore) {
+	cronJobListerWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return client.BatchV1().CronJobs(metav1.NamespaceAll).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return client.BatchV1().CronJobs(metav1.NamespaceAll).Watch(ctx, options)
+		},
+	}
+
+	cronJobStore := newWorkloadReflectorStore(wlm, cfg, kubernetesresourceparsers.NewCronJobParser)
+	cronJobReflector := cache.NewNamedReflector(
+		componentName,
+		cronJobListerWatcher,
+		&batchv1.CronJob{},
+		cronJobStore,
+		noResync,
+	)
+	log.Debug("cronjob reflector enabled")
+	return cronJobReflector, cronJobStore
+}
+
+func newStatefulSetStore(ctx context.Context, wlm workloadmeta.Component, cfg config.Reader, client kubernetes.Interface) (*cache.Reflector, *reflectorStore) {
+	statefulSetListerWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return client.AppsV1().StatefulSets(metav1.NamespaceAll).Watch(ctx, options)
+		},
+	}
+
+	statefulSetStore := newWorkloadReflectorStore(wlm, cfg, kubernetesresourceparsers.NewStatefulSetParser)
+	statefulSetReflector := cache.NewNamedReflector(
+		componentName,
+		statefulSetListerWatcher,
+		&appsv1.StatefulSet{},
+		statefulSetStore,
+		noResync,
+	)
+	log.Debug("statefulset reflector enabled")
+	return statefulSetReflector, statefulSetStore
+}
+
+func newDaemonSetStore(ctx context.Context, wlm workloadmeta.Component, cfg config.Reader, client kubernetes.Interface) (*cache.Reflector, *reflectorStore) {
+	daemonSetListerWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return client.AppsV1().DaemonSets(metav1.NamespaceAll).Watch(ctx, options)
+		},
+	}
+
+	daemonSetStore := newWorkloadReflectorStore(wlm, cfg, kubernetesresourceparsers.NewDaemonSetParser)
+	daemonSetReflector := cache.NewNamedReflector(
+		componentName,
+		daemonSetListerWatcher,
+		&appsv1.DaemonSet{},
+		daemonSetStore,
+		noResync,
+	)
+	log.Debug("daemonset reflector enabled")
+	return daemonSetReflector, daemonSetStore
+}
+
+func newWorkloadReflectorStore(wlmetaStore workloadmeta.Component, cfg config.Reader, newParser func([]string) (kubernetesresourceparsers.ObjectParser, error)) *reflectorStore {
+	annotationsExclude := cfg.GetStringSlice("cluster_agent.kubernetes_resources_collection.workload_annotations_exclude")
+	parser, err := newParser(annotationsExclude)
+	if err != nil {
+		_ = log.Errorf("unable to parse all workload_annotations_exclude: %v, err:", err)
+		parser, _ = newParser(nil)
+	}
+
+	return &reflectorStore{
+		wlmetaStore: wlmetaStore,
+		seen:        make(map[string]workloadmeta.EntityID),
+		parser:      parser,
+	}
+}
@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubernetesresourceparsers
+
+import (
+	"regexp"
+
+	batchv1 "k8s.io/api/batch/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+type cronJobParser struct {
+	annotationsFilter []*regexp.Regexp
+}
+
+// NewCronJobParser initialises and returns a cronjob parser
+func NewCronJobParser(annotationsExclude []string) (ObjectParser, error) {
+	filters, err := parseFilters(annotationsExclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return cronJobParser{annotationsFilter: filters}, nil
+}
+
+func (p cronJobParser) Parse(obj interface{}) workloadmeta.Entity {
+	cronJob := obj.(*batchv1.CronJob)
+
+	entity := &workloadmeta.KubernetesCronJob{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesCronJob,
+			ID:   cronJob.Namespace + "/" + cronJob.Name,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:        cronJob.Name,
+			Namespace:   cronJob.Namespace,
+			Labels:      cronJob.Labels,
+			Annotations: filterMapStringKey(cronJob.Annotations, p.annotationsFilter),
+		},
+		Schedule: cronJob.Spec.Schedule,
+		Suspend:  cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+	}
+
+	if cronJob.Status.LastScheduleTime != nil {
+		entity.LastScheduleTime = cronJob.Status.LastScheduleTime.Time
+	}
+
+	return entity
+}
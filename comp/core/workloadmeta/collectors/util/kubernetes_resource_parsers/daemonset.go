@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubernetesresourceparsers
+
+import (
+	"regexp"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+type daemonSetParser struct {
+	annotationsFilter []*regexp.Regexp
+}
+
+// NewDaemonSetParser initialises and returns a daemonset parser
+func NewDaemonSetParser(annotationsExclude []string) (ObjectParser, error) {
+	filters, err := parseFilters(annotationsExclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return daemonSetParser{annotationsFilter: filters}, nil
+}
+
+func (p daemonSetParser) Parse(obj interface{}) workloadmeta.Entity {
+	daemonSet := obj.(*appsv1.DaemonSet)
+
+	return &workloadmeta.KubernetesDaemonSet{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesDaemonSet,
+			ID:   daemonSet.Namespace + "/" + daemonSet.Name,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:        daemonSet.Name,
+			Namespace:   daemonSet.Namespace,
+			Labels:      daemonSet.Labels,
+			Annotations: filterMapStringKey(daemonSet.Annotations, p.annotationsFilter),
+		},
+		DesiredNumberScheduled: daemonSet.Status.DesiredNumberScheduled,
+		UpdatedNumberScheduled: daemonSet.Status.UpdatedNumberScheduled,
+	}
+}
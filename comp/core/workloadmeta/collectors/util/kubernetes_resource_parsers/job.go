@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubernetesresourceparsers
+
+import (
+	"regexp"
+
+	batchv1 "k8s.io/api/batch/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+type jobParser struct {
+	annotationsFilter []*regexp.Regexp
+}
+
+// NewJobParser initialises and returns a job parser
+func NewJobParser(annotationsExclude []string) (ObjectParser, error) {
+	filters, err := parseFilters(annotationsExclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobParser{annotationsFilter: filters}, nil
+}
+
+func (p jobParser) Parse(obj interface{}) workloadmeta.Entity {
+	job := obj.(*batchv1.Job)
+	owners := make([]workloadmeta.KubernetesPodOwner, 0, len(job.OwnerReferences))
+	for _, o := range job.OwnerReferences {
+		owners = append(owners, workloadmeta.KubernetesPodOwner{
+			Kind: o.Kind,
+			Name: o.Name,
+			ID:   string(o.UID),
+		})
+	}
+
+	entity := &workloadmeta.KubernetesJob{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesJob,
+			ID:   job.Namespace + "/" + job.Name,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:        job.Name,
+			Namespace:   job.Namespace,
+			Labels:      job.Labels,
+			Annotations: filterMapStringKey(job.Annotations, p.annotationsFilter),
+		},
+		Owners:    owners,
+		Active:    job.Status.Active,
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+	}
+
+	if job.Status.StartTime != nil {
+		entity.StartTime = job.Status.StartTime.Time
+	}
+	if job.Status.CompletionTime != nil {
+		entity.CompletionTime = job.Status.CompletionTime.Time
+	}
+
+	return entity
+}
@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && test
+
+package kubernetesresourceparsers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+func TestJobParser_Parse(t *testing.T) {
+	startTime := time.Date(2024, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	parser, err := NewJobParser([]string{"ignore-annotation"})
+	require.NoError(t, err)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "export-28761230",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "export"},
+			Annotations: map[string]string{
+				"ignore-annotation": "ignore",
+				"team":              "data",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CronJob", Name: "export", UID: types.UID("cronjob-uid")},
+			},
+		},
+		Status: batchv1.JobStatus{
+			StartTime: &metav1.Time{Time: startTime},
+			Active:    1,
+		},
+	}
+
+	expected := &workloadmeta.KubernetesJob{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesJob,
+			ID:   "default/export-28761230",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:        "export-28761230",
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "export"},
+			Annotations: map[string]string{"team": "data"},
+		},
+		Owners: []workloadmeta.KubernetesPodOwner{
+			{Kind: "CronJob", Name: "export", ID: "cronjob-uid"},
+		},
+		StartTime: startTime,
+		Active:    1,
+	}
+
+	entity := parser.Parse(job)
+	assert.Equal(t, expected, entity)
+	assert.Equal(t, "export", entity.(*workloadmeta.KubernetesJob).CronJobOwner())
+}
@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package kubernetesresourceparsers
+
+import (
+	"regexp"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+type statefulSetParser struct {
+	annotationsFilter []*regexp.Regexp
+}
+
+// NewStatefulSetParser initialises and returns a statefulset parser
+func NewStatefulSetParser(annotationsExclude []string) (ObjectParser, error) {
+	filters, err := parseFilters(annotationsExclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return statefulSetParser{annotationsFilter: filters}, nil
+}
+
+func (p statefulSetParser) Parse(obj interface{}) workloadmeta.Entity {
+	statefulSet := obj.(*appsv1.StatefulSet)
+
+	var replicas int32
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	return &workloadmeta.KubernetesStatefulSet{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindKubernetesStatefulSet,
+			ID:   statefulSet.Namespace + "/" + statefulSet.Name,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:        statefulSet.Name,
+			Namespace:   statefulSet.Namespace,
+			Labels:      statefulSet.Labels,
+			Annotations: filterMapStringKey(statefulSet.Annotations, p.annotationsFilter),
+		},
+		Replicas:        replicas,
+		CurrentRevision: statefulSet.Status.CurrentRevision,
+		UpdateRevision:  statefulSet.Status.UpdateRevision,
+	}
+}
@@ -64,6 +64,10 @@ type Component interface {
 	// the entity with kind KindKubernetesDeployment and the given ID.
 	GetKubernetesDeployment(id string) (*KubernetesDeployment, error)
 
+	// GetKubernetesJob returns metadata about a Kubernetes job. It fetches
+	// the entity with kind KindKubernetesJob and the given ID.
+	GetKubernetesJob(id string) (*KubernetesJob, error)
+
 	// GetKubernetesMetadata returns metadata about a Kubernetes resource. It fetches
 	// the entity with kind KubernetesMetadata and the given ID.
 	GetKubernetesMetadata(id KubeMetadataEntityID) (*KubernetesMetadata, error)
@@ -44,6 +44,10 @@ const (
 	KindKubernetesPod          Kind = "kubernetes_pod"
 	KindKubernetesMetadata     Kind = "kubernetes_metadata"
 	KindKubernetesDeployment   Kind = "kubernetes_deployment"
+	KindKubernetesJob          Kind = "kubernetes_job"
+	KindKubernetesCronJob      Kind = "kubernetes_cronjob"
+	KindKubernetesStatefulSet  Kind = "kubernetes_statefulset"
+	KindKubernetesDaemonSet    Kind = "kubernetes_daemonset"
 	KindECSTask                Kind = "ecs_task"
 	KindContainerImageMetadata Kind = "container_image_metadata"
 	KindProcess                Kind = "process"
@@ -931,6 +935,224 @@ func (d KubernetesDeployment) String(verbose bool) string {
 
 var _ Entity = &KubernetesDeployment{}
 
+// KubernetesJob is an Entity representing a Kubernetes Job.
+type KubernetesJob struct {
+	EntityID
+	EntityMeta
+
+	// Owners is extracted from the job's owner references, typically a CronJob
+	Owners []KubernetesPodOwner
+
+	StartTime      time.Time
+	CompletionTime time.Time
+	Active         int32
+	Succeeded      int32
+	Failed         int32
+}
+
+// GetID implements Entity#GetID.
+func (j *KubernetesJob) GetID() EntityID {
+	return j.EntityID
+}
+
+// Merge implements Entity#Merge.
+func (j *KubernetesJob) Merge(e Entity) error {
+	jj, ok := e.(*KubernetesJob)
+	if !ok {
+		return fmt.Errorf("cannot merge KubernetesJob with different kind %T", e)
+	}
+
+	return merge(j, jj)
+}
+
+// DeepCopy implements Entity#DeepCopy.
+func (j KubernetesJob) DeepCopy() Entity {
+	cj := deepcopy.Copy(j).(KubernetesJob)
+	return &cj
+}
+
+// String implements Entity#String
+func (j KubernetesJob) String(verbose bool) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintln(&sb, "----------- Entity ID -----------")
+	_, _ = fmt.Fprintln(&sb, j.EntityID.String(verbose))
+	_, _ = fmt.Fprintln(&sb, "----------- Entity Meta -----------")
+	_, _ = fmt.Fprint(&sb, j.EntityMeta.String(verbose))
+
+	if len(j.Owners) > 0 {
+		_, _ = fmt.Fprintln(&sb, "----------- Owners -----------")
+		for _, o := range j.Owners {
+			_, _ = fmt.Fprint(&sb, o.String(verbose))
+		}
+	}
+
+	_, _ = fmt.Fprintln(&sb, "----------- Job Info -----------")
+	_, _ = fmt.Fprintln(&sb, "Active:", j.Active, "Succeeded:", j.Succeeded, "Failed:", j.Failed)
+	if verbose {
+		_, _ = fmt.Fprintln(&sb, "Start Time:", j.StartTime)
+		_, _ = fmt.Fprintln(&sb, "Completion Time:", j.CompletionTime)
+	}
+
+	return sb.String()
+}
+
+// CronJobOwner returns the name of the CronJob owning the job, if any.
+func (j *KubernetesJob) CronJobOwner() string {
+	for _, owner := range j.Owners {
+		if owner.Kind == "CronJob" {
+			return owner.Name
+		}
+	}
+
+	return ""
+}
+
+var _ Entity = &KubernetesJob{}
+
+// KubernetesCronJob is an Entity representing a Kubernetes CronJob.
+type KubernetesCronJob struct {
+	EntityID
+	EntityMeta
+
+	Schedule         string
+	Suspend          bool
+	LastScheduleTime time.Time
+}
+
+// GetID implements Entity#GetID.
+func (c *KubernetesCronJob) GetID() EntityID {
+	return c.EntityID
+}
+
+// Merge implements Entity#Merge.
+func (c *KubernetesCronJob) Merge(e Entity) error {
+	cc, ok := e.(*KubernetesCronJob)
+	if !ok {
+		return fmt.Errorf("cannot merge KubernetesCronJob with different kind %T", e)
+	}
+
+	return merge(c, cc)
+}
+
+// DeepCopy implements Entity#DeepCopy.
+func (c KubernetesCronJob) DeepCopy() Entity {
+	cc := deepcopy.Copy(c).(KubernetesCronJob)
+	return &cc
+}
+
+// String implements Entity#String
+func (c KubernetesCronJob) String(verbose bool) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintln(&sb, "----------- Entity ID -----------")
+	_, _ = fmt.Fprintln(&sb, c.EntityID.String(verbose))
+	_, _ = fmt.Fprintln(&sb, "----------- Entity Meta -----------")
+	_, _ = fmt.Fprint(&sb, c.EntityMeta.String(verbose))
+	_, _ = fmt.Fprintln(&sb, "----------- CronJob Info -----------")
+	_, _ = fmt.Fprintln(&sb, "Schedule:", c.Schedule)
+	_, _ = fmt.Fprintln(&sb, "Suspend:", c.Suspend)
+	if verbose {
+		_, _ = fmt.Fprintln(&sb, "Last Schedule Time:", c.LastScheduleTime)
+	}
+
+	return sb.String()
+}
+
+var _ Entity = &KubernetesCronJob{}
+
+// KubernetesStatefulSet is an Entity representing a Kubernetes StatefulSet.
+type KubernetesStatefulSet struct {
+	EntityID
+	EntityMeta
+
+	Replicas        int32
+	CurrentRevision string
+	UpdateRevision  string
+}
+
+// GetID implements Entity#GetID.
+func (s *KubernetesStatefulSet) GetID() EntityID {
+	return s.EntityID
+}
+
+// Merge implements Entity#Merge.
+func (s *KubernetesStatefulSet) Merge(e Entity) error {
+	ss, ok := e.(*KubernetesStatefulSet)
+	if !ok {
+		return fmt.Errorf("cannot merge KubernetesStatefulSet with different kind %T", e)
+	}
+
+	return merge(s, ss)
+}
+
+// DeepCopy implements Entity#DeepCopy.
+func (s KubernetesStatefulSet) DeepCopy() Entity {
+	cs := deepcopy.Copy(s).(KubernetesStatefulSet)
+	return &cs
+}
+
+// String implements Entity#String
+func (s KubernetesStatefulSet) String(verbose bool) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintln(&sb, "----------- Entity ID -----------")
+	_, _ = fmt.Fprintln(&sb, s.EntityID.String(verbose))
+	_, _ = fmt.Fprintln(&sb, "----------- Entity Meta -----------")
+	_, _ = fmt.Fprint(&sb, s.EntityMeta.String(verbose))
+	_, _ = fmt.Fprintln(&sb, "----------- StatefulSet Info -----------")
+	_, _ = fmt.Fprintln(&sb, "Replicas:", s.Replicas)
+	_, _ = fmt.Fprintln(&sb, "Current Revision:", s.CurrentRevision)
+	_, _ = fmt.Fprintln(&sb, "Update Revision:", s.UpdateRevision)
+
+	return sb.String()
+}
+
+var _ Entity = &KubernetesStatefulSet{}
+
+// KubernetesDaemonSet is an Entity representing a Kubernetes DaemonSet.
+type KubernetesDaemonSet struct {
+	EntityID
+	EntityMeta
+
+	DesiredNumberScheduled int32
+	UpdatedNumberScheduled int32
+}
+
+// GetID implements Entity#GetID.
+func (d *KubernetesDaemonSet) GetID() EntityID {
+	return d.EntityID
+}
+
+// Merge implements Entity#Merge.
+func (d *KubernetesDaemonSet) Merge(e Entity) error {
+	dd, ok := e.(*KubernetesDaemonSet)
+	if !ok {
+		return fmt.Errorf("cannot merge KubernetesDaemonSet with different kind %T", e)
+	}
+
+	return merge(d, dd)
+}
+
+// DeepCopy implements Entity#DeepCopy.
+func (d KubernetesDaemonSet) DeepCopy() Entity {
+	cd := deepcopy.Copy(d).(KubernetesDaemonSet)
+	return &cd
+}
+
+// String implements Entity#String
+func (d KubernetesDaemonSet) String(verbose bool) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintln(&sb, "----------- Entity ID -----------")
+	_, _ = fmt.Fprintln(&sb, d.EntityID.String(verbose))
+	_, _ = fmt.Fprintln(&sb, "----------- Entity Meta -----------")
+	_, _ = fmt.Fprint(&sb, d.EntityMeta.String(verbose))
+	_, _ = fmt.Fprintln(&sb, "----------- DaemonSet Info -----------")
+	_, _ = fmt.Fprintln(&sb, "Desired Number Scheduled:", d.DesiredNumberScheduled)
+	_, _ = fmt.Fprintln(&sb, "Updated Number Scheduled:", d.UpdatedNumberScheduled)
+
+	return sb.String()
+}
+
+var _ Entity = &KubernetesDaemonSet{}
+
 // ECSTaskKnownStatusStopped is the known status of an ECS task that has stopped.
 const ECSTaskKnownStatusStopped = "STOPPED"
 
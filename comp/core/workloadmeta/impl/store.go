@@ -329,6 +329,16 @@ func (w *workloadmeta) GetKubernetesDeployment(id string) (*wmdef.KubernetesDepl
 	return entity.(*wmdef.KubernetesDeployment), nil
 }
 
+// GetKubernetesJob implements Store#GetKubernetesJob
+func (w *workloadmeta) GetKubernetesJob(id string) (*wmdef.KubernetesJob, error) {
+	entity, err := w.getEntityByKind(wmdef.KindKubernetesJob, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.(*wmdef.KubernetesJob), nil
+}
+
 // ListECSTasks implements Store#ListECSTasks
 func (w *workloadmeta) ListECSTasks() []*wmdef.ECSTask {
 	entities := w.listEntitiesByKind(wmdef.KindECSTask)
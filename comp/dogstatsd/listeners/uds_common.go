@@ -237,6 +237,7 @@ func (l *UDSListener) handleConnection(conn *net.UnixConn, closeFunc CloseFuncti
 			capBuff.Pb.AncillarySize = int32(0)
 			capBuff.Pb.PayloadSize = int32(0)
 			capBuff.ContainerID = ""
+			capBuff.Transport = l.transport
 		}
 
 		if l.OriginDetection {
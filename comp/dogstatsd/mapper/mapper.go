@@ -75,6 +75,42 @@ type MapResult struct {
 
 // NewMetricMapper creates, validates, prepares a new MetricMapper
 func NewMetricMapper(configProfiles []MappingProfileConfig, cacheSize int) (*MetricMapper, error) {
+	profiles, err := buildProfiles(configProfiles)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := newMapperCache(cacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricMapper{Profiles: profiles, cache: cache}, nil
+}
+
+// ValidateProfiles returns an error if one of the profiles is misconfigured
+func ValidateProfiles(configProfiles []MappingProfileConfig) error {
+	_, err := buildProfiles(configProfiles)
+	return err
+}
+
+// MergeProfiles returns the profiles to use when remote profiles are set on top of the
+// local ones: remote profiles are evaluated first, and replace the local profiles with
+// the same name.
+func MergeProfiles(local []MappingProfileConfig, remote []MappingProfileConfig) []MappingProfileConfig {
+	merged := make([]MappingProfileConfig, 0, len(local)+len(remote))
+	remoteNames := make(map[string]struct{}, len(remote))
+	for _, profile := range remote {
+		remoteNames[profile.Name] = struct{}{}
+		merged = append(merged, profile)
+	}
+	for _, profile := range local {
+		if _, found := remoteNames[profile.Name]; !found {
+			merged = append(merged, profile)
+		}
+	}
+	return merged
+}
+
+func buildProfiles(configProfiles []MappingProfileConfig) ([]MappingProfile, error) {
 	profiles := make([]MappingProfile, 0, len(configProfiles))
 	for profileIndex, configProfile := range configProfiles {
 		if configProfile.Name == "" {
@@ -110,11 +146,7 @@ func NewMetricMapper(configProfiles []MappingProfileConfig, cacheSize int) (*Met
 		}
 		profiles = append(profiles, profile)
 	}
-	cache, err := newMapperCache(cacheSize)
-	if err != nil {
-		return nil, err
-	}
-	return &MetricMapper{Profiles: profiles, cache: cache}, nil
+	return profiles, nil
 }
 
 func buildRegex(matchRe string, matchType string) (*regexp.Regexp, error) {
@@ -530,3 +530,28 @@ func getMapper(t *testing.T, configString string) (*MetricMapper, error) {
 	}
 	return mapper, err
 }
+
+func TestMergeProfiles(t *testing.T) {
+	local := []MappingProfileConfig{
+		{Name: "airflow", Prefix: "airflow."},
+		{Name: "test", Prefix: "test."},
+	}
+	remote := []MappingProfileConfig{
+		{Name: "test", Prefix: "test.job."},
+		{Name: "remote", Prefix: "remote."},
+	}
+
+	assert.Equal(t, []MappingProfileConfig{
+		{Name: "test", Prefix: "test.job."},
+		{Name: "remote", Prefix: "remote."},
+		{Name: "airflow", Prefix: "airflow."},
+	}, MergeProfiles(local, remote))
+	assert.Equal(t, local, MergeProfiles(local, nil))
+}
+
+func TestValidateProfiles(t *testing.T) {
+	assert.NoError(t, ValidateProfiles([]MappingProfileConfig{
+		{Name: "test", Prefix: "test.", Mappings: []MetricMappingConfig{{Match: "test.*", Name: "test"}}},
+	}))
+	assert.EqualError(t, ValidateProfiles([]MappingProfileConfig{{Name: "test"}}), "missing prefix for profile: test")
+}
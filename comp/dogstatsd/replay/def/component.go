@@ -21,7 +21,7 @@ type Component interface {
 	IsOngoing() bool
 
 	// StartCapture starts a TrafficCapture and returns an error in the event of an issue.
-	StartCapture(p string, d time.Duration, compressed bool) (string, error)
+	StartCapture(p string, d time.Duration, compressed bool, opts CaptureOptions) (string, error)
 
 	// StopCapture stops an ongoing TrafficCapture.
 	StopCapture()
@@ -52,6 +52,27 @@ type UnixDogstatsdMsg struct {
 	Ancillary     []byte
 }
 
+// CaptureFilter restricts a capture to the traffic matching all of its non-empty fields.
+type CaptureFilter struct {
+	// MetricNameRegex only keeps the metrics whose name matches the regular expression.
+	MetricNameRegex string
+	// Tags only keeps the metrics carrying all of these tags.
+	Tags []string
+	// ContainerID only keeps the traffic sent from this container.
+	ContainerID string
+	// Pid only keeps the traffic sent by this process.
+	Pid int32
+	// Transport only keeps the traffic received on this transport (unix, unixgram or unixpacket).
+	Transport string
+}
+
+// CaptureOptions holds the optional settings of a capture.
+type CaptureOptions struct {
+	Filter CaptureFilter
+	// MaxSize stops the capture once this many bytes of traffic were written, 0 means no limit.
+	MaxSize int64
+}
+
 // CaptureBuffer holds pointers to captured packet's buffers (and oob buffer if required) and the protobuf
 // message used for serialization.
 type CaptureBuffer struct {
@@ -59,6 +80,7 @@ type CaptureBuffer struct {
 	Oob         *[]byte
 	Pid         int32
 	ContainerID string
+	Transport   string
 	Buff        *packets.Packet
 }
 
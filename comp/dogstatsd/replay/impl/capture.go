@@ -72,17 +72,24 @@ func (tc *trafficCapture) IsOngoing() bool {
 }
 
 // StartCapture starts a TrafficCapture and returns an error in the event of an issue.
-func (tc *trafficCapture) StartCapture(p string, d time.Duration, compressed bool) (string, error) {
+func (tc *trafficCapture) StartCapture(p string, d time.Duration, compressed bool, opts replay.CaptureOptions) (string, error) {
 	if tc.IsOngoing() {
 		return "", fmt.Errorf("Ongoing capture in progress")
 	}
 
+	if _, err := newMessageFilter(opts.Filter); err != nil {
+		return "", err
+	}
+	if opts.MaxSize < 0 {
+		return "", fmt.Errorf("invalid maximum capture size: %d", opts.MaxSize)
+	}
+
 	target, path, err := OpenFile(afero.NewOsFs(), p, tc.defaultlocation())
 	if err != nil {
 		return "", err
 	}
 
-	go tc.writer.Capture(target, d, compressed)
+	go tc.writer.Capture(target, d, compressed, opts)
 
 	return path, nil
 }
@@ -0,0 +1,220 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package replayimpl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
+)
+
+const (
+	// pcapng block types
+	pcapngSectionHeaderBlock   = 0x0A0D0D0A
+	pcapngInterfaceDescBlock   = 0x00000001
+	pcapngEnhancedPacketBlock  = 0x00000006
+	pcapngByteOrderMagic       = 0x1A2B3C4D
+	pcapngOptionEnd            = 0
+	pcapngOptionComment        = 1
+	pcapngOptionTimestampResol = 9
+	pcapngLinkTypeRaw          = 101
+	pcapngTimestampResolNanos  = 9
+
+	// the payloads are exported as UDP datagrams sent to the default dogstatsd port on localhost
+	pcapngSourcePort      = 49152
+	pcapngDestinationPort = 8125
+	ipv4HeaderLength      = 20
+	udpHeaderLength       = 8
+	maxUDPPayloadLength   = 65535 - ipv4HeaderLength - udpHeaderLength
+)
+
+// exportedMessage is the JSON representation of a captured message.
+type exportedMessage struct {
+	Timestamp   int64    `json:"timestamp"`
+	Pid         int32    `json:"pid,omitempty"`
+	ContainerID string   `json:"container_id,omitempty"`
+	PayloadSize int32    `json:"payload_size"`
+	Payload     []string `json:"payload"`
+}
+
+// forEachMessage calls fn with every message of the capture, from the first one. It returns the
+// number of messages read.
+func (tc *TrafficCaptureReader) forEachMessage(fn func(msg *pb.UnixDogstatsdMsg, containerID string) error) (int, error) {
+	// captures without a state don't map PIDs to containers
+	pidMap, _, _ := tc.ReadState()
+
+	tc.Seek(0)
+
+	cnt := 0
+	for {
+		msg, err := tc.ReadNext()
+		if err == io.EOF {
+			return cnt, nil
+		} else if err != nil {
+			return cnt, err
+		}
+
+		if err := fn(msg, pidMap[msg.Pid]); err != nil {
+			return cnt, err
+		}
+		cnt++
+	}
+}
+
+// ExportJSONL writes the messages of the capture to w as JSON lines, with their timestamp in
+// nanoseconds and their payload split in lines. It returns the number of messages exported.
+func ExportJSONL(tc *TrafficCaptureReader, w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+
+	cnt, err := tc.forEachMessage(func(msg *pb.UnixDogstatsdMsg, containerID string) error {
+		payload := strings.TrimRight(string(msg.Payload[:msg.PayloadSize]), "\n")
+		return encoder.Encode(exportedMessage{
+			Timestamp:   tc.timestamp(msg),
+			Pid:         msg.Pid,
+			ContainerID: containerID,
+			PayloadSize: msg.PayloadSize,
+			Payload:     strings.Split(payload, "\n"),
+		})
+	})
+	if err != nil {
+		return cnt, err
+	}
+
+	return cnt, bw.Flush()
+}
+
+// ExportPcapng writes the messages of the capture to w in the pcapng format, so they can be
+// analyzed with standard tools. Each message is exported as an IPv4 UDP datagram sent to
+// 127.0.0.1:8125, with its PID and container ID in the packet comment. It returns the number of
+// messages exported.
+func ExportPcapng(tc *TrafficCaptureReader, w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.Write(pcapngHeader()); err != nil {
+		return 0, err
+	}
+
+	cnt, err := tc.forEachMessage(func(msg *pb.UnixDogstatsdMsg, containerID string) error {
+		comment := fmt.Sprintf("pid=%d", msg.Pid)
+		if containerID != "" {
+			comment += " container_id=" + containerID
+		}
+		_, err := bw.Write(pcapngPacket(tc.timestamp(msg), msg.Payload[:msg.PayloadSize], comment))
+		return err
+	})
+	if err != nil {
+		return cnt, err
+	}
+
+	return cnt, bw.Flush()
+}
+
+// pcapngBlock returns a pcapng block of the given type and body, padded to 32 bits.
+func pcapngBlock(blockType uint32, body []byte) []byte {
+	length := 12 + len(body) + padding(len(body))
+	block := make([]byte, length)
+	binary.LittleEndian.PutUint32(block[0:], blockType)
+	binary.LittleEndian.PutUint32(block[4:], uint32(length))
+	copy(block[8:], body)
+	binary.LittleEndian.PutUint32(block[length-4:], uint32(length))
+	return block
+}
+
+// pcapngOption returns a pcapng option, padded to 32 bits.
+func pcapngOption(code uint16, value []byte) []byte {
+	option := make([]byte, 4+len(value)+padding(len(value)))
+	binary.LittleEndian.PutUint16(option[0:], code)
+	binary.LittleEndian.PutUint16(option[2:], uint16(len(value)))
+	copy(option[4:], value)
+	return option
+}
+
+// pcapngHeader returns the section header block and the description of the single interface the
+// packets are captured on.
+func pcapngHeader() []byte {
+	shb := make([]byte, 16)
+	binary.LittleEndian.PutUint32(shb[0:], pcapngByteOrderMagic)
+	binary.LittleEndian.PutUint16(shb[4:], 1) // major version
+	binary.LittleEndian.PutUint16(shb[6:], 0) // minor version
+	binary.LittleEndian.PutUint64(shb[8:], ^uint64(0))
+
+	idb := make([]byte, 8)
+	binary.LittleEndian.PutUint16(idb[0:], pcapngLinkTypeRaw)
+	idb = append(idb, pcapngOption(pcapngOptionTimestampResol, []byte{pcapngTimestampResolNanos})...)
+	idb = append(idb, pcapngOption(pcapngOptionEnd, nil)...)
+
+	return append(pcapngBlock(pcapngSectionHeaderBlock, shb), pcapngBlock(pcapngInterfaceDescBlock, idb)...)
+}
+
+// pcapngPacket returns an enhanced packet block holding the payload in an IPv4 UDP datagram.
+// Payloads larger than the maximum UDP payload are truncated.
+func pcapngPacket(timestamp int64, payload []byte, comment string) []byte {
+	if len(payload) > maxUDPPayloadLength {
+		payload = payload[:maxUDPPayloadLength]
+	}
+	packet := ipv4UDPPacket(payload)
+
+	epb := make([]byte, 20)
+	binary.LittleEndian.PutUint32(epb[0:], 0) // interface ID
+	binary.LittleEndian.PutUint32(epb[4:], uint32(uint64(timestamp)>>32))
+	binary.LittleEndian.PutUint32(epb[8:], uint32(timestamp))
+	binary.LittleEndian.PutUint32(epb[12:], uint32(len(packet)))
+	binary.LittleEndian.PutUint32(epb[16:], uint32(len(packet)))
+	epb = append(epb, packet...)
+	epb = append(epb, make([]byte, padding(len(packet)))...)
+	epb = append(epb, pcapngOption(pcapngOptionComment, []byte(comment))...)
+	epb = append(epb, pcapngOption(pcapngOptionEnd, nil)...)
+
+	return pcapngBlock(pcapngEnhancedPacketBlock, epb)
+}
+
+// ipv4UDPPacket returns a synthetic IPv4 UDP datagram sent to 127.0.0.1:8125 holding the payload.
+func ipv4UDPPacket(payload []byte) []byte {
+	length := ipv4HeaderLength + udpHeaderLength + len(payload)
+	packet := make([]byte, length)
+
+	// IPv4 header
+	packet[0] = 0x45 // version 4, 5 words header
+	binary.BigEndian.PutUint16(packet[2:], uint16(length))
+	binary.BigEndian.PutUint16(packet[6:], 0x4000) // don't fragment
+	packet[8] = 64                                 // TTL
+	packet[9] = 17                                 // UDP
+	copy(packet[12:], []byte{127, 0, 0, 1})
+	copy(packet[16:], []byte{127, 0, 0, 1})
+	binary.BigEndian.PutUint16(packet[10:], ipv4Checksum(packet[:ipv4HeaderLength]))
+
+	// UDP header, the checksum is optional over IPv4
+	udp := packet[ipv4HeaderLength:]
+	binary.BigEndian.PutUint16(udp[0:], pcapngSourcePort)
+	binary.BigEndian.PutUint16(udp[2:], pcapngDestinationPort)
+	binary.BigEndian.PutUint16(udp[4:], uint16(udpHeaderLength+len(payload)))
+	copy(udp[udpHeaderLength:], payload)
+
+	return packet
+}
+
+// ipv4Checksum returns the checksum of an IPv4 header.
+func ipv4Checksum(header []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(header); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(header[i:]))
+	}
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return ^uint16(sum)
+}
+
+// padding returns the number of bytes needed to pad length to 32 bits.
+func padding(length int) int {
+	return (4 - length%4) % 4
+}
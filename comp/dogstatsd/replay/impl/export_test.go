@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package replayimpl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportJSONL(t *testing.T) {
+	reader, err := NewTrafficCaptureReader("resources/test/datadog-capture.dog", 1, false)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	n, err := ExportJSONL(reader, &b)
+	require.NoError(t, err)
+	assert.Equal(t, 21, n)
+
+	scanner := bufio.NewScanner(&b)
+	lines := 0
+	for scanner.Scan() {
+		var msg exportedMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		assert.NotZero(t, msg.Timestamp)
+		assert.NotZero(t, msg.PayloadSize)
+		assert.NotEmpty(t, msg.Payload)
+		lines++
+	}
+	assert.Equal(t, n, lines)
+
+	// exporting again reads the capture from the start
+	b.Reset()
+	n, err = ExportJSONL(reader, &b)
+	require.NoError(t, err)
+	assert.Equal(t, 21, n)
+}
+
+func TestExportPcapng(t *testing.T) {
+	reader, err := NewTrafficCaptureReader("resources/test/datadog-capture.dog", 1, false)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	n, err := ExportPcapng(reader, &b)
+	require.NoError(t, err)
+	assert.Equal(t, 21, n)
+
+	data := b.Bytes()
+	var blockTypes []uint32
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 12)
+		blockType := binary.LittleEndian.Uint32(data[0:])
+		length := binary.LittleEndian.Uint32(data[4:])
+		require.Zero(t, length%4)
+		require.LessOrEqual(t, int(length), len(data))
+		assert.Equal(t, length, binary.LittleEndian.Uint32(data[length-4:]))
+
+		if blockType == pcapngEnhancedPacketBlock {
+			captured := binary.LittleEndian.Uint32(data[20:])
+			packet := data[28 : 28+captured]
+			assert.Equal(t, byte(0x45), packet[0])
+			assert.Equal(t, uint16(len(packet)), binary.BigEndian.Uint16(packet[2:]))
+			assert.Zero(t, ipv4Checksum(packet[:ipv4HeaderLength]))
+			assert.Equal(t, uint16(pcapngDestinationPort), binary.BigEndian.Uint16(packet[ipv4HeaderLength+2:]))
+		}
+
+		blockTypes = append(blockTypes, blockType)
+		data = data[length:]
+	}
+
+	require.Len(t, blockTypes, 2+n)
+	assert.Equal(t, uint32(pcapngSectionHeaderBlock), blockTypes[0])
+	assert.Equal(t, uint32(pcapngInterfaceDescBlock), blockTypes[1])
+	for _, blockType := range blockTypes[2:] {
+		assert.Equal(t, uint32(pcapngEnhancedPacketBlock), blockType)
+	}
+}
@@ -0,0 +1,160 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package replayimpl
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
+)
+
+var (
+	eventPrefix        = []byte("_e{")
+	serviceCheckPrefix = []byte("_sc|")
+)
+
+// messageFilter selects the captured traffic, see replay.CaptureFilter.
+type messageFilter struct {
+	metricName  *regexp.Regexp
+	tags        [][]byte
+	containerID string
+	pid         int32
+	transport   string
+}
+
+// newMessageFilter compiles a capture filter. It returns nil when the filter is empty.
+func newMessageFilter(f replay.CaptureFilter) (*messageFilter, error) {
+	if f.MetricNameRegex == "" && len(f.Tags) == 0 && f.ContainerID == "" && f.Pid == 0 && f.Transport == "" {
+		return nil, nil
+	}
+
+	switch f.Transport {
+	case "", "unix", "unixgram", "unixpacket":
+	default:
+		return nil, fmt.Errorf("unknown transport %q, expected unix, unixgram or unixpacket", f.Transport)
+	}
+
+	filter := &messageFilter{
+		containerID: f.ContainerID,
+		pid:         f.Pid,
+		transport:   f.Transport,
+	}
+
+	if f.MetricNameRegex != "" {
+		re, err := regexp.Compile(f.MetricNameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric name regex: %w", err)
+		}
+		filter.metricName = re
+	}
+
+	for _, tag := range f.Tags {
+		filter.tags = append(filter.tags, []byte(tag))
+	}
+
+	return filter, nil
+}
+
+// filterPayload returns the part of the message payload matching the filter, and false if nothing
+// in the message matches. The payload is filtered line by line when a metric name or tags are set.
+func (f *messageFilter) filterPayload(msg *replay.CaptureBuffer) ([]byte, bool) {
+	payload := msg.Pb.Payload
+	if f == nil {
+		return payload, true
+	}
+
+	if f.transport != "" && msg.Transport != f.transport {
+		return nil, false
+	}
+	if f.pid != 0 && msg.Pid != f.pid {
+		return nil, false
+	}
+	if f.containerID != "" && msg.ContainerID != f.containerID {
+		return nil, false
+	}
+	if f.metricName == nil && len(f.tags) == 0 {
+		return payload, true
+	}
+
+	var filtered []byte
+	dropped := false
+	for rest := payload; len(rest) > 0; {
+		var line []byte
+		line, rest, _ = bytes.Cut(rest, []byte{'\n'})
+		if len(line) == 0 {
+			continue
+		}
+		if !f.matchLine(line) {
+			dropped = true
+			continue
+		}
+		if len(filtered) > 0 {
+			filtered = append(filtered, '\n')
+		}
+		filtered = append(filtered, line...)
+	}
+
+	if len(filtered) == 0 {
+		return nil, false
+	}
+	if !dropped {
+		return payload, true
+	}
+	return filtered, true
+}
+
+// matchLine returns whether a single metric, event or service check line matches the
+// metric name and tags of the filter. Events never match a metric name filter.
+func (f *messageFilter) matchLine(line []byte) bool {
+	fields := bytes.Split(line, []byte{'|'})
+
+	if f.metricName != nil {
+		var name []byte
+		switch {
+		case bytes.HasPrefix(line, eventPrefix):
+			return false
+		case bytes.HasPrefix(line, serviceCheckPrefix):
+			if len(fields) < 2 {
+				return false
+			}
+			name = fields[1]
+		default:
+			name, _, _ = bytes.Cut(fields[0], []byte{':'})
+		}
+		if !f.metricName.Match(name) {
+			return false
+		}
+	}
+
+	if len(f.tags) == 0 {
+		return true
+	}
+
+	var tags [][]byte
+	for _, field := range fields[1:] {
+		if len(field) > 0 && field[0] == '#' {
+			tags = bytes.Split(field[1:], []byte{','})
+			break
+		}
+	}
+
+	for _, expected := range f.tags {
+		found := false
+		for _, tag := range tags {
+			if bytes.Equal(tag, expected) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
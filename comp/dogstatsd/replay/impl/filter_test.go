@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package replayimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
+)
+
+func newCaptureBuffer(payload string) *replay.CaptureBuffer {
+	buff := &replay.CaptureBuffer{Pid: 42, ContainerID: "abcdef", Transport: "unixgram"}
+	buff.Pb.Payload = []byte(payload)
+	buff.Pb.PayloadSize = int32(len(payload))
+	return buff
+}
+
+func TestNewMessageFilter(t *testing.T) {
+	f, err := newMessageFilter(replay.CaptureFilter{})
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+
+	_, err = newMessageFilter(replay.CaptureFilter{MetricNameRegex: "("})
+	assert.Error(t, err)
+
+	_, err = newMessageFilter(replay.CaptureFilter{Transport: "udp"})
+	assert.Error(t, err)
+}
+
+func TestMessageFilterOrigin(t *testing.T) {
+	msg := newCaptureBuffer("foo.bar:1|c")
+
+	for _, tc := range []struct {
+		filter   replay.CaptureFilter
+		expected bool
+	}{
+		{replay.CaptureFilter{Pid: 42}, true},
+		{replay.CaptureFilter{Pid: 43}, false},
+		{replay.CaptureFilter{ContainerID: "abcdef"}, true},
+		{replay.CaptureFilter{ContainerID: "other"}, false},
+		{replay.CaptureFilter{Transport: "unixgram"}, true},
+		{replay.CaptureFilter{Transport: "unix"}, false},
+		{replay.CaptureFilter{Pid: 42, Transport: "unix"}, false},
+	} {
+		f, err := newMessageFilter(tc.filter)
+		require.NoError(t, err)
+		_, ok := f.filterPayload(msg)
+		assert.Equal(t, tc.expected, ok, "%+v", tc.filter)
+	}
+}
+
+func TestMessageFilterPayload(t *testing.T) {
+	msg := newCaptureBuffer("custom.a:1|c|#env:prod,team:a\nother.b:2|g|#env:prod\ncustom.c:3|h|#env:dev\n_sc|custom.check|0|#env:prod\n_e{5,4}:title|text|#env:prod\n")
+
+	for _, tc := range []struct {
+		filter   replay.CaptureFilter
+		expected string
+	}{
+		{replay.CaptureFilter{MetricNameRegex: `^custom\.`}, "custom.a:1|c|#env:prod,team:a\ncustom.c:3|h|#env:dev\n_sc|custom.check|0|#env:prod"},
+		{replay.CaptureFilter{Tags: []string{"env:prod"}}, "custom.a:1|c|#env:prod,team:a\nother.b:2|g|#env:prod\n_sc|custom.check|0|#env:prod\n_e{5,4}:title|text|#env:prod"},
+		{replay.CaptureFilter{Tags: []string{"env:prod", "team:a"}}, "custom.a:1|c|#env:prod,team:a"},
+		{replay.CaptureFilter{MetricNameRegex: `^custom\.`, Tags: []string{"env:dev"}}, "custom.c:3|h|#env:dev"},
+		{replay.CaptureFilter{MetricNameRegex: `.`, Tags: []string{"env:prod"}, Pid: 42}, "custom.a:1|c|#env:prod,team:a\nother.b:2|g|#env:prod\n_sc|custom.check|0|#env:prod"},
+	} {
+		f, err := newMessageFilter(tc.filter)
+		require.NoError(t, err)
+		payload, ok := f.filterPayload(msg)
+		assert.True(t, ok)
+		assert.Equal(t, tc.expected, string(payload), "%+v", tc.filter)
+	}
+
+	f, err := newMessageFilter(replay.CaptureFilter{MetricNameRegex: "^unknown$"})
+	require.NoError(t, err)
+	_, ok := f.filterPayload(msg)
+	assert.False(t, ok)
+
+	// the payload is kept as is when all the metrics match
+	f, err = newMessageFilter(replay.CaptureFilter{MetricNameRegex: "^foo"})
+	require.NoError(t, err)
+	payload, ok := f.filterPayload(newCaptureBuffer("foo.a:1|c\nfoo.b:1|c\n"))
+	assert.True(t, ok)
+	assert.Equal(t, "foo.a:1|c\nfoo.b:1|c\n", string(payload))
+}
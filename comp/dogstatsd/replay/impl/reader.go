@@ -29,6 +29,10 @@ type TrafficCaptureReader struct {
 	offset      uint32
 	mmap        bool
 
+	// Speed is the replay speed multiplier, the traffic is replayed at its captured
+	// cadence when it is not set.
+	Speed float64
+
 	sync.Mutex
 }
 
@@ -50,6 +54,11 @@ func (tc *TrafficCaptureReader) Read(ready chan struct{}) {
 	} else {
 		tsResolution = time.Nanosecond
 	}
+
+	speed := tc.Speed
+	if speed <= 0 {
+		speed = 1
+	}
 	tc.Unlock()
 
 	first := int64(0)
@@ -75,7 +84,7 @@ func (tc *TrafficCaptureReader) Read(ready chan struct{}) {
 			first = msg.Timestamp
 		}
 
-		t := time.Duration(msg.Timestamp-first) * tsResolution
+		t := time.Duration(float64(time.Duration(msg.Timestamp-first)*tsResolution) / speed)
 		time.Sleep(t - time.Since(start))
 
 		tc.Traffic <- msg
@@ -113,6 +122,14 @@ func (tc *TrafficCaptureReader) Shutdown() error {
 	return tc.Close()
 }
 
+// timestamp returns the timestamp of a message read from the capture in nanoseconds.
+func (tc *TrafficCaptureReader) timestamp(msg *pb.UnixDogstatsdMsg) int64 {
+	if tc.Version < minNanoVersion {
+		return msg.Timestamp * int64(time.Second)
+	}
+	return msg.Timestamp
+}
+
 // ReadNext reads the next packet found in the file and returns the protobuf representation and an error if any.
 func (tc *TrafficCaptureReader) ReadNext() (*pb.UnixDogstatsdMsg, error) {
 
@@ -182,7 +199,6 @@ func (tc *TrafficCaptureReader) ReadState() (map[int32]string, map[string]*pb.En
 	pbState := &pb.TaggerState{}
 	err := proto.Unmarshal(tc.Contents[length-int(sz)-4:length-4], pbState)
 	if err != nil {
-		tc.Unlock()
 		return nil, nil, err
 	}
 
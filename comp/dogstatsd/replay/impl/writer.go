@@ -8,6 +8,7 @@ package replayimpl
 import (
 	"bufio"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -34,6 +35,9 @@ const (
 	fileTemplate = "datadog-capture-%d"
 )
 
+// errMaxSizeReached is returned when writing a message would exceed the maximum size of the capture
+var errMaxSizeReached = errors.New("maximum capture size reached")
+
 // for testing purposes
 //
 //nolint:unused
@@ -63,6 +67,11 @@ type TrafficCaptureWriter struct {
 
 	taggerState map[int32]string
 
+	filter  *messageFilter
+	maxSize int64
+	written int64
+	full    bool
+
 	// Synchronizes access to ongoing, accepting and closing of Traffic
 	sync.RWMutex
 }
@@ -79,6 +88,20 @@ func NewTrafficCaptureWriter(depth int) *TrafficCaptureWriter {
 // processMessage receives a capture buffer and writes it to disk while also tracking
 // the PID map to be persisted to the taggerState. Should not normally be called directly.
 func (tc *TrafficCaptureWriter) processMessage(msg *replay.CaptureBuffer) error {
+	defer tc.releaseBuffers(msg)
+
+	// the capture is being stopped, the remaining messages are dropped
+	if tc.full {
+		return nil
+	}
+
+	payload, ok := tc.filter.filterPayload(msg)
+	if !ok {
+		return nil
+	}
+	msg.Pb.Payload = payload
+	msg.Pb.PayloadSize = int32(len(payload))
+
 	err := tc.writeNext(msg)
 
 	if err != nil {
@@ -89,6 +112,11 @@ func (tc *TrafficCaptureWriter) processMessage(msg *replay.CaptureBuffer) error
 		tc.taggerState[msg.Pid] = msg.ContainerID
 	}
 
+	return nil
+}
+
+// releaseBuffers returns the buffers of a capture buffer to their pools.
+func (tc *TrafficCaptureWriter) releaseBuffers(msg *replay.CaptureBuffer) {
 	if tc.sharedPacketPoolManager != nil {
 		tc.sharedPacketPoolManager.Put(msg.Buff)
 	}
@@ -96,8 +124,6 @@ func (tc *TrafficCaptureWriter) processMessage(msg *replay.CaptureBuffer) error
 	if tc.oobPacketPoolManager != nil {
 		tc.oobPacketPoolManager.Put(msg.Oob)
 	}
-
-	return nil
 }
 
 // validateLocation validates the location passed as an argument is writable.
@@ -151,11 +177,19 @@ func OpenFile(fs afero.Fs, l string, defaultLocation string) (afero.File, string
 }
 
 // Capture start the traffic capture and writes the packets to file at the
-// specified location and for the specified duration.
-func (tc *TrafficCaptureWriter) Capture(target io.WriteCloser, d time.Duration, compressed bool) {
+// specified location and for the specified duration. Only the traffic matching the
+// filter of the options is written, and the capture stops early once its maximum size
+// is reached.
+func (tc *TrafficCaptureWriter) Capture(target io.WriteCloser, d time.Duration, compressed bool, opts replay.CaptureOptions) {
 	defer target.Close()
 	log.Debug("Starting capture...")
 
+	filter, err := newMessageFilter(opts.Filter)
+	if err != nil {
+		log.Errorf("Unable to start the capture: %v", err)
+		return
+	}
+
 	if compressed {
 		tc.zWriter = zstd.NewWriter(target)
 		tc.writer = bufio.NewWriter(tc.zWriter)
@@ -170,9 +204,13 @@ func (tc *TrafficCaptureWriter) Capture(target io.WriteCloser, d time.Duration,
 	}
 	tc.ongoing = true
 	tc.accepting = true
+	tc.filter = filter
+	tc.maxSize = opts.MaxSize
+	tc.written = 0
+	tc.full = false
 	tc.Unlock()
 
-	err := tc.writeHeader()
+	err = tc.writeHeader()
 	if err != nil {
 		log.Errorf("There was an issue writing the capture file header: %v ", err)
 
@@ -201,7 +239,12 @@ func (tc *TrafficCaptureWriter) Capture(target io.WriteCloser, d time.Duration,
 	for msg := range tc.Traffic {
 		err = tc.processMessage(msg)
 
-		if err != nil {
+		if errors.Is(err, errMaxSizeReached) {
+			log.Infof("The capture reached its maximum size of %d bytes, stopping capture", tc.maxSize)
+			tc.full = true
+			// the traffic is drained while stopping, enqueuers may hold the lock waiting for room in the channel
+			go tc.StopCapture()
+		} else if err != nil {
 			log.Errorf("There was an issue writing the captured message to disk, stopping capture: %v", err)
 			tc.StopCapture()
 		}
@@ -380,7 +423,13 @@ func (tc *TrafficCaptureWriter) writeNext(msg *replay.CaptureBuffer) error {
 		return err
 	}
 
-	_, err = tc.Write(buff)
+	// 4 bytes for the record size
+	if tc.maxSize > 0 && tc.written+int64(len(buff))+4 > tc.maxSize {
+		return errMaxSizeReached
+	}
+
+	n, err := tc.Write(buff)
+	tc.written += int64(n)
 	return err
 }
 
@@ -101,7 +101,7 @@ func writerTest(t *testing.T, z bool) {
 		defer wg.Done()
 
 		close(start)
-		writer.Capture(file, testDuration, z)
+		writer.Capture(file, testDuration, z, replay.CaptureOptions{})
 	}(&wg)
 
 	wgc := make(chan struct{})
@@ -186,3 +186,60 @@ func TestValidateLocation(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, locationGood, l)
 }
+
+func TestWriterFilterAndMaxSize(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	fs.MkdirAll("foo/bar", 0777)
+	file, path, err := OpenFile(fs, "foo/bar", "")
+	require.NoError(t, err)
+
+	writer := NewTrafficCaptureWriter(1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		writer.Capture(file, time.Minute, false, replay.CaptureOptions{
+			Filter:  replay.CaptureFilter{MetricNameRegex: `^kept\.`},
+			MaxSize: 100,
+		})
+	}()
+	require.Eventually(t, writer.IsOngoing, 5*time.Second, 10*time.Millisecond)
+
+	enqueue := func(payload string) bool {
+		buff := new(replay.CaptureBuffer)
+		buff.Pb.Timestamp = time.Now().UnixNano()
+		buff.Pb.Payload = []byte(payload)
+		buff.Pb.PayloadSize = int32(len(payload))
+		return writer.Enqueue(buff)
+	}
+
+	// each record is ~30 bytes, the capture stops once 100 bytes were written
+	for i := 0; i < 10 && enqueue("kept.metric:1|c\ndropped.metric:1|c"); i++ {
+		enqueue("dropped.metric:1|c")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Timed out waiting for the capture to reach its maximum size")
+	}
+	assert.False(t, writer.IsOngoing())
+
+	buf, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+
+	reader := &TrafficCaptureReader{
+		Contents: buf,
+		Version:  int(datadogFileVersion),
+	}
+	reader.Seek(0)
+
+	var cnt int
+	for msg, err := reader.ReadNext(); err != io.EOF; msg, err = reader.ReadNext() {
+		require.NoError(t, err)
+		assert.Equal(t, "kept.metric:1|c", string(msg.Payload))
+		assert.Equal(t, int32(len("kept.metric:1|c")), msg.PayloadSize)
+		cnt++
+	}
+	assert.Equal(t, 3, cnt)
+}
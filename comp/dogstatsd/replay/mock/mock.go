@@ -45,7 +45,7 @@ func (tc *mockTrafficCapture) IsOngoing() bool {
 }
 
 // StartCapture does nothign on the mock
-func (tc *mockTrafficCapture) StartCapture(_ string, _ time.Duration, _ bool) (string, error) {
+func (tc *mockTrafficCapture) StartCapture(_ string, _ time.Duration, _ bool, _ replay.CaptureOptions) (string, error) {
 	tc.Lock()
 	defer tc.Unlock()
 	tc.isRunning = true
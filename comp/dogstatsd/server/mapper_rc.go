@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/comp/dogstatsd/mapper"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+// updateMapper replaces the mapper by one using the remote and local mapping
+// profiles, or removes it if there are none. mapperLock must be held.
+func (s *server) updateMapper() error {
+	mappings := mapper.MergeProfiles(s.localMappings, s.remoteMappings)
+	if len(mappings) == 0 {
+		s.mapper.Store(nil)
+		return nil
+	}
+
+	mapperInstance, err := mapper.NewMetricMapper(mappings, s.config.GetInt("dogstatsd_mapper_cache_size"))
+	if err != nil {
+		return err
+	}
+	s.mapper.Store(mapperInstance)
+	return nil
+}
+
+// onUpdateMapperProfiles applies the mapper profiles received through remote
+// config on top of the dogstatsd_mapper_profiles setting. Invalid configurations
+// are reported in error and ignored, the others are applied.
+func (s *server) onUpdateMapperProfiles(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	s.mapperLock.Lock()
+	defer s.mapperLock.Unlock()
+
+	var remoteMappings []mapper.MappingProfileConfig
+	state.ApplyAgentPipelineRules(updates, applyStateCallback,
+		func(d state.AgentPipelineRulesData) json.RawMessage { return d.DogstatsdMapperProfiles },
+		func(cfgPath string, profiles json.RawMessage, metadata state.Metadata) error {
+			var mappings []mapper.MappingProfileConfig
+			if err := json.Unmarshal(profiles, &mappings); err != nil {
+				return fmt.Errorf("could not parse dogstatsd_mapper_profiles: %v", err)
+			}
+			if err := mapper.ValidateProfiles(mappings); err != nil {
+				s.log.Errorf("Invalid DogStatsD mapper profiles received through remote config in %s: %v", cfgPath, err)
+				return err
+			}
+
+			s.log.Infof("Applying version %d of the DogStatsD mapper profiles %s received through remote config", metadata.Version, cfgPath)
+			remoteMappings = append(remoteMappings, mappings...)
+			return nil
+		},
+	)
+
+	s.remoteMappings = remoteMappings
+	if err := s.updateMapper(); err != nil {
+		s.log.Errorf("Could not update the metric mapper with the remote config mapper profiles: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build test
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
+)
+
+func TestOnUpdateMapperProfiles(t *testing.T) {
+	deps := fulfillDepsWithConfigYaml(t, `
+dogstatsd_port: __random__
+dogstatsd_mapper_profiles:
+  - name: test
+    prefix: 'test.'
+    mappings:
+      - match: "test.job.*"
+        name: "test.job"
+        tags:
+          job: "$1"
+`)
+	s := deps.Server.(*server)
+	requireStart(t, s)
+
+	mapName := func(name string) string {
+		parser := newParser(deps.Config, s.sharedFloat64List, 1, deps.WMeta, s.stringInternerTelemetry)
+		samples, err := s.parseMetricMessage(nil, parser, []byte(name+":1|g"), "", "", false)
+		require.NoError(t, err)
+		require.Len(t, samples, 1)
+		return samples[0].Name
+	}
+	assert.Equal(t, "test.job", mapName("test.job.backup"))
+	assert.Equal(t, "airflow.job.backup", mapName("airflow.job.backup"))
+
+	statuses := map[string]state.ApplyStatus{}
+	applyStatus := func(cfgPath string, status state.ApplyStatus) { statuses[cfgPath] = status }
+
+	s.onUpdateMapperProfiles(map[string]state.RawConfig{
+		"datadog/2/AGENT_PIPELINE_RULES/airflow/config": {Config: []byte(`{"dogstatsd_mapper_profiles": [
+			{"name": "airflow", "prefix": "airflow.", "mappings": [{"match": "airflow.job.*", "name": "airflow.job", "tags": {"job": "$1"}}]}
+		]}`)},
+		"datadog/2/AGENT_PIPELINE_RULES/invalid/config": {Config: []byte(`{"dogstatsd_mapper_profiles": [{"name": "invalid"}]}`)},
+		"datadog/2/AGENT_PIPELINE_RULES/logs/config":    {Config: []byte(`{"logs_processing_rules": [{"type": "exclude_at_match", "name": "health", "pattern": "health"}]}`)},
+	}, applyStatus)
+
+	assert.Equal(t, map[string]state.ApplyStatus{
+		"datadog/2/AGENT_PIPELINE_RULES/airflow/config": {State: state.ApplyStateAcknowledged},
+		"datadog/2/AGENT_PIPELINE_RULES/invalid/config": {State: state.ApplyStateError, Error: "missing prefix for profile: invalid"},
+	}, statuses)
+
+	// Remote profiles are applied along the local ones
+	assert.Equal(t, "test.job", mapName("test.job.backup"))
+	assert.Equal(t, "airflow.job", mapName("airflow.job.backup"))
+
+	// Removing the remote configurations falls back to the local profiles
+	s.onUpdateMapperProfiles(map[string]state.RawConfig{}, applyStatus)
+	assert.Equal(t, "test.job", mapName("test.job.backup"))
+	assert.Equal(t, "airflow.job.backup", mapName("airflow.job.backup"))
+}
@@ -13,6 +13,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/fx"
@@ -28,6 +29,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/dogstatsd/pidmap"
 	replay "github.com/DataDog/datadog-agent/comp/dogstatsd/replay/def"
 	serverdebug "github.com/DataDog/datadog-agent/comp/dogstatsd/serverDebug"
+	rctypes "github.com/DataDog/datadog-agent/comp/remote-config/rcclient/types"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/config/model"
 	"github.com/DataDog/datadog-agent/pkg/config/structure"
@@ -35,6 +37,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/metrics/event"
 	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 
 	"github.com/DataDog/datadog-agent/pkg/util"
@@ -85,6 +88,7 @@ type provides struct {
 
 	Comp          Component
 	StatsEndpoint api.AgentEndpointProvider
+	RCListener    rctypes.ListenerProvider
 }
 
 // When the internal telemetry is enabled, used to tag the origin
@@ -130,10 +134,17 @@ type server struct {
 
 	tCapture                replay.Component
 	pidMap                  pidmap.Component
-	mapper                  *mapper.MetricMapper
+	mapper                  atomic.Pointer[mapper.MetricMapper]
 	eolTerminationUDP       bool
 	eolTerminationUDS       bool
 	eolTerminationNamedPipe bool
+
+	// mapperLock must be held when accessing localMappings and remoteMappings,
+	// and when replacing the mapper
+	mapperLock     sync.Mutex
+	localMappings  []mapper.MappingProfileConfig
+	remoteMappings []mapper.MappingProfileConfig
+
 	// disableVerboseLogs is a feature flag to disable the logs capable
 	// of flooding the logger output (e.g. parsing messages error).
 	// NOTE(remy): this should probably be dropped and use a throttler logger, see
@@ -185,16 +196,22 @@ func initTelemetry() {
 func newServer(deps dependencies) provides {
 	s := newServerCompat(deps.Config, deps.Log, deps.Replay, deps.Debug, deps.Params.Serverless, deps.Demultiplexer, deps.WMeta, deps.PidMap, deps.Telemetry)
 
+	var rcListener rctypes.ListenerProvider
 	if deps.Config.GetBool("use_dogstatsd") {
 		deps.Lc.Append(fx.Hook{
 			OnStart: s.startHook,
 			OnStop:  s.stop,
 		})
+
+		rcListener.ListenerProvider = rctypes.RCListener{
+			state.ProductAgentPipelineRules: s.onUpdateMapperProfiles,
+		}
 	}
 
 	return provides{
 		Comp:          s,
 		StatsEndpoint: api.NewAgentEndpointProvider(s.writeStats, "/dogstatsd-stats", "GET"),
+		RCListener:    rcListener,
 	}
 }
 
@@ -451,18 +468,19 @@ func (s *server) start(context.Context) error {
 	// map some metric name
 	// ----------------------
 
-	cacheSize := s.config.GetInt("dogstatsd_mapper_cache_size")
-
 	mappings, err := getDogstatsdMappingProfiles(s.config)
+	if err == nil {
+		err = mapper.ValidateProfiles(mappings)
+	}
 	if err != nil {
-		s.log.Warn(err)
-	} else if len(mappings) != 0 {
-		mapperInstance, err := mapper.NewMetricMapper(mappings, cacheSize)
-		if err != nil {
+		s.log.Warnf("Could not create metric mapper: %v", err)
+	} else {
+		s.mapperLock.Lock()
+		s.localMappings = mappings
+		if err := s.updateMapper(); err != nil {
 			s.log.Warnf("Could not create metric mapper: %v", err)
-		} else {
-			s.mapper = mapperInstance
 		}
+		s.mapperLock.Unlock()
 	}
 
 	// start the workers processing the packets read on the socket
@@ -759,8 +777,8 @@ func (s *server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		return metricSamples, err
 	}
 
-	if s.mapper != nil {
-		mapResult := s.mapper.Map(sample.name)
+	if metricMapper := s.mapper.Load(); metricMapper != nil {
+		mapResult := metricMapper.Map(sample.name)
 		if mapResult != nil {
 			s.log.Tracef("Dogstatsd mapper: metric mapped from %q to %q with tags %v", sample.name, mapResult.Name, mapResult.Tags)
 			sample.name = mapResult.Name
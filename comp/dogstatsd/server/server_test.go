@@ -750,7 +750,7 @@ func TestNoMappingsConfig(t *testing.T) {
 
 	requireStart(t, s)
 
-	assert.Nil(t, s.mapper)
+	assert.Nil(t, s.mapper.Load())
 
 	parser := newParser(deps.Config, s.sharedFloat64List, 1, deps.WMeta, s.stringInternerTelemetry)
 	samples, err := s.parseMetricMessage(samples, parser, []byte("test.metric:666|g"), "", "", false)
@@ -779,7 +779,7 @@ func TestParseMetricMessageTelemetry(t *testing.T) {
 
 	s := newServerCompat(deps.Config, deps.Log, deps.Replay, deps.Debug, false, deps.Demultiplexer, deps.WMeta, deps.PidMap, deps.Telemetry)
 
-	assert.Nil(t, s.mapper)
+	assert.Nil(t, s.mapper.Load())
 
 	var samples []metrics.MetricSample
 
@@ -8,6 +8,7 @@ package agentimpl
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -120,6 +121,10 @@ type logAgent struct {
 	schedulerProviders        []schedulers.Scheduler
 	integrationsLogs          integrations.Component
 
+	// pipelineProviderMu guards pipelineProvider, which remote config callbacks read
+	// while the agent is starting
+	pipelineProviderMu sync.RWMutex
+
 	// make sure this is done only once, when we're ready
 	prepareSchedulers sync.Once
 
@@ -157,11 +162,12 @@ func newLogsAgent(deps dependencies) provides {
 		})
 
 		var rcListener rctypes.ListenerProvider
+		rcListener.ListenerProvider = rctypes.RCListener{
+			state.ProductAgentPipelineRules: logsAgent.onUpdateProcessingRules,
+		}
 		if sds.SDSEnabled {
-			rcListener.ListenerProvider = rctypes.RCListener{
-				state.ProductSDSAgentConfig: logsAgent.onUpdateSDSAgentConfig,
-				state.ProductSDSRules:       logsAgent.onUpdateSDSRules,
-			}
+			rcListener.ListenerProvider[state.ProductSDSAgentConfig] = logsAgent.onUpdateSDSAgentConfig
+			rcListener.ListenerProvider[state.ProductSDSRules] = logsAgent.onUpdateSDSRules
 		}
 
 		return provides{
@@ -343,6 +349,8 @@ func (a *logAgent) GetMessageReceiver() *diagnostic.BufferedMessageReceiver {
 }
 
 func (a *logAgent) GetPipelineProvider() pipeline.Provider {
+	a.pipelineProviderMu.RLock()
+	defer a.pipelineProviderMu.RUnlock()
 	return a.pipelineProvider
 }
 
@@ -404,3 +412,52 @@ func (a *logAgent) onUpdateSDS(reconfigType sds.ReconfigureOrderType, updates ma
 		}
 	}
 }
+
+// onUpdateProcessingRules applies the global processing rules received through
+// remote config on top of the logs_config.processing_rules setting. Invalid
+// configurations are reported in error and ignored, the others are applied.
+func (a *logAgent) onUpdateProcessingRules(updates map[string]state.RawConfig, applyStateCallback func(string, state.ApplyStatus)) {
+	provider := a.GetPipelineProvider()
+	var remoteRules []*config.ProcessingRule
+	state.ApplyAgentPipelineRules(updates, applyStateCallback,
+		func(d state.AgentPipelineRulesData) json.RawMessage { return d.LogsProcessingRules },
+		func(cfgPath string, section json.RawMessage, metadata state.Metadata) error {
+			if provider == nil {
+				return errors.New("the logs agent is not running")
+			}
+
+			rules, err := parseRemoteProcessingRules(section)
+			if err != nil {
+				a.log.Errorf("Invalid logs processing rules received through remote config in %s: %v", cfgPath, err)
+				return err
+			}
+
+			a.log.Infof("Applying version %d of the logs processing rules %s received through remote config", metadata.Version, cfgPath)
+			remoteRules = append(remoteRules, rules...)
+			return nil
+		},
+	)
+
+	if provider != nil {
+		provider.ReconfigureProcessingRules(remoteRules)
+	}
+}
+
+// parseRemoteProcessingRules parses and compiles global processing rules
+// received through remote config
+func parseRemoteProcessingRules(raw json.RawMessage) ([]*config.ProcessingRule, error) {
+	var rules []*config.ProcessingRule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("could not parse logs_processing_rules: %v", err)
+	}
+	if err := config.ValidateProcessingRules(rules); err != nil {
+		return nil, err
+	}
+	if config.HasMultiLineRule(rules) {
+		return nil, errors.New(multiLineWarning)
+	}
+	if err := config.CompileProcessingRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
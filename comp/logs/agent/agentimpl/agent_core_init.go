@@ -71,7 +71,9 @@ func (a *logAgent) SetupPipeline(processingRules []*config.ProcessingRule, wmeta
 	a.schedulers = schedulers.NewSchedulers(a.sources, a.services)
 	a.auditor = auditor
 	a.destinationsCtx = destinationsCtx
+	a.pipelineProviderMu.Lock()
 	a.pipelineProvider = pipelineProvider
+	a.pipelineProviderMu.Unlock()
 	a.launchers = lnchrs
 	a.health = health
 	a.diagnosticMessageReceiver = diagnosticMessageReceiver
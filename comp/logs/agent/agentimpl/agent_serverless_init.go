@@ -68,7 +68,9 @@ func (a *logAgent) SetupPipeline(
 		a.tagger))
 	a.schedulers = schedulers.NewSchedulers(a.sources, a.services)
 	a.destinationsCtx = destinationsCtx
+	a.pipelineProviderMu.Lock()
 	a.pipelineProvider = pipelineProvider
+	a.pipelineProviderMu.Unlock()
 	a.launchers = lnchrs
 	a.health = health
 	a.diagnosticMessageReceiver = diagnosticMessageReceiver
@@ -46,6 +46,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/sources"
 	logsStatus "github.com/DataDog/datadog-agent/pkg/logs/status"
 	"github.com/DataDog/datadog-agent/pkg/logs/tailers"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/testutil"
 )
@@ -234,6 +235,31 @@ func (suite *AgentTestSuite) TestGetPipelineProvider() {
 	assert.NotNil(suite.T(), agent.GetPipelineProvider())
 }
 
+func (suite *AgentTestSuite) TestOnUpdateProcessingRules() {
+	l := mock.NewMockLogsIntake(suite.T())
+	defer l.Close()
+
+	endpoint := tcp.AddrToEndPoint(l.Addr())
+	endpoints := config.NewEndpoints(endpoint, nil, true, false)
+
+	agent, _, _ := createAgent(suite, endpoints)
+	agent.startPipeline()
+	defer agent.stop(context.TODO())
+
+	statuses := map[string]state.ApplyStatus{}
+	agent.onUpdateProcessingRules(map[string]state.RawConfig{
+		"datadog/2/AGENT_PIPELINE_RULES/health/config":    {Config: []byte(`{"logs_processing_rules": [{"type": "exclude_at_match", "name": "health", "pattern": "GET /health"}]}`)},
+		"datadog/2/AGENT_PIPELINE_RULES/invalid/config":   {Config: []byte(`{"logs_processing_rules": [{"type": "exclude_at_match", "name": "invalid", "pattern": "("}]}`)},
+		"datadog/2/AGENT_PIPELINE_RULES/multiline/config": {Config: []byte(`{"logs_processing_rules": [{"type": "multi_line", "name": "multiline", "pattern": "\\d+"}]}`)},
+		"datadog/2/AGENT_PIPELINE_RULES/mapper/config":    {Config: []byte(`{"dogstatsd_mapper_profiles": [{"name": "airflow", "prefix": "airflow."}]}`)},
+	}, func(cfgPath string, status state.ApplyStatus) { statuses[cfgPath] = status })
+
+	assert.Len(suite.T(), statuses, 3)
+	assert.Equal(suite.T(), state.ApplyStatus{State: state.ApplyStateAcknowledged}, statuses["datadog/2/AGENT_PIPELINE_RULES/health/config"])
+	assert.Equal(suite.T(), state.ApplyStateError, statuses["datadog/2/AGENT_PIPELINE_RULES/invalid/config"].State)
+	assert.Equal(suite.T(), state.ApplyStatus{State: state.ApplyStateError, Error: multiLineWarning}, statuses["datadog/2/AGENT_PIPELINE_RULES/multiline/config"])
+}
+
 func (suite *AgentTestSuite) TestStatusProvider() {
 	tests := []struct {
 		name     string
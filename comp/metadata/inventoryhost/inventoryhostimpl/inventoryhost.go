@@ -9,6 +9,7 @@ package inventoryhostimpl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -24,10 +25,14 @@ import (
 	"github.com/DataDog/datadog-agent/comp/metadata/inventoryhost"
 	pkgUtils "github.com/DataDog/datadog-agent/comp/metadata/packagesigning/utils"
 	"github.com/DataDog/datadog-agent/comp/metadata/runner/runnerimpl"
+	"github.com/DataDog/datadog-agent/pkg/gohai/blockdevice"
 	"github.com/DataDog/datadog-agent/pkg/gohai/cpu"
 	"github.com/DataDog/datadog-agent/pkg/gohai/memory"
 	"github.com/DataDog/datadog-agent/pkg/gohai/network"
+	"github.com/DataDog/datadog-agent/pkg/gohai/nic"
+	"github.com/DataDog/datadog-agent/pkg/gohai/pci"
 	"github.com/DataDog/datadog-agent/pkg/gohai/platform"
+	gohaiutils "github.com/DataDog/datadog-agent/pkg/gohai/utils"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
 	"github.com/DataDog/datadog-agent/pkg/util/cloudproviders"
@@ -53,8 +58,50 @@ var (
 	platformGet   = platform.CollectInfo
 	osVersionGet  = utils.GetOSVersion
 	pkgSigningGet = pkgUtils.GetLinuxGlobalSigningPolicies
+
+	blockDevicesGet = blockdevice.CollectInfo
+	nicGet          = nic.CollectInfo
+	pciGet          = pci.CollectInfo
 )
 
+// blockDevice contains metadata about a physical block device of the host
+type blockDevice struct {
+	Name            string `json:"name"`
+	Vendor          string `json:"vendor"`
+	Model           string `json:"model"`
+	Serial          string `json:"serial"`
+	FirmwareVersion string `json:"firmware_version"`
+	WWID            string `json:"wwid"`
+	SizeBytes       uint64 `json:"size_bytes"`
+	Rotational      bool   `json:"rotational"`
+	Removable       bool   `json:"removable"`
+	State           string `json:"state"`
+}
+
+// networkAdapter contains metadata about a physical network adapter of the host
+type networkAdapter struct {
+	Name            string `json:"name"`
+	MacAddress      string `json:"mac_address"`
+	Driver          string `json:"driver"`
+	DriverVersion   string `json:"driver_version"`
+	FirmwareVersion string `json:"firmware_version"`
+	PCIAddress      string `json:"pci_address"`
+	SpeedMbps       uint64 `json:"speed_mbps"`
+	Duplex          string `json:"duplex"`
+}
+
+// pciDevice contains metadata about a PCI device of the host
+type pciDevice struct {
+	Address           string `json:"address"`
+	VendorID          string `json:"vendor_id"`
+	DeviceID          string `json:"device_id"`
+	SubsystemVendorID string `json:"subsystem_vendor_id"`
+	SubsystemDeviceID string `json:"subsystem_device_id"`
+	Class             string `json:"class"`
+	Revision          string `json:"revision"`
+	Driver            string `json:"driver"`
+}
+
 // hostMetadata contains metadata about the host
 type hostMetadata struct {
 	// from gohai/cpu
@@ -98,6 +145,29 @@ type hostMetadata struct {
 	DmiBoardAssetTag    string `json:"dmi_board_asset_tag"`
 	DmiBoardVendor      string `json:"dmi_board_vendor"`
 
+	// from the DMI attributes, only set when inventories_collect_hardware is enabled
+	DmiSystemVendor    string `json:"dmi_system_vendor"`
+	DmiProductName     string `json:"dmi_product_name"`
+	DmiProductVersion  string `json:"dmi_product_version"`
+	DmiProductFamily   string `json:"dmi_product_family"`
+	DmiProductSKU      string `json:"dmi_product_sku"`
+	DmiProductSerial   string `json:"dmi_product_serial"`
+	DmiBoardName       string `json:"dmi_board_name"`
+	DmiBoardVersion    string `json:"dmi_board_version"`
+	DmiBoardSerial     string `json:"dmi_board_serial"`
+	DmiChassisVendor   string `json:"dmi_chassis_vendor"`
+	DmiChassisType     string `json:"dmi_chassis_type"`
+	DmiChassisSerial   string `json:"dmi_chassis_serial"`
+	DmiChassisAssetTag string `json:"dmi_chassis_asset_tag"`
+	DmiBIOSVendor      string `json:"dmi_bios_vendor"`
+	DmiBIOSVersion     string `json:"dmi_bios_version"`
+	DmiBIOSDate        string `json:"dmi_bios_date"`
+
+	// from gohai/blockdevice, gohai/nic and gohai/pci
+	BlockDevices    []blockDevice    `json:"block_devices"`
+	NetworkAdapters []networkAdapter `json:"network_adapters"`
+	PCIDevices      []pciDevice      `json:"pci_devices"`
+
 	// from package repositories
 	LinuxPackageSigningEnabled   bool `json:"linux_package_signing_enabled"`
 	RPMGlobalRepoGPGCheckEnabled bool `json:"rpm_global_repo_gpg_check_enabled"`
@@ -249,6 +319,115 @@ func (ih *invHost) fillData() {
 	gpgcheck, repoGPGCheck := pkgSigningGet(ih.log)
 	ih.data.LinuxPackageSigningEnabled = gpgcheck
 	ih.data.RPMGlobalRepoGPGCheckEnabled = repoGPGCheck
+
+	if ih.conf.GetBool("inventories_collect_hardware") {
+		ih.fillHardwareData(logWarnings)
+	}
+}
+
+// fillHardwareData fills the hardware inventory of the host: DMI information, block devices, network adapters and
+// PCI devices
+func (ih *invHost) fillHardwareData(logWarnings func([]string)) {
+	logError := func(name string, err error) {
+		if errors.Is(err, gohaiutils.ErrNotCollectable) {
+			ih.log.Debugf("%s metadata %s", name, err)
+			return
+		}
+		ih.log.Errorf("failed to retrieve host %s metadata from gohai: %s", name, err) //nolint:errcheck
+	}
+
+	dmiInfo := dmi.GetHardwareInfo()
+	ih.data.DmiSystemVendor = dmiInfo.SystemVendor
+	ih.data.DmiProductName = dmiInfo.ProductName
+	ih.data.DmiProductVersion = dmiInfo.ProductVersion
+	ih.data.DmiProductFamily = dmiInfo.ProductFamily
+	ih.data.DmiProductSKU = dmiInfo.ProductSKU
+	ih.data.DmiProductSerial = dmiInfo.ProductSerial
+	ih.data.DmiBoardName = dmiInfo.BoardName
+	ih.data.DmiBoardVersion = dmiInfo.BoardVersion
+	ih.data.DmiBoardSerial = dmiInfo.BoardSerial
+	ih.data.DmiChassisVendor = dmiInfo.ChassisVendor
+	ih.data.DmiChassisType = dmiInfo.ChassisType
+	ih.data.DmiChassisSerial = dmiInfo.ChassisSerial
+	ih.data.DmiChassisAssetTag = dmiInfo.ChassisAssetTag
+	ih.data.DmiBIOSVendor = dmiInfo.BIOSVendor
+	ih.data.DmiBIOSVersion = dmiInfo.BIOSVersion
+	ih.data.DmiBIOSDate = dmiInfo.BIOSDate
+
+	var warnings []string
+	ih.data.BlockDevices = nil
+	blockDevices, err := blockDevicesGet()
+	if err == nil {
+		_, warnings, err = blockDevices.AsJSON()
+	}
+	if err != nil {
+		logError("block devices", err)
+	} else {
+		logWarnings(warnings)
+
+		for _, device := range blockDevices {
+			ih.data.BlockDevices = append(ih.data.BlockDevices, blockDevice{
+				Name:            device.Name.ValueOrDefault(),
+				Vendor:          device.Vendor.ValueOrDefault(),
+				Model:           device.Model.ValueOrDefault(),
+				Serial:          device.Serial.ValueOrDefault(),
+				FirmwareVersion: device.FirmwareVersion.ValueOrDefault(),
+				WWID:            device.WWID.ValueOrDefault(),
+				SizeBytes:       device.SizeBytes.ValueOrDefault(),
+				Rotational:      device.Rotational.ValueOrDefault(),
+				Removable:       device.Removable.ValueOrDefault(),
+				State:           device.State.ValueOrDefault(),
+			})
+		}
+	}
+
+	ih.data.NetworkAdapters = nil
+	adapters, err := nicGet()
+	if err == nil {
+		_, warnings, err = adapters.AsJSON()
+	}
+	if err != nil {
+		logError("network adapters", err)
+	} else {
+		logWarnings(warnings)
+
+		for _, adapter := range adapters {
+			ih.data.NetworkAdapters = append(ih.data.NetworkAdapters, networkAdapter{
+				Name:            adapter.Name.ValueOrDefault(),
+				MacAddress:      adapter.MacAddress.ValueOrDefault(),
+				Driver:          adapter.Driver.ValueOrDefault(),
+				DriverVersion:   adapter.DriverVersion.ValueOrDefault(),
+				FirmwareVersion: adapter.FirmwareVersion.ValueOrDefault(),
+				PCIAddress:      adapter.PCIAddress.ValueOrDefault(),
+				SpeedMbps:       adapter.SpeedMbps.ValueOrDefault(),
+				Duplex:          adapter.Duplex.ValueOrDefault(),
+			})
+		}
+	}
+
+	ih.data.PCIDevices = nil
+	pciDevices, err := pciGet()
+	if err == nil {
+		_, warnings, err = pciDevices.AsJSON()
+	}
+	if err != nil {
+		logError("pci devices", err)
+	} else {
+		logWarnings(warnings)
+
+		for _, device := range pciDevices {
+			ih.data.PCIDevices = append(ih.data.PCIDevices, pciDevice{
+				Address:           device.Address.ValueOrDefault(),
+				VendorID:          device.VendorID.ValueOrDefault(),
+				DeviceID:          device.DeviceID.ValueOrDefault(),
+				SubsystemVendorID: device.SubsystemVendorID.ValueOrDefault(),
+				SubsystemDeviceID: device.SubsystemDeviceID.ValueOrDefault(),
+				Class:             device.Class.ValueOrDefault(),
+				Revision:          device.Revision.ValueOrDefault(),
+				Driver:            device.Driver.ValueOrDefault(),
+			})
+		}
+	}
 }
 
 func (ih *invHost) getPayload() marshaler.JSONMarshaler {
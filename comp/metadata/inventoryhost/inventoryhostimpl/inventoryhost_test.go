@@ -17,9 +17,13 @@ import (
 	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
 	"github.com/DataDog/datadog-agent/comp/metadata/host/hostimpl/utils"
 	pkgUtils "github.com/DataDog/datadog-agent/comp/metadata/packagesigning/utils"
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/gohai/blockdevice"
 	"github.com/DataDog/datadog-agent/pkg/gohai/cpu"
 	"github.com/DataDog/datadog-agent/pkg/gohai/memory"
 	"github.com/DataDog/datadog-agent/pkg/gohai/network"
+	"github.com/DataDog/datadog-agent/pkg/gohai/nic"
+	"github.com/DataDog/datadog-agent/pkg/gohai/pci"
 	"github.com/DataDog/datadog-agent/pkg/gohai/platform"
 	gohaiutils "github.com/DataDog/datadog-agent/pkg/gohai/utils"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
@@ -82,10 +86,72 @@ func platformMock() *platform.Info {
 }
 func pkgSigningMock(_ log.Component) (bool, bool) { return true, false }
 
+func blockDevicesMock() (blockdevice.Info, error) {
+	return blockdevice.Info{{
+		Name:            gohaiutils.NewValue("nvme0n1"),
+		Vendor:          gohaiutils.NewErrorValue[string](fmt.Errorf("not found")),
+		Model:           gohaiutils.NewValue("Samsung SSD 970 EVO Plus 500GB"),
+		Serial:          gohaiutils.NewValue("S4EVNF0M123456A"),
+		FirmwareVersion: gohaiutils.NewValue("2B2QEXM7"),
+		WWID:            gohaiutils.NewValue("eui.0025385b71b0a2c3"),
+		SizeBytes:       gohaiutils.NewValue[uint64](500107862016),
+		Rotational:      gohaiutils.NewValue(false),
+		Removable:       gohaiutils.NewValue(false),
+		State:           gohaiutils.NewValue("live"),
+	}}, nil
+}
+
+func nicMock() (nic.Info, error) {
+	return nic.Info{{
+		Name:            gohaiutils.NewValue("eno1"),
+		MacAddress:      gohaiutils.NewValue("3c:ec:ef:12:34:56"),
+		Driver:          gohaiutils.NewValue("ixgbe"),
+		DriverVersion:   gohaiutils.NewValue("5.15.0"),
+		FirmwareVersion: gohaiutils.NewValue("0x8000b6e9"),
+		PCIAddress:      gohaiutils.NewValue("0000:3b:00.0"),
+		SpeedMbps:       gohaiutils.NewValue[uint64](10000),
+		Duplex:          gohaiutils.NewValue("full"),
+	}}, nil
+}
+
+func pciMock() (pci.Info, error) {
+	return pci.Info{{
+		Address:           gohaiutils.NewValue("0000:3b:00.0"),
+		VendorID:          gohaiutils.NewValue("8086"),
+		DeviceID:          gohaiutils.NewValue("10fb"),
+		SubsystemVendorID: gohaiutils.NewValue("15d9"),
+		SubsystemDeviceID: gohaiutils.NewValue("0611"),
+		Class:             gohaiutils.NewValue("020000"),
+		Revision:          gohaiutils.NewValue("01"),
+		Driver:            gohaiutils.NewValue("ixgbe"),
+	}}, nil
+}
+
+var dmiHardwareInfo = dmi.HardwareInfo{
+	SystemVendor:    "Supermicro",
+	ProductName:     "SYS-1029P-WTR",
+	ProductVersion:  "0123456789",
+	ProductFamily:   "Family",
+	ProductSKU:      "SKU",
+	BoardName:       "X11DDW-L",
+	BoardVersion:    "1.10",
+	ChassisVendor:   "Supermicro",
+	ChassisType:     "23",
+	ChassisAssetTag: "Default string",
+	BIOSVendor:      "American Megatrends Inc.",
+	BIOSVersion:     "3.4",
+	BIOSDate:        "10/21/2020",
+}
+
 func cpuErrorMock() *cpu.Info                  { return &cpu.Info{} }
 func memoryErrorMock() *memory.Info            { return &memory.Info{} }
 func networkErrorMock() (*network.Info, error) { return nil, fmt.Errorf("err") }
 func platformErrorMock() *platform.Info        { return &platform.Info{} }
+func blockDevicesErrorMock() (blockdevice.Info, error) {
+	return nil, gohaiutils.ErrNotCollectable
+}
+func nicErrorMock() (nic.Info, error) { return nil, fmt.Errorf("err") }
+func pciErrorMock() (pci.Info, error) { return nil, fmt.Errorf("err") }
 
 func setupHostMetadataMock(t *testing.T) {
 	t.Cleanup(func() {
@@ -95,6 +161,9 @@ func setupHostMetadataMock(t *testing.T) {
 		platformGet = platform.CollectInfo
 		osVersionGet = utils.GetOSVersion
 		pkgSigningGet = pkgUtils.GetLinuxGlobalSigningPolicies
+		blockDevicesGet = blockdevice.CollectInfo
+		nicGet = nic.CollectInfo
+		pciGet = pci.CollectInfo
 	})
 
 	cpuGet = cpuMock
@@ -105,6 +174,10 @@ func setupHostMetadataMock(t *testing.T) {
 	dmi.SetupMock(t, "hypervisorUUID", "dmiUUID", "boardTag", "boardVendor")
 	cloudproviders.Mock(t, "some_cloud_provider", "some_host_id", "test_source", "test_id_1234")
 	pkgSigningGet = pkgSigningMock
+	blockDevicesGet = blockDevicesMock
+	nicGet = nicMock
+	pciGet = pciMock
+	dmi.SetupHardwareInfoMock(t, dmiHardwareInfo)
 }
 
 func setupHostMetadataErrorMock(t *testing.T) {
@@ -114,7 +187,11 @@ func setupHostMetadataErrorMock(t *testing.T) {
 	memoryGet = memoryErrorMock
 	networkGet = networkErrorMock
 	platformGet = platformErrorMock
+	blockDevicesGet = blockDevicesErrorMock
+	nicGet = nicErrorMock
+	pciGet = pciErrorMock
 	dmi.SetupMock(t, "", "", "", "")
+	dmi.SetupHardwareInfoMock(t, dmi.HardwareInfo{})
 }
 
 func getTestInventoryHost(t *testing.T) *invHost {
@@ -133,40 +210,83 @@ func TestGetPayload(t *testing.T) {
 	setupHostMetadataMock(t)
 
 	expectedMetadata := &hostMetadata{
-		CPUCores:                     6,
-		CPULogicalProcessors:         6,
-		CPUVendor:                    "GenuineIntel",
-		CPUModel:                     "Intel_i7-8750H",
-		CPUModelID:                   "158",
-		CPUFamily:                    "6",
-		CPUStepping:                  "10",
-		CPUFrequency:                 2208.006,
-		CPUCacheSize:                 9437184,
-		KernelName:                   "Linux",
-		KernelRelease:                "5.17.0-1-amd64",
-		KernelVersion:                "Debian_5.17.3-1",
-		OS:                           "GNU/Linux",
-		CPUArchitecture:              "unknown",
-		MemoryTotalKb:                1205632,
-		MemorySwapTotalKb:            1205632,
-		IPAddress:                    "192.168.24.138",
-		IPv6Address:                  "fe80::20c:29ff:feb6:d232",
-		MacAddress:                   "00:0c:29:b6:d2:32",
-		AgentVersion:                 version.AgentVersion,
-		CloudProvider:                "some_cloud_provider",
-		CloudProviderAccountID:       "some_host_id",
-		CloudProviderSource:          "test_source",
-		CloudProviderHostID:          "test_id_1234",
-		OsVersion:                    "testOS",
-		HypervisorGuestUUID:          "hypervisorUUID",
-		DmiProductUUID:               "dmiUUID",
-		DmiBoardAssetTag:             "boardTag",
-		DmiBoardVendor:               "boardVendor",
+		CPUCores:               6,
+		CPULogicalProcessors:   6,
+		CPUVendor:              "GenuineIntel",
+		CPUModel:               "Intel_i7-8750H",
+		CPUModelID:             "158",
+		CPUFamily:              "6",
+		CPUStepping:            "10",
+		CPUFrequency:           2208.006,
+		CPUCacheSize:           9437184,
+		KernelName:             "Linux",
+		KernelRelease:          "5.17.0-1-amd64",
+		KernelVersion:          "Debian_5.17.3-1",
+		OS:                     "GNU/Linux",
+		CPUArchitecture:        "unknown",
+		MemoryTotalKb:          1205632,
+		MemorySwapTotalKb:      1205632,
+		IPAddress:              "192.168.24.138",
+		IPv6Address:            "fe80::20c:29ff:feb6:d232",
+		MacAddress:             "00:0c:29:b6:d2:32",
+		AgentVersion:           version.AgentVersion,
+		CloudProvider:          "some_cloud_provider",
+		CloudProviderAccountID: "some_host_id",
+		CloudProviderSource:    "test_source",
+		CloudProviderHostID:    "test_id_1234",
+		OsVersion:              "testOS",
+		HypervisorGuestUUID:    "hypervisorUUID",
+		DmiProductUUID:         "dmiUUID",
+		DmiBoardAssetTag:       "boardTag",
+		DmiBoardVendor:         "boardVendor",
+		DmiSystemVendor:        "Supermicro",
+		DmiProductName:         "SYS-1029P-WTR",
+		DmiProductVersion:      "0123456789",
+		DmiProductFamily:       "Family",
+		DmiProductSKU:          "SKU",
+		DmiBoardName:           "X11DDW-L",
+		DmiBoardVersion:        "1.10",
+		DmiChassisVendor:       "Supermicro",
+		DmiChassisType:         "23",
+		DmiChassisAssetTag:     "Default string",
+		DmiBIOSVendor:          "American Megatrends Inc.",
+		DmiBIOSVersion:         "3.4",
+		DmiBIOSDate:            "10/21/2020",
+		BlockDevices: []blockDevice{{
+			Name:            "nvme0n1",
+			Model:           "Samsung SSD 970 EVO Plus 500GB",
+			Serial:          "S4EVNF0M123456A",
+			FirmwareVersion: "2B2QEXM7",
+			WWID:            "eui.0025385b71b0a2c3",
+			SizeBytes:       500107862016,
+			State:           "live",
+		}},
+		NetworkAdapters: []networkAdapter{{
+			Name:            "eno1",
+			MacAddress:      "3c:ec:ef:12:34:56",
+			Driver:          "ixgbe",
+			DriverVersion:   "5.15.0",
+			FirmwareVersion: "0x8000b6e9",
+			PCIAddress:      "0000:3b:00.0",
+			SpeedMbps:       10000,
+			Duplex:          "full",
+		}},
+		PCIDevices: []pciDevice{{
+			Address:           "0000:3b:00.0",
+			VendorID:          "8086",
+			DeviceID:          "10fb",
+			SubsystemVendorID: "15d9",
+			SubsystemDeviceID: "0611",
+			Class:             "020000",
+			Revision:          "01",
+			Driver:            "ixgbe",
+		}},
 		LinuxPackageSigningEnabled:   true,
 		RPMGlobalRepoGPGCheckEnabled: false,
 	}
 
 	ih := getTestInventoryHost(t)
+	ih.conf.Set("inventories_collect_hardware", true, model.SourceAgentRuntime)
 
 	p := ih.getPayload().(*Payload)
 	assert.Equal(t, expectedMetadata, p.Metadata)
@@ -176,6 +296,7 @@ func TestGetPayloadError(t *testing.T) {
 	setupHostMetadataErrorMock(t)
 
 	ih := getTestInventoryHost(t)
+	ih.conf.Set("inventories_collect_hardware", true, model.SourceAgentRuntime)
 
 	p := ih.getPayload().(*Payload)
 	expected := &hostMetadata{
@@ -191,6 +312,19 @@ func TestGetPayloadError(t *testing.T) {
 	assert.Equal(t, expected, p.Metadata)
 }
 
+func TestGetPayloadHardwareDisabled(t *testing.T) {
+	setupHostMetadataMock(t)
+
+	// the hardware inventory is disabled by default
+	ih := getTestInventoryHost(t)
+
+	p := ih.getPayload().(*Payload)
+	assert.Empty(t, p.Metadata.DmiSystemVendor)
+	assert.Nil(t, p.Metadata.BlockDevices)
+	assert.Nil(t, p.Metadata.NetworkAdapters)
+	assert.Nil(t, p.Metadata.PCIDevices)
+}
+
 func TestFlareProviderFilename(t *testing.T) {
 	ih := getTestInventoryHost(t)
 	assert.Equal(t, "host.json", ih.FlareFileName)
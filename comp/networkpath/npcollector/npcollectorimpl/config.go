@@ -16,6 +16,7 @@ type collectorConfigs struct {
 	workers                      int
 	timeout                      time.Duration
 	maxTTL                       int
+	tracerouteRuns               int
 	pathtestInputChanSize        int
 	pathtestProcessingChanSize   int
 	pathtestContextsLimit        int
@@ -32,6 +33,7 @@ func newConfig(agentConfig config.Component) *collectorConfigs {
 		workers:                      agentConfig.GetInt("network_path.collector.workers"),
 		timeout:                      agentConfig.GetDuration("network_path.collector.timeout") * time.Millisecond,
 		maxTTL:                       agentConfig.GetInt("network_path.collector.max_ttl"),
+		tracerouteRuns:               agentConfig.GetInt("network_path.collector.traceroute_runs"),
 		pathtestInputChanSize:        agentConfig.GetInt("network_path.collector.input_chan_size"),
 		pathtestProcessingChanSize:   agentConfig.GetInt("network_path.collector.processing_chan_size"),
 		pathtestContextsLimit:        agentConfig.GetInt("network_path.collector.pathtest_contexts_limit"),
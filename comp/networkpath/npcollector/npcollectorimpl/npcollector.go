@@ -75,10 +75,11 @@ func newNoopNpCollectorImpl() *npCollectorImpl {
 }
 
 func newNpCollectorImpl(epForwarder eventplatform.Forwarder, collectorConfigs *collectorConfigs, logger log.Component, telemetrycomp telemetryComp.Component) *npCollectorImpl {
-	logger.Infof("New NpCollector (workers=%d timeout=%d max_ttl=%d input_chan_size=%d processing_chan_size=%d pathtest_contexts_limit=%d pathtest_ttl=%s pathtest_interval=%s flush_interval=%s)",
+	logger.Infof("New NpCollector (workers=%d timeout=%d max_ttl=%d traceroute_runs=%d input_chan_size=%d processing_chan_size=%d pathtest_contexts_limit=%d pathtest_ttl=%s pathtest_interval=%s flush_interval=%s)",
 		collectorConfigs.workers,
 		collectorConfigs.timeout,
 		collectorConfigs.maxTTL,
+		collectorConfigs.tracerouteRuns,
 		collectorConfigs.pathtestInputChanSize,
 		collectorConfigs.pathtestProcessingChanSize,
 		collectorConfigs.pathtestContextsLimit,
@@ -222,16 +223,29 @@ func (s *npCollectorImpl) runTracerouteForPath(ptest *pathteststore.PathtestCont
 		Protocol:     ptest.Pathtest.Protocol,
 	}
 
-	path, err := s.runTraceroute(cfg, s.telemetrycomp)
-	if err != nil {
-		s.logger.Errorf("%s", err)
+	// Each traceroute run sends one probe per hop, when the path is traced several
+	// times the runs are aggregated to compute jitter and packet loss statistics.
+	var paths []payload.NetworkPath
+	for i := 0; i < max(s.collectorConfigs.tracerouteRuns, 1); i++ {
+		probePath, err := s.runTraceroute(cfg, s.telemetrycomp)
+		if err != nil {
+			s.logger.Errorf("%s", err)
+			continue
+		}
+		paths = append(paths, probePath)
+	}
+	if len(paths) == 0 {
 		return
 	}
+	path := paths[0]
 	path.Source.ContainerID = ptest.Pathtest.SourceContainerID
 	path.Namespace = s.networkDevicesNamespace
 	path.Origin = payload.PathOriginNetworkTraffic
 
+	stats := telemetry.ComputePathStats(paths)
+
 	s.sendTelemetry(path, startTime, ptest)
+	s.sendPathStats(path, stats, ptest)
 
 	payloadBytes, err := json.Marshal(path)
 	if err != nil {
@@ -321,6 +335,19 @@ func (s *npCollectorImpl) sendTelemetry(path payload.NetworkPath, startTime time
 	)
 }
 
+func (s *npCollectorImpl) sendPathStats(path payload.NetworkPath, stats telemetry.PathStats, ptest *pathteststore.PathtestContext) {
+	telemetry.SubmitNetworkPathStats(
+		s.metricSender,
+		path,
+		stats,
+		ptest.LastHopCount(),
+		[]string{},
+	)
+	if stats.HopCount > 0 {
+		ptest.SetLastHopCount(stats.HopCount)
+	}
+}
+
 func (s *npCollectorImpl) startWorkers() {
 	s.logger.Debugf("Starting workers (%d)", s.workers)
 	for w := 0; w < s.workers; w++ {
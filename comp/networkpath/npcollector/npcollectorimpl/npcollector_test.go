@@ -11,8 +11,10 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -302,6 +304,7 @@ func Test_newNpCollectorImpl_defaultConfigs(t *testing.T) {
 	assert.Equal(t, 100000, cap(npCollector.pathtestInputChan))
 	assert.Equal(t, 100000, cap(npCollector.pathtestProcessingChan))
 	assert.Equal(t, 100000, npCollector.collectorConfigs.pathtestContextsLimit)
+	assert.Equal(t, 3, npCollector.collectorConfigs.tracerouteRuns)
 	assert.Equal(t, "default", npCollector.networkDevicesNamespace)
 }
 
@@ -312,6 +315,7 @@ func Test_newNpCollectorImpl_overrideConfigs(t *testing.T) {
 		"network_path.collector.input_chan_size":         300,
 		"network_path.collector.processing_chan_size":    400,
 		"network_path.collector.pathtest_contexts_limit": 500,
+		"network_path.collector.traceroute_runs":         5,
 		"network_devices.namespace":                      "ns1",
 	}
 
@@ -322,6 +326,7 @@ func Test_newNpCollectorImpl_overrideConfigs(t *testing.T) {
 	assert.Equal(t, 300, cap(npCollector.pathtestInputChan))
 	assert.Equal(t, 400, cap(npCollector.pathtestProcessingChan))
 	assert.Equal(t, 500, npCollector.collectorConfigs.pathtestContextsLimit)
+	assert.Equal(t, 5, npCollector.collectorConfigs.tracerouteRuns)
 	assert.Equal(t, "ns1", npCollector.networkDevicesNamespace)
 }
 
@@ -725,6 +730,87 @@ func Test_npCollectorImpl_sendTelemetry(t *testing.T) {
 	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.check_interval", Value: (2 * time.Minute).Seconds(), Tags: tags, Rate: 1})
 }
 
+func Test_npCollectorImpl_runTracerouteForPath_pathStats(t *testing.T) {
+	// GIVEN
+	agentConfigs := map[string]any{
+		"network_path.connections_monitoring.enabled": true,
+		"network_path.collector.traceroute_runs":      3,
+	}
+	_, npCollector := newTestNpCollector(t, agentConfigs)
+
+	stats := &teststatsd.Client{}
+	npCollector.statsdClient = stats
+	npCollector.metricSender = metricsender.NewMetricSenderStatsd(stats)
+
+	mockEpForwarder := eventplatformimpl.NewMockEventPlatformForwarder(gomock.NewController(t))
+	npCollector.epForwarder = mockEpForwarder
+	mockEpForwarder.EXPECT().SendEventPlatformEventBlocking(gomock.Any(), eventplatform.EventTypeNetworkPath).Return(nil).Times(2)
+
+	var runs int
+	rtts := []float64{10, 14, 12}
+	hopCount := 2
+	npCollector.runTraceroute = func(_ traceroute.Config, _ telemetry.Component) (payload.NetworkPath, error) {
+		defer func() { runs++ }()
+		if runs == 1 {
+			return payload.NetworkPath{}, errors.New("traceroute error")
+		}
+		var hops []payload.NetworkPathHop
+		for ttl := 1; ttl < hopCount; ttl++ {
+			hops = append(hops, payload.NetworkPathHop{TTL: ttl, IPAddress: "10.0.0.1", RTT: 1, Reachable: true})
+		}
+		hops = append(hops, payload.NetworkPathHop{TTL: hopCount, IPAddress: "10.0.0.2", RTT: rtts[runs%3], Reachable: true})
+		return payload.NetworkPath{
+			Protocol:    payload.ProtocolUDP,
+			Destination: payload.NetworkPathDestination{Hostname: "abc", IPAddress: "10.0.0.2"},
+			Hops:        hops,
+		}, nil
+	}
+	ptestCtx := &pathteststore.PathtestContext{
+		Pathtest: &common.Pathtest{Hostname: "10.0.0.2", Protocol: payload.ProtocolUDP},
+	}
+	tags := []string{
+		"collector:network_path_collector",
+		"destination_hostname:abc",
+		"destination_ip:10.0.0.2",
+		"destination_port:unspecified",
+		"origin:network_traffic",
+		"protocol:UDP",
+	}
+
+	// WHEN
+	npCollector.runTracerouteForPath(ptestCtx)
+
+	// THEN
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, 2, ptestCtx.LastHopCount())
+	calls := stats.GaugeCalls
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.path.packet_loss", Value: 0, Tags: tags, Rate: 1})
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.path.rtt.min", Value: 10, Tags: tags, Rate: 1})
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.path.rtt.avg", Value: 11, Tags: tags, Rate: 1})
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.path.rtt.max", Value: 12, Tags: tags, Rate: 1})
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.path.jitter", Value: 2, Tags: tags, Rate: 1})
+	for _, call := range calls {
+		assert.NotContains(t, call.Name, "hop_count")
+	}
+	hopTags := append([]string{"hop_ip_address:10.0.0.2", "hop_ttl:2"}, tags...)
+	sort.Strings(hopTags)
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.hop.packet_loss", Value: 0, Tags: hopTags, Rate: 1})
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.hop.rtt.avg", Value: 11, Tags: hopTags, Rate: 1})
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.hop.jitter", Value: 2, Tags: hopTags, Rate: 1})
+
+	// WHEN the path gets longer
+	runs = 0
+	hopCount = 3
+	stats.Reset()
+	npCollector.runTracerouteForPath(ptestCtx)
+
+	// THEN
+	assert.Equal(t, 3, ptestCtx.LastHopCount())
+	calls = stats.GaugeCalls
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.path.hop_count_changed", Value: 1, Tags: tags, Rate: 1})
+	assert.Contains(t, calls, teststatsd.MetricsArgs{Name: "datadog.network_path.path.hop_count_delta", Value: 1, Tags: tags, Rate: 1})
+}
+
 func Benchmark_npCollectorImpl_ScheduleConns(b *testing.B) {
 	agentConfigs := map[string]any{
 		"network_path.connections_monitoring.enabled": true,
@@ -24,6 +24,10 @@ type PathtestContext struct {
 	runUntil          time.Time
 	lastFlushTime     time.Time
 	lastFlushInterval time.Duration
+
+	// lastHopCount is read and set by the workers running the pathtest
+	lastHopCount      int
+	lastHopCountMutex sync.Mutex
 }
 
 // LastFlushInterval returns last flush interval
@@ -36,6 +40,20 @@ func (p *PathtestContext) SetLastFlushInterval(lastFlushInterval time.Duration)
 	p.lastFlushInterval = lastFlushInterval
 }
 
+// LastHopCount returns the hop count of the last run reaching the destination
+func (p *PathtestContext) LastHopCount() int {
+	p.lastHopCountMutex.Lock()
+	defer p.lastHopCountMutex.Unlock()
+	return p.lastHopCount
+}
+
+// SetLastHopCount sets the hop count of the last run reaching the destination
+func (p *PathtestContext) SetLastHopCount(lastHopCount int) {
+	p.lastHopCountMutex.Lock()
+	defer p.lastHopCountMutex.Unlock()
+	p.lastHopCount = lastHopCount
+}
+
 // Store is used to accumulate aggregated contexts
 type Store struct {
 	logger log.Component
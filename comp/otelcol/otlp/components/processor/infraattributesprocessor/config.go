@@ -6,6 +6,8 @@
 package infraattributesprocessor
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/component"
 
 	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
@@ -18,8 +20,54 @@ type Config struct {
 	Traces  TraceInfraAttributes  `mapstructure:"traces"`
 
 	Cardinality types.TagCardinality `mapstructure:"cardinality"`
+
+	// AttributesMapping holds the attribute mapping rules of each signal.
+	AttributesMapping AttributesMapping `mapstructure:"attributes_mapping"`
+}
+
+// AttributesMapping - attribute mapping rules for each signal.
+type AttributesMapping struct {
+	Metrics AttributesMappingRules `mapstructure:"metrics"`
+	Logs    AttributesMappingRules `mapstructure:"logs"`
+	Traces  AttributesMappingRules `mapstructure:"traces"`
+}
+
+// AttributesMappingRules - attribute mapping rules of a signal.
+// Rules are applied in the following order: rename, promote, drop. An attribute
+// can therefore be promoted to a tag and dropped from the telemetry.
+type AttributesMappingRules struct {
+	// Cardinality overrides the tagger cardinality for the signal.
+	Cardinality *types.TagCardinality `mapstructure:"cardinality"`
+	// Rename renames resource and scope attribute keys.
+	Rename []RenameRule `mapstructure:"rename"`
+	// Promote promotes resource or scope attributes to Datadog tags.
+	Promote []PromoteRule `mapstructure:"promote"`
+	// Drop removes resource and scope attributes.
+	Drop []string `mapstructure:"drop"`
+}
+
+// RenameRule - renames the key of an attribute.
+type RenameRule struct {
+	From string `mapstructure:"from"`
+	To   string `mapstructure:"to"`
+}
+
+// PromoteRule - promotes an attribute to a Datadog tag.
+type PromoteRule struct {
+	// Attribute is the key of the attribute to promote.
+	Attribute string `mapstructure:"attribute"`
+	// From is the level of the attribute, either "resource" (default) or "scope".
+	From string `mapstructure:"from"`
+	// Tag is the name of the tag, it defaults to the attribute key.
+	Tag string `mapstructure:"tag"`
 }
 
+// List of attribute levels a promote rule can read from
+const (
+	AttributeLevelResource = "resource"
+	AttributeLevelScope    = "scope"
+)
+
 // MetricInfraAttributes - configuration for metrics.
 type MetricInfraAttributes struct {
 	MetricInfraAttributes []string `mapstructure:"metric"`
@@ -39,5 +87,59 @@ var _ component.Config = (*Config)(nil)
 
 // Validate configuration
 func (cfg *Config) Validate() error {
+	if err := validateCardinality(cfg.Cardinality); err != nil {
+		return err
+	}
+	for signal, rules := range map[string]AttributesMappingRules{
+		"metrics": cfg.AttributesMapping.Metrics,
+		"logs":    cfg.AttributesMapping.Logs,
+		"traces":  cfg.AttributesMapping.Traces,
+	} {
+		if err := rules.validate(); err != nil {
+			return fmt.Errorf("invalid attributes_mapping for %s: %w", signal, err)
+		}
+	}
+	return nil
+}
+
+func (r *AttributesMappingRules) validate() error {
+	if r.Cardinality != nil {
+		if err := validateCardinality(*r.Cardinality); err != nil {
+			return err
+		}
+	}
+	for _, rule := range r.Rename {
+		if rule.From == "" || rule.To == "" {
+			return fmt.Errorf("rename rules require both 'from' and 'to'")
+		}
+	}
+	for _, rule := range r.Promote {
+		if rule.Attribute == "" {
+			return fmt.Errorf("promote rules require an 'attribute'")
+		}
+		if rule.From != "" && rule.From != AttributeLevelResource && rule.From != AttributeLevelScope {
+			return fmt.Errorf("invalid 'from' value %q for attribute %q, must be %q or %q", rule.From, rule.Attribute, AttributeLevelResource, AttributeLevelScope)
+		}
+	}
+	for _, key := range r.Drop {
+		if key == "" {
+			return fmt.Errorf("drop rules cannot contain empty attribute keys")
+		}
+	}
 	return nil
 }
+
+func validateCardinality(cardinality types.TagCardinality) error {
+	if cardinality < types.LowCardinality || cardinality > types.HighCardinality {
+		return fmt.Errorf("invalid cardinality %d", cardinality)
+	}
+	return nil
+}
+
+// cardinality returns the tagger cardinality to use for a signal
+func (cfg *Config) cardinality(rules AttributesMappingRules) types.TagCardinality {
+	if rules.Cardinality != nil {
+		return *rules.Cardinality
+	}
+	return cfg.Cardinality
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
 )
 
 // TestLoadingConfigStrictLogs tests loading testdata/logs_strict.yaml
@@ -48,3 +50,63 @@ func TestLoadingConfigStrictLogs(t *testing.T) {
 		})
 	}
 }
+
+// TestLoadingConfigAttributesMapping tests loading testdata/attributes_mapping.yaml
+func TestLoadingConfigAttributesMapping(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "attributes_mapping.yaml"))
+	require.NoError(t, err)
+
+	highCardinality := types.HighCardinality
+	tests := []struct {
+		id          component.ID
+		expected    *Config
+		expectedErr string
+	}{
+		{
+			id: component.MustNewIDWithName("infraattributes", "mapping"),
+			expected: &Config{
+				Cardinality: types.LowCardinality,
+				AttributesMapping: AttributesMapping{
+					Metrics: AttributesMappingRules{
+						Cardinality: &highCardinality,
+						Promote: []PromoteRule{
+							{Attribute: "team"},
+							{Attribute: "app.tier", From: AttributeLevelScope, Tag: "tier"},
+						},
+					},
+					Logs: AttributesMappingRules{
+						Rename: []RenameRule{{From: "customer.id", To: "customer"}},
+						Drop:   []string{"secret.token"},
+					},
+					Traces: AttributesMappingRules{
+						Drop: []string{"secret.token"},
+					},
+				},
+			},
+		},
+		{
+			id:          component.MustNewIDWithName("infraattributes", "invalid"),
+			expectedErr: `invalid attributes_mapping for logs: invalid 'from' value "span" for attribute "team", must be "resource" or "scope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			f := NewFactory(newTestTaggerClient(), newTestGenerateIDClient().generateID)
+			cfg := f.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(&cfg))
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+			assert.Equal(t, types.HighCardinality, cfg.(*Config).cardinality(cfg.(*Config).AttributesMapping.Metrics))
+			assert.Equal(t, types.LowCardinality, cfg.(*Config).cardinality(cfg.(*Config).AttributesMapping.Logs))
+		})
+	}
+}
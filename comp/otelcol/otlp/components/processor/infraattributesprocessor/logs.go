@@ -20,14 +20,16 @@ type infraAttributesLogProcessor struct {
 	tagger      taggerClient
 	cardinality types.TagCardinality
 	generateID  GenerateKubeMetadataEntityID
+	mapper      *attributesMapper
 }
 
 func newInfraAttributesLogsProcessor(set processor.Settings, cfg *Config, tagger taggerClient, generateID GenerateKubeMetadataEntityID) (*infraAttributesLogProcessor, error) {
 	ialp := &infraAttributesLogProcessor{
 		logger:      set.Logger,
 		tagger:      tagger,
-		cardinality: cfg.Cardinality,
+		cardinality: cfg.cardinality(cfg.AttributesMapping.Logs),
 		generateID:  generateID,
+		mapper:      newAttributesMapper(cfg.AttributesMapping.Logs),
 	}
 
 	set.Logger.Info("Logs Infra Attributes Processor configured")
@@ -71,6 +73,16 @@ func (ialp *infraAttributesLogProcessor) processLogs(_ context.Context, ld plog.
 		for k, v := range tagMap {
 			resourceAttributes.PutStr(k, v)
 		}
+
+		if ialp.mapper.isNoop() {
+			continue
+		}
+		resourceTags := ialp.mapper.mapAttributes(resourceAttributes, AttributeLevelResource)
+		sls := rls.At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			scopeTags := ialp.mapper.mapAttributes(sls.At(j).Scope().Attributes(), AttributeLevelScope)
+			addTagsToLogRecords(sls.At(j), append(scopeTags, resourceTags...))
+		}
 	}
 	return ld, nil
 }
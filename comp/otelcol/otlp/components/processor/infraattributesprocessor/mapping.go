@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package infraattributesprocessor
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// attributesMapper applies the attributes mapping rules of a signal
+type attributesMapper struct {
+	rename  []RenameRule
+	promote map[string][]PromoteRule
+	drop    []string
+}
+
+func newAttributesMapper(rules AttributesMappingRules) *attributesMapper {
+	m := &attributesMapper{
+		rename: rules.Rename,
+		promote: map[string][]PromoteRule{
+			AttributeLevelResource: nil,
+			AttributeLevelScope:    nil,
+		},
+		drop: rules.Drop,
+	}
+	for _, rule := range rules.Promote {
+		level := rule.From
+		if level == "" {
+			level = AttributeLevelResource
+		}
+		m.promote[level] = append(m.promote[level], rule)
+	}
+	return m
+}
+
+// isNoop returns whether the mapper has no rule to apply
+func (m *attributesMapper) isNoop() bool {
+	return len(m.rename) == 0 && len(m.drop) == 0 &&
+		len(m.promote[AttributeLevelResource]) == 0 && len(m.promote[AttributeLevelScope]) == 0
+}
+
+// mapAttributes renames, promotes and drops the attributes of the given level.
+// It returns the promoted tags.
+func (m *attributesMapper) mapAttributes(attrs pcommon.Map, level string) []string {
+	for _, rule := range m.rename {
+		if v, ok := attrs.Get(rule.From); ok {
+			value := pcommon.NewValueEmpty()
+			v.CopyTo(value)
+			attrs.Remove(rule.From)
+			value.CopyTo(attrs.PutEmpty(rule.To))
+		}
+	}
+
+	var tags []string
+	for _, rule := range m.promote[level] {
+		v, ok := attrs.Get(rule.Attribute)
+		if !ok || v.AsString() == "" {
+			continue
+		}
+		tag := rule.Tag
+		if tag == "" {
+			tag = rule.Attribute
+		}
+		tags = append(tags, tag+":"+v.AsString())
+	}
+
+	for _, key := range m.drop {
+		attrs.Remove(key)
+	}
+
+	return tags
+}
+
+// ddTagsAttribute is the log record attribute holding comma separated tags, the Datadog logs
+// translation adds them to the tags of the log.
+const ddTagsAttribute = "ddtags"
+
+// addTagsToDataPoints adds the promoted tags as attributes of every data point of the scope metrics,
+// so that they are translated into metric tags. Data point attributes take precedence over promoted tags.
+func addTagsToDataPoints(sm pmetric.ScopeMetrics, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	ms := sm.Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		forEachDataPointAttributes(ms.At(i), func(attrs pcommon.Map) {
+			putMissingTags(attrs, tags)
+		})
+	}
+}
+
+// addTagsToSpans adds tags to the attributes of the spans, keeping the attributes the spans already have.
+func addTagsToSpans(ss ptrace.ScopeSpans, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	spans := ss.Spans()
+	for i := 0; i < spans.Len(); i++ {
+		putMissingTags(spans.At(i).Attributes(), tags)
+	}
+}
+
+// putMissingTags adds tags to attrs, skipping the keys attrs already has.
+func putMissingTags(attrs pcommon.Map, tags []string) {
+	for _, tag := range tags {
+		k, v := splitTag(tag)
+		if _, exists := attrs.Get(k); k != "" && !exists {
+			attrs.PutStr(k, v)
+		}
+	}
+}
+
+func forEachDataPointAttributes(m pmetric.Metric, fn func(attrs pcommon.Map)) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < m.Gauge().DataPoints().Len(); i++ {
+			fn(m.Gauge().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		for i := 0; i < m.Sum().DataPoints().Len(); i++ {
+			fn(m.Sum().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < m.Histogram().DataPoints().Len(); i++ {
+			fn(m.Histogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		for i := 0; i < m.ExponentialHistogram().DataPoints().Len(); i++ {
+			fn(m.ExponentialHistogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		for i := 0; i < m.Summary().DataPoints().Len(); i++ {
+			fn(m.Summary().DataPoints().At(i).Attributes())
+		}
+	}
+}
+
+// addTagsToLogRecords appends the promoted tags to the ddtags attribute of every log record of the
+// scope logs, so that they are translated into log tags.
+func addTagsToLogRecords(sl plog.ScopeLogs, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	joined := strings.Join(tags, ",")
+	lrs := sl.LogRecords()
+	for i := 0; i < lrs.Len(); i++ {
+		attrs := lrs.At(i).Attributes()
+		if v, ok := attrs.Get(ddTagsAttribute); ok && v.AsString() != "" {
+			attrs.PutStr(ddTagsAttribute, v.AsString()+","+joined)
+			continue
+		}
+		attrs.PutStr(ddTagsAttribute, joined)
+	}
+}
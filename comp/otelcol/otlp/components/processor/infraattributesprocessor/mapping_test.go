@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package infraattributesprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+var testMappingRules = AttributesMappingRules{
+	Rename: []RenameRule{{From: "customer.id", To: "customer"}},
+	Promote: []PromoteRule{
+		{Attribute: "team"},
+		{Attribute: "customer"},
+		{Attribute: "secret.token", Tag: "token"},
+		{Attribute: "app.tier", From: AttributeLevelScope, Tag: "tier"},
+	},
+	Drop: []string{"secret.token"},
+}
+
+func TestAttributesMapper(t *testing.T) {
+	mapper := newAttributesMapper(testMappingRules)
+	assert.False(t, mapper.isNoop())
+	assert.True(t, newAttributesMapper(AttributesMappingRules{}).isNoop())
+
+	attrs := pcommon.NewMap()
+	require.NoError(t, attrs.FromRaw(map[string]any{
+		"team":         "core",
+		"customer.id":  int64(42),
+		"secret.token": "s3cr3t",
+		"app.tier":     "frontend",
+	}))
+
+	tags := mapper.mapAttributes(attrs, AttributeLevelResource)
+	assert.ElementsMatch(t, []string{"team:core", "customer:42", "token:s3cr3t"}, tags)
+	assert.Equal(t, map[string]any{
+		"team":     "core",
+		"customer": int64(42),
+		"app.tier": "frontend",
+	}, attrs.AsRaw())
+
+	scopeAttrs := pcommon.NewMap()
+	scopeAttrs.PutStr("app.tier", "frontend")
+	scopeAttrs.PutStr("team", "core")
+	assert.Equal(t, []string{"tier:frontend"}, mapper.mapAttributes(scopeAttrs, AttributeLevelScope))
+}
+
+func TestInfraAttributesMetricProcessorMapping(t *testing.T) {
+	next := new(consumertest.MetricsSink)
+	cfg := &Config{AttributesMapping: AttributesMapping{Metrics: testMappingRules}}
+	factory := NewFactory(newTestTaggerClient(), newTestGenerateIDClient().generateID)
+	mp, err := factory.CreateMetricsProcessor(context.Background(), processortest.NewNopSettings(), cfg, next)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("team", "core")
+	rm.Resource().Attributes().PutStr("secret.token", "s3cr3t")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().Attributes().PutStr("app.tier", "frontend")
+	dps := sm.Metrics().AppendEmpty().SetEmptyGauge().DataPoints()
+	dps.AppendEmpty()
+	dps.AppendEmpty().Attributes().PutStr("team", "payments")
+
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+	require.Len(t, next.AllMetrics(), 1)
+
+	out := next.AllMetrics()[0].ResourceMetrics().At(0)
+	assert.Equal(t, map[string]any{"team": "core"}, out.Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"app.tier": "frontend"}, out.ScopeMetrics().At(0).Scope().Attributes().AsRaw())
+	outDps := out.ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+	assert.Equal(t, map[string]any{"team": "core", "token": "s3cr3t", "tier": "frontend"}, outDps.At(0).Attributes().AsRaw())
+	// data point attributes take precedence over promoted tags
+	assert.Equal(t, map[string]any{"team": "payments", "token": "s3cr3t", "tier": "frontend"}, outDps.At(1).Attributes().AsRaw())
+}
+
+func TestInfraAttributesLogProcessorMapping(t *testing.T) {
+	next := new(consumertest.LogsSink)
+	cfg := &Config{AttributesMapping: AttributesMapping{Logs: testMappingRules}}
+	factory := NewFactory(newTestTaggerClient(), newTestGenerateIDClient().generateID)
+	lp, err := factory.CreateLogsProcessor(context.Background(), processortest.NewNopSettings(), cfg, next)
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("customer.id", "42")
+	rl.Resource().Attributes().PutStr("secret.token", "s3cr3t")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().Attributes().PutStr("app.tier", "frontend")
+	sl.LogRecords().AppendEmpty()
+	sl.LogRecords().AppendEmpty().Attributes().PutStr("ddtags", "foo:bar")
+
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	require.Len(t, next.AllLogs(), 1)
+
+	out := next.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{"customer": "42"}, out.Resource().Attributes().AsRaw())
+	records := out.ScopeLogs().At(0).LogRecords()
+	assert.Equal(t, map[string]any{"ddtags": "tier:frontend,customer:42,token:s3cr3t"}, records.At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"ddtags": "foo:bar,tier:frontend,customer:42,token:s3cr3t"}, records.At(1).Attributes().AsRaw())
+}
+
+func TestInfraAttributesSpanProcessorMapping(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	cfg := &Config{AttributesMapping: AttributesMapping{Traces: testMappingRules}}
+	factory := NewFactory(newTestTaggerClient(), newTestGenerateIDClient().generateID)
+	tp, err := factory.CreateTracesProcessor(context.Background(), processortest.NewNopSettings(), cfg, next)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("customer.id", "42")
+	rs.Resource().Attributes().PutStr("secret.token", "s3cr3t")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().Attributes().PutStr("app.tier", "frontend")
+	ss.Spans().AppendEmpty().SetName("span")
+	withTier := ss.Spans().AppendEmpty()
+	withTier.SetName("span with tier")
+	withTier.Attributes().PutStr("tier", "backend")
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	require.Len(t, next.AllTraces(), 1)
+
+	out := next.AllTraces()[0].ResourceSpans().At(0)
+	assert.Equal(t, map[string]any{
+		"customer": "42",
+		"token":    "s3cr3t",
+	}, out.Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"tier": "frontend"}, out.ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw())
+	// span attributes take precedence over promoted tags
+	assert.Equal(t, map[string]any{"tier": "backend"}, out.ScopeSpans().At(0).Spans().At(1).Attributes().AsRaw())
+}
@@ -24,14 +24,16 @@ type infraAttributesMetricProcessor struct {
 	tagger      taggerClient
 	cardinality types.TagCardinality
 	generateID  GenerateKubeMetadataEntityID
+	mapper      *attributesMapper
 }
 
 func newInfraAttributesMetricProcessor(set processor.Settings, cfg *Config, tagger taggerClient, generateID GenerateKubeMetadataEntityID) (*infraAttributesMetricProcessor, error) {
 	iamp := &infraAttributesMetricProcessor{
 		logger:      set.Logger,
 		tagger:      tagger,
-		cardinality: cfg.Cardinality,
+		cardinality: cfg.cardinality(cfg.AttributesMapping.Metrics),
 		generateID:  generateID,
+		mapper:      newAttributesMapper(cfg.AttributesMapping.Metrics),
 	}
 	set.Logger.Info("Metric Infra Attributes Processor configured")
 	return iamp, nil
@@ -126,6 +128,16 @@ func (iamp *infraAttributesMetricProcessor) processMetrics(_ context.Context, md
 		for k, v := range tagMap {
 			resourceAttributes.PutStr(k, v)
 		}
+
+		if iamp.mapper.isNoop() {
+			continue
+		}
+		resourceTags := iamp.mapper.mapAttributes(resourceAttributes, AttributeLevelResource)
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			scopeTags := iamp.mapper.mapAttributes(sms.At(j).Scope().Attributes(), AttributeLevelScope)
+			addTagsToDataPoints(sms.At(j), append(scopeTags, resourceTags...))
+		}
 	}
 	return md, nil
 }
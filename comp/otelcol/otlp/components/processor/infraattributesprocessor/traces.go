@@ -20,14 +20,16 @@ type infraAttributesSpanProcessor struct {
 	tagger      taggerClient
 	cardinality types.TagCardinality
 	generateID  GenerateKubeMetadataEntityID
+	mapper      *attributesMapper
 }
 
 func newInfraAttributesSpanProcessor(set processor.Settings, cfg *Config, tagger taggerClient, generateID GenerateKubeMetadataEntityID) (*infraAttributesSpanProcessor, error) {
 	iasp := &infraAttributesSpanProcessor{
 		logger:      set.Logger,
 		tagger:      tagger,
-		cardinality: cfg.Cardinality,
+		cardinality: cfg.cardinality(cfg.AttributesMapping.Traces),
 		generateID:  generateID,
+		mapper:      newAttributesMapper(cfg.AttributesMapping.Traces),
 	}
 	set.Logger.Info("Span Infra Attributes Processor configured")
 	return iasp, nil
@@ -70,6 +72,16 @@ func (iasp *infraAttributesSpanProcessor) processTraces(_ context.Context, td pt
 		for k, v := range tagMap {
 			resourceAttributes.PutStr(k, v)
 		}
+
+		if iasp.mapper.isNoop() {
+			continue
+		}
+		// Span tags are built from resource and span attributes, promoted tags are added as such
+		putMissingTags(resourceAttributes, iasp.mapper.mapAttributes(resourceAttributes, AttributeLevelResource))
+		sss := rss.At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			addTagsToSpans(sss.At(j), iasp.mapper.mapAttributes(sss.At(j).Scope().Attributes(), AttributeLevelScope))
+		}
 	}
 	return td, nil
 }
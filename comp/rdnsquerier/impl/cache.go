@@ -168,7 +168,7 @@ func (c *cacheImpl) sendQuery(addr string) error {
 			c.mutex.Lock()
 			if entry, ok := c.data[addr]; ok {
 				entry.Hostname = hostname
-				entry.ExpirationTime = time.Now().Add(c.config.cache.entryTTL)
+				entry.ExpirationTime = time.Now().Add(c.entryTTL(hostname))
 				entry.queryInProgress = false
 
 				callbacks := entry.callbacks
@@ -195,6 +195,15 @@ func (c *cacheImpl) sendQuery(addr string) error {
 	)
 }
 
+// entryTTL returns the TTL of a cache entry.  Negative results, i.e. IP addresses that could not be resolved to a
+// hostname, use their own TTL so they can be retried sooner than successful results.
+func (c *cacheImpl) entryTTL(hostname string) time.Duration {
+	if hostname == "" {
+		return c.config.cache.negativeEntryTTL
+	}
+	return c.config.cache.entryTTL
+}
+
 func (c *cacheImpl) runExpireLoop() {
 	// call expire() periodically to remove expired entries from the cache
 	ticker := time.NewTicker(c.config.cache.cleanInterval)
@@ -272,13 +281,15 @@ func (c *cacheImpl) loadPersistentCache() {
 	for ip, entry := range persistedMap {
 		// remove expired entries
 		if entry.ExpirationTime.Before(now) {
-			delete(c.data, ip)
+			delete(persistedMap, ip)
+			continue
 		}
 
 		// Adjust ExpirationTime for entries that are too far in the future, which can occur if entryTTL
-		// was decreased since the cache was persisted.
-		if entry.ExpirationTime.After(now.Add(c.config.cache.entryTTL)) {
-			entry.ExpirationTime = now.Add(c.config.cache.entryTTL)
+		// or negativeEntryTTL was decreased since the cache was persisted.
+		ttl := c.entryTTL(entry.Hostname)
+		if entry.ExpirationTime.After(now.Add(ttl)) {
+			entry.ExpirationTime = now.Add(ttl)
 		}
 	}
 	size := len(persistedMap)
@@ -6,9 +6,14 @@
 package rdnsquerierimpl
 
 import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
 )
 
 type rdnsQuerierConfig struct {
@@ -16,17 +21,37 @@ type rdnsQuerierConfig struct {
 	workers  int
 	chanSize int
 
+	staticMappings []staticMappingConfig
+	hostsFile      hostsFileConfig
+	dnsServer      dnsServerConfig
+
 	cache       cacheConfig
 	rateLimiter rateLimiterConfig
 }
 
+type staticMappingConfig struct {
+	IP       string `mapstructure:"ip"`
+	Hostname string `mapstructure:"hostname"`
+}
+
+type hostsFileConfig struct {
+	enabled bool
+	path    string
+}
+
+type dnsServerConfig struct {
+	address string
+	timeout time.Duration
+}
+
 type cacheConfig struct {
-	enabled         bool
-	entryTTL        time.Duration
-	cleanInterval   time.Duration
-	persistInterval time.Duration
-	maxRetries      int
-	maxSize         int
+	enabled          bool
+	entryTTL         time.Duration
+	negativeEntryTTL time.Duration
+	cleanInterval    time.Duration
+	persistInterval  time.Duration
+	maxRetries       int
+	maxSize          int
 }
 
 type rateLimiterConfig struct {
@@ -42,7 +67,11 @@ const (
 	defaultWorkers  = 10
 	defaultChanSize = 5000
 
+	defaultDNSServerPort    = "53"
+	defaultDNSServerTimeout = 5 * time.Second
+
 	defaultCacheEntryTTL        = 24 * time.Hour
+	defaultCacheNegativeTTL     = 1 * time.Hour
 	defaultCacheCleanInterval   = 2 * time.Hour
 	defaultCachePersistInterval = 2 * time.Hour
 	defaultCacheMaxRetries      = 10
@@ -63,13 +92,24 @@ func newConfig(agentConfig config.Component) *rdnsQuerierConfig {
 		workers:  agentConfig.GetInt("reverse_dns_enrichment.workers"),
 		chanSize: agentConfig.GetInt("reverse_dns_enrichment.chan_size"),
 
+		hostsFile: hostsFileConfig{
+			enabled: agentConfig.GetBool("reverse_dns_enrichment.hosts_file.enabled"),
+			path:    agentConfig.GetString("reverse_dns_enrichment.hosts_file.path"),
+		},
+
+		dnsServer: dnsServerConfig{
+			address: agentConfig.GetString("reverse_dns_enrichment.dns_server.address"),
+			timeout: agentConfig.GetDuration("reverse_dns_enrichment.dns_server.timeout"),
+		},
+
 		cache: cacheConfig{
-			enabled:         agentConfig.GetBool("reverse_dns_enrichment.cache.enabled"),
-			entryTTL:        agentConfig.GetDuration("reverse_dns_enrichment.cache.entry_ttl"),
-			cleanInterval:   agentConfig.GetDuration("reverse_dns_enrichment.cache.clean_interval"),
-			persistInterval: agentConfig.GetDuration("reverse_dns_enrichment.cache.persist_interval"),
-			maxRetries:      agentConfig.GetInt("reverse_dns_enrichment.cache.max_retries"),
-			maxSize:         agentConfig.GetInt("reverse_dns_enrichment.cache.max_size"),
+			enabled:          agentConfig.GetBool("reverse_dns_enrichment.cache.enabled"),
+			entryTTL:         agentConfig.GetDuration("reverse_dns_enrichment.cache.entry_ttl"),
+			negativeEntryTTL: agentConfig.GetDuration("reverse_dns_enrichment.cache.negative_entry_ttl"),
+			cleanInterval:    agentConfig.GetDuration("reverse_dns_enrichment.cache.clean_interval"),
+			persistInterval:  agentConfig.GetDuration("reverse_dns_enrichment.cache.persist_interval"),
+			maxRetries:       agentConfig.GetInt("reverse_dns_enrichment.cache.max_retries"),
+			maxSize:          agentConfig.GetInt("reverse_dns_enrichment.cache.max_size"),
 		},
 
 		rateLimiter: rateLimiterConfig{
@@ -82,10 +122,22 @@ func newConfig(agentConfig config.Component) *rdnsQuerierConfig {
 		},
 	}
 
+	if agentConfig.IsSet("reverse_dns_enrichment.static_mappings") {
+		// invalid mappings are reported when the component is created, see newLocalResolver()
+		_ = structure.UnmarshalKey(agentConfig, "reverse_dns_enrichment.static_mappings", &c.staticMappings)
+	}
+
 	c.setDefaults()
 	return c
 }
 
+func defaultHostsFilePath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
 func (c *rdnsQuerierConfig) setDefaults() {
 	if !c.enabled {
 		return
@@ -99,10 +151,26 @@ func (c *rdnsQuerierConfig) setDefaults() {
 		c.chanSize = defaultChanSize
 	}
 
+	if c.hostsFile.enabled && c.hostsFile.path == "" {
+		c.hostsFile.path = defaultHostsFilePath()
+	}
+
+	if c.dnsServer.address != "" {
+		if _, _, err := net.SplitHostPort(c.dnsServer.address); err != nil {
+			c.dnsServer.address = net.JoinHostPort(c.dnsServer.address, defaultDNSServerPort)
+		}
+		if c.dnsServer.timeout <= 0 {
+			c.dnsServer.timeout = defaultDNSServerTimeout
+		}
+	}
+
 	if c.cache.enabled {
 		if c.cache.entryTTL <= 0 {
 			c.cache.entryTTL = defaultCacheEntryTTL
 		}
+		if c.cache.negativeEntryTTL <= 0 {
+			c.cache.negativeEntryTTL = defaultCacheNegativeTTL
+		}
 		if c.cache.cleanInterval <= 0 {
 			c.cache.cleanInterval = defaultCacheCleanInterval
 		}
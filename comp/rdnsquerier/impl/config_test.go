@@ -56,12 +56,13 @@ network_devices:
 				workers:  defaultWorkers,
 				chanSize: defaultChanSize,
 				cache: cacheConfig{
-					enabled:         true,
-					entryTTL:        defaultCacheEntryTTL,
-					cleanInterval:   defaultCacheCleanInterval,
-					persistInterval: defaultCachePersistInterval,
-					maxRetries:      defaultCacheMaxRetries,
-					maxSize:         defaultCacheMaxSize,
+					enabled:          true,
+					entryTTL:         defaultCacheEntryTTL,
+					negativeEntryTTL: defaultCacheNegativeTTL,
+					cleanInterval:    defaultCacheCleanInterval,
+					persistInterval:  defaultCachePersistInterval,
+					maxRetries:       defaultCacheMaxRetries,
+					maxSize:          defaultCacheMaxSize,
 				},
 				rateLimiter: rateLimiterConfig{
 					enabled:                true,
@@ -102,12 +103,13 @@ reverse_dns_enrichment:
 				workers:  defaultWorkers,
 				chanSize: defaultChanSize,
 				cache: cacheConfig{
-					enabled:         true,
-					entryTTL:        defaultCacheEntryTTL,
-					cleanInterval:   defaultCacheCleanInterval,
-					persistInterval: defaultCachePersistInterval,
-					maxRetries:      defaultCacheMaxRetries,
-					maxSize:         defaultCacheMaxSize,
+					enabled:          true,
+					entryTTL:         defaultCacheEntryTTL,
+					negativeEntryTTL: defaultCacheNegativeTTL,
+					cleanInterval:    defaultCacheCleanInterval,
+					persistInterval:  defaultCachePersistInterval,
+					maxRetries:       defaultCacheMaxRetries,
+					maxSize:          defaultCacheMaxSize,
 				},
 				rateLimiter: rateLimiterConfig{
 					enabled:                true,
@@ -131,6 +133,7 @@ reverse_dns_enrichment:
   cache:
     enabled: true
     entry_ttl: 24h
+    negative_entry_ttl: 30m
     clean_interval: 30m
     persist_interval: 2h
     max_retries: 1
@@ -148,12 +151,13 @@ reverse_dns_enrichment:
 				workers:  25,
 				chanSize: 999,
 				cache: cacheConfig{
-					enabled:         true,
-					entryTTL:        24 * time.Hour,
-					cleanInterval:   30 * time.Minute,
-					persistInterval: 2 * time.Hour,
-					maxRetries:      1,
-					maxSize:         100_000,
+					enabled:          true,
+					entryTTL:         24 * time.Hour,
+					negativeEntryTTL: 30 * time.Minute,
+					cleanInterval:    30 * time.Minute,
+					persistInterval:  2 * time.Hour,
+					maxRetries:       1,
+					maxSize:          100_000,
 				},
 				rateLimiter: rateLimiterConfig{
 					enabled:                true,
@@ -184,12 +188,13 @@ reverse_dns_enrichment:
 				workers:  25,
 				chanSize: 999,
 				cache: cacheConfig{
-					enabled:         true,
-					entryTTL:        defaultCacheEntryTTL,
-					cleanInterval:   defaultCacheCleanInterval,
-					persistInterval: defaultCachePersistInterval,
-					maxRetries:      defaultCacheMaxRetries,
-					maxSize:         defaultCacheMaxSize,
+					enabled:          true,
+					entryTTL:         defaultCacheEntryTTL,
+					negativeEntryTTL: defaultCacheNegativeTTL,
+					cleanInterval:    defaultCacheCleanInterval,
+					persistInterval:  defaultCachePersistInterval,
+					maxRetries:       defaultCacheMaxRetries,
+					maxSize:          defaultCacheMaxSize,
 				},
 				rateLimiter: rateLimiterConfig{
 					enabled:                true,
@@ -256,12 +261,13 @@ reverse_dns_enrichment:
 				workers:  25,
 				chanSize: 999,
 				cache: cacheConfig{
-					enabled:         true,
-					entryTTL:        defaultCacheEntryTTL,
-					cleanInterval:   defaultCacheCleanInterval,
-					persistInterval: defaultCachePersistInterval,
-					maxRetries:      defaultCacheMaxRetries,
-					maxSize:         defaultCacheMaxSize,
+					enabled:          true,
+					entryTTL:         defaultCacheEntryTTL,
+					negativeEntryTTL: defaultCacheNegativeTTL,
+					cleanInterval:    defaultCacheCleanInterval,
+					persistInterval:  defaultCachePersistInterval,
+					maxRetries:       defaultCacheMaxRetries,
+					maxSize:          defaultCacheMaxSize,
 				},
 				rateLimiter: rateLimiterConfig{
 					enabled:                true,
@@ -273,6 +279,91 @@ reverse_dns_enrichment:
 				},
 			},
 		},
+		{
+			name: "static mappings, hosts file and dns server",
+			configYaml: `
+network_devices:
+  netflow:
+    reverse_dns_enrichment_enabled: true
+reverse_dns_enrichment:
+  static_mappings:
+    - ip: 192.168.1.10
+      hostname: printer.corp
+    - ip: 10.0.0.1
+      hostname: gateway.corp
+  hosts_file:
+    enabled: true
+    path: /tmp/hosts
+  dns_server:
+    address: 10.0.0.53
+  cache:
+    enabled: false
+  rate_limiter:
+    enabled: false
+`,
+			expectedConfig: rdnsQuerierConfig{
+				enabled:  true,
+				workers:  defaultWorkers,
+				chanSize: defaultChanSize,
+				staticMappings: []staticMappingConfig{
+					{IP: "192.168.1.10", Hostname: "printer.corp"},
+					{IP: "10.0.0.1", Hostname: "gateway.corp"},
+				},
+				hostsFile: hostsFileConfig{
+					enabled: true,
+					path:    "/tmp/hosts",
+				},
+				dnsServer: dnsServerConfig{
+					address: "10.0.0.53:53",
+					timeout: defaultDNSServerTimeout,
+				},
+				cache: cacheConfig{
+					enabled:    false,
+					maxRetries: -1,
+				},
+				rateLimiter: rateLimiterConfig{
+					enabled: false,
+				},
+			},
+		},
+		{
+			name: "dns server with port and timeout",
+			configYaml: `
+network_devices:
+  netflow:
+    reverse_dns_enrichment_enabled: true
+reverse_dns_enrichment:
+  hosts_file:
+    enabled: true
+  dns_server:
+    address: "[fd00::53]:5353"
+    timeout: 2s
+  cache:
+    enabled: false
+  rate_limiter:
+    enabled: false
+`,
+			expectedConfig: rdnsQuerierConfig{
+				enabled:  true,
+				workers:  defaultWorkers,
+				chanSize: defaultChanSize,
+				hostsFile: hostsFileConfig{
+					enabled: true,
+					path:    defaultHostsFilePath(),
+				},
+				dnsServer: dnsServerConfig{
+					address: "[fd00::53]:5353",
+					timeout: 2 * time.Second,
+				},
+				cache: cacheConfig{
+					enabled:    false,
+					maxRetries: -1,
+				},
+				rateLimiter: rateLimiterConfig{
+					enabled: false,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
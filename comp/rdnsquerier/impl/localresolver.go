@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package rdnsquerierimpl
+
+import (
+	"bufio"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+)
+
+// localResolver resolves IP addresses from sources that do not require a DNS query.  The sources are queried in order:
+// the static mappings from the configuration, then the hosts file.  Since local lookups are cheap they are performed
+// synchronously, without going through the cache, the rate limiter or the workers.
+type localResolver struct {
+	staticMappings map[string]string
+	hostsFile      map[string]string
+}
+
+func newLocalResolver(config *rdnsQuerierConfig, logger log.Component) *localResolver {
+	r := &localResolver{
+		staticMappings: make(map[string]string),
+		hostsFile:      make(map[string]string),
+	}
+
+	for _, mapping := range config.staticMappings {
+		addr, err := netip.ParseAddr(mapping.IP)
+		if err != nil || mapping.Hostname == "" {
+			_ = logger.Warnf("Reverse DNS Enrichment ignoring invalid static mapping ip=%q hostname=%q", mapping.IP, mapping.Hostname)
+			continue
+		}
+		r.staticMappings[addr.String()] = mapping.Hostname
+	}
+
+	if config.hostsFile.enabled {
+		f, err := os.Open(config.hostsFile.path)
+		if err != nil {
+			_ = logger.Warnf("Reverse DNS Enrichment unable to open hosts file %s: %v", config.hostsFile.path, err)
+		} else {
+			r.hostsFile = parseHostsFile(f)
+			f.Close()
+			logger.Debugf("Reverse DNS Enrichment loaded %d entries from hosts file %s", len(r.hostsFile), config.hostsFile.path)
+		}
+	}
+
+	return r
+}
+
+// lookup returns the hostname of the IP address and true if it is found in one of the local sources
+func (r *localResolver) lookup(addr string) (string, bool) {
+	if hostname, ok := r.staticMappings[addr]; ok {
+		return hostname, true
+	}
+	if hostname, ok := r.hostsFile[addr]; ok {
+		return hostname, true
+	}
+	return "", false
+}
+
+// parseHostsFile returns the first hostname of each IP address in a hosts file, as the system resolver does
+func parseHostsFile(reader io.Reader) map[string]string {
+	hosts := make(map[string]string)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		// zone identifiers (e.g. fe80::1%lo0) are dropped since they are not part of the queried addresses
+		ip, _, _ := strings.Cut(fields[0], "%")
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			continue
+		}
+		if _, ok := hosts[addr.String()]; !ok {
+			hosts[addr.String()] = fields[1]
+		}
+	}
+
+	return hosts
+}
@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package rdnsquerierimpl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHostsFile(t *testing.T) {
+	hostsFile := `
+# comment line
+127.0.0.1       localhost
+::1             localhost ip6-localhost ip6-loopback
+192.168.1.10    printer.corp printer # trailing comment
+192.168.1.10    duplicate.corp
+fe80::1%lo0     link-local
+10.0.0.1
+not-an-ip       invalid.corp
+`
+	hosts := parseHostsFile(strings.NewReader(hostsFile))
+
+	assert.Equal(t, map[string]string{
+		"127.0.0.1":    "localhost",
+		"::1":          "localhost",
+		"192.168.1.10": "printer.corp",
+		"fe80::1":      "link-local",
+	}, hosts)
+}
@@ -39,6 +39,7 @@ type rdnsQuerierTelemetry = struct {
 	total            telemetry.Counter
 	invalidIPAddress telemetry.Counter
 	private          telemetry.Counter
+	localHit         telemetry.Counter
 
 	// cache
 	cacheHit             telemetry.Counter
@@ -72,19 +73,27 @@ type rdnsQuerierImpl struct {
 
 	started bool
 
-	cache cache
+	localResolver *localResolver
+	cache         cache
 }
 
 // NewComponent creates a new rdnsquerier component
 func NewComponent(reqs Requires) (Provides, error) {
 	rdnsQuerierConfig := newConfig(reqs.AgentConfig)
-	reqs.Logger.Infof("Reverse DNS Enrichment config: (enabled=%t workers=%d chan_size=%d cache.enabled=%t cache.entry_ttl=%d cache.clean_interval=%d cache.persist_interval=%d cache.max_retries=%d cache.max_size=%d rate_limiter.enabled=%t rate_limiter.limit_per_sec=%d rate_limiter.limit_throttled_per_sec=%d rate_limiter.throttle_error_threshold=%d rate_limiter.recovery_intervals=%d rate_limiter.recovery_interval=%d)",
+	reqs.Logger.Infof("Reverse DNS Enrichment config: (enabled=%t workers=%d chan_size=%d static_mappings=%d hosts_file.enabled=%t hosts_file.path=%s dns_server.address=%s dns_server.timeout=%d cache.enabled=%t cache.entry_ttl=%d cache.negative_entry_ttl=%d cache.clean_interval=%d cache.persist_interval=%d cache.max_retries=%d cache.max_size=%d rate_limiter.enabled=%t rate_limiter.limit_per_sec=%d rate_limiter.limit_throttled_per_sec=%d rate_limiter.throttle_error_threshold=%d rate_limiter.recovery_intervals=%d rate_limiter.recovery_interval=%d)",
 		rdnsQuerierConfig.enabled,
 		rdnsQuerierConfig.workers,
 		rdnsQuerierConfig.chanSize,
 
+		len(rdnsQuerierConfig.staticMappings),
+		rdnsQuerierConfig.hostsFile.enabled,
+		rdnsQuerierConfig.hostsFile.path,
+		rdnsQuerierConfig.dnsServer.address,
+		rdnsQuerierConfig.dnsServer.timeout,
+
 		rdnsQuerierConfig.cache.enabled,
 		rdnsQuerierConfig.cache.entryTTL,
+		rdnsQuerierConfig.cache.negativeEntryTTL,
 		rdnsQuerierConfig.cache.cleanInterval,
 		rdnsQuerierConfig.cache.persistInterval,
 		rdnsQuerierConfig.cache.maxRetries,
@@ -108,6 +117,7 @@ func NewComponent(reqs Requires) (Provides, error) {
 		reqs.Telemetry.NewCounter(moduleName, "total", []string{}, "Counter measuring the total number of rDNS requests"),
 		reqs.Telemetry.NewCounter(moduleName, "invalid_ip_address", []string{}, "Counter measuring the number of rDNS requests with an invalid IP address"),
 		reqs.Telemetry.NewCounter(moduleName, "private", []string{}, "Counter measuring the number of rDNS requests in the private address space"),
+		reqs.Telemetry.NewCounter(moduleName, "local_hit", []string{}, "Counter measuring the number of rDNS requests resolved from static mappings or the hosts file"),
 
 		reqs.Telemetry.NewCounter(moduleName, "cache_hit", []string{}, "Counter measuring the number of successful rDNS cache hits"),
 		reqs.Telemetry.NewCounter(moduleName, "cache_hit_expired", []string{}, "Counter measuring the number of expired rDNS cache hits"),
@@ -136,8 +146,9 @@ func NewComponent(reqs Requires) (Provides, error) {
 		logger:            reqs.Logger,
 		internalTelemetry: internalTelemetry,
 
-		started: false,
-		cache:   newCache(rdnsQuerierConfig, reqs.Logger, internalTelemetry, newQuerier(rdnsQuerierConfig, reqs.Logger, internalTelemetry)),
+		started:       false,
+		localResolver: newLocalResolver(rdnsQuerierConfig, reqs.Logger),
+		cache:         newCache(rdnsQuerierConfig, reqs.Logger, internalTelemetry, newQuerier(rdnsQuerierConfig, reqs.Logger, internalTelemetry)),
 	}
 
 	reqs.Lifecycle.Append(compdef.Hook{
@@ -152,7 +163,8 @@ func NewComponent(reqs Requires) (Provides, error) {
 
 // GetHostnameAsync attempts to resolve the hostname for the given IP address.
 // If the IP address is invalid then an error is returned.
-// If the IP address is not in the private address space then it is ignored - no lookup is performed and nil error is returned.
+// If the IP address is found in the static mappings or in the hosts file then the updateHostnameSync callback is invoked synchronously.
+// Otherwise, if the IP address is not in the private address space then it is ignored - no lookup is performed and nil error is returned.
 // If the IP address is in the private address space then the IP address will be resolved to a hostname.
 // If the hostname for the IP address is immediately available (i.e. cache is enabled and entry is cached) then the updateHostnameSync callback
 // will be invoked synchronously, otherwise a query is sent to a channel to be processed asynchronously.  If the channel is full then an error
@@ -166,6 +178,12 @@ func (q *rdnsQuerierImpl) GetHostname(ipAddr []byte, updateHostnameSync func(str
 		return fmt.Errorf("invalid IP address %v", ipAddr)
 	}
 
+	if hostname, ok := q.localResolver.lookup(netipAddr.String()); ok {
+		q.internalTelemetry.localHit.Inc()
+		updateHostnameSync(hostname)
+		return nil
+	}
+
 	if !netipAddr.IsPrivate() {
 		q.logger.Tracef("Reverse DNS Enrichment IP address %s is not in the private address space", netipAddr)
 		return nil
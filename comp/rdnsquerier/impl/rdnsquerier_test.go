@@ -8,6 +8,8 @@ package rdnsquerierimpl
 import (
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -986,3 +988,159 @@ func TestCachePersist(t *testing.T) {
 	})
 	ts.validateExpected(t, expectedTelemetry)
 }
+
+// Test that negative results use their own TTL
+func TestNegativeCacheEntryTTL(t *testing.T) {
+	overrides := map[string]interface{}{
+		"network_devices.netflow.reverse_dns_enrichment_enabled": true,
+		"reverse_dns_enrichment.cache.negative_entry_ttl":        time.Duration(100) * time.Millisecond,
+	}
+	ts := testSetup(t, overrides, true,
+		map[string]*fakeResults{
+			"192.168.1.100": {errors: []error{
+				&net.DNSError{Err: "no such host", IsNotFound: true}},
+			},
+		},
+	)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	err := ts.rdnsQuerier.GetHostname(
+		[]byte{192, 168, 1, 100},
+		func(_ string) {
+			assert.FailNow(t, "Sync callback should not be called")
+		},
+		func(hostname string, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, "", hostname)
+			wg.Done()
+		},
+	)
+	assert.NoError(t, err)
+	wg.Wait()
+
+	// successful results are cached for entry_ttl
+	wg.Add(1)
+	err = ts.rdnsQuerier.GetHostname(
+		[]byte{192, 168, 1, 101},
+		func(_ string) {
+			assert.FailNow(t, "Sync callback should not be called")
+		},
+		func(hostname string, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, "fakehostname-192.168.1.101", hostname)
+			wg.Done()
+		},
+	)
+	assert.NoError(t, err)
+	wg.Wait()
+
+	time.Sleep(200 * time.Millisecond)
+
+	// the negative result is expired, the IP address is queried again
+	wg.Add(1)
+	err = ts.rdnsQuerier.GetHostname(
+		[]byte{192, 168, 1, 100},
+		func(_ string) {
+			assert.FailNow(t, "Sync callback should not be called")
+		},
+		func(hostname string, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, "fakehostname-192.168.1.100", hostname)
+			wg.Done()
+		},
+	)
+	assert.NoError(t, err)
+	wg.Wait()
+
+	wg.Add(1)
+	err = ts.rdnsQuerier.GetHostname(
+		[]byte{192, 168, 1, 101},
+		func(hostname string) {
+			assert.Equal(t, "fakehostname-192.168.1.101", hostname)
+			wg.Done()
+		},
+		func(_ string, _ error) {
+			assert.FailNow(t, "Async callback should not be called")
+		},
+	)
+	assert.NoError(t, err)
+	wg.Wait()
+
+	expectedTelemetry := ts.makeExpectedTelemetry(map[string]float64{
+		"total":                4.0,
+		"private":              4.0,
+		"chan_added":           3.0,
+		"lookup_err_not_found": 1.0,
+		"successful":           2.0,
+		"cache_hit":            1.0,
+		"cache_hit_expired":    1.0,
+		"cache_miss":           3.0,
+	})
+	ts.validateExpected(t, expectedTelemetry)
+}
+
+// Test that static mappings and the hosts file are used before querying DNS
+func TestLocalSources(t *testing.T) {
+	hostsFile := filepath.Join(t.TempDir(), "hosts")
+	err := os.WriteFile(hostsFile, []byte("192.168.1.100 hostsfile-100\n192.168.1.101 hostsfile-101\n"), 0644)
+	assert.NoError(t, err)
+
+	overrides := map[string]interface{}{
+		"network_devices.netflow.reverse_dns_enrichment_enabled": true,
+		"reverse_dns_enrichment.static_mappings": []map[string]interface{}{
+			{"ip": "192.168.1.101", "hostname": "static-101"},
+			{"ip": "8.8.8.8", "hostname": "static-public"},
+		},
+		"reverse_dns_enrichment.hosts_file.enabled": true,
+		"reverse_dns_enrichment.hosts_file.path":    hostsFile,
+	}
+	ts := testSetup(t, overrides, true, nil)
+
+	for ip, expectedHostname := range map[[4]byte]string{
+		{192, 168, 1, 100}: "hostsfile-100",
+		{192, 168, 1, 101}: "static-101",
+		{8, 8, 8, 8}:       "static-public",
+	} {
+		var syncHostname string
+		err := ts.rdnsQuerier.GetHostname(
+			ip[:],
+			func(hostname string) {
+				syncHostname = hostname
+			},
+			func(_ string, _ error) {
+				assert.FailNow(t, "Async callback should not be called")
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedHostname, syncHostname)
+	}
+
+	// IP addresses not found in local sources are resolved via DNS
+	var wg sync.WaitGroup
+	wg.Add(1)
+	err = ts.rdnsQuerier.GetHostname(
+		[]byte{192, 168, 1, 102},
+		func(_ string) {
+			assert.FailNow(t, "Sync callback should not be called")
+		},
+		func(hostname string, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, "fakehostname-192.168.1.102", hostname)
+			wg.Done()
+		},
+	)
+	assert.NoError(t, err)
+	wg.Wait()
+
+	expectedTelemetry := ts.makeExpectedTelemetry(map[string]float64{
+		"total":      4.0,
+		"local_hit":  3.0,
+		"private":    1.0,
+		"chan_added": 1.0,
+		"successful": 1.0,
+		"cache_miss": 1.0,
+	})
+	ts.validateExpected(t, expectedTelemetry)
+}
@@ -115,6 +115,7 @@ func (ts *testState) makeExpectedTelemetry(checkTelemetry map[string]float64) ma
 	et := map[string]float64{
 		"total":                   0.0,
 		"private":                 0.0,
+		"local_hit":               0.0,
 		"chan_added":              0.0,
 		"dropped_chan_full":       0.0,
 		"dropped_rate_limiter":    0.0,
@@ -6,6 +6,7 @@
 package rdnsquerierimpl
 
 import (
+	"context"
 	"fmt"
 	"net"
 )
@@ -15,6 +16,9 @@ type resolver interface {
 }
 
 func newResolver(config *rdnsQuerierConfig) resolver {
+	if config.dnsServer.address != "" {
+		return newDNSServerResolver(config)
+	}
 	return &resolverImpl{
 		config: config,
 	}
@@ -27,14 +31,50 @@ type resolverImpl struct {
 
 func (r *resolverImpl) lookup(addr string) (string, error) {
 	// net.LookupAddr() can return both a non-zero length slice of hostnames and an error, but when
-	// using the host C library resolver at most one result will be returned.  So for now, since
-	// specifying other DNS resolvers is not supported, if we get an error we know that no valid
-	// hostname was returned.
+	// using the host C library resolver at most one result will be returned.  So if we get an error
+	// we know that no valid hostname was returned.
 	hostnames, err := net.LookupAddr(addr)
 	if err != nil {
 		return "", err
 	}
 
+	return firstHostname(addr, hostnames)
+}
+
+// Resolver implementation querying the DNS server set in the configuration instead of the system resolver
+type dnsServerResolver struct {
+	config   *rdnsQuerierConfig
+	resolver *net.Resolver
+}
+
+func newDNSServerResolver(config *rdnsQuerierConfig) *dnsServerResolver {
+	return &dnsServerResolver{
+		config: config,
+		resolver: &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: config.dnsServer.timeout}
+				return dialer.DialContext(ctx, network, config.dnsServer.address)
+			},
+		},
+	}
+}
+
+func (r *dnsServerResolver) lookup(addr string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.dnsServer.timeout)
+	defer cancel()
+
+	// The Go resolver can return valid hostnames along with an error when some of the PTR records are malformed,
+	// the valid hostnames are used in that case.
+	hostnames, err := r.resolver.LookupAddr(ctx, addr)
+	if err != nil && len(hostnames) == 0 {
+		return "", err
+	}
+
+	return firstHostname(addr, hostnames)
+}
+
+func firstHostname(addr string, hostnames []string) (string, error) {
 	// if !err then there should be at least one, but just to be safe
 	if len(hostnames) == 0 {
 		return "", fmt.Errorf("net.LookupAddr returned no hostnames for IP address %v", addr)
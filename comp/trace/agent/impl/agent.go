@@ -36,6 +36,7 @@ import (
 	agentrt "github.com/DataDog/datadog-agent/pkg/runtime"
 	pkgagent "github.com/DataDog/datadog-agent/pkg/trace/agent"
 	tracecfg "github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/spanmetrics"
 	"github.com/DataDog/datadog-agent/pkg/trace/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/trace/watchdog"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -92,6 +93,7 @@ type component struct {
 	tagger             tagger.Component
 	telemetryCollector telemetry.TelemetryCollector
 	workloadmeta       workloadmeta.Component
+	spanMetricsStatsd  ddgostatsd.ClientInterface // sends the user defined span metrics, if any
 	wg                 *sync.WaitGroup
 }
 
@@ -131,6 +133,10 @@ func NewAgent(deps dependencies) (traceagent.Component, error) {
 		statsdCl,
 		deps.Compressor,
 	)
+	c.Agent.SpanMetrics, c.spanMetricsStatsd, err = setupSpanMetrics(deps.Statsd, tracecfg)
+	if err != nil {
+		return nil, err
+	}
 
 	deps.Lc.Append(fx.Hook{
 		// Provided contexts have a timeout, so it can't be used for gracefully stopping long-running components.
@@ -235,12 +241,35 @@ func setupMetrics(statsd statsd.Component, cfg config.Component, telemetryCollec
 	return client, nil
 }
 
+// setupSpanMetrics returns the generator of the user defined span metrics and its statsd client, if any
+// span metric is configured. The span metrics are user metrics, their client doesn't carry the tags of
+// the agent's own metrics.
+func setupSpanMetrics(statsd statsd.Component, cfg *tracecfg.AgentConfig) (*spanmetrics.Generator, ddgostatsd.ClientInterface, error) {
+	if len(cfg.SpanMetrics) == 0 {
+		return nil, nil, nil
+	}
+	addr, err := findAddr(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := statsd.CreateForAddr(addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot configure dogstatsd for span metrics: %v", err)
+	}
+	return spanmetrics.NewGenerator(cfg.SpanMetrics, client), client, nil
+}
+
 func stop(ag component) error {
 	ag.cancel()
 	ag.wg.Wait()
 	if err := ag.Statsd.Flush(); err != nil {
 		log.Error("Could not flush statsd: ", err)
 	}
+	if ag.spanMetricsStatsd != nil {
+		if err := ag.spanMetricsStatsd.Flush(); err != nil {
+			log.Error("Could not flush the span metrics statsd client: ", err)
+		}
+	}
 	stopAgentSidekicks(ag.config, ag.Statsd)
 	if ag.params.CPUProfile != "" {
 		pprof.StopCPUProfile()
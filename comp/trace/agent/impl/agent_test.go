@@ -9,9 +9,12 @@ package agentimpl
 import (
 	"testing"
 
+	"github.com/DataDog/datadog-agent/comp/dogstatsd/statsd"
 	"github.com/DataDog/datadog-agent/pkg/trace/config"
 
+	ddgostatsd "github.com/DataDog/datadog-go/v5/statsd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFindAddr(t *testing.T) {
@@ -50,3 +53,34 @@ func TestFindAddr(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+// spanMetricsStatsd is a statsd.Component recording the clients created for an address.
+type spanMetricsStatsd struct {
+	statsd.Component
+	addr    string
+	options []ddgostatsd.Option
+}
+
+func (s *spanMetricsStatsd) CreateForAddr(addr string, options ...ddgostatsd.Option) (ddgostatsd.ClientInterface, error) {
+	s.addr, s.options = addr, options
+	return &ddgostatsd.NoOpClient{}, nil
+}
+
+func TestSetupSpanMetrics(t *testing.T) {
+	cfg := config.New()
+	sc := &spanMetricsStatsd{}
+	g, client, err := setupSpanMetrics(sc, cfg)
+	assert.NoError(t, err)
+	assert.Nil(t, g)
+	assert.Nil(t, client)
+	assert.Empty(t, sc.addr)
+
+	cfg.SpanMetrics = []*config.SpanMetricRule{{Name: "checkout.count"}}
+	g, client, err = setupSpanMetrics(sc, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, g)
+	assert.NotNil(t, client)
+	assert.Equal(t, "localhost:8125", sc.addr)
+	// the span metrics don't carry the tags of the agent's own metrics
+	assert.Empty(t, sc.options)
+}
@@ -58,6 +58,17 @@ func TestParseRepaceRules(t *testing.T) {
 	}
 }
 
+// TestValidateSamplingRules tests the validateSamplingRules helper function.
+func TestValidateSamplingRules(t *testing.T) {
+	assert.NoError(t, validateSamplingRules([]*traceconfig.SamplingRule{
+		{Service: "web", SampleRate: 0},
+		{SampleRate: 1, MaxPerSecond: 100},
+	}))
+	assert.Error(t, validateSamplingRules([]*traceconfig.SamplingRule{{SampleRate: 1.5}}))
+	assert.Error(t, validateSamplingRules([]*traceconfig.SamplingRule{{SampleRate: -0.1}}))
+	assert.Error(t, validateSamplingRules([]*traceconfig.SamplingRule{{SampleRate: 1, MaxPerSecond: -1}}))
+}
+
 // TestSplitTag tests various split-tagging scenarios
 func TestSplitTag(t *testing.T) {
 	for _, tt := range []struct {
@@ -1164,6 +1175,48 @@ func TestLoadEnv(t *testing.T) {
 		assert.Contains(t, cfg.ReplaceTags, rule2)
 	})
 
+	env = "DD_APM_SAMPLING_RULES"
+	t.Run(env, func(t *testing.T) {
+		t.Setenv(env, `[{"service":"web-*","resource":"GET /health","sample_rate":0}, {"service":"db","tags":{"env":"prod"},"sample_rate":0.5,"max_per_second":10}]`)
+
+		c := fxutil.Test[Component](t, fx.Options(
+			corecomp.MockModule(),
+			fx.Replace(corecomp.MockParams{
+				Params: corecomp.Params{ConfFilePath: "./testdata/full.yaml"},
+			}),
+			MockModule(),
+		))
+
+		cfg := c.Object()
+
+		assert.NotNil(t, cfg)
+		assert.Equal(t, []*traceconfig.SamplingRule{
+			{Service: "web-*", Resource: "GET /health", SampleRate: 0},
+			{Service: "db", Tags: map[string]string{"env": "prod"}, SampleRate: 0.5, MaxPerSecond: 10},
+		}, cfg.SamplingRules)
+	})
+
+	env = "DD_APM_SPAN_METRICS"
+	t.Run(env, func(t *testing.T) {
+		t.Setenv(env, `[{"name":"checkout.count"}, {"name":"checkout.value","query":"service:web-store","measure":"metric:value","group_by":["tier"]}]`)
+
+		c := fxutil.Test[Component](t, fx.Options(
+			corecomp.MockModule(),
+			fx.Replace(corecomp.MockParams{
+				Params: corecomp.Params{ConfFilePath: "./testdata/full.yaml"},
+			}),
+			MockModule(),
+		))
+
+		cfg := c.Object()
+
+		assert.NotNil(t, cfg)
+		assert.Equal(t, []*traceconfig.SpanMetricRule{
+			{Name: "checkout.count"},
+			{Name: "checkout.value", Query: "service:web-store", Measure: "metric:value", GroupBy: []string{"tier"}},
+		}, cfg.SpanMetrics)
+	})
+
 	env = "DD_APM_FILTER_TAGS_REQUIRE"
 	t.Run(env, func(t *testing.T) {
 		t.Setenv(env, `important1 important2:value1`)
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -234,6 +235,8 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 	if core.IsSet("apm_config.peer_tags") {
 		c.PeerTags = core.GetStringSlice("apm_config.peer_tags")
 	}
+	c.ExtraAggregationTags = core.GetStringSlice("apm_config.extra_aggregation_tags")
+	c.ExtraAggregationTagsCardinalityLimit = core.GetInt("apm_config.extra_aggregation_tags_cardinality_limit")
 
 	if core.IsSet("apm_config.extra_sample_rate") {
 		c.ExtraSampleRate = core.GetFloat64("apm_config.extra_sample_rate")
@@ -277,6 +280,17 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 	if core.IsSet("apm_config.max_remote_traces_per_second") {
 		c.MaxRemoteTPS = core.GetFloat64("apm_config.max_remote_traces_per_second")
 	}
+	if k := "apm_config.sampling_rules"; core.IsSet(k) {
+		rules := make([]*config.SamplingRule, 0)
+		if err := structure.UnmarshalKey(core, k, &rules); err != nil {
+			log.Errorf("Bad format for %q it should be of the form '[{\"service\": \"pattern\",\"sample_rate\":0.5}]', error: %v", k, err)
+		} else {
+			if err := validateSamplingRules(rules); err != nil {
+				return fmt.Errorf("sampling_rules: %s", err)
+			}
+			c.SamplingRules = rules
+		}
+	}
 	if k := "apm_config.features"; core.IsSet(k) {
 		feats := core.GetStringSlice(k)
 		for _, f := range feats {
@@ -324,6 +338,15 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 		}
 	}
 
+	if k := "apm_config.span_metrics"; core.IsSet(k) {
+		rules := make([]*config.SpanMetricRule, 0)
+		if err := structure.UnmarshalKey(core, k, &rules); err != nil {
+			log.Errorf("Bad format for %q it should be of the form '[{\"name\": \"metric_name\",\"query\":\"key:value\",\"measure\":\"count\",\"group_by\":[\"tag\"]}]', error: %v", k, err)
+		} else {
+			c.SpanMetrics = rules
+		}
+	}
+
 	if core.IsSet("bind_host") || core.IsSet("apm_config.apm_non_local_traffic") {
 		if core.IsSet("bind_host") {
 			host := core.GetString("bind_host")
@@ -367,6 +390,9 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 		ProbabilisticSampling:  core.GetFloat64("otlp_config.traces.probabilistic_sampler.sampling_percentage"),
 		AttributesTranslator:   attributesTranslator,
 	}
+	c.ZipkinReceiver.Enabled = core.GetBool("apm_config.zipkin.enabled")
+	c.JaegerReceiver.Enabled = core.GetBool("apm_config.jaeger.enabled")
+	c.JaegerReceiver.GRPCPort = core.GetInt("apm_config.jaeger.grpc_port")
 
 	if core.IsSet("apm_config.install_id") {
 		c.InstallSignature.Found = true
@@ -536,6 +562,13 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 			log.Errorf("Error reading writer config %q: %v", key, err)
 		}
 	}
+	c.Spool.Enabled = core.GetBool("apm_config.spool.enabled")
+	c.Spool.Path = core.GetString("apm_config.spool.path")
+	if c.Spool.Path == "" {
+		c.Spool.Path = filepath.Join(core.GetString("run_path"), "apm-spool")
+	}
+	c.Spool.MaxSize = int64(core.GetInt("apm_config.spool.max_size_mb")) * 1024 * 1024
+	c.Spool.MaxAge = time.Duration(core.GetInt("apm_config.spool.max_age_seconds")) * time.Second
 	if core.IsSet("apm_config.connection_reset_interval") {
 		c.ConnectionResetInterval = getDuration(core.GetInt("apm_config.connection_reset_interval"))
 	}
@@ -655,6 +688,8 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 		c.EVPProxy.ReceiverTimeout = core.GetInt(k)
 	}
 	c.DebugServerPort = core.GetInt("apm_config.debug.port")
+	c.TraceInspection.Enabled = core.GetBool("apm_config.debug.trace_inspection.enabled")
+	c.TraceInspection.BufferSize = core.GetInt("apm_config.debug.trace_inspection.buffer_size")
 	return nil
 }
 
@@ -722,6 +757,20 @@ func compileReplaceRules(rules []*config.ReplaceRule) error {
 	return nil
 }
 
+// validateSamplingRules checks the sample rates and limits of the sampling rules.
+// If it fails it returns the first error.
+func validateSamplingRules(rules []*config.SamplingRule) error {
+	for i, r := range rules {
+		if r.SampleRate < 0 || r.SampleRate > 1 {
+			return fmt.Errorf("rule %d: sample_rate must be between 0 and 1, got %v", i, r.SampleRate)
+		}
+		if r.MaxPerSecond < 0 {
+			return fmt.Errorf("rule %d: max_per_second must not be negative, got %v", i, r.MaxPerSecond)
+		}
+	}
+	return nil
+}
+
 // getDuration returns the duration of the provided value in seconds
 func getDuration(seconds int) time.Duration {
 	return time.Duration(seconds) * time.Second
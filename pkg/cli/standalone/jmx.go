@@ -9,6 +9,8 @@ package standalone
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/DataDog/datadog-agent/comp/agent/jmxlogger"
@@ -60,6 +62,78 @@ func execJmxCommand(command string,
 	agentAPI internalAPI.Component,
 	logger jmxlogger.Component) error {
 
+	processes := jmxfetch.GroupConfigsByProcess(loadJMXConfigs(selectedChecks, configs))
+	if len(processes) <= 1 {
+		var process string
+		for name := range processes {
+			process = name
+		}
+		if err := runJmxCommand(process, processes[process], command, reporter, output, logLevel, agentAPI, logger); err != nil {
+			return err
+		}
+
+		fmt.Printf(
+			"JMXFetch exited successfully. If nothing was displayed please check your configuration and flags, "+
+				"or re-run the command with a more verbose log level (current log level: '%s').\n",
+			logLevel,
+		)
+		return nil
+	}
+
+	// JMX integrations run in several JMXFetch processes, each process is run in turn
+	names := make([]string, 0, len(processes))
+	for name := range processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failures := map[string]error{}
+	for _, name := range names {
+		if reporter == jmxfetch.ReporterConsole {
+			fmt.Printf("\n=== JMXFetch process %s ===\n", name)
+		}
+		if err := runJmxCommand(name, processes[name], command, reporter, output, logLevel, agentAPI, logger); err != nil {
+			failures[name] = err
+		}
+	}
+
+	if reporter != jmxfetch.ReporterConsole {
+		// don't pollute the JSON output
+		if len(failures) > 0 {
+			return fmt.Errorf("%d out of %d JMXFetch processes failed", len(failures), len(names))
+		}
+		return nil
+	}
+
+	fmt.Println("\nJMXFetch processes:")
+	for _, name := range names {
+		if err, ok := failures[name]; ok {
+			fmt.Printf("  %s: failed: %v\n", name, err)
+		} else {
+			fmt.Printf("  %s: exited successfully\n", name)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d out of %d JMXFetch processes failed", len(failures), len(names))
+	}
+	fmt.Printf(
+		"If nothing was displayed please check your configuration and flags, "+
+			"or re-run the command with a more verbose log level (current log level: '%s').\n",
+		logLevel,
+	)
+	return nil
+}
+
+// runJmxCommand runs a JMXFetch process on the configs it runs, and waits for it to exit
+func runJmxCommand(process string,
+	configs []integration.Config,
+	command string,
+	reporter jmxfetch.JMXReporter,
+	output func(...interface{}),
+	logLevel string,
+	agentAPI internalAPI.Component,
+	logger jmxlogger.Component) error {
+
 	runner := jmxfetch.NewJMXFetch(logger)
 
 	runner.Reporter = reporter
@@ -67,53 +141,70 @@ func execJmxCommand(command string,
 	runner.IPCPort = agentAPI.CMDServerAddress().Port
 	runner.Output = output
 	runner.LogLevel = logLevel
+	runner.ProcessName = process
 
-	loadJMXConfigs(runner, selectedChecks, configs)
+	for _, c := range configs {
+		runner.ConfigureFromInitConfig(c.InitConfig) //nolint:errcheck
+		for _, instance := range c.Instances {
+			runner.ConfigureFromInstance(instance) //nolint:errcheck
+		}
+	}
 
-	err := runner.Start(false)
-	if err != nil {
-		return err
+	if process != "" {
+		// the process loads its configs from files, kept apart from the ones of the running Agent
+		dir, err := os.MkdirTemp("", "jmxfetch-"+process)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		if err := runner.SetConfigFiles(dir, configs); err != nil {
+			return err
+		}
 	}
 
-	err = runner.Wait()
+	err := runner.Start(false)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf(
-		"JMXFetch exited successfully. If nothing was displayed please check your configuration and flags, "+
-			"or re-run the command with a more verbose log level (current log level: '%s').\n",
-		logLevel,
-	)
-	return nil
+	return runner.Wait()
 }
 
-func loadJMXConfigs(runner *jmxfetch.JMXFetch, selectedChecks []string, configs []integration.Config) {
+// loadJMXConfigs adds the selected JMX configs to the ones pulled by JMXFetch, and returns them. Configs are
+// split by JMXFetch process, retaining only JMX instances.
+func loadJMXConfigs(selectedChecks []string, configs []integration.Config) []integration.Config {
 	fmt.Println("Loading configs...")
 
 	includeEverything := len(selectedChecks) == 0
 
+	var loaded []integration.Config
 	for _, c := range configs {
 		if check.IsJMXConfig(c) && (includeEverything || configIncluded(c, selectedChecks)) {
 			fmt.Println("Config ", c.Name, " was loaded.")
-			instances := []integration.Data{}
 
-			// Retain only JMX instances
+			// Retain only JMX instances, grouped by the JMXFetch process running them
+			instancesConfigs := []integration.Config{}
 			for _, instance := range c.Instances {
 				if !check.IsJMXInstance(c.Name, instance, c.InitConfig) {
 					continue
 				}
-				instances = append(instances, instance)
+				instanceConfig := c
+				instanceConfig.Instances = []integration.Data{instance}
+				instancesConfigs = append(instancesConfigs, instanceConfig)
 			}
-			c.Instances = instances
 
-			jmxfetch.AddScheduledConfig(c)
-			runner.ConfigureFromInitConfig(c.InitConfig) //nolint:errcheck
-			for _, instance := range c.Instances {
-				runner.ConfigureFromInstance(instance) //nolint:errcheck
+			for _, processConfigs := range jmxfetch.GroupConfigsByProcess(instancesConfigs) {
+				processConfig := c
+				processConfig.Instances = []integration.Data{}
+				for _, instanceConfig := range processConfigs {
+					processConfig.Instances = append(processConfig.Instances, instanceConfig.Instances...)
+				}
+				jmxfetch.AddScheduledConfig(processConfig)
+				loaded = append(loaded, processConfig)
 			}
 		}
 	}
+	return loaded
 }
 
 func configIncluded(config integration.Config, selectedChecks []string) bool {
@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -121,6 +122,9 @@ type cliParams struct {
 	discoveryRetryInterval    uint
 	discoveryMinInstances     uint
 	generateIntegrationTraces bool
+	recordPath                string
+	replayPath                string
+	goldenPath                string
 }
 
 // GlobalParams contains the values of agent-global Cobra flags.
@@ -230,6 +234,9 @@ func MakeCommand(globalParamsGetter func() GlobalParams) *cobra.Command {
 	cmd.Flags().UintVarP(&cliParams.discoveryTimeout, "discovery-timeout", "", 5, "max retry duration until Autodiscovery resolves the check template (in seconds)")
 	cmd.Flags().UintVarP(&cliParams.discoveryRetryInterval, "discovery-retry-interval", "", 1, "(unused)")
 	cmd.Flags().UintVarP(&cliParams.discoveryMinInstances, "discovery-min-instances", "", 1, "minimum number of config instances to be discovered before running the check(s)")
+	cmd.Flags().StringVar(&cliParams.recordPath, "record", "", "record the HTTP requests made by the check and their responses to a file")
+	cmd.Flags().StringVar(&cliParams.replayPath, "replay", "", "serve the HTTP requests made by the check from a file recorded with --record instead of reaching the network")
+	cmd.Flags().StringVar(&cliParams.goldenPath, "golden", "", "compare the metrics, service checks and events collected with a golden file, the file is written instead when used with --record")
 
 	// Power user flags - mark as hidden
 	createHiddenStringFlag(cmd, &cliParams.profileMemoryDir, "m-dir", "", "an existing directory in which to store memory profiling data, ignoring clean-up")
@@ -286,6 +293,22 @@ func run(
 		return nil
 	}
 
+	if cliParams.goldenPath != "" && (cliParams.formatJSON || cliParams.formatTable || cliParams.profileMemory) {
+		return errors.New("--golden cannot be used with --json, --table or --profile-memory")
+	}
+
+	if cliParams.recordPath != "" || cliParams.replayPath != "" {
+		recording, err := startHTTPRecording(cliParams, pkgconfigsetup.Datadog())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := recording.stop(); err != nil {
+				fmt.Println(err)
+			}
+		}()
+	}
+
 	// TODO: (components) - Until the checks are components we set there context so they can depends on components.
 	check.InitializeInventoryChecksContext(invChecks)
 	pkgcollector.InitPython(common.GetPythonPaths()...)
@@ -459,6 +482,7 @@ func run(
 
 	var checkFileOutput bytes.Buffer
 	var instancesData []interface{}
+	var goldenResults []string
 	printer := aggregator.AgentDemultiplexerPrinter{DemultiplexerWithAggregator: demultiplexer}
 	data, err := statusComponent.GetStatusBySections([]string{status.CollectorSection}, "json", false)
 
@@ -558,7 +582,11 @@ func run(
 				return fmt.Errorf("no diff data found in %s", profileDataDir)
 			}
 		} else {
-			printer.PrintMetrics(&checkFileOutput, cliParams.formatTable)
+			if cliParams.goldenPath != "" {
+				goldenResults = append(goldenResults, collectCheckResults(printer.Aggregator())...)
+			} else {
+				printer.PrintMetrics(&checkFileOutput, cliParams.formatTable)
+			}
 
 			p := func(data string) {
 				fmt.Println(data)
@@ -621,6 +649,18 @@ func run(
 		pkgconfigsetup.Datadog().Set("integration_tracing_exhaustive", previousIntegrationTracingExhaustive, model.SourceAgentRuntime)
 	}
 
+	if cliParams.goldenPath != "" {
+		sort.Strings(goldenResults)
+		if cliParams.recordPath != "" {
+			if err := writeGolden(cliParams.goldenPath, goldenResults); err != nil {
+				return err
+			}
+			fmt.Printf("%d check results written to %s\n", len(goldenResults), cliParams.goldenPath)
+		} else if err := compareGolden(os.Stdout, cliParams.goldenPath, goldenResults); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -44,3 +44,27 @@ func TestCommand(t *testing.T) {
 			require.Equal(t, true, secretParams.Enabled)
 		})
 }
+
+func TestCommandReplay(t *testing.T) {
+	commands := []*cobra.Command{
+		MakeCommand(func() GlobalParams {
+			config := path.Join(t.TempDir(), "datadog.yaml")
+			err := os.WriteFile(config, []byte("hostname: test"), 0644)
+			require.NoError(t, err)
+
+			return GlobalParams{
+				ConfFilePath: config,
+			}
+		}),
+	}
+
+	fxutil.TestOneShotSubcommand(t,
+		commands,
+		[]string{"check", "cleopatra", "--replay", "cassette.json", "--golden", "golden.txt"},
+		run,
+		func(cliParams *cliParams, _ core.BundleParams) {
+			require.Equal(t, "cassette.json", cliParams.replayPath)
+			require.Equal(t, "golden.txt", cliParams.goldenPath)
+			require.Empty(t, cliParams.recordPath)
+		})
+}
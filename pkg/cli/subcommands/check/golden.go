@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+// collectCheckResults flushes the aggregator and returns the collected results, see checkResults
+func collectCheckResults(agg *aggregator.BufferedAggregator) []string {
+	series, sketches := agg.GetSeriesAndSketches(time.Now())
+	return checkResults(series, sketches, agg.GetServiceChecks(), agg.GetEvents())
+}
+
+// checkResults returns one line per metric, service check and event collected, sorted so that results can be
+// compared between runs. Timestamps and hostnames are left out since they differ between runs.
+func checkResults(series metrics.Series, sketches metrics.SketchSeriesList, serviceChecks servicecheck.ServiceChecks, events event.Events) []string {
+	var results []string
+
+	for _, serie := range series {
+		values := make([]string, 0, len(serie.Points))
+		for _, point := range serie.Points {
+			values = append(values, strconv.FormatFloat(point.Value, 'g', -1, 64))
+		}
+		results = append(results, fmt.Sprintf("metric %s type=%s values=%s tags=%s",
+			serie.Name, serie.MType, strings.Join(values, ","), joinTags(serie.Tags.UnsafeToReadOnlySliceString())))
+	}
+
+	for _, sketch := range sketches {
+		for _, point := range sketch.Points {
+			results = append(results, fmt.Sprintf("sketch %s count=%d sum=%s tags=%s",
+				sketch.Name, point.Sketch.Basic.Cnt, strconv.FormatFloat(point.Sketch.Basic.Sum, 'g', -1, 64),
+				joinTags(sketch.Tags.UnsafeToReadOnlySliceString())))
+		}
+	}
+
+	for _, sc := range serviceChecks {
+		results = append(results, fmt.Sprintf("service_check %s status=%d tags=%s", sc.CheckName, sc.Status, joinTags(sc.Tags)))
+	}
+
+	for _, e := range events {
+		results = append(results, fmt.Sprintf("event %q alert_type=%s tags=%s", e.Title, e.AlertType, joinTags(e.Tags)))
+	}
+
+	sort.Strings(results)
+	return results
+}
+
+func joinTags(tags []string) string {
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// writeGolden writes the check results to a golden file
+func writeGolden(path string, results []string) error {
+	var b bytes.Buffer
+	for _, result := range results {
+		b.WriteString(result + "\n")
+	}
+	return os.WriteFile(path, b.Bytes(), 0644)
+}
+
+// compareGolden prints the differences between the check results and a golden file, and returns an error when
+// they differ
+func compareGolden(w io.Writer, path string, results []string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to read the golden file: %w", err)
+	}
+	defer f.Close()
+
+	var expected []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			expected = append(expected, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("unable to read the golden file: %w", err)
+	}
+
+	missing, unexpected := diffResults(expected, results)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		fmt.Fprintf(w, "Check results match the golden file %s\n", path)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Check results differ from the golden file %s:\n", path)
+	for _, result := range missing {
+		fmt.Fprintf(w, "- %s\n", result)
+	}
+	for _, result := range unexpected {
+		fmt.Fprintf(w, "+ %s\n", result)
+	}
+	return fmt.Errorf("%d missing and %d unexpected check results", len(missing), len(unexpected))
+}
+
+// diffResults returns the expected results that are missing and the unexpected ones, duplicates are counted
+func diffResults(expected []string, actual []string) (missing []string, unexpected []string) {
+	counts := make(map[string]int, len(expected))
+	for _, result := range expected {
+		counts[result]++
+	}
+	for _, result := range actual {
+		if counts[result] > 0 {
+			counts[result]--
+			continue
+		}
+		unexpected = append(unexpected, result)
+	}
+	for _, result := range expected {
+		if counts[result] > 0 {
+			counts[result]--
+			missing = append(missing, result)
+		}
+	}
+	return missing, unexpected
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/tagset"
+)
+
+func TestCheckResults(t *testing.T) {
+	series := metrics.Series{
+		{Name: "redis.net.clients", MType: metrics.APIGaugeType, Host: "host1", Points: []metrics.Point{{Ts: 1, Value: 12}}, Tags: tagset.CompositeTagsFromSlice([]string{"port:6379", "env:prod"})},
+		{Name: "redis.net.commands", MType: metrics.APIRateType, Points: []metrics.Point{{Ts: 1, Value: 0.5}, {Ts: 2, Value: 1.5}}},
+	}
+	serviceChecks := servicecheck.ServiceChecks{
+		{CheckName: "redis.can_connect", Host: "host1", Ts: 10, Status: servicecheck.ServiceCheckCritical, Tags: []string{"port:6379"}},
+	}
+	events := event.Events{
+		{Title: "Redis restarted", Ts: 10, AlertType: event.AlertTypeError, Tags: []string{"b", "a"}},
+	}
+
+	assert.Equal(t, []string{
+		`event "Redis restarted" alert_type=error tags=a,b`,
+		"metric redis.net.clients type=gauge values=12 tags=env:prod,port:6379",
+		"metric redis.net.commands type=rate values=0.5,1.5 tags=",
+		"service_check redis.can_connect status=2 tags=port:6379",
+	}, checkResults(series, nil, serviceChecks, events))
+}
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.txt")
+	results := []string{"metric a type=gauge values=1 tags=", "metric b type=gauge values=2 tags="}
+	require.NoError(t, writeGolden(path, results))
+
+	var out bytes.Buffer
+	require.NoError(t, compareGolden(&out, path, results))
+	assert.Contains(t, out.String(), "match the golden file")
+
+	out.Reset()
+	err := compareGolden(&out, path, []string{"metric a type=gauge values=1 tags=", "metric b type=gauge values=3 tags=", "metric b type=gauge values=3 tags="})
+	assert.EqualError(t, err, "1 missing and 2 unexpected check results")
+	assert.Contains(t, out.String(), "- metric b type=gauge values=2 tags=\n")
+	assert.Contains(t, out.String(), "+ metric b type=gauge values=3 tags=\n")
+
+	assert.Error(t, compareGolden(&out, filepath.Join(t.TempDir(), "missing.txt"), results))
+}
+
+func TestDiffResults(t *testing.T) {
+	missing, unexpected := diffResults([]string{"a", "a", "b"}, []string{"a", "c"})
+	assert.Equal(t, []string{"a", "b"}, missing)
+	assert.Equal(t, []string{"c"}, unexpected)
+}
@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/DataDog/datadog-agent/pkg/config/model"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/http/recorder"
+)
+
+// caBundleEnvVar is the environment variable setting the certificate authorities trusted by the Python requests
+// library
+const caBundleEnvVar = "REQUESTS_CA_BUNDLE"
+
+// httpRecording records the HTTP requests made by the checks and their responses, or replays them.
+//
+// Core checks are covered through the transports created by pkg/util/http. Python checks are covered through
+// their HTTP wrapper, which uses the Agent proxy settings: they are pointed to a local proxy intercepting HTTPS
+// connections with a certificate authority trusted through REQUESTS_CA_BUNDLE.
+type httpRecording struct {
+	recorder     *recorder.Recorder
+	proxy        *recorder.Proxy
+	caFile       string
+	cassettePath string
+
+	// previous value of REQUESTS_CA_BUNDLE, restored when stopping
+	previousCABundle    string
+	hadPreviousCABundle bool
+}
+
+// startHTTPRecording starts the record or replay mode, it must be called before Python is initialized for the
+// environment to be visible to Python checks
+func startHTTPRecording(cliParams *cliParams, cfg model.Config) (*httpRecording, error) {
+	if cliParams.recordPath != "" && cliParams.replayPath != "" {
+		return nil, errors.New("--record and --replay cannot be used together")
+	}
+
+	h := &httpRecording{}
+	if cliParams.recordPath != "" {
+		// the upstream transport is created before overriding the proxy settings, for the requests to reach
+		// their targets through the configured proxy
+		h.recorder = recorder.NewRecorder(httputils.CreateHTTPTransport(cfg))
+		h.cassettePath = cliParams.recordPath
+	} else {
+		cassette, err := recorder.LoadCassette(cliParams.replayPath)
+		if err != nil {
+			return nil, err
+		}
+		h.recorder = recorder.NewReplayer(cassette)
+	}
+
+	proxy, err := recorder.NewProxy(h.recorder)
+	if err != nil {
+		return nil, err
+	}
+	h.proxy = proxy
+
+	caFile, err := os.CreateTemp("", "datadog-agent-check-ca-*.pem")
+	if err != nil {
+		return nil, err
+	}
+	h.caFile = caFile.Name()
+	_, err = caFile.Write(proxy.CACertificatePEM())
+	caFile.Close()
+	if err != nil {
+		os.Remove(h.caFile)
+		return nil, err
+	}
+
+	proxy.Start()
+	httputils.SetTransportHook(h.recorder.Hook)
+	cfg.Set("proxy.http", proxy.URL(), model.SourceAgentRuntime)
+	cfg.Set("proxy.https", proxy.URL(), model.SourceAgentRuntime)
+	cfg.Set("proxy.no_proxy", []string{}, model.SourceAgentRuntime)
+	h.previousCABundle, h.hadPreviousCABundle = os.LookupEnv(caBundleEnvVar)
+	os.Setenv(caBundleEnvVar, h.caFile)
+
+	return h, nil
+}
+
+// stop stops the record or replay mode, and saves the recorded requests
+func (h *httpRecording) stop() error {
+	httputils.SetTransportHook(nil)
+	h.proxy.Stop() //nolint:errcheck
+	os.Remove(h.caFile)
+	if h.hadPreviousCABundle {
+		os.Setenv(caBundleEnvVar, h.previousCABundle)
+	} else {
+		os.Unsetenv(caBundleEnvVar)
+	}
+
+	if h.recorder.Mode() != recorder.ModeRecord {
+		return nil
+	}
+	if err := h.recorder.Cassette().Save(h.cassettePath); err != nil {
+		return fmt.Errorf("unable to save the recorded HTTP requests: %w", err)
+	}
+	fmt.Printf("%d HTTP requests recorded to %s\n", len(h.recorder.Cassette().Interactions), h.cassettePath)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func TestHTTPRecordingRestoresCABundle(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		set      bool
+	}{
+		{name: "set", previous: "/etc/ssl/custom.pem", set: true},
+		{name: "unset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.set {
+				t.Setenv(caBundleEnvVar, tt.previous)
+			} else {
+				// registers the restoration of the current value
+				t.Setenv(caBundleEnvVar, "")
+				os.Unsetenv(caBundleEnvVar)
+			}
+
+			cliParams := &cliParams{recordPath: filepath.Join(t.TempDir(), "cassette.json")}
+			recording, err := startHTTPRecording(cliParams, configmock.New(t))
+			require.NoError(t, err)
+			assert.Equal(t, recording.caFile, os.Getenv(caBundleEnvVar))
+
+			require.NoError(t, recording.stop())
+			value, found := os.LookupEnv(caBundleEnvVar)
+			assert.Equal(t, tt.set, found)
+			assert.Equal(t, tt.previous, value)
+			assert.NoFileExists(t, recording.caFile)
+		})
+	}
+}
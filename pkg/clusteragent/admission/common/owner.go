@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Owner is the object owning a pod
+type Owner struct {
+	Name            string
+	Namespace       string
+	Kind            string
+	Labels          map[string]string
+	OwnerReferences []metav1.OwnerReference
+}
+
+// OwnerInfo wraps the information needed to get pod's owner object
+type OwnerInfo struct {
+	GVR  schema.GroupVersionResource
+	Name string
+}
+
+// BuildID returns a unique identifier for the OwnerInfo object
+func (o *OwnerInfo) BuildID(ns string) string {
+	return fmt.Sprintf("%s/%s/%s", ns, o.Name, o.GVR.String())
+}
+
+// GetOwnerInfo returns the required information to get the owner object
+func GetOwnerInfo(owner metav1.OwnerReference) (*OwnerInfo, error) {
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &OwnerInfo{
+		GVR:  gv.WithResource(fmt.Sprintf("%ss", strings.ToLower(owner.Kind))),
+		Name: owner.Name,
+	}, nil
+}
+
+// GetOwner returns the object of the pod's owner, owner objects are cached for cacheTTL.
+// If the owner is a replicaset it returns the corresponding deployment
+func GetOwner(owner metav1.OwnerReference, ns string, dc dynamic.Interface, cacheTTL time.Duration) (*Owner, error) {
+	ownerInfo, err := GetOwnerInfo(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := GetAndCacheOwner(ownerInfo, ns, dc, cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.Kind == "ReplicaSet" && len(obj.OwnerReferences) > 0 {
+		rsOwnerInfo, err := GetOwnerInfo(obj.OwnerReferences[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return GetAndCacheOwner(rsOwnerInfo, ns, dc, cacheTTL)
+	}
+
+	return obj, nil
+}
+
+// GetAndCacheOwner tries to fetch the owner object from cache before querying the api server
+func GetAndCacheOwner(info *OwnerInfo, ns string, dc dynamic.Interface, cacheTTL time.Duration) (*Owner, error) {
+	infoID := info.BuildID(ns)
+	if cachedObj, hit := cache.Cache.Get(infoID); hit {
+		metrics.GetOwnerCacheHit.Inc(info.GVR.Resource)
+		owner, valid := cachedObj.(*Owner)
+		if !valid {
+			log.Debugf("Invalid owner object for '%s', forcing a cache miss", infoID)
+		} else {
+			return owner, nil
+		}
+	}
+
+	log.Tracef("Cache miss while getting owner '%s'", infoID)
+	metrics.GetOwnerCacheMiss.Inc(info.GVR.Resource)
+	ownerObj, err := dc.Resource(info.GVR).Namespace(ns).Get(context.TODO(), info.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	owner := &Owner{
+		Name:            ownerObj.GetName(),
+		Kind:            ownerObj.GetKind(),
+		Namespace:       ownerObj.GetNamespace(),
+		Labels:          ownerObj.GetLabels(),
+		OwnerReferences: ownerObj.GetOwnerReferences(),
+	}
+
+	cache.Cache.Set(infoID, owner, cacheTTL)
+	return owner, nil
+}
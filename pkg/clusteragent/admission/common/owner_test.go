@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+func Test_GetOwnerInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		owner   metav1.OwnerReference
+		want    *OwnerInfo
+		wantErr bool
+	}{
+		{
+			name: "replicaset",
+			owner: metav1.OwnerReference{
+				APIVersion:         "apps/v1",
+				BlockOwnerDeletion: pointer.Ptr(true),
+				Controller:         pointer.Ptr(true),
+				Kind:               "ReplicaSet",
+				Name:               "my-app-547c56f566",
+				UID:                "2dfa7d22-245f-4769-8854-bc3b056cd224",
+			},
+			want: &OwnerInfo{
+				Name: "my-app-547c56f566",
+				GVR: schema.GroupVersionResource{
+					Group:    "apps",
+					Version:  "v1",
+					Resource: "replicasets",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "job",
+			owner: metav1.OwnerReference{
+				APIVersion:         "batch/v1",
+				BlockOwnerDeletion: pointer.Ptr(true),
+				Controller:         pointer.Ptr(true),
+				Kind:               "Job",
+				Name:               "my-job",
+				UID:                "89e8148c-8601-4c69-b8a6-3fbb176547d0",
+			},
+			want: &OwnerInfo{
+				Name: "my-job",
+				GVR: schema.GroupVersionResource{
+					Group:    "batch",
+					Version:  "v1",
+					Resource: "jobs",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid APIVersion",
+			owner: metav1.OwnerReference{
+				APIVersion:         "batch/v1/",
+				BlockOwnerDeletion: pointer.Ptr(true),
+				Controller:         pointer.Ptr(true),
+				Kind:               "Job",
+				Name:               "my-job",
+				UID:                "89e8148c-8601-4c69-b8a6-3fbb176547d0",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOwnerInfo(tt.owner)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetOwnerInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOwnerInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+const (
+	testGroup      = "testgroup"
+	testVersion    = "testversion"
+	testResource   = "testkinds"
+	testNamespace  = "testns"
+	testName       = "testname"
+	testKind       = "TestKind"
+	testAPIVersion = "testgroup/testversion"
+)
+
+func TestGetAndCacheOwner(t *testing.T) {
+	ownerInfo := dummyInfo()
+	kubeObj := newUnstructuredWithSpec(map[string]interface{}{"foo": "bar"})
+	owner := newOwner(kubeObj)
+
+	// Cache hit
+	cache.Cache.Set(ownerInfo.BuildID(testNamespace), owner, time.Minute)
+	dc := fake.NewSimpleDynamicClient(kscheme.Scheme)
+	obj, err := GetAndCacheOwner(ownerInfo, testNamespace, dc, time.Minute)
+	assert.NoError(t, err)
+	assert.NotNil(t, obj)
+	assert.Equal(t, owner, obj)
+	assert.Len(t, dc.Actions(), 0)
+	cache.Cache.Flush()
+
+	// Cache miss
+	dc = fake.NewSimpleDynamicClient(kscheme.Scheme, kubeObj)
+	obj, err = GetAndCacheOwner(ownerInfo, testNamespace, dc, time.Minute)
+	assert.NoError(t, err)
+	assert.NotNil(t, obj)
+	assert.Equal(t, owner, obj)
+	assert.Len(t, dc.Actions(), 1)
+	cachedObj, found := cache.Cache.Get(ownerInfo.BuildID(testNamespace))
+	assert.True(t, found)
+	assert.NotNil(t, cachedObj)
+}
+
+func dummyInfo() *OwnerInfo {
+	return &OwnerInfo{
+		Name: testName,
+		GVR: schema.GroupVersionResource{
+			Group:    testGroup,
+			Resource: testResource,
+			Version:  testVersion,
+		},
+	}
+}
+
+func newOwner(obj *unstructured.Unstructured) *Owner {
+	return &Owner{
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		Kind:            obj.GetKind(),
+		Labels:          obj.GetLabels(),
+		OwnerReferences: obj.GetOwnerReferences(),
+	}
+}
+
+func newUnstructured(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+			},
+		},
+	}
+}
+
+func newUnstructuredWithSpec(spec map[string]interface{}) *unstructured.Unstructured {
+	u := newUnstructured(testAPIVersion, testKind, testNamespace, testName)
+	u.Object["spec"] = spec
+	return u
+}
@@ -31,6 +31,7 @@ import (
 	configWebhook "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/config"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/cwsinstrumentation"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/tagsfromlabels"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/validate/workloadconfig"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -100,8 +101,9 @@ func (c *controllerBase) generateWebhooks(wmeta workloadmeta.Component, pa workl
 
 	// Add Validating webhooks.
 	if c.config.isValidationEnabled() {
-		// Future validating webhooks can be added here.
-		validatingWebhooks = []Webhook{}
+		validatingWebhooks = []Webhook{
+			workloadconfig.NewWebhook(),
+		}
 		webhooks = append(webhooks, validatingWebhooks...)
 	}
 
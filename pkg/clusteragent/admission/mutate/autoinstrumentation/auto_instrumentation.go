@@ -158,8 +158,38 @@ func (w *Webhook) LabelSelectors(useNamespaceSelector bool) (namespaceSelector *
 // WebhookFunc returns the function that mutates the resources
 func (w *Webhook) WebhookFunc() admission.WebhookFunc {
 	return func(request *admission.Request) *admiv1.AdmissionResponse {
-		return common.MutationResponse(mutatecommon.Mutate(request.Raw, request.Namespace, w.Name(), w.inject, request.DynamicClient))
+		return common.MutationResponse(mutatecommon.Mutate(request, w.Name(), func(pod *corev1.Pod, ns string, dc dynamic.Interface) (bool, error) {
+			return w.inject(pod, ns, dc, request.DryRun)
+		}))
+	}
+}
+
+// SkipReason returns why the webhook wouldn't mutate the pod, or an empty
+// string if it would
+func (w *Webhook) SkipReason(pod *corev1.Pod, _ string) string {
+	if reason := w.skipReason(pod); reason != "" {
+		return reason
+	}
+	if len(w.extractLibInfo(pod).libs) == 0 {
+		return "no library to inject, none is set by the pod annotations, the configuration or language detection"
 	}
+	return ""
+}
+
+// skipReason returns why the pod isn't eligible for injection, or an empty
+// string if it is
+func (w *Webhook) skipReason(pod *corev1.Pod) string {
+	if reason := w.injectionFilter.SkipReason(pod); reason != "" {
+		return reason
+	}
+
+	for _, lang := range supportedLanguages {
+		if containsInitContainer(pod, initContainerName(lang)) {
+			// The admission can be re-run for the same pod
+			return fmt.Sprintf("init container %q already exists, the library was already injected", initContainerName(lang))
+		}
+	}
+	return ""
 }
 
 func initContainerName(lang language) string {
@@ -177,7 +207,9 @@ func (w *Webhook) isEnabledInNamespace(namespace string) bool {
 	return w.injectionFilter.NSFilter.IsNamespaceEligible(namespace)
 }
 
-func (w *Webhook) inject(pod *corev1.Pod, ns string, _ dynamic.Interface) (bool, error) {
+// inject injects the APM libraries into the pod. The injection attempts aren't
+// counted for dry-run requests.
+func (w *Webhook) inject(pod *corev1.Pod, ns string, _ dynamic.Interface, dryRun bool) (bool, error) {
 	if pod == nil {
 		return false, errors.New(metrics.InvalidInput)
 	}
@@ -186,19 +218,12 @@ func (w *Webhook) inject(pod *corev1.Pod, ns string, _ dynamic.Interface) (bool,
 	}
 	injectApmTelemetryConfig(pod)
 
-	if !w.isPodEligible(pod) {
+	if reason := w.skipReason(pod); reason != "" {
+		// Fast return if we injected the library already
+		log.Debugf("Skipping pod %q: %s", mutatecommon.PodString(pod), reason)
 		return false, nil
 	}
 
-	for _, lang := range supportedLanguages {
-		if containsInitContainer(pod, initContainerName(lang)) {
-			// The admission can be re-run for the same pod
-			// Fast return if we injected the library already
-			log.Debugf("Init container %q already exists in pod %q", initContainerName(lang), mutatecommon.PodString(pod))
-			return false, nil
-		}
-	}
-
 	extractedLibInfo := w.extractLibInfo(pod)
 	if len(extractedLibInfo.libs) == 0 {
 		return false, nil
@@ -216,7 +241,7 @@ func (w *Webhook) inject(pod *corev1.Pod, ns string, _ dynamic.Interface) (bool,
 		}
 	}
 
-	if err := w.injectAutoInstruConfig(pod, extractedLibInfo); err != nil {
+	if err := w.injectAutoInstruConfig(pod, extractedLibInfo, dryRun); err != nil {
 		log.Errorf("failed to inject auto instrumentation configurations: %v", err)
 		return false, errors.New(metrics.ConfigInjectionError)
 	}
@@ -590,7 +615,7 @@ func (w *Webhook) newInjector(startTime time.Time, pod *corev1.Pod, opts ...inje
 		podMutator(w.version)
 }
 
-func (w *Webhook) injectAutoInstruConfig(pod *corev1.Pod, config extractedPodLibInfo) error {
+func (w *Webhook) injectAutoInstruConfig(pod *corev1.Pod, config extractedPodLibInfo, dryRun bool) error {
 	if len(config.libs) == 0 {
 		return nil
 	}
@@ -621,7 +646,9 @@ func (w *Webhook) injectAutoInstruConfig(pod *corev1.Pod, config extractedPodLib
 		injected := false
 		langStr := string(lib.lang)
 		defer func() {
-			metrics.LibInjectionAttempts.Inc(langStr, strconv.FormatBool(injected), strconv.FormatBool(autoDetected), injectionType)
+			if !dryRun {
+				metrics.LibInjectionAttempts.Inc(langStr, strconv.FormatBool(injected), strconv.FormatBool(autoDetected), injectionType)
+			}
 		}()
 
 		if err := lib.podMutator(w.version, libRequirementOptions{
@@ -629,7 +656,9 @@ func (w *Webhook) injectAutoInstruConfig(pod *corev1.Pod, config extractedPodLib
 			initContainerMutators: initContainerMutators,
 			podMutators:           []podMutator{configInjector.podMutator(lib.lang), injector},
 		}).mutatePod(pod); err != nil {
-			metrics.LibInjectionErrors.Inc(langStr, strconv.FormatBool(autoDetected), injectionType)
+			if !dryRun {
+				metrics.LibInjectionErrors.Inc(langStr, strconv.FormatBool(autoDetected), injectionType)
+			}
 			lastError = err
 			continue
 		}
@@ -638,7 +667,9 @@ func (w *Webhook) injectAutoInstruConfig(pod *corev1.Pod, config extractedPodLib
 	}
 
 	if err := configInjector.podMutator(language("all")).mutatePod(pod); err != nil {
-		metrics.LibInjectionErrors.Inc("all", strconv.FormatBool(autoDetected), injectionType)
+		if !dryRun {
+			metrics.LibInjectionErrors.Inc("all", strconv.FormatBool(autoDetected), injectionType)
+		}
 		lastError = err
 		log.Errorf("Cannot inject library configuration into pod %s: %s", mutatecommon.PodString(pod), err)
 	}
@@ -222,7 +222,7 @@ func TestInjectAutoInstruConfigV2(t *testing.T) {
 				tt.expectedInstallType = "k8s_single_step"
 			}
 
-			err := webhook.injectAutoInstruConfig(tt.pod, tt.libInfo)
+			err := webhook.injectAutoInstruConfig(tt.pod, tt.libInfo, false)
 
 			if tt.wantErr {
 				require.Error(t, err, "expected injectAutoInstruConfig to error")
@@ -566,7 +566,7 @@ func TestInjectAutoInstruConfig(t *testing.T) {
 			err := webhook.injectAutoInstruConfig(tt.pod, extractedPodLibInfo{
 				libs:   tt.libsToInject,
 				source: libInfoSourceLibInjection,
-			})
+			}, false)
 			if tt.wantErr {
 				require.Error(t, err, "expected injectAutoInstruConfig to error")
 			} else {
@@ -2925,7 +2925,7 @@ func TestInjectAutoInstrumentation(t *testing.T) {
 
 			require.NoError(t, errInitAPMInstrumentation)
 
-			_, err := webhook.inject(tt.pod, tt.pod.Namespace, fake.NewSimpleDynamicClient(scheme.Scheme))
+			_, err := webhook.inject(tt.pod, tt.pod.Namespace, fake.NewSimpleDynamicClient(scheme.Scheme), false)
 			require.False(t, (err != nil) != tt.wantErr)
 
 			container := tt.pod.Spec.Containers[0]
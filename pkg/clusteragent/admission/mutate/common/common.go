@@ -19,6 +19,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/dynamic"
 
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -32,27 +33,32 @@ const K8sAutoscalerSafeToEvictVolumesAnnotation = "cluster-autoscaler.kubernetes
 type MutationFunc func(pod *corev1.Pod, ns string, cl dynamic.Interface) (bool, error)
 
 // Mutate handles mutating pods and encoding and decoding admission
-// requests and responses for the public mutate functions
-func Mutate(rawPod []byte, ns string, mutationType string, m MutationFunc, dc dynamic.Interface) ([]byte, error) {
+// requests and responses for the public mutate functions. The mutation
+// attempts aren't counted for dry-run requests.
+func Mutate(request *admission.Request, mutationType string, m MutationFunc) ([]byte, error) {
 	var pod corev1.Pod
-	if err := json.Unmarshal(rawPod, &pod); err != nil {
+	if err := json.Unmarshal(request.Raw, &pod); err != nil {
 		return nil, fmt.Errorf("failed to decode raw object: %v", err)
 	}
 
-	injected, err := m(&pod, ns, dc)
+	injected, err := m(&pod, request.Namespace, request.DynamicClient)
 	if err != nil {
-		metrics.MutationAttempts.Inc(mutationType, metrics.StatusError, strconv.FormatBool(false), err.Error())
+		if !request.DryRun {
+			metrics.MutationAttempts.Inc(mutationType, metrics.StatusError, strconv.FormatBool(false), err.Error())
+		}
 		return nil, fmt.Errorf("failed to mutate pod: %v", err)
 	}
 
-	metrics.MutationAttempts.Inc(mutationType, metrics.StatusSuccess, strconv.FormatBool(injected), "")
+	if !request.DryRun {
+		metrics.MutationAttempts.Inc(mutationType, metrics.StatusSuccess, strconv.FormatBool(injected), "")
+	}
 
 	bytes, err := json.Marshal(pod)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode the mutated Pod object: %v", err)
 	}
 
-	patch, err := jsondiff.CompareJSON(rawPod, bytes) // TODO: Try to generate the patch at the MutationFunc
+	patch, err := jsondiff.CompareJSON(request.Raw, bytes) // TODO: Try to generate the patch at the MutationFunc
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare the JSON patch: %v", err)
 	}
@@ -8,11 +8,20 @@
 package common
 
 import (
+	"encoding/json"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
 )
 
 func Test_contains(t *testing.T) {
@@ -265,3 +274,81 @@ func TestMarkVolumeAsSafeToEvictForAutoscaler(t *testing.T) {
 	}
 
 }
+
+func TestMutate(t *testing.T) {
+	raw, err := json.Marshal(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+	require.NoError(t, err)
+	addLabel := func(pod *corev1.Pod, _ string, _ dynamic.Interface) (bool, error) {
+		pod.Labels = map[string]string{"injected": "true"}
+		return true, nil
+	}
+	attempts := func(mutationType string) float64 {
+		return metrics.MutationAttempts.WithValues(mutationType, metrics.StatusSuccess, strconv.FormatBool(true), "").Get()
+	}
+
+	patch, err := Mutate(&admission.Request{Raw: raw}, "test_mutate", addLabel)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"add","path":"/metadata/labels","value":{"injected":"true"}}]`, string(patch))
+	assert.Equal(t, float64(1), attempts("test_mutate"))
+
+	// dry-run requests return the same patch, without counting the mutation
+	patch, err = Mutate(&admission.Request{Raw: raw, DryRun: true}, "test_mutate_dry_run", addLabel)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"add","path":"/metadata/labels","value":{"injected":"true"}}]`, string(patch))
+	assert.Equal(t, float64(0), attempts("test_mutate_dry_run"))
+}
+
+type fakeNamespaceFilter struct {
+	eligible map[string]bool
+}
+
+func (f fakeNamespaceFilter) IsNamespaceEligible(ns string) bool { return f.eligible[ns] }
+func (f fakeNamespaceFilter) Err() error                         { return nil }
+
+func TestInjectionFilterSkipReason(t *testing.T) {
+	filter := InjectionFilter{NSFilter: fakeNamespaceFilter{eligible: map[string]bool{"apps": true}}}
+	pod := func(ns string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: ns, Labels: labels}}
+	}
+
+	tests := []struct {
+		name             string
+		pod              *corev1.Pod
+		mutateUnlabelled bool
+		want             string
+	}{
+		{
+			name: "enabled by label",
+			pod:  pod("default", map[string]string{"admission.datadoghq.com/enabled": "true"}),
+		},
+		{
+			name:             "disabled by label",
+			pod:              pod("apps", map[string]string{"admission.datadoghq.com/enabled": "false"}),
+			mutateUnlabelled: true,
+			want:             "the pod has the label admission.datadoghq.com/enabled=false",
+		},
+		{
+			name: "eligible namespace",
+			pod:  pod("apps", nil),
+		},
+		{
+			name:             "mutate unlabelled",
+			pod:              pod("default", nil),
+			mutateUnlabelled: true,
+		},
+		{
+			name: "unlabelled",
+			pod:  pod("default", map[string]string{"admission.datadoghq.com/enabled": "yes"}),
+			want: "the pod doesn't have the label admission.datadoghq.com/enabled=true, its namespace isn't enabled for single step instrumentation and admission_controller.mutate_unlabelled is disabled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfig := configmock.New(t)
+			mockConfig.SetWithoutSource("admission_controller.mutate_unlabelled", tt.mutateUnlabelled)
+
+			assert.Equal(t, tt.want, filter.SkipReason(tt.pod))
+			assert.Equal(t, tt.want == "", filter.ShouldMutatePod(tt.pod))
+		})
+	}
+}
@@ -8,6 +8,8 @@
 package common
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
@@ -25,18 +27,27 @@ type InjectionFilter struct {
 // ShouldMutatePod checks if a pod is mutable per explicit rules and
 // the NSFilter if InjectionFilter has one.
 func (f InjectionFilter) ShouldMutatePod(pod *corev1.Pod) bool {
+	return f.SkipReason(pod) == ""
+}
+
+// SkipReason returns why the pod isn't mutable per explicit rules and the
+// NSFilter if InjectionFilter has one, or an empty string if it is.
+func (f InjectionFilter) SkipReason(pod *corev1.Pod) string {
 	switch getPodMutationLabelFlag(pod) {
 	case podMutationDisabled:
-		return false
+		return fmt.Sprintf("the pod has the label %s=false", common.EnabledLabelKey)
 	case podMutationEnabled:
-		return true
+		return ""
 	}
 
 	if f.NSFilter != nil && f.NSFilter.IsNamespaceEligible(pod.Namespace) {
-		return true
+		return ""
 	}
 
-	return pkgconfigsetup.Datadog().GetBool("admission_controller.mutate_unlabelled")
+	if pkgconfigsetup.Datadog().GetBool("admission_controller.mutate_unlabelled") {
+		return ""
+	}
+	return fmt.Sprintf("the pod doesn't have the label %s=true, its namespace isn't enabled for single step instrumentation and admission_controller.mutate_unlabelled is disabled", common.EnabledLabelKey)
 }
 
 type podMutationLabelFlag int
@@ -164,10 +164,16 @@ func (w *Webhook) LabelSelectors(useNamespaceSelector bool) (namespaceSelector *
 	return common.DefaultLabelSelectors(useNamespaceSelector)
 }
 
+// SkipReason returns why the webhook wouldn't mutate the pod, or an empty
+// string if it would
+func (w *Webhook) SkipReason(pod *corev1.Pod, _ string) string {
+	return w.injectionFilter.SkipReason(pod)
+}
+
 // WebhookFunc returns the function that mutates the resources
 func (w *Webhook) WebhookFunc() admission.WebhookFunc {
 	return func(request *admission.Request) *admiv1.AdmissionResponse {
-		return common.MutationResponse(mutatecommon.Mutate(request.Raw, request.Namespace, w.Name(), w.inject, request.DynamicClient))
+		return common.MutationResponse(mutatecommon.Mutate(request, w.Name(), w.inject))
 	}
 }
 
@@ -182,7 +188,8 @@ func (w *Webhook) inject(pod *corev1.Pod, _ string, _ dynamic.Interface) (bool,
 		return false, errors.New(metrics.InvalidInput)
 	}
 
-	if !w.injectionFilter.ShouldMutatePod(pod) {
+	if reason := w.SkipReason(pod, ""); reason != "" {
+		log.Debugf("Skipping pod %s: %s", mutatecommon.PodString(pod), reason)
 		return false, nil
 	}
 
@@ -92,9 +92,10 @@ type WebhookForPods struct {
 	resources     []string
 	operations    []admissionregistrationv1.OperationType
 	admissionFunc admission.WebhookFunc
+	skipReason    func(pod *corev1.Pod, ns string) string
 }
 
-func newWebhookForPods(admissionFunc admission.WebhookFunc) *WebhookForPods {
+func newWebhookForPods(admissionFunc admission.WebhookFunc, skipReason func(pod *corev1.Pod, ns string) string) *WebhookForPods {
 	return &WebhookForPods{
 		name: webhookForPodsName,
 		isEnabled: pkgconfigsetup.Datadog().GetBool("admission_controller.cws_instrumentation.enabled") &&
@@ -103,6 +104,7 @@ func newWebhookForPods(admissionFunc admission.WebhookFunc) *WebhookForPods {
 		resources:     []string{"pods"},
 		operations:    []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
 		admissionFunc: admissionFunc,
+		skipReason:    skipReason,
 	}
 }
 
@@ -149,6 +151,12 @@ func (w *WebhookForPods) WebhookFunc() admission.WebhookFunc {
 	return w.admissionFunc
 }
 
+// SkipReason returns why the webhook wouldn't mutate the pod, or an empty
+// string if it would
+func (w *WebhookForPods) SkipReason(pod *corev1.Pod, ns string) string {
+	return w.skipReason(pod, ns)
+}
+
 // WebhookForCommands is the webhook that injects CWS pods/exec instrumentation
 type WebhookForCommands struct {
 	name          string
@@ -351,7 +359,7 @@ func NewCWSInstrumentation(wmeta workloadmeta.Component) (*CWSInstrumentation, e
 		return nil, fmt.Errorf("couldn't parse CWS Instrumentation init container resources: %w", err)
 	}
 
-	ci.webhookForPods = newWebhookForPods(ci.injectForPod)
+	ci.webhookForPods = newWebhookForPods(ci.injectForPod, ci.podSkipReason)
 	ci.webhookForCommands = newWebhookForCommands(ci.injectForCommand)
 
 	return &ci, nil
@@ -616,25 +624,48 @@ func (ci *CWSInstrumentation) injectCWSCommandInstrumentationRemoteCopy(pod *cor
 }
 
 func (ci *CWSInstrumentation) injectForPod(request *admission.Request) *admiv1.AdmissionResponse {
-	return common.MutationResponse(mutatecommon.Mutate(request.Raw, request.Namespace, ci.webhookForPods.Name(), ci.injectCWSPodInstrumentation, request.DynamicClient))
+	return common.MutationResponse(mutatecommon.Mutate(request, ci.webhookForPods.Name(), func(pod *corev1.Pod, ns string, dc dynamic.Interface) (bool, error) {
+		return ci.injectCWSPodInstrumentation(pod, ns, dc, request.DryRun)
+	}))
+}
+
+// podSkipReason returns why the pod wouldn't be instrumented, or an empty
+// string if it would
+func (ci *CWSInstrumentation) podSkipReason(pod *corev1.Pod, ns string) string {
+	if ci.filter.IsExcluded(pod.Annotations, "", "", ns) {
+		return "the pod is excluded by admission_controller.cws_instrumentation.include and exclude"
+	}
+	if isPodCWSInstrumentationReady(pod.Annotations) {
+		return "the pod is already instrumented"
+	}
+	return ""
+}
+
+// observePodInstrumentationAttempt counts a pod instrumentation attempt,
+// unless the request is a dry run
+func (ci *CWSInstrumentation) observePodInstrumentationAttempt(dryRun bool, instrumented string, reason string) {
+	if dryRun {
+		return
+	}
+	metrics.CWSPodInstrumentationAttempts.Observe(1, ci.mode.String(), instrumented, reason)
 }
 
-func (ci *CWSInstrumentation) injectCWSPodInstrumentation(pod *corev1.Pod, ns string, _ dynamic.Interface) (bool, error) {
+func (ci *CWSInstrumentation) injectCWSPodInstrumentation(pod *corev1.Pod, ns string, _ dynamic.Interface, dryRun bool) (bool, error) {
 	if pod == nil {
 		log.Errorf("cannot inject CWS instrumentation into nil pod")
-		metrics.CWSPodInstrumentationAttempts.Observe(1, ci.mode.String(), "false", cwsNilInputReason)
+		ci.observePodInstrumentationAttempt(dryRun, "false", cwsNilInputReason)
 		return false, errors.New(metrics.InvalidInput)
 	}
 
 	// is the pod targeted by the instrumentation ?
 	if ci.filter.IsExcluded(pod.Annotations, "", "", ns) {
-		metrics.CWSPodInstrumentationAttempts.Observe(1, ci.mode.String(), "false", cwsExcludedResourceReason)
+		ci.observePodInstrumentationAttempt(dryRun, "false", cwsExcludedResourceReason)
 		return false, nil
 	}
 
 	// check if the pod has already been instrumented
 	if isPodCWSInstrumentationReady(pod.Annotations) {
-		metrics.CWSPodInstrumentationAttempts.Observe(1, ci.mode.String(), "false", cwsAlreadyInstrumentedReason)
+		ci.observePodInstrumentationAttempt(dryRun, "false", cwsAlreadyInstrumentedReason)
 		// nothing to do, return
 		return true, nil
 	}
@@ -649,7 +680,7 @@ func (ci *CWSInstrumentation) injectCWSPodInstrumentation(pod *corev1.Pod, ns st
 		instrumented = ci.injectCWSPodInstrumentationRemoteCopy(pod)
 	default:
 		log.Errorf("Ignoring Pod %s admission request: unknown CWS Instrumentation mode %v", mutatecommon.PodString(pod), ci.mode)
-		metrics.CWSPodInstrumentationAttempts.Observe(1, ci.mode.String(), "false", cwsUnknownModeReason)
+		ci.observePodInstrumentationAttempt(dryRun, "false", cwsUnknownModeReason)
 		return false, errors.New(metrics.InvalidInput)
 	}
 
@@ -660,9 +691,9 @@ func (ci *CWSInstrumentation) injectCWSPodInstrumentation(pod *corev1.Pod, ns st
 		}
 		pod.Annotations[cwsInstrumentationPodAnotationStatus] = cwsInstrumentationPodAnotationReady
 		log.Debugf("Pod %s is now instrumented for CWS", mutatecommon.PodString(pod))
-		metrics.CWSPodInstrumentationAttempts.Observe(1, ci.mode.String(), "true", "")
+		ci.observePodInstrumentationAttempt(dryRun, "true", "")
 	} else {
-		metrics.CWSPodInstrumentationAttempts.Observe(1, ci.mode.String(), "false", cwsNoInstrumentationNeededReason)
+		ci.observePodInstrumentationAttempt(dryRun, "false", cwsNoInstrumentationNeededReason)
 	}
 
 	return true, nil
@@ -850,7 +850,7 @@ func Test_injectCWSPodInstrumentation(t *testing.T) {
 			if err != nil {
 				require.Fail(t, "couldn't instantiate CWS Instrumentation", "%v", err)
 			} else {
-				injected, err := ci.injectCWSPodInstrumentation(tt.args.pod, tt.args.ns, nil)
+				injected, err := ci.injectCWSPodInstrumentation(tt.args.pod, tt.args.ns, nil, false)
 
 				if tt.wantErr {
 					assert.False(t, injected)
@@ -10,17 +10,13 @@
 package tagsfromlabels
 
 import (
-	"context"
 	"errors"
-	"fmt"
-	"strings"
 	"time"
 
 	admiv1 "k8s.io/api/admission/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
 	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
@@ -29,7 +25,6 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
 	mutatecommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
-	"github.com/DataDog/datadog-agent/pkg/util/cache"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -106,32 +101,19 @@ func (w *Webhook) LabelSelectors(useNamespaceSelector bool) (namespaceSelector *
 	return common.DefaultLabelSelectors(useNamespaceSelector)
 }
 
-type owner struct {
-	name            string
-	namespace       string
-	kind            string
-	labels          map[string]string
-	ownerReferences []metav1.OwnerReference
-}
-
-// ownerInfo wraps the information needed to get pod's owner object
-type ownerInfo struct {
-	gvr  schema.GroupVersionResource
-	name string
-}
-
-// buildID returns a unique identifier for the ownerInfo object
-func (o *ownerInfo) buildID(ns string) string {
-	return fmt.Sprintf("%s/%s/%s", ns, o.name, o.gvr.String())
+// SkipReason returns why the webhook wouldn't mutate the pod, or an empty
+// string if it would
+func (w *Webhook) SkipReason(pod *corev1.Pod, _ string) string {
+	return w.injectionFilter.SkipReason(pod)
 }
 
 // WebhookFunc returns the function that mutates the resources
 func (w *Webhook) WebhookFunc() admission.WebhookFunc {
 	return func(request *admission.Request) *admiv1.AdmissionResponse {
-		return common.MutationResponse(mutatecommon.Mutate(request.Raw, request.Namespace, w.Name(), func(pod *corev1.Pod, ns string, dc dynamic.Interface) (bool, error) {
+		return common.MutationResponse(mutatecommon.Mutate(request, w.Name(), func(pod *corev1.Pod, ns string, dc dynamic.Interface) (bool, error) {
 			// Adds the DD_ENV, DD_VERSION, DD_SERVICE env vars to the pod template from pod and higher-level resource labels.
 			return w.injectTags(pod, ns, dc)
-		}, request.DynamicClient))
+		}))
 	}
 }
 
@@ -144,9 +126,10 @@ func (w *Webhook) injectTags(pod *corev1.Pod, ns string, dc dynamic.Interface) (
 		return false, errors.New(metrics.InvalidInput)
 	}
 
-	if !w.injectionFilter.ShouldMutatePod(pod) {
+	if reason := w.SkipReason(pod, ns); reason != "" {
 		// Ignore pod if it has the label admission.datadoghq.com/enabled=false
 		// or Single step configuration is disabled
+		log.Debugf("Skipping pod %s: %s", mutatecommon.PodString(pod), reason)
 		return false, nil
 	}
 
@@ -171,14 +154,14 @@ func (w *Webhook) injectTags(pod *corev1.Pod, ns string, dc dynamic.Interface) (
 		return false, nil
 	}
 
-	owner, err := w.getOwner(owners[0], ns, dc)
+	owner, err := common.GetOwner(owners[0], ns, dc, w.ownerCacheTTL)
 	if err != nil {
 		log.Error(err)
 		return false, errors.New(metrics.InternalError)
 	}
 
-	log.Debugf("Looking for standard labels on '%s/%s' - kind '%s' owner of pod %s", owner.namespace, owner.name, owner.kind, mutatecommon.PodString(pod))
-	_, injected = injectTagsFromLabels(owner.labels, pod)
+	log.Debugf("Looking for standard labels on '%s/%s' - kind '%s' owner of pod %s", owner.Namespace, owner.Name, owner.Kind, mutatecommon.PodString(pod))
+	_, injected = injectTagsFromLabels(owner.Labels, pod)
 
 	return injected, nil
 }
@@ -203,76 +186,6 @@ func injectTagsFromLabels(labels map[string]string, pod *corev1.Pod) (bool, bool
 	return found, injectedAtLeastOnce
 }
 
-// getOwnerInfo returns the required information to get the owner object
-func getOwnerInfo(owner metav1.OwnerReference) (*ownerInfo, error) {
-	gv, err := schema.ParseGroupVersion(owner.APIVersion)
-	if err != nil {
-		return nil, err
-	}
-	return &ownerInfo{
-		gvr:  gv.WithResource(fmt.Sprintf("%ss", strings.ToLower(owner.Kind))),
-		name: owner.Name,
-	}, nil
-}
-
-// getOwner returns the object of the pod's owner
-// If the owner is a replicaset it returns the corresponding deployment
-func (w *Webhook) getOwner(owner metav1.OwnerReference, ns string, dc dynamic.Interface) (*owner, error) {
-	ownerInfo, err := getOwnerInfo(owner)
-	if err != nil {
-		return nil, err
-	}
-
-	obj, err := w.getAndCacheOwner(ownerInfo, ns, dc)
-	if err != nil {
-		return nil, err
-	}
-
-	// Try to discover standard labels from the deployment object if the owner is a replicaset
-	if obj.kind == "ReplicaSet" && len(obj.ownerReferences) > 0 {
-		rsOwnerInfo, err := getOwnerInfo(obj.ownerReferences[0])
-		if err != nil {
-			return nil, err
-		}
-
-		return w.getAndCacheOwner(rsOwnerInfo, ns, dc)
-	}
-
-	return obj, nil
-}
-
-// getAndCacheOwner tries to fetch the owner object from cache before querying the api server
-func (w *Webhook) getAndCacheOwner(info *ownerInfo, ns string, dc dynamic.Interface) (*owner, error) {
-	infoID := info.buildID(ns)
-	if cachedObj, hit := cache.Cache.Get(infoID); hit {
-		metrics.GetOwnerCacheHit.Inc(info.gvr.Resource)
-		owner, valid := cachedObj.(*owner)
-		if !valid {
-			log.Debugf("Invalid owner object for '%s', forcing a cache miss", infoID)
-		} else {
-			return owner, nil
-		}
-	}
-
-	log.Tracef("Cache miss while getting owner '%s'", infoID)
-	metrics.GetOwnerCacheMiss.Inc(info.gvr.Resource)
-	ownerObj, err := dc.Resource(info.gvr).Namespace(ns).Get(context.TODO(), info.name, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	owner := &owner{
-		name:            ownerObj.GetName(),
-		kind:            ownerObj.GetKind(),
-		namespace:       ownerObj.GetNamespace(),
-		labels:          ownerObj.GetLabels(),
-		ownerReferences: ownerObj.GetOwnerReferences(),
-	}
-
-	cache.Cache.Set(infoID, owner, w.ownerCacheTTL)
-	return owner, nil
-}
-
 func ownerCacheTTL() time.Duration {
 	if pkgconfigsetup.Datadog().IsSet("admission_controller.pod_owners_cache_validity") { // old option. Kept for backwards compatibility
 		return pkgconfigsetup.Datadog().GetDuration("admission_controller.pod_owners_cache_validity") * time.Minute
@@ -0,0 +1,280 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package preview runs a pod through the enabled mutating webhooks of the
+// admission controller without persisting anything, to show the mutations
+// the pod would receive.
+package preview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	admiv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/controllers/webhook"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// StatusMutated is the status of a webhook that changed the pod
+	StatusMutated = "mutated"
+	// StatusSkipped is the status of a webhook that didn't change the pod
+	StatusSkipped = "skipped"
+	// StatusError is the status of a webhook that failed to mutate the pod
+	StatusError = "error"
+
+	defaultNamespace = "default"
+)
+
+// Request is the payload of a preview request
+type Request struct {
+	// Namespace is the namespace the pod would be created in. It defaults
+	// to the namespace of the pod, or to "default".
+	Namespace string `json:"namespace,omitempty"`
+	// Pod is the pod to run through the mutating webhooks
+	Pod json.RawMessage `json:"pod"`
+}
+
+// WebhookResult is the outcome of a mutating webhook
+type WebhookResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Reason explains why the webhook was skipped or failed
+	Reason string `json:"reason,omitempty"`
+	// Patch is the JSON patch returned by the webhook, relative to the pod
+	// mutated by the previous webhooks
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+// Result is the response of a preview request
+type Result struct {
+	// Patch is the JSON patch of all the webhooks, relative to the given pod
+	Patch json.RawMessage `json:"patch"`
+	// Webhooks are the results of each mutating webhook, in execution order
+	Webhooks []WebhookResult `json:"webhooks"`
+	// Pod is the mutated pod
+	Pod json.RawMessage `json:"pod"`
+}
+
+// skipReasoner is implemented by the mutating webhooks that can tell why
+// they wouldn't mutate a pod
+type skipReasoner interface {
+	SkipReason(pod *corev1.Pod, ns string) string
+}
+
+// Previewer runs pods through the enabled mutating webhooks
+type Previewer struct {
+	webhooks  []webhook.Webhook
+	dc        dynamic.Interface
+	apiClient kubernetes.Interface
+}
+
+// NewPreviewer returns a new Previewer for the given webhooks, in the order
+// in which the admission controller registers them
+func NewPreviewer(webhooks []webhook.Webhook, dc dynamic.Interface, apiClient kubernetes.Interface) *Previewer {
+	return &Previewer{
+		webhooks:  webhooks,
+		dc:        dc,
+		apiClient: apiClient,
+	}
+}
+
+// Preview runs the pod of the request through the enabled mutating webhooks.
+// The webhooks are invoked as they would be for a pod creation, each one
+// receiving the pod mutated by the previous ones.
+func (p *Previewer) Preview(ctx context.Context, req Request) (*Result, error) {
+	if len(req.Pod) == 0 {
+		return nil, errors.New("missing pod")
+	}
+
+	var pod corev1.Pod
+	if err := json.Unmarshal(req.Pod, &pod); err != nil {
+		return nil, fmt.Errorf("failed to decode pod: %w", err)
+	}
+	if pod.Kind != "" && pod.Kind != "Pod" {
+		return nil, fmt.Errorf("expected a Pod, got a %s", pod.Kind)
+	}
+
+	ns := req.Namespace
+	if ns == "" {
+		ns = pod.Namespace
+	}
+	if ns == "" {
+		ns = defaultNamespace
+	}
+	pod.Namespace = ns
+
+	raw, err := json.Marshal(&pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pod: %w", err)
+	}
+
+	nsLabels := p.namespaceLabels(ctx, ns)
+
+	result := &Result{Webhooks: []WebhookResult{}}
+	var ops []json.RawMessage
+	for _, w := range p.webhooks {
+		if w.WebhookType() != common.MutatingWebhook || !w.IsEnabled() {
+			continue
+		}
+
+		res := WebhookResult{Name: w.Name(), Status: StatusSkipped}
+		if reason := skipReason(w, &pod, nsLabels); reason != "" {
+			res.Reason = reason
+			result.Webhooks = append(result.Webhooks, res)
+			continue
+		}
+		if s, ok := w.(skipReasoner); ok {
+			if reason := s.SkipReason(&pod, ns); reason != "" {
+				res.Reason = reason
+				result.Webhooks = append(result.Webhooks, res)
+				continue
+			}
+		}
+
+		// The request is a dry run so that the webhooks don't submit the
+		// metrics of the pods they mutate
+		response := w.WebhookFunc()(&admission.Request{
+			Raw:           raw,
+			Name:          pod.Name,
+			Namespace:     ns,
+			DynamicClient: p.dc,
+			APIClient:     p.apiClient,
+			DryRun:        true,
+		})
+
+		var patchOps []json.RawMessage
+		switch {
+		case response.Result != nil && response.Result.Message != "":
+			res.Status = StatusError
+			res.Reason = response.Result.Message
+		case len(response.Patch) == 0:
+			res.Reason = "the webhook didn't change the pod"
+		default:
+			if err := json.Unmarshal(response.Patch, &patchOps); err != nil {
+				res.Status = StatusError
+				res.Reason = fmt.Sprintf("invalid JSON patch: %v", err)
+				break
+			}
+			if len(patchOps) == 0 {
+				res.Reason = "the webhook didn't change the pod"
+				break
+			}
+
+			mutated, err := applyPatch(raw, response.Patch)
+			if err != nil {
+				res.Status = StatusError
+				res.Reason = err.Error()
+				break
+			}
+
+			res.Status = StatusMutated
+			res.Patch = response.Patch
+			raw = mutated
+			ops = append(ops, patchOps...)
+			if err := json.Unmarshal(raw, &pod); err != nil {
+				return nil, fmt.Errorf("failed to decode the pod mutated by %s: %w", w.Name(), err)
+			}
+		}
+
+		result.Webhooks = append(result.Webhooks, res)
+	}
+
+	// JSON patches are sequences of operations, so the concatenation of the
+	// patches of each webhook is the patch of all the webhooks
+	if ops == nil {
+		ops = []json.RawMessage{}
+	}
+	if result.Patch, err = json.Marshal(ops); err != nil {
+		return nil, fmt.Errorf("failed to encode patch: %w", err)
+	}
+	result.Pod = raw
+
+	return result, nil
+}
+
+// namespaceLabels returns the labels of the namespace, or nil if they can't
+// be retrieved
+func (p *Previewer) namespaceLabels(ctx context.Context, ns string) map[string]string {
+	if p.apiClient == nil {
+		return nil
+	}
+
+	namespace, err := p.apiClient.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+	if err != nil {
+		log.Debugf("Cannot get namespace %s, namespace selectors won't be evaluated: %v", ns, err)
+		return nil
+	}
+	return namespace.Labels
+}
+
+// skipReason returns why the API server wouldn't send the pod creation
+// request to the webhook, or an empty string if it would
+func skipReason(w webhook.Webhook, pod *corev1.Pod, nsLabels map[string]string) string {
+	if !slices.Contains(w.Resources(), "pods") {
+		return "the webhook doesn't handle pods"
+	}
+	if !slices.Contains(w.Operations(), admiv1.Create) && !slices.Contains(w.Operations(), admiv1.OperationAll) {
+		return "the webhook doesn't handle pod creations"
+	}
+
+	// Object selectors are used on all supported Kubernetes versions, only
+	// AKS also sets a namespace selector in that case.
+	namespaceSelector, objectSelector := w.LabelSelectors(false)
+	if matches, err := selectorMatches(objectSelector, pod.Labels); err != nil {
+		return fmt.Sprintf("invalid object selector: %v", err)
+	} else if !matches {
+		return fmt.Sprintf("the pod labels don't match the object selector of the webhook (%s)", metav1.FormatLabelSelector(objectSelector))
+	}
+	if nsLabels != nil {
+		if matches, err := selectorMatches(namespaceSelector, nsLabels); err != nil {
+			return fmt.Sprintf("invalid namespace selector: %v", err)
+		} else if !matches {
+			return fmt.Sprintf("the namespace labels don't match the namespace selector of the webhook (%s)", metav1.FormatLabelSelector(namespaceSelector))
+		}
+	}
+
+	return ""
+}
+
+// selectorMatches returns whether the labels match the selector. A nil
+// selector matches everything.
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(labels.Set(set)), nil
+}
+
+// applyPatch applies the JSON patch to the pod
+func applyPatch(raw []byte, patch []byte) ([]byte, error) {
+	decoded, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+	mutated, err := decoded.Apply(raw)
+	if err != nil {
+		return nil, fmt.Errorf("cannot apply JSON patch: %w", err)
+	}
+	return mutated, nil
+}
@@ -0,0 +1,230 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package preview
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admiv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/controllers/webhook"
+)
+
+type fakeWebhook struct {
+	name              string
+	webhookType       common.WebhookType
+	resources         []string
+	operations        []admissionregistrationv1.OperationType
+	namespaceSelector *metav1.LabelSelector
+	objectSelector    *metav1.LabelSelector
+	webhookFunc       admission.WebhookFunc
+}
+
+func (w *fakeWebhook) Name() string                       { return w.name }
+func (w *fakeWebhook) WebhookType() common.WebhookType    { return w.webhookType }
+func (w *fakeWebhook) IsEnabled() bool                    { return true }
+func (w *fakeWebhook) Endpoint() string                   { return "/" + w.name }
+func (w *fakeWebhook) Resources() []string                { return w.resources }
+func (w *fakeWebhook) WebhookFunc() admission.WebhookFunc { return w.webhookFunc }
+func (w *fakeWebhook) Operations() []admissionregistrationv1.OperationType {
+	return w.operations
+}
+func (w *fakeWebhook) LabelSelectors(_ bool) (*metav1.LabelSelector, *metav1.LabelSelector) {
+	return w.namespaceSelector, w.objectSelector
+}
+
+// skippingWebhook is a webhook telling why it wouldn't mutate pods
+type skippingWebhook struct {
+	*fakeWebhook
+	reason string
+}
+
+func (w *skippingWebhook) SkipReason(_ *corev1.Pod, _ string) string { return w.reason }
+
+func newFakeWebhook(name string, f admission.WebhookFunc) *fakeWebhook {
+	return &fakeWebhook{
+		name:        name,
+		webhookType: common.MutatingWebhook,
+		resources:   []string{"pods"},
+		operations:  []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		webhookFunc: f,
+	}
+}
+
+func patchResponse(patch string) admission.WebhookFunc {
+	return func(_ *admission.Request) *admiv1.AdmissionResponse {
+		return common.MutationResponse([]byte(patch), nil)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	var envWebhookRequest *admission.Request
+
+	webhooks := []webhook.Webhook{
+		newFakeWebhook("add_label", patchResponse(`[{"op":"add","path":"/metadata/labels/injected","value":"true"}]`)),
+		newFakeWebhook("add_env", func(request *admission.Request) *admiv1.AdmissionResponse {
+			envWebhookRequest = request
+			return common.MutationResponse([]byte(`[{"op":"add","path":"/spec/containers/0/env","value":[{"name":"DD_ENV","value":"prod"}]}]`), nil)
+		}),
+		newFakeWebhook("no_change", patchResponse(`[]`)),
+		&skippingWebhook{
+			fakeWebhook: newFakeWebhook("skipping", func(_ *admission.Request) *admiv1.AdmissionResponse {
+				t.Error("the webhook func of a skipped webhook shouldn't be called")
+				return nil
+			}),
+			reason: "the pod has the label admission.datadoghq.com/enabled=false",
+		},
+		&skippingWebhook{
+			fakeWebhook: newFakeWebhook("not_skipping", patchResponse(`[]`)),
+		},
+		newFakeWebhook("failing", func(_ *admission.Request) *admiv1.AdmissionResponse {
+			return common.MutationResponse(nil, assert.AnError)
+		}),
+		&fakeWebhook{
+			name:        "validating",
+			webhookType: common.ValidatingWebhook,
+			resources:   []string{"pods"},
+			operations:  []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		},
+		&fakeWebhook{
+			name:        "exec",
+			webhookType: common.MutatingWebhook,
+			resources:   []string{"pods/exec"},
+			operations:  []admissionregistrationv1.OperationType{admissionregistrationv1.Connect},
+		},
+		&fakeWebhook{
+			name:           "labelled_only",
+			webhookType:    common.MutatingWebhook,
+			resources:      []string{"pods"},
+			operations:     []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+			objectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"admission.datadoghq.com/enabled": "true"}},
+		},
+		&fakeWebhook{
+			name:              "selected_namespaces",
+			webhookType:       common.MutatingWebhook,
+			resources:         []string{"pods"},
+			operations:        []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+			namespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "bar"}},
+		},
+	}
+
+	apiClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{"team": "foo"}},
+	})
+
+	pod := `{"metadata":{"name":"foo","labels":{"app":"foo"}},"spec":{"containers":[{"name":"app","image":"foo"}]}}`
+	result, err := NewPreviewer(webhooks, nil, apiClient).Preview(context.Background(), Request{
+		Namespace: "apps",
+		Pod:       json.RawMessage(pod),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []WebhookResult{
+		{
+			Name:   "add_label",
+			Status: StatusMutated,
+			Patch:  json.RawMessage(`[{"op":"add","path":"/metadata/labels/injected","value":"true"}]`),
+		},
+		{
+			Name:   "add_env",
+			Status: StatusMutated,
+			Patch:  json.RawMessage(`[{"op":"add","path":"/spec/containers/0/env","value":[{"name":"DD_ENV","value":"prod"}]}]`),
+		},
+		{
+			Name:   "no_change",
+			Status: StatusSkipped,
+			Reason: "the webhook didn't change the pod",
+		},
+		{
+			Name:   "skipping",
+			Status: StatusSkipped,
+			Reason: "the pod has the label admission.datadoghq.com/enabled=false",
+		},
+		{
+			Name:   "not_skipping",
+			Status: StatusSkipped,
+			Reason: "the webhook didn't change the pod",
+		},
+		{
+			Name:   "failing",
+			Status: StatusError,
+			Reason: assert.AnError.Error(),
+		},
+		{
+			Name:   "exec",
+			Status: StatusSkipped,
+			Reason: "the webhook doesn't handle pods",
+		},
+		{
+			Name:   "labelled_only",
+			Status: StatusSkipped,
+			Reason: "the pod labels don't match the object selector of the webhook (admission.datadoghq.com/enabled=true)",
+		},
+		{
+			Name:   "selected_namespaces",
+			Status: StatusSkipped,
+			Reason: "the namespace labels don't match the namespace selector of the webhook (team=bar)",
+		},
+	}, result.Webhooks)
+
+	// Each webhook receives the pod mutated by the previous ones
+	require.NotNil(t, envWebhookRequest)
+	assert.Equal(t, "apps", envWebhookRequest.Namespace)
+	assert.True(t, envWebhookRequest.DryRun)
+	var receivedPod corev1.Pod
+	require.NoError(t, json.Unmarshal(envWebhookRequest.Raw, &receivedPod))
+	assert.Equal(t, "true", receivedPod.Labels["injected"])
+
+	assert.JSONEq(t, `[
+		{"op":"add","path":"/metadata/labels/injected","value":"true"},
+		{"op":"add","path":"/spec/containers/0/env","value":[{"name":"DD_ENV","value":"prod"}]}
+	]`, string(result.Patch))
+
+	var mutatedPod corev1.Pod
+	require.NoError(t, json.Unmarshal(result.Pod, &mutatedPod))
+	assert.Equal(t, "apps", mutatedPod.Namespace)
+	assert.Equal(t, map[string]string{"app": "foo", "injected": "true"}, mutatedPod.Labels)
+	assert.Equal(t, []corev1.EnvVar{{Name: "DD_ENV", Value: "prod"}}, mutatedPod.Spec.Containers[0].Env)
+}
+
+func TestPreviewNoMutation(t *testing.T) {
+	result, err := NewPreviewer(nil, nil, nil).Preview(context.Background(), Request{
+		Pod: json.RawMessage(`{"metadata":{"name":"foo"},"spec":{"containers":[{"name":"app"}]}}`),
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Webhooks)
+	assert.JSONEq(t, `[]`, string(result.Patch))
+
+	var pod corev1.Pod
+	require.NoError(t, json.Unmarshal(result.Pod, &pod))
+	assert.Equal(t, "default", pod.Namespace)
+}
+
+func TestPreviewInvalidRequest(t *testing.T) {
+	previewer := NewPreviewer(nil, nil, nil)
+
+	_, err := previewer.Preview(context.Background(), Request{})
+	assert.EqualError(t, err, "missing pod")
+
+	_, err = previewer.Preview(context.Background(), Request{Pod: json.RawMessage(`{"kind":"Deployment"}`)})
+	assert.EqualError(t, err, "expected a Pod, got a Deployment")
+
+	_, err = previewer.Preview(context.Background(), Request{Pod: json.RawMessage(`[]`)})
+	assert.ErrorContains(t, err, "failed to decode pod")
+}
@@ -0,0 +1,323 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package workloadconfig implements the webhook that validates the Datadog
+// configuration of a pod: autodiscovery annotations, admission controller
+// labels and unified service tagging labels
+package workloadconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	admiv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/utils"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
+	validatecommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/validate/common"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
+	apiServerCommon "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/common"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	webhookName = "workload_config_validation"
+
+	// ModeWarn admits invalid pods and returns the problems as warnings
+	ModeWarn = "warn"
+	// ModeReject refuses invalid pods
+	ModeReject = "reject"
+
+	// namespaceNameLabelKey is the label set by Kubernetes on every namespace
+	// to its name
+	namespaceNameLabelKey = "kubernetes.io/metadata.name"
+)
+
+// ustLabels are the unified service tagging labels that should be consistent
+// between a pod and its owner
+var ustLabels = []string{
+	kubernetes.EnvTagLabelKey,
+	kubernetes.ServiceTagLabelKey,
+	kubernetes.VersionTagLabelKey,
+}
+
+// adTemplateSuffixes are the suffixes of the autodiscovery annotations
+// holding check and logs templates
+var adTemplateSuffixes = []string{
+	"check_names",
+	"init_configs",
+	"instances",
+	"logs",
+	"checks",
+}
+
+// Webhook is the webhook that validates the Datadog configuration of pods
+type Webhook struct {
+	name          string
+	isEnabled     bool
+	endpoint      string
+	resources     []string
+	operations    []admissionregistrationv1.OperationType
+	reject        bool
+	knownChecks   map[string]struct{}
+	ownerCacheTTL time.Duration
+}
+
+// NewWebhook returns a new Webhook
+func NewWebhook() *Webhook {
+	mode := strings.ToLower(pkgconfigsetup.Datadog().GetString("admission_controller.workload_config_validation.mode"))
+	if mode != ModeWarn && mode != ModeReject {
+		log.Warnf("Unknown workload config validation mode %q, falling back to %q", mode, ModeWarn)
+		mode = ModeWarn
+	}
+
+	knownChecks := map[string]struct{}{}
+	for _, check := range pkgconfigsetup.Datadog().GetStringSlice("admission_controller.workload_config_validation.known_checks") {
+		knownChecks[check] = struct{}{}
+	}
+
+	return &Webhook{
+		name:          webhookName,
+		isEnabled:     pkgconfigsetup.Datadog().GetBool("admission_controller.workload_config_validation.enabled"),
+		endpoint:      pkgconfigsetup.Datadog().GetString("admission_controller.workload_config_validation.endpoint"),
+		resources:     []string{"pods"},
+		operations:    []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		reject:        mode == ModeReject,
+		knownChecks:   knownChecks,
+		ownerCacheTTL: pkgconfigsetup.Datadog().GetDuration("admission_controller.workload_config_validation.pod_owners_cache_validity") * time.Minute,
+	}
+}
+
+// Name returns the name of the webhook
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// WebhookType returns the type of the webhook
+func (w *Webhook) WebhookType() common.WebhookType {
+	return common.ValidatingWebhook
+}
+
+// IsEnabled returns whether the webhook is enabled
+func (w *Webhook) IsEnabled() bool {
+	return w.isEnabled
+}
+
+// Endpoint returns the endpoint of the webhook
+func (w *Webhook) Endpoint() string {
+	return w.endpoint
+}
+
+// Resources returns the kubernetes resources for which the webhook should
+// be invoked
+func (w *Webhook) Resources() []string {
+	return w.resources
+}
+
+// Operations returns the operations on the resources specified for which
+// the webhook should be invoked
+func (w *Webhook) Operations() []admissionregistrationv1.OperationType {
+	return w.operations
+}
+
+// LabelSelectors returns the label selectors that specify when the webhook
+// should be invoked
+func (w *Webhook) LabelSelectors(useNamespaceSelector bool) (namespaceSelector *metav1.LabelSelector, objectSelector *metav1.LabelSelector) {
+	namespaceSelector, objectSelector = common.DefaultLabelSelectors(useNamespaceSelector)
+
+	// The pods of the system namespace and of the agent aren't validated
+	excludedNamespaces := metav1.LabelSelectorRequirement{
+		Key:      namespaceNameLabelKey,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{"kube-system", apiServerCommon.GetResourcesNamespace()},
+	}
+	if namespaceSelector == nil {
+		namespaceSelector = &metav1.LabelSelector{}
+	}
+	namespaceSelector.MatchExpressions = append(namespaceSelector.MatchExpressions, excludedNamespaces)
+
+	return namespaceSelector, objectSelector
+}
+
+// WebhookFunc returns the function that validates the resources
+func (w *Webhook) WebhookFunc() admission.WebhookFunc {
+	return func(request *admission.Request) *admiv1.AdmissionResponse {
+		var problems []string
+		validated, err := validatecommon.Validate(request.Raw, request.Namespace, w.Name(), func(pod *corev1.Pod, ns string, dc dynamic.Interface) (bool, error) {
+			problems = w.validate(pod, ns, dc)
+			return len(problems) == 0 || !w.reject, nil
+		}, request.DynamicClient)
+
+		response := common.ValidationResponse(validated, err)
+		if err != nil || len(problems) == 0 {
+			return response
+		}
+
+		if response.Allowed {
+			response.Warnings = problems
+		} else {
+			response.Result = &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Reason:  metav1.StatusReasonInvalid,
+				Message: "invalid Datadog configuration: " + strings.Join(problems, "; "),
+			}
+		}
+		return response
+	}
+}
+
+// validate returns the list of problems found in the Datadog configuration of the pod
+func (w *Webhook) validate(pod *corev1.Pod, ns string, dc dynamic.Interface) []string {
+	if pod == nil {
+		return nil
+	}
+
+	problems := w.validateADAnnotations(pod)
+
+	enabled, hasEnabledLabel := pod.GetLabels()[common.EnabledLabelKey]
+	if hasEnabledLabel && enabled != "true" && enabled != "false" {
+		problems = append(problems, fmt.Sprintf("label %s must be \"true\" or \"false\", got %q", common.EnabledLabelKey, enabled))
+	}
+
+	if ns == "" {
+		ns = pod.GetNamespace()
+	}
+	owners := pod.GetOwnerReferences()
+	if len(owners) == 0 || ns == "" || dc == nil {
+		return problems
+	}
+
+	owner, err := common.GetOwner(owners[0], ns, dc, w.ownerCacheTTL)
+	if err != nil {
+		// The owner lookup is best effort, the pod shouldn't be refused because of it
+		log.Debugf("Cannot get the owner of pod %s/%s, skipping owner labels validation: %v", ns, podName(pod), err)
+		return problems
+	}
+
+	return append(problems, validateOwnerLabels(pod.GetLabels(), owner)...)
+}
+
+// validateADAnnotations returns the problems found in the autodiscovery check
+// and logs annotations of the pod
+func (w *Webhook) validateADAnnotations(pod *corev1.Pod) []string {
+	containers := map[string]struct{}{}
+	for _, c := range pod.Spec.InitContainers {
+		containers[c.Name] = struct{}{}
+	}
+	for _, c := range pod.Spec.Containers {
+		containers[c.Name] = struct{}{}
+	}
+
+	annotations := pod.GetAnnotations()
+	identifiers := map[string]struct{}{}
+	for key := range annotations {
+		if !strings.HasPrefix(key, utils.KubeAnnotationPrefix) {
+			continue
+		}
+		for _, suffix := range adTemplateSuffixes {
+			if identifier, found := strings.CutSuffix(strings.TrimPrefix(key, utils.KubeAnnotationPrefix), "."+suffix); found {
+				identifiers[identifier] = struct{}{}
+			}
+		}
+	}
+
+	var problems []string
+	for _, identifier := range sortedKeys(identifiers) {
+		if _, found := containers[identifier]; !found {
+			problems = append(problems, fmt.Sprintf("autodiscovery annotations %s%s.* don't match any container of the pod", utils.KubeAnnotationPrefix, identifier))
+			continue
+		}
+
+		configs, errs := utils.ExtractTemplatesFromAnnotations(identifier, annotations, identifier)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("invalid autodiscovery annotations for container %q: %v", identifier, err))
+		}
+		if len(errs) > 0 {
+			continue
+		}
+
+		if _, found := annotations[utils.KubeAnnotationPrefix+identifier+".check_names"]; found && !hasCheckConfig(configs) {
+			problems = append(problems, fmt.Sprintf("invalid autodiscovery annotations for container %q: check_names, init_configs and instances don't have the same length", identifier))
+			continue
+		}
+
+		if len(w.knownChecks) == 0 {
+			continue
+		}
+		checkNames, err := utils.ExtractCheckNamesFromPodAnnotations(annotations, identifier)
+		if err != nil {
+			continue
+		}
+		for _, name := range checkNames {
+			if _, known := w.knownChecks[name]; !known {
+				problems = append(problems, fmt.Sprintf("unknown check %q in autodiscovery annotations for container %q", name, identifier))
+			}
+		}
+	}
+
+	return problems
+}
+
+// validateOwnerLabels returns the problems found when comparing the labels of
+// the pod to the ones of its owner
+func validateOwnerLabels(podLabels map[string]string, owner *common.Owner) []string {
+	var problems []string
+
+	podEnabled, podFound := podLabels[common.EnabledLabelKey]
+	ownerEnabled, ownerFound := owner.Labels[common.EnabledLabelKey]
+	if podFound && ownerFound && podEnabled != ownerEnabled {
+		problems = append(problems, fmt.Sprintf("conflicting %s labels: %q on the pod and %q on %s %s", common.EnabledLabelKey, podEnabled, ownerEnabled, owner.Kind, owner.Name))
+	}
+
+	for _, label := range ustLabels {
+		podValue, podFound := podLabels[label]
+		ownerValue, ownerFound := owner.Labels[label]
+		// Labels set only on the pod template or only on the owner are
+		// common and valid, only conflicting values are reported
+		if podFound && ownerFound && podValue != ownerValue {
+			problems = append(problems, fmt.Sprintf("label %s doesn't match: %q on the pod and %q on %s %s", label, podValue, ownerValue, owner.Kind, owner.Name))
+		}
+	}
+
+	return problems
+}
+
+// hasCheckConfig returns whether one of the configs is a check configuration
+func hasCheckConfig(configs []integration.Config) bool {
+	for _, c := range configs {
+		if len(c.Instances) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// podName returns the name of the pod, or its generate name if not set yet
+func podName(pod *corev1.Pod) string {
+	if pod.GetName() != "" {
+		return pod.GetName()
+	}
+	return pod.GetGenerateName()
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,291 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package workloadconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic/fake"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+func newPod(labels, annotations map[string]string, owners ...metav1.OwnerReference) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "foo-pod",
+			Namespace:       "default",
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: owners,
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "redis"}},
+		},
+	}
+}
+
+func newOwnerObject(apiVersion, kind, name string, labels map[string]string, owners ...metav1.OwnerReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	obj.SetOwnerReferences(owners)
+	return obj
+}
+
+func replicaSetOwner() metav1.OwnerReference {
+	return metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "foo-rs", Controller: pointer.Ptr(true)}
+}
+
+func TestValidate(t *testing.T) {
+	deploymentLabels := map[string]string{
+		"tags.datadoghq.com/env":          "prod",
+		"tags.datadoghq.com/service":      "foo",
+		"admission.datadoghq.com/enabled": "true",
+	}
+	replicaSet := newOwnerObject("apps/v1", "ReplicaSet", "foo-rs", nil,
+		metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo", Controller: pointer.Ptr(true)})
+	deployment := newOwnerObject("apps/v1", "Deployment", "foo", deploymentLabels)
+
+	tests := []struct {
+		name        string
+		knownChecks []string
+		pod         *corev1.Pod
+		want        []string
+	}{
+		{
+			name: "valid pod",
+			pod: newPod(
+				map[string]string{"tags.datadoghq.com/env": "prod", "tags.datadoghq.com/service": "foo", "admission.datadoghq.com/enabled": "true"},
+				map[string]string{
+					"ad.datadoghq.com/redis.check_names":  `["redisdb"]`,
+					"ad.datadoghq.com/redis.init_configs": `[{}]`,
+					"ad.datadoghq.com/redis.instances":    `[{"host": "%%host%%"}]`,
+					"ad.datadoghq.com/redis.logs":         `[{"source": "redis"}]`,
+					"ad.datadoghq.com/tags":               `{"team": "foo"}`,
+				},
+				replicaSetOwner(),
+			),
+			knownChecks: []string{"redisdb"},
+		},
+		{
+			name: "invalid JSON",
+			pod: newPod(nil, map[string]string{
+				"ad.datadoghq.com/redis.checks": `{"redisdb": {"instances": [{"host": "%%host%%"}]}`,
+			}),
+			want: []string{`invalid autodiscovery annotations for container "redis": cannot parse check configuration: unexpected end of JSON input`},
+		},
+		{
+			name: "invalid logs configuration",
+			pod: newPod(nil, map[string]string{
+				"ad.datadoghq.com/redis.logs": `{"source": "redis"}`,
+			}),
+			want: []string{`invalid autodiscovery annotations for container "redis": could not extract logs config: invalid format, expected an array, got: 'map[source:redis]'`},
+		},
+		{
+			name: "mismatched lengths",
+			pod: newPod(nil, map[string]string{
+				"ad.datadoghq.com/redis.check_names":  `["redisdb", "openmetrics"]`,
+				"ad.datadoghq.com/redis.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis.instances":    `[{"host": "%%host%%"}]`,
+			}),
+			want: []string{`invalid autodiscovery annotations for container "redis": check_names, init_configs and instances don't have the same length`},
+		},
+		{
+			name: "unknown container",
+			pod: newPod(nil, map[string]string{
+				"ad.datadoghq.com/redis-server.check_names":  `["redisdb"]`,
+				"ad.datadoghq.com/redis-server.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis-server.instances":    `[{"host": "%%host%%"}]`,
+			}),
+			want: []string{"autodiscovery annotations ad.datadoghq.com/redis-server.* don't match any container of the pod"},
+		},
+		{
+			name: "unknown check",
+			pod: newPod(nil, map[string]string{
+				"ad.datadoghq.com/redis.checks": `{"redis": {"instances": [{"host": "%%host%%"}]}}`,
+			}),
+			knownChecks: []string{"redisdb"},
+			want:        []string{`unknown check "redis" in autodiscovery annotations for container "redis"`},
+		},
+		{
+			name: "unknown check without known checks",
+			pod: newPod(nil, map[string]string{
+				"ad.datadoghq.com/redis.checks": `{"redis": {"instances": [{"host": "%%host%%"}]}}`,
+			}),
+		},
+		{
+			name: "invalid enabled label",
+			pod:  newPod(map[string]string{"admission.datadoghq.com/enabled": "yes"}, nil),
+			want: []string{`label admission.datadoghq.com/enabled must be "true" or "false", got "yes"`},
+		},
+		{
+			name: "conflicting enabled label and mismatched tags",
+			pod: newPod(
+				map[string]string{"tags.datadoghq.com/env": "staging", "tags.datadoghq.com/version": "1.2", "admission.datadoghq.com/enabled": "false"},
+				nil,
+				replicaSetOwner(),
+			),
+			want: []string{
+				`conflicting admission.datadoghq.com/enabled labels: "false" on the pod and "true" on Deployment foo`,
+				`label tags.datadoghq.com/env doesn't match: "staging" on the pod and "prod" on Deployment foo`,
+			},
+		},
+		{
+			name: "labels set only on the pod or only on the owner",
+			pod: newPod(
+				map[string]string{"tags.datadoghq.com/env": "prod", "tags.datadoghq.com/version": "1.2"},
+				nil,
+				replicaSetOwner(),
+			),
+		},
+		{
+			name: "unknown owner",
+			pod: newPod(nil, nil,
+				metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "foo-job", Controller: pointer.Ptr(true)}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfig := configmock.New(t)
+			mockConfig.SetWithoutSource("admission_controller.workload_config_validation.known_checks", tt.knownChecks)
+
+			dc := fake.NewSimpleDynamicClient(kscheme.Scheme, replicaSet, deployment)
+			assert.Equal(t, tt.want, NewWebhook().validate(tt.pod, "default", dc))
+		})
+	}
+}
+
+func TestWebhookFunc(t *testing.T) {
+	pod := newPod(map[string]string{"admission.datadoghq.com/enabled": "yes"}, nil)
+	raw, err := json.Marshal(pod)
+	require.NoError(t, err)
+
+	validPod := newPod(map[string]string{"admission.datadoghq.com/enabled": "true"}, nil)
+	validRaw, err := json.Marshal(validPod)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		mode         string
+		raw          []byte
+		wantAllowed  bool
+		wantWarnings []string
+		wantMessage  string
+	}{
+		{
+			name:         "warn mode",
+			mode:         "warn",
+			raw:          raw,
+			wantAllowed:  true,
+			wantWarnings: []string{`label admission.datadoghq.com/enabled must be "true" or "false", got "yes"`},
+		},
+		{
+			name:        "reject mode",
+			mode:        "reject",
+			raw:         raw,
+			wantAllowed: false,
+			wantMessage: `invalid Datadog configuration: label admission.datadoghq.com/enabled must be "true" or "false", got "yes"`,
+		},
+		{
+			name:         "unknown mode falls back to warn",
+			mode:         "block",
+			raw:          raw,
+			wantAllowed:  true,
+			wantWarnings: []string{`label admission.datadoghq.com/enabled must be "true" or "false", got "yes"`},
+		},
+		{
+			name:        "valid pod in reject mode",
+			mode:        "reject",
+			raw:         validRaw,
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfig := configmock.New(t)
+			mockConfig.SetWithoutSource("admission_controller.workload_config_validation.mode", tt.mode)
+
+			response := NewWebhook().WebhookFunc()(&admission.Request{Raw: tt.raw, Namespace: "default"})
+			assert.Equal(t, tt.wantAllowed, response.Allowed)
+			assert.Equal(t, tt.wantWarnings, []string(response.Warnings))
+			if tt.wantMessage != "" {
+				require.NotNil(t, response.Result)
+				assert.Equal(t, tt.wantMessage, response.Result.Message)
+			} else {
+				assert.Nil(t, response.Result)
+			}
+		})
+	}
+}
+
+func TestLabelSelectors(t *testing.T) {
+	excludedNamespaces := metav1.LabelSelectorRequirement{
+		Key:      "kubernetes.io/metadata.name",
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{"kube-system", "datadog"},
+	}
+	enabledLabel := metav1.LabelSelectorRequirement{
+		Key:      "admission.datadoghq.com/enabled",
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   []string{"false"},
+	}
+
+	tests := []struct {
+		name                 string
+		mutateUnlabelled     bool
+		useNamespaceSelector bool
+		wantNamespace        *metav1.LabelSelector
+		wantObject           *metav1.LabelSelector
+	}{
+		{
+			name:          "object selector",
+			wantNamespace: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{excludedNamespaces}},
+			wantObject:    &metav1.LabelSelector{MatchLabels: map[string]string{"admission.datadoghq.com/enabled": "true"}},
+		},
+		{
+			name:             "object selector with unlabelled pods",
+			mutateUnlabelled: true,
+			wantNamespace:    &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{excludedNamespaces}},
+			wantObject:       &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{enabledLabel}},
+		},
+		{
+			name:                 "namespace selector",
+			mutateUnlabelled:     true,
+			useNamespaceSelector: true,
+			wantNamespace:        &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{enabledLabel, excludedNamespaces}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfig := configmock.New(t)
+			mockConfig.SetWithoutSource("kube_resources_namespace", "datadog")
+			mockConfig.SetWithoutSource("admission_controller.mutate_unlabelled", tt.mutateUnlabelled)
+
+			namespaceSelector, objectSelector := NewWebhook().LabelSelectors(tt.useNamespaceSelector)
+			assert.Equal(t, tt.wantNamespace, namespaceSelector)
+			assert.Equal(t, tt.wantObject, objectSelector)
+		})
+	}
+}
@@ -273,6 +273,9 @@ func (c *Controller) syncPodAutoscaler(ctx context.Context, key, ns, name string
 		}
 	}
 
+	// Annotations are always set in Kubernetes, whatever the owner
+	podAutoscalerInternal.UpdateFromAnnotations(podAutoscaler.Annotations)
+
 	// Reaching this point, we had an error in processing, clearing up global error
 	podAutoscalerInternal.SetError(nil)
 
@@ -427,6 +430,10 @@ func (c *Controller) validateAutoscaler(podAutoscaler *datadoghq.DatadogPodAutos
 	if podAutoscaler.Namespace == clusterAgentNs && podAutoscaler.Spec.TargetRef.Name == resourceName {
 		return fmt.Errorf("Autoscaling target cannot be set to the cluster agent")
 	}
+
+	if _, err := model.ParseLocalRecommenderSettings(podAutoscaler.Annotations); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,276 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload/model"
+	le "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	localRecommenderStoreID string = "lr"
+)
+
+// Subinterface of autoscalers.ProcessorInterface to allow mocking
+type metricsQuerier interface {
+	QueryExternalMetric(queries []string, timeWindow time.Duration) (map[string]autoscalers.Point, error)
+}
+
+// localRecommendation is a replicas recommendation computed by the local recommender at a given time
+type localRecommendation struct {
+	timestamp time.Time
+	replicas  int32
+}
+
+// localRecommender computes horizontal scaling values from metrics queried by the Cluster Agent
+// for PodAutoscalers having the local recommender annotation.
+// Computed values are stored in the PodAutoscaler, they are applied by the controller like any other recommendation.
+type localRecommender struct {
+	store         *store
+	isLeader      func() bool
+	clock         clock.Clock
+	querier       metricsQuerier
+	refreshPeriod time.Duration
+	timeWindow    time.Duration
+
+	// history keeps the raw recommendations per PodAutoscaler ID, used to implement stabilization windows.
+	// Only accessed from the recommender goroutine.
+	history map[string][]localRecommendation
+}
+
+func newLocalRecommender(store *store, isLeader func() bool, querier metricsQuerier, refreshPeriod, timeWindow time.Duration) *localRecommender {
+	return &localRecommender{
+		store:         store,
+		isLeader:      isLeader,
+		clock:         clock.RealClock{},
+		querier:       querier,
+		refreshPeriod: refreshPeriod,
+		timeWindow:    timeWindow,
+		history:       make(map[string][]localRecommendation),
+	}
+}
+
+// Run starts the local recommender loop
+func (lr *localRecommender) Run(ctx context.Context) {
+	log.Debugf("Starting local horizontal recommender, refresh period: %v", lr.refreshPeriod)
+
+	ticker := time.NewTicker(lr.refreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debugf("Stopping local horizontal recommender")
+			return
+		case <-ticker.C:
+			lr.process()
+		}
+	}
+}
+
+func (lr *localRecommender) process() {
+	// Only the leader is allowed to compute recommendations.
+	// History is reset as it would be outdated when becoming leader again.
+	if !lr.isLeader() {
+		clear(lr.history)
+		return
+	}
+
+	podAutoscalers := lr.store.GetFiltered(func(podAutoscaler model.PodAutoscalerInternal) bool {
+		return podAutoscaler.LocalRecommender() != nil
+	})
+
+	// Querying all metrics at once, as the processor is batching queries
+	seenQueries := make(map[string]struct{})
+	var queries []string
+	for _, podAutoscaler := range podAutoscalers {
+		for _, metric := range podAutoscaler.LocalRecommender().Metrics {
+			if _, found := seenQueries[metric.Query]; !found {
+				seenQueries[metric.Query] = struct{}{}
+				queries = append(queries, metric.Query)
+			}
+		}
+	}
+
+	var points map[string]autoscalers.Point
+	var queryErr error
+	if len(queries) > 0 {
+		points, queryErr = lr.querier.QueryExternalMetric(queries, lr.timeWindow)
+		if queryErr != nil {
+			queryErr = fmt.Errorf("failed to query metrics for local recommendations: %w", queryErr)
+		}
+	}
+
+	now := lr.clock.Now()
+	processed := make(map[string]struct{}, len(podAutoscalers))
+	for _, podAutoscaler := range podAutoscalers {
+		id := podAutoscaler.ID()
+		processed[id] = struct{}{}
+
+		var horizontal *model.HorizontalScalingValues
+		err := queryErr
+		if err == nil {
+			horizontal, err = lr.recommend(now, podAutoscaler, points)
+		}
+
+		if err != nil {
+			log.Debugf("Unable to compute local recommendation for PodAutoscaler %s: %v", id, err)
+		}
+		lr.updateStore(id, horizontal, err)
+	}
+
+	// Cleaning up history for PodAutoscalers that are gone or not using the local recommender anymore
+	for id := range lr.history {
+		if _, found := processed[id]; !found {
+			delete(lr.history, id)
+		}
+	}
+}
+
+func (lr *localRecommender) recommend(now time.Time, podAutoscaler model.PodAutoscalerInternal, points map[string]autoscalers.Point) (*model.HorizontalScalingValues, error) {
+	settings := podAutoscaler.LocalRecommender()
+	recommendedReplicas, err := computeLocalReplicas(settings, points)
+	if err != nil {
+		return nil, err
+	}
+
+	// Using the current number of replicas as reference for stabilization.
+	// If unknown (not synced yet by the controller), the raw recommendation is used.
+	currentReplicas := recommendedReplicas
+	if podAutoscaler.CurrentReplicas() != nil {
+		currentReplicas = *podAutoscaler.CurrentReplicas()
+	}
+
+	var stabilizedReplicas int32
+	stabilizedReplicas, lr.history[podAutoscaler.ID()] = stabilizeRecommendation(
+		now,
+		lr.history[podAutoscaler.ID()],
+		recommendedReplicas,
+		currentReplicas,
+		settings.ScaleUpStabilizationWindow(),
+		settings.ScaleDownStabilizationWindow(),
+	)
+
+	return &model.HorizontalScalingValues{
+		Source:    model.LocalValueSource,
+		Timestamp: now,
+		Replicas:  stabilizedReplicas,
+	}, nil
+}
+
+func (lr *localRecommender) updateStore(id string, horizontal *model.HorizontalScalingValues, err error) {
+	podAutoscaler, podAutoscalerFound := lr.store.LockRead(id, false)
+	// The PodAutoscaler may have been deleted or updated since we listed it
+	if !podAutoscalerFound {
+		return
+	}
+	if podAutoscaler.LocalRecommender() == nil {
+		lr.store.Unlock(id)
+		return
+	}
+
+	// Avoid triggering a reconcile (and a status update) if nothing has changed
+	currentValues := podAutoscaler.ScalingValues()
+	if err == nil && currentValues.HorizontalError == nil &&
+		currentValues.Horizontal != nil && horizontal != nil &&
+		currentValues.Horizontal.Source == horizontal.Source &&
+		currentValues.Horizontal.Replicas == horizontal.Replicas {
+		lr.store.Unlock(id)
+		return
+	}
+
+	podAutoscaler.UpdateFromLocalValues(horizontal, err)
+	lr.store.UnlockSet(id, podAutoscaler, localRecommenderStoreID)
+
+	if horizontal != nil && podAutoscaler.Spec() != nil {
+		telemetryHorizontalScaleReceivedRecommendations.Set(
+			float64(horizontal.Replicas),
+			podAutoscaler.Namespace(),
+			podAutoscaler.Spec().TargetRef.Name,
+			podAutoscaler.Name(),
+			string(horizontal.Source),
+			le.JoinLeaderValue,
+		)
+	}
+}
+
+// computeLocalReplicas computes the desired number of replicas from metrics values, using target-per-replica semantics.
+// When multiple metrics are configured, the highest number of replicas is used.
+func computeLocalReplicas(settings *model.LocalRecommenderSettings, points map[string]autoscalers.Point) (int32, error) {
+	var replicas int32
+	for _, metric := range settings.Metrics {
+		point, found := points[metric.Query]
+		if !found {
+			return 0, fmt.Errorf("no value returned for query: %s", metric.Query)
+		}
+		if !point.Valid {
+			if point.Error != nil {
+				return 0, fmt.Errorf("invalid value returned for query: %s, err: %w", metric.Query, point.Error)
+			}
+			return 0, fmt.Errorf("invalid value returned for query: %s", metric.Query)
+		}
+
+		metricReplicas := math.Ceil(point.Value / metric.TargetPerReplica)
+		if metricReplicas >= math.MaxInt32 {
+			metricReplicas = math.MaxInt32
+		}
+		replicas = max(replicas, int32(max(metricReplicas, 0)))
+	}
+
+	return replicas, nil
+}
+
+// stabilizeRecommendation implements stabilization windows the same way as the Kubernetes HPA:
+// when scaling up, the lowest recommendation over the scale up window is used,
+// when scaling down, the highest recommendation over the scale down window is used.
+// It returns the stabilized recommendation and the updated history.
+func stabilizeRecommendation(
+	now time.Time,
+	history []localRecommendation,
+	recommendedReplicas, currentReplicas int32,
+	scaleUpWindow, scaleDownWindow time.Duration,
+) (int32, []localRecommendation) {
+	// Dropping recommendations that are outside of both windows
+	longestWindow := max(scaleUpWindow, scaleDownWindow)
+	firstIndex := 0
+	for firstIndex < len(history) && now.Sub(history[firstIndex].timestamp) > longestWindow {
+		firstIndex++
+	}
+	history = append(history[firstIndex:], localRecommendation{timestamp: now, replicas: recommendedReplicas})
+
+	upRecommendation := recommendedReplicas
+	downRecommendation := recommendedReplicas
+	for _, rec := range history {
+		age := now.Sub(rec.timestamp)
+		if age <= scaleUpWindow {
+			upRecommendation = min(upRecommendation, rec.replicas)
+		}
+		if age <= scaleDownWindow {
+			downRecommendation = max(downRecommendation, rec.replicas)
+		}
+	}
+
+	stabilizedReplicas := currentReplicas
+	if stabilizedReplicas < upRecommendation {
+		stabilizedReplicas = upRecommendation
+	}
+	if stabilizedReplicas > downRecommendation {
+		stabilizedReplicas = downRecommendation
+	}
+
+	return stabilizedReplicas, history
+}
@@ -0,0 +1,216 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package workload
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	clock "k8s.io/utils/clock/testing"
+
+	datadoghq "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload/model"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+type fakeMetricsQuerier struct {
+	points  map[string]autoscalers.Point
+	err     error
+	queries []string
+}
+
+func (f *fakeMetricsQuerier) QueryExternalMetric(queries []string, _ time.Duration) (map[string]autoscalers.Point, error) {
+	f.queries = queries
+	return f.points, f.err
+}
+
+func TestComputeLocalReplicas(t *testing.T) {
+	settings := &model.LocalRecommenderSettings{
+		Metrics: []model.LocalRecommenderMetric{
+			{Query: "max:kafka.consumer_lag{consumer_group:workers}", TargetPerReplica: 1000},
+			{Query: "avg:aws.sqs.approximate_number_of_messages_visible{queuename:jobs}", TargetPerReplica: 50},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		points           map[string]autoscalers.Point
+		expectedReplicas int32
+		expectedErr      string
+	}{
+		{
+			name: "highest number of replicas is used",
+			points: map[string]autoscalers.Point{
+				"max:kafka.consumer_lag{consumer_group:workers}":                    {Value: 4500, Valid: true},
+				"avg:aws.sqs.approximate_number_of_messages_visible{queuename:jobs}": {Value: 120, Valid: true},
+			},
+			expectedReplicas: 5,
+		},
+		{
+			name: "empty queues recommend zero replicas",
+			points: map[string]autoscalers.Point{
+				"max:kafka.consumer_lag{consumer_group:workers}":                    {Value: 0, Valid: true},
+				"avg:aws.sqs.approximate_number_of_messages_visible{queuename:jobs}": {Value: -1, Valid: true},
+			},
+			expectedReplicas: 0,
+		},
+		{
+			name: "missing metric",
+			points: map[string]autoscalers.Point{
+				"max:kafka.consumer_lag{consumer_group:workers}": {Value: 4500, Valid: true},
+			},
+			expectedErr: "no value returned for query: avg:aws.sqs.approximate_number_of_messages_visible{queuename:jobs}",
+		},
+		{
+			name: "invalid metric",
+			points: map[string]autoscalers.Point{
+				"max:kafka.consumer_lag{consumer_group:workers}":                    {Value: 4500, Valid: true},
+				"avg:aws.sqs.approximate_number_of_messages_visible{queuename:jobs}": {Valid: false, Error: errors.New("stale")},
+			},
+			expectedErr: "invalid value returned for query: avg:aws.sqs.approximate_number_of_messages_visible{queuename:jobs}, err: stale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas, err := computeLocalReplicas(settings, tt.points)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedReplicas, replicas)
+		})
+	}
+}
+
+func TestStabilizeRecommendation(t *testing.T) {
+	testTime := time.Now()
+	upWindow := time.Minute
+	downWindow := 5 * time.Minute
+
+	var history []localRecommendation
+	var replicas int32
+
+	// No history, scale up to the recommendation as the up window contains a single recommendation
+	replicas, history = stabilizeRecommendation(testTime, history, 10, 5, upWindow, downWindow)
+	assert.Equal(t, int32(10), replicas)
+
+	// Recommendation drops, scale down is prevented by the down window
+	replicas, history = stabilizeRecommendation(testTime.Add(30*time.Second), history, 4, 10, upWindow, downWindow)
+	assert.Equal(t, int32(10), replicas)
+
+	// Recommendation increases again, scale up is limited to the lowest recommendation in the up window
+	replicas, history = stabilizeRecommendation(testTime.Add(60*time.Second), history, 12, 10, upWindow, downWindow)
+	assert.Equal(t, int32(10), replicas)
+
+	// Up window has expired for the lower recommendation, scaling up
+	replicas, history = stabilizeRecommendation(testTime.Add(100*time.Second), history, 12, 10, upWindow, downWindow)
+	assert.Equal(t, int32(12), replicas)
+
+	// Down window has expired for all recommendations, scaling down
+	replicas, history = stabilizeRecommendation(testTime.Add(100*time.Second+downWindow+time.Second), history, 3, 12, upWindow, downWindow)
+	assert.Equal(t, int32(3), replicas)
+	assert.Len(t, history, 1)
+}
+
+func TestLocalRecommenderProcess(t *testing.T) {
+	testTime := time.Now()
+	isLeader := true
+	store := autoscaling.NewStore[model.PodAutoscalerInternal]()
+	querier := &fakeMetricsQuerier{
+		points: map[string]autoscalers.Point{
+			"max:kafka.consumer_lag{consumer_group:workers}": {Value: 2500, Valid: true},
+		},
+	}
+	lr := newLocalRecommender(store, func() bool { return isLeader }, querier, time.Minute, time.Minute)
+	fakeClock := clock.NewFakeClock(testTime)
+	lr.clock = fakeClock
+
+	settings := &model.LocalRecommenderSettings{
+		Metrics: []model.LocalRecommenderMetric{
+			{Query: "max:kafka.consumer_lag{consumer_group:workers}", TargetPerReplica: 1000},
+		},
+		ScaleDownStabilizationWindowSeconds: pointer.Ptr[int32](0),
+	}
+	spec := &datadoghq.DatadogPodAutoscalerSpec{
+		TargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "workers", APIVersion: "apps/v1"},
+	}
+	local := model.FakePodAutoscalerInternal{
+		Namespace:        "ns",
+		Name:             "workers",
+		Spec:             spec,
+		LocalRecommender: settings,
+		CurrentReplicas:  pointer.Ptr[int32](1),
+	}
+	remote := model.FakePodAutoscalerInternal{
+		Namespace: "ns",
+		Name:      "other",
+		Spec:      spec,
+	}
+	store.Set("ns/workers", local.Build(), "unittest")
+	store.Set("ns/other", remote.Build(), "unittest")
+
+	// Not leader, nothing happens
+	isLeader = false
+	lr.process()
+	assert.Nil(t, querier.queries)
+	model.AssertPodAutoscalersEqual(t, []model.FakePodAutoscalerInternal{local, remote}, store.GetAll())
+
+	// Leader, values computed for the autoscaler with the local recommender only
+	isLeader = true
+	lr.process()
+	assert.Equal(t, []string{"max:kafka.consumer_lag{consumer_group:workers}"}, querier.queries)
+	local.ScalingValues = model.ScalingValues{
+		Horizontal: &model.HorizontalScalingValues{
+			Source:    model.LocalValueSource,
+			Timestamp: testTime,
+			Replicas:  3,
+		},
+	}
+	model.AssertPodAutoscalersEqual(t, []model.FakePodAutoscalerInternal{local, remote}, store.GetAll())
+
+	// Values received from RC do not override local horizontal values
+	podAutoscaler, _ := store.Get("ns/workers")
+	podAutoscaler.UpdateFromValues(model.ScalingValues{
+		Horizontal: &model.HorizontalScalingValues{
+			Source:    datadoghq.DatadogPodAutoscalerAutoscalingValueSource,
+			Timestamp: testTime,
+			Replicas:  10,
+		},
+	})
+	assert.Equal(t, local.ScalingValues, podAutoscaler.ScalingValues())
+
+	// Query error, last values are kept with the error
+	fakeClock.Step(time.Minute)
+	querier.err = errors.New("rate limited")
+	lr.process()
+	podAutoscaler, _ = store.Get("ns/workers")
+	assert.Equal(t, local.ScalingValues.Horizontal, podAutoscaler.ScalingValues().Horizontal)
+	assert.EqualError(t, podAutoscaler.ScalingValues().HorizontalError, "failed to query metrics for local recommendations: rate limited")
+
+	// Metric value changes, new values are computed and error is cleared
+	fakeClock.Step(time.Minute)
+	querier.err = nil
+	querier.points["max:kafka.consumer_lag{consumer_group:workers}"] = autoscalers.Point{Value: 500, Valid: true}
+	lr.process()
+	local.ScalingValues = model.ScalingValues{
+		Horizontal: &model.HorizontalScalingValues{
+			Source:    model.LocalValueSource,
+			Timestamp: testTime.Add(2 * time.Minute),
+			Replicas:  1,
+		},
+	}
+	model.AssertPodAutoscalersEqual(t, []model.FakePodAutoscalerInternal{local, remote}, store.GetAll())
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	datadoghq "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+const (
+	// LocalRecommenderAnnotation is the annotation key used to configure the local horizontal recommender on a DatadogPodAutoscaler
+	LocalRecommenderAnnotation = "autoscaling.datadoghq.com/local-recommender"
+
+	// LocalValueSource is the source of scaling values computed by the Cluster Agent local recommender
+	LocalValueSource datadoghq.DatadogPodAutoscalerValueSource = "Local"
+
+	// defaultScaleDownStabilizationWindow is the default scale down stabilization window (same as the Kubernetes HPA)
+	defaultScaleDownStabilizationWindow = 5 * time.Minute
+
+	// maxStabilizationWindow is the maximum stabilization window allowed
+	maxStabilizationWindow = time.Hour
+)
+
+// LocalRecommenderSettings holds the configuration of the local horizontal recommender,
+// parsed from the `autoscaling.datadoghq.com/local-recommender` annotation.
+type LocalRecommenderSettings struct {
+	// Metrics is the list of metrics used to compute the desired number of replicas.
+	// The highest number of replicas computed from all metrics is used.
+	Metrics []LocalRecommenderMetric `json:"metrics"`
+
+	// ScaleUpStabilizationWindowSeconds is the duration during which past recommendations are considered when scaling up
+	// Defaults to 0 (scale up immediately)
+	ScaleUpStabilizationWindowSeconds *int32 `json:"scaleUpStabilizationWindowSeconds,omitempty"`
+
+	// ScaleDownStabilizationWindowSeconds is the duration during which past recommendations are considered when scaling down
+	// Defaults to 300 (5 minutes)
+	ScaleDownStabilizationWindowSeconds *int32 `json:"scaleDownStabilizationWindowSeconds,omitempty"`
+}
+
+// LocalRecommenderMetric is a metric used by the local horizontal recommender
+type LocalRecommenderMetric struct {
+	// Query is a Datadog metric query returning a single value (e.g. `max:kafka.consumer_lag{consumer_group:workers}`)
+	Query string `json:"query"`
+
+	// TargetPerReplica is the value of the metric that a single replica is expected to handle
+	TargetPerReplica float64 `json:"targetPerReplica"`
+}
+
+// ParseLocalRecommenderSettings parses the local recommender settings from the annotations of a DatadogPodAutoscaler.
+// Returns nil if the annotation is not present.
+func ParseLocalRecommenderSettings(annotations map[string]string) (*LocalRecommenderSettings, error) {
+	rawSettings, found := annotations[LocalRecommenderAnnotation]
+	if !found {
+		return nil, nil
+	}
+
+	settings := &LocalRecommenderSettings{}
+	if err := json.Unmarshal([]byte(rawSettings), settings); err != nil {
+		return nil, fmt.Errorf("unable to parse annotation %s: %w", LocalRecommenderAnnotation, err)
+	}
+
+	if err := settings.validate(); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %w", LocalRecommenderAnnotation, err)
+	}
+
+	return settings, nil
+}
+
+func (s *LocalRecommenderSettings) validate() error {
+	if len(s.Metrics) == 0 {
+		return errors.New("at least one metric is required")
+	}
+
+	for i, metric := range s.Metrics {
+		if metric.Query == "" {
+			return fmt.Errorf("metric %d: query is required", i)
+		}
+		if metric.TargetPerReplica <= 0 {
+			return fmt.Errorf("metric %d: targetPerReplica must be strictly positive", i)
+		}
+	}
+
+	for name, window := range map[string]*int32{
+		"scaleUpStabilizationWindowSeconds":   s.ScaleUpStabilizationWindowSeconds,
+		"scaleDownStabilizationWindowSeconds": s.ScaleDownStabilizationWindowSeconds,
+	} {
+		if window != nil && (*window < 0 || time.Duration(*window)*time.Second > maxStabilizationWindow) {
+			return fmt.Errorf("%s must be between 0 and %d", name, int(maxStabilizationWindow.Seconds()))
+		}
+	}
+
+	return nil
+}
+
+// ScaleUpStabilizationWindow returns the scale up stabilization window
+func (s *LocalRecommenderSettings) ScaleUpStabilizationWindow() time.Duration {
+	if s.ScaleUpStabilizationWindowSeconds == nil {
+		return 0
+	}
+	return time.Duration(*s.ScaleUpStabilizationWindowSeconds) * time.Second
+}
+
+// ScaleDownStabilizationWindow returns the scale down stabilization window
+func (s *LocalRecommenderSettings) ScaleDownStabilizationWindow() time.Duration {
+	if s.ScaleDownStabilizationWindowSeconds == nil {
+		return defaultScaleDownStabilizationWindow
+	}
+	return time.Duration(*s.ScaleDownStabilizationWindowSeconds) * time.Second
+}
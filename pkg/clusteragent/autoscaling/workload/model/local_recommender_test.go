@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && test
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+func TestParseLocalRecommenderSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    *LocalRecommenderSettings
+		expectedErr string
+	}{
+		{
+			name:        "no annotation",
+			annotations: map[string]string{"foo": "bar"},
+		},
+		{
+			name: "valid settings",
+			annotations: map[string]string{
+				LocalRecommenderAnnotation: `{"metrics":[{"query":"max:kafka.consumer_lag{*}","targetPerReplica":100}],"scaleUpStabilizationWindowSeconds":30}`,
+			},
+			expected: &LocalRecommenderSettings{
+				Metrics: []LocalRecommenderMetric{
+					{Query: "max:kafka.consumer_lag{*}", TargetPerReplica: 100},
+				},
+				ScaleUpStabilizationWindowSeconds: pointer.Ptr[int32](30),
+			},
+		},
+		{
+			name:        "invalid json",
+			annotations: map[string]string{LocalRecommenderAnnotation: `{"metrics":`},
+			expectedErr: "unable to parse annotation autoscaling.datadoghq.com/local-recommender: unexpected end of JSON input",
+		},
+		{
+			name:        "no metrics",
+			annotations: map[string]string{LocalRecommenderAnnotation: `{"metrics":[]}`},
+			expectedErr: "invalid annotation autoscaling.datadoghq.com/local-recommender: at least one metric is required",
+		},
+		{
+			name:        "invalid target",
+			annotations: map[string]string{LocalRecommenderAnnotation: `{"metrics":[{"query":"max:foo{*}","targetPerReplica":0}]}`},
+			expectedErr: "invalid annotation autoscaling.datadoghq.com/local-recommender: metric 0: targetPerReplica must be strictly positive",
+		},
+		{
+			name:        "invalid window",
+			annotations: map[string]string{LocalRecommenderAnnotation: `{"metrics":[{"query":"max:foo{*}","targetPerReplica":1}],"scaleDownStabilizationWindowSeconds":7200}`},
+			expectedErr: "invalid annotation autoscaling.datadoghq.com/local-recommender: scaleDownStabilizationWindowSeconds must be between 0 and 3600",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := ParseLocalRecommenderSettings(tt.annotations)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, settings)
+		})
+	}
+}
+
+func TestLocalRecommenderSettingsWindows(t *testing.T) {
+	settings := LocalRecommenderSettings{}
+	assert.Equal(t, time.Duration(0), settings.ScaleUpStabilizationWindow())
+	assert.Equal(t, 5*time.Minute, settings.ScaleDownStabilizationWindow())
+
+	settings.ScaleUpStabilizationWindowSeconds = pointer.Ptr[int32](60)
+	settings.ScaleDownStabilizationWindowSeconds = pointer.Ptr[int32](0)
+	assert.Equal(t, time.Minute, settings.ScaleUpStabilizationWindow())
+	assert.Equal(t, time.Duration(0), settings.ScaleDownStabilizationWindow())
+}
+
+func TestUpdateFromAnnotations(t *testing.T) {
+	pai := PodAutoscalerInternal{}
+	pai.UpdateFromAnnotations(map[string]string{
+		LocalRecommenderAnnotation: `{"metrics":[{"query":"max:foo{*}","targetPerReplica":1}]}`,
+	})
+	assert.NotNil(t, pai.LocalRecommender())
+
+	pai.UpdateFromLocalValues(&HorizontalScalingValues{Source: LocalValueSource, Replicas: 3}, nil)
+	pai.RemoveValues()
+	assert.Equal(t, int32(3), pai.ScalingValues().Horizontal.Replicas)
+
+	// Removing the annotation clears local values
+	pai.UpdateFromAnnotations(nil)
+	assert.Nil(t, pai.LocalRecommender())
+	assert.Nil(t, pai.ScalingValues().Horizontal)
+}
@@ -52,6 +52,10 @@ type PodAutoscalerInternal struct {
 	// scalingValues represents the current target scaling values (retrieved from RC)
 	scalingValues ScalingValues
 
+	// localRecommender is the configuration of the local horizontal recommender (retrieved from annotations)
+	// When set, horizontal scaling values are computed locally and not retrieved from RC
+	localRecommender *LocalRecommenderSettings
+
 	// horizontalLastActions is the last horizontal action successfully taken
 	horizontalLastActions []datadoghq.DatadogPodAutoscalerHorizontalAction
 
@@ -129,6 +133,21 @@ func (p *PodAutoscalerInternal) UpdateFromPodAutoscaler(podAutoscaler *datadoghq
 	p.targetGVK = schema.GroupVersionKind{}
 	// Compute the horizontal events retention again in case .Spec.Policy has changed
 	p.horizontalEventsRetention = getHorizontalEventsRetention(podAutoscaler.Spec.Policy, longestScalingRulePeriodAllowed)
+	p.UpdateFromAnnotations(podAutoscaler.Annotations)
+}
+
+// UpdateFromAnnotations updates the PodAutoscalerInternal from the annotations of the PodAutoscaler object inside K8S.
+// Annotations changes do not increase the generation, so it needs to be called independently of UpdateFromPodAutoscaler.
+func (p *PodAutoscalerInternal) UpdateFromAnnotations(annotations map[string]string) {
+	// Invalid settings are reported by the controller validation, considering the local recommender as disabled
+	localRecommender, _ := ParseLocalRecommenderSettings(annotations)
+
+	// Clear values computed by the local recommender if it's not enabled anymore
+	if p.localRecommender != nil && localRecommender == nil {
+		p.scalingValues.Horizontal = nil
+		p.scalingValues.HorizontalError = nil
+	}
+	p.localRecommender = localRecommender
 }
 
 // UpdateFromSettings updates the PodAutoscalerInternal from a new settings
@@ -145,12 +164,26 @@ func (p *PodAutoscalerInternal) UpdateFromSettings(podAutoscalerSpec *datadoghq.
 
 // UpdateFromValues updates the PodAutoscalerInternal from a new scaling values
 func (p *PodAutoscalerInternal) UpdateFromValues(scalingValues ScalingValues) {
+	// Horizontal values are owned by the local recommender when enabled
+	if p.localRecommender != nil {
+		scalingValues.Horizontal = p.scalingValues.Horizontal
+		scalingValues.HorizontalError = p.scalingValues.HorizontalError
+	}
 	p.scalingValues = scalingValues
 }
 
+// UpdateFromLocalValues updates the PodAutoscalerInternal from horizontal scaling values computed by the local recommender.
+// On error, the last computed values are kept.
+func (p *PodAutoscalerInternal) UpdateFromLocalValues(horizontal *HorizontalScalingValues, err error) {
+	p.scalingValues.HorizontalError = err
+	if err == nil {
+		p.scalingValues.Horizontal = horizontal
+	}
+}
+
 // RemoveValues clears autoscaling values data from the PodAutoscalerInternal as we stopped autoscaling
 func (p *PodAutoscalerInternal) RemoveValues() {
-	p.scalingValues = ScalingValues{}
+	p.UpdateFromValues(ScalingValues{})
 }
 
 // UpdateFromHorizontalAction updates the PodAutoscalerInternal from a new horizontal action
@@ -310,6 +343,11 @@ func (p *PodAutoscalerInternal) ScalingValues() ScalingValues {
 	return p.scalingValues
 }
 
+// LocalRecommender returns the local horizontal recommender settings, nil if not enabled
+func (p *PodAutoscalerInternal) LocalRecommender() *LocalRecommenderSettings {
+	return p.localRecommender
+}
+
 // HorizontalLastActions returns the last horizontal actions taken
 func (p *PodAutoscalerInternal) HorizontalLastActions() []datadoghq.DatadogPodAutoscalerHorizontalAction {
 	return p.horizontalLastActions
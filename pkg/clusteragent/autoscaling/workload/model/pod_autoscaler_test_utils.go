@@ -30,6 +30,7 @@ type FakePodAutoscalerInternal struct {
 	Spec                      *datadoghq.DatadogPodAutoscalerSpec
 	SettingsTimestamp         time.Time
 	ScalingValues             ScalingValues
+	LocalRecommender          *LocalRecommenderSettings
 	HorizontalLastActions     []datadoghq.DatadogPodAutoscalerHorizontalAction
 	HorizontalLastLimitReason string
 	HorizontalLastActionError error
@@ -52,6 +53,7 @@ func (f FakePodAutoscalerInternal) Build() PodAutoscalerInternal {
 		spec:                      f.Spec,
 		settingsTimestamp:         f.SettingsTimestamp,
 		scalingValues:             f.ScalingValues,
+		localRecommender:          f.LocalRecommender,
 		horizontalLastActions:     f.HorizontalLastActions,
 		horizontalLastLimitReason: f.HorizontalLastLimitReason,
 		horizontalLastActionError: f.HorizontalLastActionError,
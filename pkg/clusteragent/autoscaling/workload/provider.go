@@ -10,18 +10,24 @@ package workload
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 
+	datadogclient "github.com/DataDog/datadog-agent/comp/autoscaling/datadogclient/def"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload/model"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
 )
 
 // StartWorkloadAutoscaling starts the workload autoscaling controller
@@ -32,11 +38,19 @@ func StartWorkloadAutoscaling(
 	rcClient rcClient,
 	wlm workloadmeta.Component,
 	senderManager sender.SenderManager,
+	datadogClient optional.Option[datadogclient.Component],
 ) (PodPatcher, error) {
 	if apiCl == nil {
 		return nil, fmt.Errorf("Impossible to start workload autoscaling without valid APIClient")
 	}
 
+	// The local recommender relies on the Datadog client used by the external metrics provider to query metrics
+	dc, localRecommenderEnabled := datadogClient.Get()
+	refreshPeriod := time.Duration(pkgconfigsetup.Datadog().GetInt64("autoscaling.workload.local_recommender.refresh_period")) * time.Second
+	if localRecommenderEnabled && refreshPeriod <= 0 {
+		return nil, fmt.Errorf("Invalid autoscaling.workload.local_recommender.refresh_period: %v, must be strictly positive", refreshPeriod)
+	}
+
 	le, err := leaderelection.GetLeaderEngine()
 	if err != nil {
 		return nil, fmt.Errorf("Unable to start workload autoscaling as LeaderElection failed with: %v", err)
@@ -75,5 +89,12 @@ func StartWorkloadAutoscaling(
 	go podWatcher.Run(ctx)
 	go controller.Run(ctx)
 
+	if localRecommenderEnabled {
+		localRecommender := newLocalRecommender(store, le.IsLeader, autoscalers.NewProcessor(dc), refreshPeriod, autoscalers.GetDefaultTimeWindow())
+		go localRecommender.Run(ctx)
+	} else {
+		log.Infof("Datadog client is not available (external_metrics_provider disabled), PodAutoscalers using annotation %s will not receive recommendations", model.LocalRecommenderAnnotation)
+	}
+
 	return podPatcher, nil
 }
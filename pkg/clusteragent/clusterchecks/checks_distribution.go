@@ -23,6 +23,9 @@ type RunnerStatus struct {
 	Workers     int
 	WorkersUsed float64
 	NumChecks   int
+	// CapacityWeight scales the workers of the runner according to the
+	// capacity of its node. Zero means no scaling.
+	CapacityWeight float64
 }
 
 func (ns RunnerStatus) utilization() float64 {
@@ -30,7 +33,12 @@ func (ns RunnerStatus) utilization() float64 {
 		return 0
 	}
 
-	return ns.WorkersUsed / (float64)(ns.Workers)
+	capacity := (float64)(ns.Workers)
+	if ns.CapacityWeight > 0 {
+		capacity *= ns.CapacityWeight
+	}
+
+	return ns.WorkersUsed / capacity
 }
 
 // checksDistribution represents the placement of cluster checks across the
@@ -56,19 +64,37 @@ func newChecksDistribution(workersPerRunner map[string]int) checksDistribution {
 	}
 }
 
+// setCapacityWeights sets the capacity weight of the runners of the distribution
+func (distribution *checksDistribution) setCapacityWeights(weights map[string]float64) {
+	for runnerName, weight := range weights {
+		if runnerStatus, found := distribution.Runners[runnerName]; found {
+			runnerStatus.CapacityWeight = weight
+		}
+	}
+}
+
+func (distribution *checksDistribution) runnerCapacityWeights() map[string]float64 {
+	res := map[string]float64{}
+
+	for runnerName, runnerStatus := range distribution.Runners {
+		res[runnerName] = runnerStatus.CapacityWeight
+	}
+
+	return res
+}
+
 // leastBusyRunner returns the runner with the lowest utilization. If there are
 // several options, it gives preference to preferredRunner. If preferredRunner
 // is not among the runners with the lowest utilization, it gives precedence to
-// the runner with the lowest number of checks deployed. excludeRunner can be set
-// to avoid assigning a check to a specific runner.
-func (distribution *checksDistribution) leastBusyRunner(preferredRunner string, excludeRunner string) string {
+// the runner with the lowest number of checks deployed. Only the runners for
+// which isCandidate returns true are considered.
+func (distribution *checksDistribution) leastBusyRunner(preferredRunner string, isCandidate func(runnerName string) bool) string {
 	leastBusyRunner := ""
 	minUtilization := 0.0
 	numChecksLeastBusyRunner := 0
 
 	for runnerName, runnerStatus := range distribution.Runners {
-		// Allow exclusion of a runner from selection
-		if runnerName == excludeRunner {
+		if !isCandidate(runnerName) {
 			continue
 		}
 
@@ -90,13 +116,24 @@ func (distribution *checksDistribution) leastBusyRunner(preferredRunner string,
 	return leastBusyRunner
 }
 
+// addToLeastBusy adds the check to the least busy runner. excludeRunner can be
+// set to avoid assigning the check to a specific runner.
 func (distribution *checksDistribution) addToLeastBusy(checkID string, workersNeeded float64, preferredRunner string, excludeRunner string) {
-	leastBusy := distribution.leastBusyRunner(preferredRunner, excludeRunner)
+	distribution.addToLeastBusyMatching(checkID, workersNeeded, preferredRunner, func(runnerName string) bool {
+		return runnerName != excludeRunner
+	})
+}
+
+// addToLeastBusyMatching adds the check to the least busy runner among the ones
+// for which isCandidate returns true. It returns false if there's no such runner.
+func (distribution *checksDistribution) addToLeastBusyMatching(checkID string, workersNeeded float64, preferredRunner string, isCandidate func(runnerName string) bool) bool {
+	leastBusy := distribution.leastBusyRunner(preferredRunner, isCandidate)
 	if leastBusy == "" {
-		return
+		return false
 	}
 
 	distribution.addCheck(checkID, workersNeeded, leastBusy)
+	return true
 }
 
 func (distribution *checksDistribution) addCheck(checkID string, workersNeeded float64, runner string) {
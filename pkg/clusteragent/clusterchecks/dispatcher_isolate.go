@@ -35,6 +35,8 @@ func (d *dispatcher) isolateCheck(isolateCheckID string) types.IsolateResponse {
 	}
 
 	proposedDistribution := newChecksDistribution(currentDistribution.runnerWorkers())
+	proposedDistribution.setCapacityWeights(currentDistribution.runnerCapacityWeights())
+	placement := d.newDistributionPlacement()
 
 	for _, checkID := range currentDistribution.checksSortedByWorkersNeeded() {
 		if checkID == isolateCheckID {
@@ -45,12 +47,20 @@ func (d *dispatcher) isolateCheck(isolateCheckID string) types.IsolateResponse {
 		workersNeededForCheck := currentDistribution.workersNeededForCheck(checkID)
 		runnerForCheck := currentDistribution.runnerForCheck(checkID)
 
-		proposedDistribution.addToLeastBusy(
+		isAllowed := placement.filter(&proposedDistribution, checkID)
+		placed := proposedDistribution.addToLeastBusyMatching(
 			checkID,
 			workersNeededForCheck,
 			runnerForCheck,
-			isolateNode,
+			func(runnerName string) bool {
+				return runnerName != isolateNode && isAllowed(runnerName)
+			},
 		)
+		if !placed {
+			// Keep the check where it is rather than breaking the
+			// placement rules
+			proposedDistribution.addCheck(checkID, workersNeededForCheck, runnerForCheck)
+		}
 	}
 
 	d.applyDistribution(proposedDistribution, currentDistribution)
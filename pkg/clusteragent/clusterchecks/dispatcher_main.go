@@ -32,6 +32,10 @@ type dispatcher struct {
 	excludedChecks                map[string]struct{}
 	excludedChecksFromDispatching map[string]struct{}
 	rebalancingPeriod             time.Duration
+	placementRules                map[string]placementRule
+	capacityWeighting             bool
+	getNodeInfo                   nodeInfoGetter
+	getRunnerNodeName             runnerNodeNameGetter
 }
 
 func newDispatcher() *dispatcher {
@@ -61,6 +65,16 @@ func newDispatcher() *dispatcher {
 
 	d.rebalancingPeriod = pkgconfigsetup.Datadog().GetDuration("cluster_checks.rebalance_period")
 
+	d.placementRules = loadPlacementRules()
+	d.capacityWeighting = pkgconfigsetup.Datadog().GetBool("cluster_checks.capacity_weighted_dispatching")
+	if d.needsNodeInfo() {
+		d.getNodeInfo = newNodeInfoGetter()
+		d.getRunnerNodeName = newRunnerNodeNameGetter()
+		if d.getNodeInfo == nil {
+			log.Warn("Node information is not available, cluster checks node selectors will never match and capacity weighting is disabled")
+		}
+	}
+
 	hname, _ := hostname.Get(context.TODO())
 	clusterTagValue := clustername.GetClusterName(context.TODO(), hname)
 	clusterTagName := pkgconfigsetup.Datadog().GetString("cluster_checks.cluster_tag_name")
@@ -161,7 +175,7 @@ func (d *dispatcher) reschedule(configs []integration.Config) {
 
 // add stores and delegates a given configuration
 func (d *dispatcher) add(config integration.Config) {
-	target := d.getNodeToScheduleCheck()
+	target := d.getNodeToScheduleCheck(config)
 	if target == "" {
 		// If no node is found, store it in the danglingConfigs map for retrying later.
 		log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
@@ -211,6 +225,11 @@ func (d *dispatcher) run(ctx context.Context) {
 			// Expire old nodes, orphaned configs are moved to dangling
 			d.expireNodes()
 
+			// Refresh the node information and move the configs that
+			// don't comply with the placement rules anymore
+			d.refreshNodesInfo()
+			d.enforcePlacement()
+
 			// Re-dispatch dangling configs
 			if d.shouldDispatchDangling() {
 				danglingConfs := d.retrieveAndClearDangling()
@@ -49,6 +49,11 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 		warmingUp = true
 	}
 	node := d.store.getOrCreateNodeStore(nodeName, clientIP)
+	if status.NodeName != "" && status.NodeName != node.kubeNodeName {
+		node.kubeNodeName = status.NodeName
+		// Fetch the information of the new node at the next refresh
+		node.infoLastUpdate = 0
+	}
 	d.store.Unlock()
 
 	node.Lock()
@@ -88,41 +93,70 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 //
 // On the other hand, when advanced dispatching is not used, we can pick the
 // node with fewer checks. It's because the number of checks is kept up to date.
-func (d *dispatcher) getNodeToScheduleCheck() string {
+//
+// In both cases, only the nodes allowed by the placement rules of the check are
+// considered, and nodes are weighted by their capacity.
+func (d *dispatcher) getNodeToScheduleCheck(config integration.Config) string {
 	if d.advancedDispatching {
-		return d.getRandomNode()
+		return d.getRandomNode(config)
 	}
 
-	return d.getNodeWithLessChecks()
+	return d.getNodeWithLessChecks(config)
 }
 
-func (d *dispatcher) getRandomNode() string {
+func (d *dispatcher) getRandomNode(config integration.Config) string {
+	digest := config.Digest()
+
 	d.store.RLock()
 	defer d.store.RUnlock()
 
+	weights := d.capacityWeights()
+
 	var nodes []string
-	for name := range d.store.nodes {
+	totalWeight := 0.0
+	for name, store := range d.store.nodes {
+		if !d.canRunOn(config, digest, store) {
+			continue
+		}
 		nodes = append(nodes, name)
+		totalWeight += weights[name]
 	}
 
 	if len(nodes) == 0 {
 		return ""
 	}
 
-	return nodes[rand.Intn(len(nodes))]
+	r := rand.Float64() * totalWeight
+	for _, name := range nodes {
+		r -= weights[name]
+		if r < 0 {
+			return name
+		}
+	}
+
+	return nodes[len(nodes)-1]
 }
 
-func (d *dispatcher) getNodeWithLessChecks() string {
+func (d *dispatcher) getNodeWithLessChecks(config integration.Config) string {
+	digest := config.Digest()
+
 	d.store.RLock()
 	defer d.store.RUnlock()
 
+	weights := d.capacityWeights()
+
 	var selectedNode string
-	minNumChecks := 0
+	minLoad := 0.0
 
 	for name, store := range d.store.nodes {
-		if selectedNode == "" || len(store.digestToConfig) < minNumChecks {
+		if !d.canRunOn(config, digest, store) {
+			continue
+		}
+
+		load := float64(len(store.digestToConfig)) / weights[name]
+		if selectedNode == "" || load < minLoad {
 			selectedNode = name
-			minNumChecks = len(store.digestToConfig)
+			minLoad = load
 		}
 	}
 
@@ -42,6 +42,11 @@ func (w Weights) Len() int           { return len(w) }
 func (w Weights) Less(i, j int) bool { return w[i].busyness > w[j].busyness }
 func (w Weights) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
 
+// weightedBusyness returns the busyness of a node relative to its capacity
+func weightedBusyness(node *nodeStore, weight float64) int {
+	return int(float64(node.GetBusyness(busynessFunc)) / weight)
+}
+
 func (d *dispatcher) calculateAvg() (int, error) {
 	busyness := 0
 	length := 0
@@ -49,8 +54,9 @@ func (d *dispatcher) calculateAvg() (int, error) {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
-	for _, node := range d.store.nodes {
-		busyness += node.GetBusyness(busynessFunc)
+	weights := d.capacityWeights()
+	for nodeName, node := range d.store.nodes {
+		busyness += weightedBusyness(node, weights[nodeName])
 		length++
 	}
 
@@ -63,7 +69,8 @@ func (d *dispatcher) calculateAvg() (int, error) {
 
 // getDiffAndWeights creates a map that contains the difference between
 // the busyness on each node and the total average busyness, and a Weights
-// struct containing nodes and their busyness values
+// struct containing nodes and their busyness values. Busyness values are
+// relative to the capacity of the nodes.
 func (d *dispatcher) getDiffAndWeights(avg int) (map[string]int, Weights) {
 	diffMap := make(map[string]int)
 	weights := Weights{}
@@ -71,8 +78,9 @@ func (d *dispatcher) getDiffAndWeights(avg int) (map[string]int, Weights) {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
+	capacityWeights := d.capacityWeights()
 	for nodeName, node := range d.store.nodes {
-		busyness := node.GetBusyness(busynessFunc)
+		busyness := weightedBusyness(node, capacityWeights[nodeName])
 		diffMap[nodeName] = busyness - avg
 		weights = append(weights, Weight{
 			nodeName: nodeName,
@@ -90,8 +98,9 @@ func (d *dispatcher) updateDiff(avg int) map[string]int {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
+	weights := d.capacityWeights()
 	for nodeName, node := range d.store.nodes {
-		busyness := node.GetBusyness(busynessFunc)
+		busyness := weightedBusyness(node, weights[nodeName])
 		diffMap[nodeName] = busyness - avg
 	}
 
@@ -120,12 +129,13 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 // if it satisfies the following
 // Diff(Ni) < Diff(Nj) (for each j != i, 0 <= j < len(nodes))
 // where Diff(N) is the difference between the busyness on N and the total average busyness.
-func pickNode(diffMap map[string]int, sourceNode string) string {
+// Only the nodes for which isCandidate returns true are considered.
+func pickNode(diffMap map[string]int, sourceNode string, isCandidate func(nodeName string) bool) string {
 	firstItr := true
 	minDiff := 0
 	pickedNode := ""
 	for _, node := range orderedKeys(diffMap) {
-		if node == sourceNode {
+		if node == sourceNode || !isCandidate(node) {
 			continue
 		}
 		if diffMap[node] < minDiff || firstItr {
@@ -200,6 +210,7 @@ func (d *dispatcher) rebalanceUsingBusyness() []types.RebalanceResponse {
 	checksMoved := []types.RebalanceResponse{}
 	diffMap, weights := d.getDiffAndWeights(totalAvg)
 	sort.Sort(weights)
+	capacityWeights := d.nodeCapacityWeights()
 
 	for _, nodeWeight := range weights {
 		for diffMap[nodeWeight.nodeName] > 0 {
@@ -211,16 +222,24 @@ func (d *dispatcher) rebalanceUsingBusyness() []types.RebalanceResponse {
 				break
 			}
 
-			destNodeName := pickNode(diffMap, sourceNodeName)
+			destNodeName := pickNode(diffMap, sourceNodeName, d.placementFilter(checkID))
+			if destNodeName == "" {
+				log.Debugf("No node complies with the placement rules of check %s, it won't move", checkID)
+				break
+			}
 			sourceDiff := diffMap[sourceNodeName]
 			destDiff := diffMap[destNodeName]
+			destCheckWeight := checkWeight
+			if destWeight, found := capacityWeights[destNodeName]; found {
+				destCheckWeight = int(float64(checkWeight) / destWeight)
+			}
 
 			// move a check to a new node only if it keeps the
 			// busyness of the new node lower than the original
 			// node's busyness multiplied by the tolerationMargin
 			// value the toleration margin is used to lean towards
 			// stability over perfectly optimal balance
-			if destDiff+checkWeight < int(float64(sourceDiff)*tolerationMargin) {
+			if destDiff+destCheckWeight < int(float64(sourceDiff)*tolerationMargin) {
 				rebalancingDecisions.Inc(le.JoinLeaderValue)
 				err = d.moveCheck(sourceNodeName, destNodeName, checkID)
 				if err != nil {
@@ -310,6 +329,8 @@ func (d *dispatcher) rebalanceUsingUtilization(force bool) []types.RebalanceResp
 	currentChecksDistribution := d.currentDistribution()
 
 	proposedDistribution := newChecksDistribution(currentChecksDistribution.runnerWorkers())
+	proposedDistribution.setCapacityWeights(currentChecksDistribution.runnerCapacityWeights())
+	placement := d.newDistributionPlacement()
 
 	// First all the checks that are excluded from rebalancing are added to the
 	// same runner where they are currently running.
@@ -320,16 +341,23 @@ func (d *dispatcher) rebalanceUsingUtilization(force bool) []types.RebalanceResp
 		}
 	}
 
-	// Place the checks that are not excluded from rebalancing
+	// Place the checks that are not excluded from rebalancing. Checks that
+	// can't be placed on any runner without breaking the placement rules stay
+	// where they are.
 	for _, checkID := range currentChecksDistribution.checksSortedByWorkersNeeded() {
 		checkName := checkid.IDToCheckName(checkid.ID(checkID))
 		if _, excluded := d.excludedChecksFromDispatching[checkName]; !excluded {
-			proposedDistribution.addToLeastBusy(
+			workersNeeded := currentChecksDistribution.workersNeededForCheck(checkID)
+			currentRunner := currentChecksDistribution.runnerForCheck(checkID)
+			placed := proposedDistribution.addToLeastBusyMatching(
 				checkID,
-				currentChecksDistribution.workersNeededForCheck(checkID),
-				currentChecksDistribution.runnerForCheck(checkID),
-				"",
+				workersNeeded,
+				currentRunner,
+				placement.filter(&proposedDistribution, checkID),
 			)
+			if !placed {
+				proposedDistribution.addCheck(checkID, workersNeeded, currentRunner)
+			}
 		}
 	}
 
@@ -375,6 +403,7 @@ func (d *dispatcher) currentDistribution() checksDistribution {
 	}
 
 	distribution := newChecksDistribution(currentWorkersPerRunner)
+	distribution.setCapacityWeights(d.capacityWeights())
 
 	for nodeName, nodeStoreInfo := range d.store.nodes {
 		for checkID, stats := range nodeStoreInfo.clcRunnerStats {
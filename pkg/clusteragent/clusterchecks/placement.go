@@ -0,0 +1,385 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+
+package clusterchecks
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/config/structure"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// nodeInfoRefreshSeconds is the period at which the Kubernetes node
+	// information of the runners is refreshed
+	nodeInfoRefreshSeconds int64 = 300
+	nodeInfoTimeout              = 5 * time.Second
+
+	// minCapacityWeight prevents the smallest nodes from being considered
+	// as having no capacity at all
+	minCapacityWeight = 0.1
+)
+
+// placementRule constrains the runners a cluster check can be dispatched to
+type placementRule struct {
+	// Check is the name of the check the rule applies to
+	Check string `mapstructure:"check"`
+	// NodeSelector lists the labels the Kubernetes node of a runner must
+	// have to run the check
+	NodeSelector map[string]string `mapstructure:"node_selector"`
+	// AntiAffinity prevents two configurations of the check from being
+	// dispatched to the same runner
+	AntiAffinity bool `mapstructure:"anti_affinity"`
+}
+
+// allowsNode returns whether the node selector of the rule matches the node.
+// Nodes without information only match rules without a node selector.
+func (r placementRule) allowsNode(info *nodeInfo) bool {
+	if len(r.NodeSelector) == 0 {
+		return true
+	}
+	if info == nil {
+		return false
+	}
+	for key, value := range r.NodeSelector {
+		if nodeValue, found := info.labels[key]; !found || nodeValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeInfo holds the information of the Kubernetes node a runner runs on
+type nodeInfo struct {
+	labels            map[string]string
+	cpuAllocatable    int64 // in millicores
+	memoryAllocatable int64 // in bytes
+}
+
+// nodeInfoGetter returns the information of a Kubernetes node
+type nodeInfoGetter func(ctx context.Context, nodeName string) (*nodeInfo, error)
+
+// runnerNodeNameGetter returns the name of the Kubernetes node a runner runs
+// on, for the runners that don't report it
+type runnerNodeNameGetter func(ctx context.Context, runnerName, clientIP string) (string, error)
+
+// loadPlacementRules returns the placement rules configured by check name
+func loadPlacementRules() map[string]placementRule {
+	if !pkgconfigsetup.Datadog().IsSet("cluster_checks.placement_rules") {
+		return nil
+	}
+
+	var rules []placementRule
+	if err := structure.UnmarshalKey(pkgconfigsetup.Datadog(), "cluster_checks.placement_rules", &rules); err != nil {
+		log.Errorf("Could not parse cluster_checks.placement_rules, placement rules will be ignored: %v", err)
+		return nil
+	}
+
+	rulesByCheck := make(map[string]placementRule, len(rules))
+	for _, rule := range rules {
+		if rule.Check == "" {
+			log.Warnf("Ignoring cluster check placement rule without a check name: %+v", rule)
+			continue
+		}
+		if _, found := rulesByCheck[rule.Check]; found {
+			log.Warnf("Several cluster check placement rules are defined for %s, only the last one is used", rule.Check)
+		}
+		rulesByCheck[rule.Check] = rule
+	}
+	return rulesByCheck
+}
+
+// needsNodeInfo returns whether the placement of the checks depends on the
+// Kubernetes node information of the runners
+func (d *dispatcher) needsNodeInfo() bool {
+	if d.capacityWeighting {
+		return true
+	}
+	for _, rule := range d.placementRules {
+		if len(rule.NodeSelector) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// canRunOn returns whether the placement rules allow dispatching the
+// configuration to the node. The store lock must be held.
+func (d *dispatcher) canRunOn(config integration.Config, digest string, node *nodeStore) bool {
+	rule, found := d.placementRules[config.Name]
+	if !found {
+		return true
+	}
+	if !rule.allowsNode(node.info) {
+		return false
+	}
+	if rule.AntiAffinity {
+		for otherDigest, other := range node.digestToConfig {
+			if other.Name == config.Name && otherDigest != digest {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// capacityWeights returns the weight of each node relative to the largest
+// one, based on the allocatable CPU and memory of their Kubernetes node.
+// Nodes get a weight of 1 when capacity weighting is disabled or their
+// information is unknown. The store lock must be held.
+func (d *dispatcher) capacityWeights() map[string]float64 {
+	weights := make(map[string]float64, len(d.store.nodes))
+
+	var maxCPU, maxMemory int64
+	if d.capacityWeighting {
+		for _, node := range d.store.nodes {
+			if node.info != nil {
+				maxCPU = max(maxCPU, node.info.cpuAllocatable)
+				maxMemory = max(maxMemory, node.info.memoryAllocatable)
+			}
+		}
+	}
+
+	for name, node := range d.store.nodes {
+		weights[name] = 1
+		if node.info == nil || (maxCPU == 0 && maxMemory == 0) {
+			continue
+		}
+
+		var sum float64
+		var resources int
+		if maxCPU > 0 {
+			sum += float64(node.info.cpuAllocatable) / float64(maxCPU)
+			resources++
+		}
+		if maxMemory > 0 {
+			sum += float64(node.info.memoryAllocatable) / float64(maxMemory)
+			resources++
+		}
+		weights[name] = max(sum/float64(resources), minCapacityWeight)
+	}
+
+	return weights
+}
+
+// nodeCapacityWeights is the thread-safe version of capacityWeights
+func (d *dispatcher) nodeCapacityWeights() map[string]float64 {
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	return d.capacityWeights()
+}
+
+// placementFilter returns a function telling whether a check can be moved to
+// a node without breaking the placement rules
+func (d *dispatcher) placementFilter(checkID string) func(nodeName string) bool {
+	config, digest := d.getConfigAndDigest(checkID)
+
+	return func(nodeName string) bool {
+		d.store.RLock()
+		defer d.store.RUnlock()
+
+		node, found := d.store.getNodeStore(nodeName)
+		return found && d.canRunOn(config, digest, node)
+	}
+}
+
+// refreshNodesInfo fetches the Kubernetes node information of the runners
+// that haven't been updated for nodeInfoRefreshSeconds.
+// Runners that don't report their node, like cluster check runners which
+// don't set kubernetes_kubelet_nodename, are looked up by the node of their
+// pod, and as a last resort by their name, which is the node name of node
+// agents.
+func (d *dispatcher) refreshNodesInfo() {
+	if d.getNodeInfo == nil {
+		return
+	}
+
+	type runnerNode struct {
+		runner   string
+		clientIP string
+		kubeNode string
+	}
+
+	cutoffTimestamp := timestampNow() - nodeInfoRefreshSeconds
+	var outdated []runnerNode
+
+	d.store.RLock()
+	for name, node := range d.store.nodes {
+		if name == "" || node.infoLastUpdate > cutoffTimestamp {
+			continue
+		}
+		outdated = append(outdated, runnerNode{runner: name, clientIP: node.clientIP, kubeNode: node.kubeNodeName})
+	}
+	d.store.RUnlock()
+
+	for _, n := range outdated {
+		if n.kubeNode == "" {
+			n.kubeNode = d.resolveRunnerNodeName(n.runner, n.clientIP)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), nodeInfoTimeout)
+		info, err := d.getNodeInfo(ctx, n.kubeNode)
+		cancel()
+		if err != nil {
+			log.Debugf("Cannot get the information of Kubernetes node %s for runner %s: %v", n.kubeNode, n.runner, err)
+		}
+
+		d.store.Lock()
+		if node, found := d.store.getNodeStore(n.runner); found {
+			if err == nil {
+				node.info = info
+			}
+			// Also updated on errors to avoid querying the API server for
+			// runners that don't run on a known node at every cleanup
+			node.infoLastUpdate = timestampNow()
+		}
+		d.store.Unlock()
+	}
+}
+
+// resolveRunnerNodeName returns the node of the pod of a runner that doesn't
+// report its node, or the name of the runner if the pod can't be found
+func (d *dispatcher) resolveRunnerNodeName(runnerName, clientIP string) string {
+	if d.getRunnerNodeName == nil {
+		return runnerName
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nodeInfoTimeout)
+	defer cancel()
+
+	nodeName, err := d.getRunnerNodeName(ctx, runnerName, clientIP)
+	if err != nil || nodeName == "" {
+		log.Debugf("Cannot find the Kubernetes node of the pod of runner %s, using the runner name: %v", runnerName, err)
+		return runnerName
+	}
+	return nodeName
+}
+
+// enforcePlacement moves the configurations that don't comply with the
+// placement rules anymore, for instance after a change of node labels, to a
+// node where they are allowed to run. Configurations that can't run anywhere
+// else are left where they are.
+func (d *dispatcher) enforcePlacement() {
+	if len(d.placementRules) == 0 {
+		return
+	}
+
+	var misplaced []integration.Config
+	d.store.RLock()
+	for _, node := range d.store.nodes {
+		for digest, config := range node.digestToConfig {
+			if !d.canRunOn(config, digest, node) {
+				misplaced = append(misplaced, config)
+			}
+		}
+	}
+	d.store.RUnlock()
+
+	for _, config := range misplaced {
+		digest := config.Digest()
+
+		// Moving a previous configuration can fix an anti-affinity conflict
+		d.store.RLock()
+		currentNodeName := d.store.digestToNode[digest]
+		node, found := d.store.getNodeStore(currentNodeName)
+		stillMisplaced := found && !d.canRunOn(config, digest, node)
+		d.store.RUnlock()
+
+		if !stillMisplaced {
+			continue
+		}
+
+		target := d.getNodeToScheduleCheck(config)
+		if target == "" {
+			log.Debugf("No node complies with the placement rules of %s:%s, keeping it on node %s", config.Name, digest, currentNodeName)
+			continue
+		}
+
+		log.Infof("Moving configuration %s:%s from node %s to node %s to comply with the placement rules", config.Name, digest, currentNodeName, target)
+		d.addConfig(config, target)
+	}
+}
+
+// distributionPlacement evaluates the placement rules against a proposed
+// checks distribution instead of the current state of the store
+type distributionPlacement struct {
+	rules  map[string]placementRule
+	nodes  map[string]*nodeInfo
+	checks map[string]checkConfigRef
+}
+
+// checkConfigRef identifies the configuration of a check
+type checkConfigRef struct {
+	name   string
+	digest string
+}
+
+// newDistributionPlacement takes a snapshot of the information needed to
+// evaluate the placement rules
+func (d *dispatcher) newDistributionPlacement() *distributionPlacement {
+	p := &distributionPlacement{
+		rules:  d.placementRules,
+		nodes:  map[string]*nodeInfo{},
+		checks: map[string]checkConfigRef{},
+	}
+	if len(d.placementRules) == 0 {
+		return p
+	}
+
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	for name, node := range d.store.nodes {
+		p.nodes[name] = node.info
+	}
+	for id, digest := range d.store.idToDigest {
+		p.checks[string(id)] = checkConfigRef{
+			name:   d.store.digestToConfig[digest].Name,
+			digest: digest,
+		}
+	}
+
+	return p
+}
+
+// filter returns a function telling whether the placement rules allow adding
+// the check to a runner of the distribution
+func (p *distributionPlacement) filter(distribution *checksDistribution, checkID string) func(runnerName string) bool {
+	ref, found := p.checks[checkID]
+	if !found {
+		ref.name = checkid.IDToCheckName(checkid.ID(checkID))
+	}
+
+	rule, found := p.rules[ref.name]
+	if !found {
+		return func(string) bool { return true }
+	}
+
+	return func(runnerName string) bool {
+		if !rule.allowsNode(p.nodes[runnerName]) {
+			return false
+		}
+		if rule.AntiAffinity {
+			for otherID, otherStatus := range distribution.Checks {
+				if otherStatus.Runner != runnerName {
+					continue
+				}
+				other := p.checks[otherID]
+				if other.name == ref.name && other.digest != ref.digest {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
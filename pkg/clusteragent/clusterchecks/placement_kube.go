@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+
+package clusterchecks
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+)
+
+func newNodeInfoGetter() nodeInfoGetter {
+	return getKubeNodeInfo
+}
+
+func newRunnerNodeNameGetter() runnerNodeNameGetter {
+	return getRunnerPodNodeName
+}
+
+func getKubeNodeInfo(ctx context.Context, nodeName string) (*nodeInfo, error) {
+	client, err := apiserver.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := client.Cl.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodeInfo{
+		labels:            node.Labels,
+		cpuAllocatable:    node.Status.Allocatable.Cpu().MilliValue(),
+		memoryAllocatable: node.Status.Allocatable.Memory().Value(),
+	}, nil
+}
+
+// getRunnerPodNodeName returns the node of the pod that has the IP of the
+// runner. The pod named after the runner is preferred when several pods share
+// the IP, otherwise the IP must belong to a single pod.
+func getRunnerPodNodeName(ctx context.Context, runnerName, clientIP string) (string, error) {
+	if clientIP == "" {
+		return "", fmt.Errorf("the IP of runner %s is unknown", runnerName)
+	}
+
+	client, err := apiserver.GetAPIClient()
+	if err != nil {
+		return "", err
+	}
+
+	pods, err := client.Cl.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.podIP", clientIP).String(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return runnerPodNodeName(runnerName, clientIP, pods.Items)
+}
+
+// runnerPodNodeName returns the node of the runner pod among the pods that
+// have the IP of the runner
+func runnerPodNodeName(runnerName, clientIP string, pods []corev1.Pod) (string, error) {
+	var candidates []corev1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if pod.Name == runnerName {
+			return pod.Spec.NodeName, nil
+		}
+		candidates = append(candidates, pod)
+	}
+
+	if len(candidates) != 1 {
+		return "", fmt.Errorf("found %d running pods with IP %s for runner %s", len(candidates), clientIP, runnerName)
+	}
+	return candidates[0].Spec.NodeName, nil
+}
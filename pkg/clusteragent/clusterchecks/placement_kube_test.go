@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+
+package clusterchecks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newRunnerPod(name, nodeName string, phase corev1.PodPhase) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestRunnerPodNodeName(t *testing.T) {
+	tests := []struct {
+		name         string
+		pods         []corev1.Pod
+		expectedNode string
+		expectedErr  bool
+	}{
+		{
+			name:        "no pod",
+			expectedErr: true,
+		},
+		{
+			name:         "single running pod",
+			pods:         []corev1.Pod{newRunnerPod("datadog-clusterchecks-abcd", "node1", corev1.PodRunning)},
+			expectedNode: "node1",
+		},
+		{
+			name: "completed pods with the same IP are ignored",
+			pods: []corev1.Pod{
+				newRunnerPod("job-abcd", "node2", corev1.PodSucceeded),
+				newRunnerPod("datadog-clusterchecks-abcd", "node1", corev1.PodRunning),
+			},
+			expectedNode: "node1",
+		},
+		{
+			name: "pod named after the runner is preferred",
+			pods: []corev1.Pod{
+				newRunnerPod("other", "node2", corev1.PodRunning),
+				newRunnerPod("clc-runner", "node1", corev1.PodRunning),
+			},
+			expectedNode: "node1",
+		},
+		{
+			name: "ambiguous pods",
+			pods: []corev1.Pod{
+				newRunnerPod("other1", "node1", corev1.PodRunning),
+				newRunnerPod("other2", "node2", corev1.PodRunning),
+			},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeName, err := runnerPodNodeName("clc-runner", "10.0.0.1", tt.pods)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedNode, nodeName)
+		})
+	}
+}
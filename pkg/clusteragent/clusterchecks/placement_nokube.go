@@ -0,0 +1,20 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && !kubeapiserver
+
+package clusterchecks
+
+// newNodeInfoGetter returns nil as node information can only be retrieved
+// from the Kubernetes API server
+func newNodeInfoGetter() nodeInfoGetter {
+	return nil
+}
+
+// newRunnerNodeNameGetter returns nil as runner pods can only be retrieved
+// from the Kubernetes API server
+func newRunnerNodeNameGetter() runnerNodeNameGetter {
+	return nil
+}
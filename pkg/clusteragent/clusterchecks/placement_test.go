@@ -0,0 +1,292 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+
+package clusterchecks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func generateInstanceIntegration(name, instance string) integration.Config {
+	return integration.Config{
+		Name:         name,
+		ClusterCheck: true,
+		Instances:    []integration.Data{integration.Data(instance)},
+	}
+}
+
+func setNodeInfo(d *dispatcher, nodeName string, info *nodeInfo) {
+	d.store.Lock()
+	defer d.store.Unlock()
+	node := d.store.getOrCreateNodeStore(nodeName, "")
+	node.info = info
+	node.infoLastUpdate = timestampNow()
+}
+
+func TestLoadPlacementRules(t *testing.T) {
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("cluster_checks.placement_rules", []map[string]interface{}{
+		{
+			"check":         "postgres",
+			"node_selector": map[string]interface{}{"pool": "databases"},
+			"anti_affinity": true,
+		},
+		{
+			"check":         "mysql",
+			"anti_affinity": true,
+		},
+		{
+			"node_selector": map[string]interface{}{"pool": "ignored"},
+		},
+	})
+
+	assert.Equal(t, map[string]placementRule{
+		"postgres": {
+			Check:        "postgres",
+			NodeSelector: map[string]string{"pool": "databases"},
+			AntiAffinity: true,
+		},
+		"mysql": {
+			Check:        "mysql",
+			AntiAffinity: true,
+		},
+	}, loadPlacementRules())
+}
+
+func TestGetNodeToScheduleCheckPlacementRules(t *testing.T) {
+	for _, advancedDispatching := range []bool{false, true} {
+		dispatcher := newDispatcher()
+		dispatcher.advancedDispatching = advancedDispatching
+		dispatcher.placementRules = map[string]placementRule{
+			"postgres": {
+				Check:        "postgres",
+				NodeSelector: map[string]string{"pool": "databases"},
+				AntiAffinity: true,
+			},
+		}
+
+		setNodeInfo(dispatcher, "node1", &nodeInfo{labels: map[string]string{"pool": "databases"}})
+		setNodeInfo(dispatcher, "node2", &nodeInfo{labels: map[string]string{"pool": "databases"}})
+		setNodeInfo(dispatcher, "node3", &nodeInfo{labels: map[string]string{"pool": "default"}})
+		dispatcher.processNodeStatus("node4", "10.0.0.4", types.NodeStatus{})
+
+		// Only nodes matching the selector are considered
+		first := generateInstanceIntegration("postgres", "host: a")
+		target := dispatcher.getNodeToScheduleCheck(first)
+		assert.Contains(t, []string{"node1", "node2"}, target)
+		dispatcher.addConfig(first, target)
+
+		// A second instance never lands on the node of the first one
+		second := generateInstanceIntegration("postgres", "host: b")
+		otherTarget := dispatcher.getNodeToScheduleCheck(second)
+		assert.Contains(t, []string{"node1", "node2"}, otherTarget)
+		assert.NotEqual(t, target, otherTarget)
+		dispatcher.addConfig(second, otherTarget)
+
+		// No node left for a third instance, it stays dangling
+		assert.Equal(t, "", dispatcher.getNodeToScheduleCheck(generateInstanceIntegration("postgres", "host: c")))
+
+		// Checks without rules can go anywhere
+		assert.NotEqual(t, "", dispatcher.getNodeToScheduleCheck(generateIntegration("http_check")))
+
+		requireNotLocked(t, dispatcher.store)
+	}
+}
+
+func TestGetNodeWithLessChecksCapacityWeighting(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.capacityWeighting = true
+
+	setNodeInfo(dispatcher, "small", &nodeInfo{cpuAllocatable: 2000, memoryAllocatable: 4 << 30})
+	setNodeInfo(dispatcher, "large", &nodeInfo{cpuAllocatable: 8000, memoryAllocatable: 16 << 30})
+
+	dispatcher.addConfig(generateIntegration("A"), "small")
+	for _, name := range []string{"B", "C", "D"} {
+		dispatcher.addConfig(generateIntegration(name), "large")
+	}
+
+	dispatcher.store.RLock()
+	assert.Equal(t, map[string]float64{"small": 0.25, "large": 1}, dispatcher.capacityWeights())
+	dispatcher.store.RUnlock()
+
+	// 3 checks on a node 4 times larger is less than 1 check on the small one
+	assert.Equal(t, "large", dispatcher.getNodeWithLessChecks(generateIntegration("E")))
+
+	// Without weighting, the node with fewer checks is picked
+	dispatcher.capacityWeighting = false
+	assert.Equal(t, "small", dispatcher.getNodeWithLessChecks(generateIntegration("E")))
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestRefreshNodesInfo(t *testing.T) {
+	dispatcher := newDispatcher()
+
+	var queried []string
+	dispatcher.getNodeInfo = func(_ context.Context, nodeName string) (*nodeInfo, error) {
+		queried = append(queried, nodeName)
+		if nodeName == "unknown" {
+			return nil, errors.New("not found")
+		}
+		return &nodeInfo{labels: map[string]string{"name": nodeName}}, nil
+	}
+
+	dispatcher.processNodeStatus("runner-abcd", "10.0.0.1", types.NodeStatus{NodeName: "kube-node-1"})
+	dispatcher.processNodeStatus("kube-node-2", "10.0.0.2", types.NodeStatus{})
+	dispatcher.processNodeStatus("unknown", "10.0.0.3", types.NodeStatus{})
+
+	dispatcher.refreshNodesInfo()
+	assert.ElementsMatch(t, []string{"kube-node-1", "kube-node-2", "unknown"}, queried)
+	assert.Equal(t, map[string]string{"name": "kube-node-1"}, dispatcher.store.nodes["runner-abcd"].info.labels)
+	assert.Equal(t, map[string]string{"name": "kube-node-2"}, dispatcher.store.nodes["kube-node-2"].info.labels)
+	assert.Nil(t, dispatcher.store.nodes["unknown"].info)
+
+	// Up to date information is not fetched again, unless the node changes
+	queried = nil
+	dispatcher.refreshNodesInfo()
+	assert.Empty(t, queried)
+
+	dispatcher.processNodeStatus("runner-abcd", "10.0.0.1", types.NodeStatus{LastChange: types.ExtraHeartbeatLastChangeValue, NodeName: "kube-node-3"})
+	dispatcher.refreshNodesInfo()
+	assert.Equal(t, []string{"kube-node-3"}, queried)
+	assert.Equal(t, map[string]string{"name": "kube-node-3"}, dispatcher.store.nodes["runner-abcd"].info.labels)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestRefreshNodesInfoRunnerWithoutNodeName(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.placementRules = map[string]placementRule{
+		"postgres": {
+			Check:        "postgres",
+			NodeSelector: map[string]string{"pool": "databases"},
+		},
+	}
+
+	var queried []string
+	dispatcher.getNodeInfo = func(_ context.Context, nodeName string) (*nodeInfo, error) {
+		queried = append(queried, nodeName)
+		if nodeName == "kube-node-db" {
+			return &nodeInfo{labels: map[string]string{"pool": "databases"}}, nil
+		}
+		return nil, errors.New("not found")
+	}
+	dispatcher.getRunnerNodeName = func(_ context.Context, runnerName, clientIP string) (string, error) {
+		if runnerName == "clc-runner-abcd" && clientIP == "10.0.0.1" {
+			return "kube-node-db", nil
+		}
+		return "", errors.New("pod not found")
+	}
+
+	// Cluster check runners don't report the node name from the kubelet
+	dispatcher.processNodeStatus("clc-runner-abcd", "10.0.0.1", types.NodeStatus{})
+	dispatcher.processNodeStatus("clc-runner-efgh", "10.0.0.2", types.NodeStatus{})
+
+	dispatcher.refreshNodesInfo()
+	assert.ElementsMatch(t, []string{"kube-node-db", "clc-runner-efgh"}, queried)
+	assert.Equal(t, map[string]string{"pool": "databases"}, dispatcher.store.nodes["clc-runner-abcd"].info.labels)
+	assert.Nil(t, dispatcher.store.nodes["clc-runner-efgh"].info)
+
+	// The node selector matches the node of the runner pod
+	assert.Equal(t, "clc-runner-abcd", dispatcher.getNodeToScheduleCheck(generateInstanceIntegration("postgres", "host: a")))
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestEnforcePlacement(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.placementRules = map[string]placementRule{
+		"postgres": {Check: "postgres", AntiAffinity: true},
+		"mysql":    {Check: "mysql", NodeSelector: map[string]string{"pool": "databases"}},
+	}
+
+	setNodeInfo(dispatcher, "node1", &nodeInfo{labels: map[string]string{"pool": "databases"}})
+	setNodeInfo(dispatcher, "node2", &nodeInfo{labels: map[string]string{"pool": "default"}})
+
+	// Both instances were dispatched before the rules were set
+	postgresA := generateInstanceIntegration("postgres", "host: a")
+	postgresB := generateInstanceIntegration("postgres", "host: b")
+	mysql := generateInstanceIntegration("mysql", "host: c")
+	dispatcher.addConfig(postgresA, "node1")
+	dispatcher.addConfig(postgresB, "node1")
+	dispatcher.addConfig(mysql, "node2")
+
+	dispatcher.enforcePlacement()
+
+	dispatcher.store.RLock()
+	assert.NotEqual(t, dispatcher.store.digestToNode[postgresA.Digest()], dispatcher.store.digestToNode[postgresB.Digest()])
+	assert.Equal(t, "node1", dispatcher.store.digestToNode[mysql.Digest()])
+	dispatcher.store.RUnlock()
+
+	// The labels of node1 change, mysql can't run anywhere and stays there
+	setNodeInfo(dispatcher, "node1", &nodeInfo{labels: map[string]string{"pool": "default"}})
+	dispatcher.enforcePlacement()
+
+	dispatcher.store.RLock()
+	assert.Equal(t, "node1", dispatcher.store.digestToNode[mysql.Digest()])
+	dispatcher.store.RUnlock()
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestDistributionPlacementFilter(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.placementRules = map[string]placementRule{
+		"postgres": {Check: "postgres", NodeSelector: map[string]string{"pool": "databases"}, AntiAffinity: true},
+	}
+
+	setNodeInfo(dispatcher, "node1", &nodeInfo{labels: map[string]string{"pool": "databases"}})
+	setNodeInfo(dispatcher, "node2", &nodeInfo{labels: map[string]string{"pool": "databases"}})
+	setNodeInfo(dispatcher, "node3", &nodeInfo{labels: map[string]string{"pool": "default"}})
+
+	postgresA := generateInstanceIntegration("postgres", "host: a")
+	postgresB := generateInstanceIntegration("postgres", "host: b")
+	dispatcher.addConfig(postgresA, "node1")
+	dispatcher.addConfig(postgresB, "node1")
+
+	var idA, idB string
+	dispatcher.store.RLock()
+	for id, digest := range dispatcher.store.idToDigest {
+		if digest == postgresA.Digest() {
+			idA = string(id)
+		} else {
+			idB = string(id)
+		}
+	}
+	dispatcher.store.RUnlock()
+	require.NotEmpty(t, idA)
+	require.NotEmpty(t, idB)
+
+	placement := dispatcher.newDistributionPlacement()
+	distribution := newChecksDistribution(map[string]int{"node1": 4, "node2": 4, "node3": 4})
+
+	// Both checks prefer node1 but can't share it, and can't go to node3
+	require.True(t, distribution.addToLeastBusyMatching(idA, 0.5, "node1", placement.filter(&distribution, idA)))
+	require.True(t, distribution.addToLeastBusyMatching(idB, 0.5, "node1", placement.filter(&distribution, idB)))
+	assert.Equal(t, "node1", distribution.runnerForCheck(idA))
+	assert.Equal(t, "node2", distribution.runnerForCheck(idB))
+
+	// A third instance can't be placed
+	assert.False(t, distribution.addToLeastBusyMatching("postgres:c", 0.5, "", placement.filter(&distribution, "postgres:c")))
+
+	// Checks without rules can go anywhere
+	assert.True(t, placement.filter(&distribution, "http_check:1234")("node3"))
+}
+
+func TestRunnerStatusUtilizationWithCapacityWeight(t *testing.T) {
+	assert.Equal(t, 0.25, RunnerStatus{Workers: 4, WorkersUsed: 1}.utilization())
+	assert.Equal(t, 0.5, RunnerStatus{Workers: 4, WorkersUsed: 1, CapacityWeight: 0.5}.utilization())
+}
@@ -89,6 +89,9 @@ type nodeStore struct {
 	clcRunnerStats   types.CLCRunnersStats
 	busyness         int
 	workers          int
+	kubeNodeName     string    // Kubernetes node the agent runs on, if reported
+	info             *nodeInfo // Kubernetes node information, protected by the store lock
+	infoLastUpdate   int64
 }
 
 func newNodeStore(name, clientIP string) *nodeStore {
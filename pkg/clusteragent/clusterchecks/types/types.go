@@ -20,6 +20,9 @@ const (
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
 	LastChange int64 `json:"last_change"`
+	// NodeName is the name of the Kubernetes node the agent runs on, used to
+	// apply the placement rules of the cluster checks
+	NodeName string `json:"node_name,omitempty"`
 }
 
 // StatusResponse holds the DCA response for a status report
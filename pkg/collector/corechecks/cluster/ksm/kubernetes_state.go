@@ -43,6 +43,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
@@ -186,6 +187,27 @@ type KSMConfig struct {
 	// PodCollectionMode defines how pods are collected.
 	// Accepted values are: "default", "node_kubelet", and "cluster_unassigned".
 	PodCollectionMode podCollectionMode `yaml:"pod_collection_mode"`
+
+	// CustomResourceState generates metrics from the fields of custom resources.
+	// It uses the kube-state-metrics custom resource state configuration format.
+	// Metrics are sent as kubernetes_state.customresource.<name>, label joins
+	// and labels_mapper apply to them.
+	// Example: Report the number of available replicas of Argo rollouts.
+	// custom_resource_state:
+	//   resources:
+	//     - groupVersionKind:
+	//         group: argoproj.io
+	//         version: v1alpha1
+	//         kind: Rollout
+	//       labelsFromPath:
+	//         rollout: [metadata, name]
+	//       metrics:
+	//         - name: rollout_available_replicas
+	//           each:
+	//             type: Gauge
+	//             gauge:
+	//               path: [status, availableReplicas]
+	CustomResourceState customresourcestate.MetricsSpec `yaml:"custom_resource_state"`
 }
 
 // KSMCheck wraps the config and the metric stores needed to run the check
@@ -355,6 +377,14 @@ func (k *KSMCheck) Configure(senderManager sender.SenderManager, integrationConf
 	// configure custom resources required for extended features and
 	// compatibility across deprecated/removed versions of APIs
 	cr := k.discoverCustomResources(c, collectors, resources)
+
+	// configure the custom resources of the custom resource state metrics
+	crs, err := buildCustomResourceStateMetrics(k.instance.CustomResourceState, c.DynamicInformerCl, resources)
+	if err != nil {
+		return err
+	}
+	cr.addCustomResourceStateMetrics(crs)
+	maps.Copy(k.metricNamesMapper, crs.metricNames)
 	builder.WithGenerateCustomResourceStoresFunc(builder.GenerateCustomResourceStoresFunc)
 	builder.WithCustomResourceStoreFactories(cr.factories...)
 	builder.WithCustomResourceClients(cr.clients)
@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package ksm
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// customResourceMetricPrefix is the prefix KSM gives to the custom
+	// resource state metrics when no prefix is configured
+	customResourceMetricPrefix = "kube_customresource_"
+	// customResourceDDMetricPrefix is the prefix of the Datadog names of the
+	// custom resource state metrics
+	customResourceDDMetricPrefix = "customresource."
+)
+
+// customResourceStateMetrics holds the custom resources configured in
+// custom_resource_state
+type customResourceStateMetrics struct {
+	factories []customresource.RegistryFactory
+	clients   map[string]interface{}
+	// metricNames maps the KSM metric names to the Datadog metric names
+	metricNames map[string]string
+}
+
+// withDefaultLabels adds the labels present on all the custom resource state
+// metrics: the namespace, mapped to kube_namespace by the labels mapper, and
+// the group, version and kind of the resource. User-defined labels take
+// precedence.
+func withDefaultLabels(resource customresourcestate.Resource) customresourcestate.Resource {
+	gvk := resource.GroupVersionKind
+	resource.Labels = customresourcestate.Labels{
+		CommonLabels: map[string]string{
+			"customresource_group":   gvk.Group,
+			"customresource_version": gvk.Version,
+			"customresource_kind":    gvk.Kind,
+		},
+		LabelsFromPath: map[string][]string{
+			"namespace": {"metadata", "namespace"},
+		},
+	}.Merge(resource.Labels)
+	return resource
+}
+
+// customResourceMetricNames returns the KSM name and the Datadog name, without
+// the kubernetes_state prefix, of a custom resource state metric
+func customResourceMetricNames(resource customresourcestate.Resource, generator customresourcestate.Generator) (string, string) {
+	ksmName := generator.Name
+	if prefix := resource.GetMetricNamePrefix(); prefix != "" {
+		ksmName = prefix + "_" + ksmName
+	}
+	return ksmName, customResourceDDMetricPrefix + strings.TrimPrefix(ksmName, customResourceMetricPrefix)
+}
+
+// buildCustomResourceStateMetrics creates the metric family generator
+// factories of the custom resources of the configuration. Resources that
+// aren't served by the API server are skipped.
+func buildCustomResourceStateMetrics(spec customresourcestate.MetricsSpec, dynamicClient dynamic.Interface, resources []*v1.APIResourceList) (*customResourceStateMetrics, error) {
+	served := make(map[schema.GroupVersionResource]struct{})
+	for _, resourceList := range resources {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			served[gv.WithResource(resource.Name)] = struct{}{}
+		}
+	}
+
+	crs := &customResourceStateMetrics{
+		clients:     map[string]interface{}{},
+		metricNames: map[string]string{},
+	}
+
+	for _, resource := range spec.Resources {
+		resource = withDefaultLabels(resource)
+
+		gvr := schema.GroupVersionResource{
+			Group:    resource.GroupVersionKind.Group,
+			Version:  resource.GroupVersionKind.Version,
+			Resource: resource.GetResourceName(),
+		}
+		if _, found := served[gvr]; !found {
+			log.Warnf("custom resource %s is unknown and will not be collected", gvr)
+			continue
+		}
+
+		factory, err := customresourcestate.NewCustomResourceMetrics(resource)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom resource state configuration for %s: %w", gvr, err)
+		}
+		if _, found := crs.clients[factory.Name()]; found {
+			return nil, fmt.Errorf("found multiple custom resource state configurations for the same resource %s", factory.Name())
+		}
+
+		crs.factories = append(crs.factories, factory)
+		crs.clients[factory.Name()] = dynamicClient.Resource(gvr)
+
+		for _, generator := range resource.Metrics {
+			ksmName, ddName := customResourceMetricNames(resource, generator)
+			crs.metricNames[ksmName] = ddName
+		}
+	}
+
+	return crs, nil
+}
+
+// addCustomResourceStateMetrics enables the custom resources of the custom
+// resource state metrics
+func (cr *customResources) addCustomResourceStateMetrics(crs *customResourceStateMetrics) {
+	for _, f := range crs.factories {
+		cr.collectors = append(cr.collectors, f.Name())
+		cr.factories = append(cr.factories, f)
+		cr.clients[f.Name()] = crs.clients[f.Name()]
+	}
+}
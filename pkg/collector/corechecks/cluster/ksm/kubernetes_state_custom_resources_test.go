@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package ksm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	ksmstore "github.com/DataDog/datadog-agent/pkg/kubestatemetrics/store"
+)
+
+const customResourceStateConfig = `
+custom_resource_state:
+  resources:
+    - groupVersionKind:
+        group: argoproj.io
+        version: v1alpha1
+        kind: Rollout
+      labelsFromPath:
+        rollout: [metadata, name]
+      metrics:
+        - name: rollout_available_replicas
+          each:
+            type: Gauge
+            gauge:
+              path: [status, availableReplicas]
+        - name: rollout_info
+          each:
+            type: Info
+            info:
+              labelsFromPath:
+                strategy: [spec, strategy, type]
+    - groupVersionKind:
+        group: cert-manager.io
+        version: v1
+        kind: Certificate
+      metricNamePrefix: cert_manager
+      metrics:
+        - name: certificate_ready
+          each:
+            type: Gauge
+            gauge:
+              path: [status, ready]
+label_joins:
+  kube_customresource_rollout_info:
+    labels_to_match:
+      - rollout
+      - namespace
+    labels_to_get:
+      - strategy
+`
+
+var argoResources = []*v1.APIResourceList{
+	{
+		GroupVersion: "argoproj.io/v1alpha1",
+		APIResources: []v1.APIResource{{Name: "rollouts", Kind: "Rollout", Namespaced: true}},
+	},
+}
+
+func TestBuildCustomResourceStateMetrics(t *testing.T) {
+	config := &KSMConfig{}
+	require.NoError(t, config.parse([]byte(customResourceStateConfig)))
+	require.Len(t, config.CustomResourceState.Resources, 2)
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	// The Certificate CRD isn't installed, it's skipped
+	crs, err := buildCustomResourceStateMetrics(config.CustomResourceState, client, argoResources)
+	require.NoError(t, err)
+
+	require.Len(t, crs.factories, 1)
+	assert.Equal(t, "rollouts", crs.factories[0].Name())
+	assert.Contains(t, crs.clients, "rollouts")
+	assert.Equal(t, map[string]string{
+		"kube_customresource_rollout_available_replicas": "customresource.rollout_available_replicas",
+		"kube_customresource_rollout_info":               "customresource.rollout_info",
+	}, crs.metricNames)
+
+	cr := customResources{clients: map[string]interface{}{}}
+	cr.addCustomResourceStateMetrics(crs)
+	assert.Equal(t, []string{"rollouts"}, cr.collectors)
+	assert.Len(t, cr.factories, 1)
+}
+
+func TestBuildCustomResourceStateMetricsInvalid(t *testing.T) {
+	config := &KSMConfig{}
+	require.NoError(t, config.parse([]byte(`
+custom_resource_state:
+  resources:
+    - groupVersionKind:
+        group: argoproj.io
+        version: v1alpha1
+        kind: Rollout
+      metrics:
+        - name: invalid
+          each:
+            type: Unknown
+`)))
+
+	_, err := buildCustomResourceStateMetrics(config.CustomResourceState, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), argoResources)
+	assert.ErrorContains(t, err, "invalid custom resource state configuration for argoproj.io/v1alpha1, Resource=rollouts")
+}
+
+func TestCustomResourceMetricNames(t *testing.T) {
+	config := &KSMConfig{}
+	require.NoError(t, config.parse([]byte(customResourceStateConfig)))
+
+	certificates := config.CustomResourceState.Resources[1]
+	ksmName, ddName := customResourceMetricNames(certificates, certificates.Metrics[0])
+	assert.Equal(t, "cert_manager_certificate_ready", ksmName)
+	assert.Equal(t, "customresource.cert_manager_certificate_ready", ddName)
+}
+
+func TestProcessCustomResourceStateMetrics(t *testing.T) {
+	config := &KSMConfig{}
+	require.NoError(t, config.parse([]byte(customResourceStateConfig)))
+	config.LabelsMapper = defaultLabelsMapper()
+
+	crs, err := buildCustomResourceStateMetrics(config.CustomResourceState, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), argoResources)
+	require.NoError(t, err)
+	require.Len(t, crs.factories, 1)
+
+	factory := crs.factories[0]
+	families := factory.MetricFamilyGenerators(nil, nil)
+	store := ksmstore.NewMetricsStore(generator.ComposeMetricGenFuncs(families), "rollouts")
+	require.NoError(t, store.Add(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"name":      "frontend",
+			"namespace": "web",
+			"uid":       "cb9b6ba5-8f4e-4c1e-b3a8-ef3d3c8f1f44",
+		},
+		"spec": map[string]interface{}{
+			"strategy": map[string]interface{}{"type": "canary"},
+		},
+		"status": map[string]interface{}{
+			"availableReplicas": int64(3),
+		},
+	}}))
+
+	check := newKSMCheck(core.NewCheckBase(CheckName), config)
+	check.processLabelJoins()
+	for ksmName, ddName := range crs.metricNames {
+		check.metricNamesMapper[ksmName] = ddName
+	}
+
+	mocked := mocksender.NewMockSender(check.ID())
+	mocked.SetupAcceptAll()
+
+	labelJoiner := newLabelJoiner(check.instance.labelJoins)
+	labelJoiner.insertFamilies(store.Push(check.familyFilter, check.metricFilter))
+	check.processMetrics(mocked, store.Push(ksmstore.GetAllFamilies, ksmstore.GetAllMetrics), labelJoiner, time.Now())
+
+	commonTags := []string{
+		"customresource_group:argoproj.io",
+		"customresource_version:v1alpha1",
+		"customresource_kind:Rollout",
+		"kube_namespace:web",
+		"rollout:frontend",
+	}
+	mocked.AssertMetric(t, "Gauge", "kubernetes_state.customresource.rollout_available_replicas", 3, "", append([]string{"strategy:canary"}, commonTags...))
+	mocked.AssertMetric(t, "Gauge", "kubernetes_state.customresource.rollout_info", 1, "", append([]string{"strategy:canary"}, commonTags...))
+	mocked.AssertNumberOfCalls(t, "Gauge", 2)
+}
@@ -42,6 +42,9 @@ type InstanceConfig struct {
 
 	Protocol string `yaml:"protocol"`
 
+	NumPaths  uint16 `yaml:"num_paths"`
+	ParisMode bool   `yaml:"paris_mode"`
+
 	SourceService      string `yaml:"source_service"`
 	DestinationService string `yaml:"destination_service"`
 
@@ -63,6 +66,8 @@ type CheckConfig struct {
 	DestinationService    string
 	MaxTTL                uint8
 	Protocol              payload.Protocol
+	NumPaths              uint16
+	ParisMode             bool
 	Timeout               time.Duration
 	MinCollectionInterval time.Duration
 	Tags                  []string
@@ -91,6 +96,8 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 	c.SourceService = instance.SourceService
 	c.DestinationService = instance.DestinationService
 	c.Protocol = payload.Protocol(strings.ToUpper(instance.Protocol))
+	c.NumPaths = instance.NumPaths
+	c.ParisMode = instance.ParisMode
 
 	c.MinCollectionInterval = firstNonZero(
 		time.Duration(instance.MinCollectionInterval)*time.Second,
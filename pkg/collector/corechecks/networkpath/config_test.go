@@ -170,6 +170,26 @@ protocol: TCP
 				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
 			},
 		},
+		{
+			name: "icmp paris multipath",
+			rawInstance: []byte(`
+hostname: 1.2.3.4
+protocol: icmp
+num_paths: 4
+paris_mode: true
+`),
+			rawInitConfig: []byte(``),
+			expectedConfig: &CheckConfig{
+				DestHostname:          "1.2.3.4",
+				MinCollectionInterval: time.Duration(60) * time.Second,
+				Namespace:             "my-namespace",
+				Protocol:              payload.ProtocolICMP,
+				NumPaths:              4,
+				ParisMode:             true,
+				Timeout:               setup.DefaultNetworkPathTimeout * time.Millisecond,
+				MaxTTL:                setup.DefaultNetworkPathMaxTTL,
+			},
+		},
 		{
 			name: "timeout from instance config",
 			rawInstance: []byte(`
@@ -38,6 +38,7 @@ type Check struct {
 	core.CheckBase
 	config        *CheckConfig
 	lastCheckTime time.Time
+	lastHopCount  int
 	telemetryComp telemetryComp.Component
 }
 
@@ -56,6 +57,8 @@ func (c *Check) Run() error {
 		MaxTTL:       c.config.MaxTTL,
 		Timeout:      c.config.Timeout,
 		Protocol:     c.config.Protocol,
+		NumPaths:     c.config.NumPaths,
+		ParisMode:    c.config.ParisMode,
 	}
 
 	tr, err := traceroute.New(cfg, c.telemetryComp)
@@ -107,6 +110,13 @@ func (c *Check) submitTelemetry(metricSender metricsender.MetricSender, path pay
 	checkDuration := time.Since(startTime)
 
 	telemetry.SubmitNetworkPathTelemetry(metricSender, path, checkDuration, checkInterval, metricTags)
+
+	// The path is traced once per check run, only latency and hop count changes are reported
+	stats := telemetry.ComputePathStats([]payload.NetworkPath{path})
+	telemetry.SubmitNetworkPathStats(metricSender, path, stats, c.lastHopCount, metricTags)
+	if stats.HopCount > 0 {
+		c.lastHopCount = stats.HopCount
+	}
 }
 
 // Interval returns the scheduling time for the check
@@ -93,6 +93,7 @@ type InitConfig struct {
 	PingConfig                   snmpintegration.PackedPingConfig  `yaml:"ping"`
 	DetectMetricsEnabled         Boolean                           `yaml:"experimental_detect_metrics_enabled"`
 	DetectMetricsRefreshInterval int                               `yaml:"experimental_detect_metrics_refresh_interval"`
+	AdaptiveBatching             Boolean                           `yaml:"adaptive_batching"`
 }
 
 // InstanceConfig is used to deserialize integration instance config
@@ -135,6 +136,13 @@ type InstanceConfig struct {
 	// The bulk_max_repetitions config indicates how many rows of the table are to be retrieved in a single GetBulk call
 	BulkMaxRepetitions Number `yaml:"bulk_max_repetitions"`
 
+	// When AdaptiveBatching is enabled, oid_batch_size and bulk_max_repetitions are used as starting values:
+	// they are shrunk when the device answers with tooBig or times out, and grown back on success
+	// up to max_oid_batch_size and max_bulk_max_repetitions.
+	AdaptiveBatching      *Boolean `yaml:"adaptive_batching"`
+	MaxOidBatchSize       Number   `yaml:"max_oid_batch_size"`
+	MaxBulkMaxRepetitions Number   `yaml:"max_bulk_max_repetitions"`
+
 	MinCollectionInterval int `yaml:"min_collection_interval"`
 	// To accept min collection interval from snmp_listener, we need to accept it as string.
 	// Using extra_min_collection_interval, we can accept both string and integer value.
@@ -148,6 +156,10 @@ type InstanceConfig struct {
 	Workers                  int      `yaml:"workers"`
 	Namespace                string   `yaml:"namespace"`
 
+	// When SpreadDeviceCollection is enabled, the collection of discovered devices is spread
+	// over the first half of the check interval instead of starting all devices at once.
+	SpreadDeviceCollection Boolean `yaml:"spread_device_collection"`
+
 	// When DetectMetricsEnabled is enabled, instead of using profile detection using sysObjectID
 	// the integration will fetch OIDs from the devices and deduct which metrics  can be monitored (from all OOTB profile metrics definition)
 	DetectMetricsEnabled         *Boolean `yaml:"experimental_detect_metrics_enabled"`
@@ -185,6 +197,9 @@ type CheckConfig struct {
 	MetricTags            []profiledefinition.MetricTagConfig
 	OidBatchSize          int
 	BulkMaxRepetitions    uint32
+	AdaptiveBatching      bool
+	MaxOidBatchSize       int
+	MaxBulkMaxRepetitions uint32
 	Profiles              profile.ProfileConfigMap
 	ProfileTags           []string
 	Profile               string
@@ -210,6 +225,7 @@ type CheckConfig struct {
 	DiscoveryInterval        int
 	IgnoredIPAddresses       map[string]bool
 	DiscoveryAllowedFailures int
+	SpreadDeviceCollection   bool
 	InterfaceConfigs         []snmpintegration.InterfaceConfig
 
 	PingEnabled bool
@@ -443,6 +459,8 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 		c.DiscoveryInterval = instance.DiscoveryInterval
 	}
 
+	c.SpreadDeviceCollection = bool(instance.SpreadDeviceCollection)
+
 	c.IgnoredIPAddresses = make(map[string]bool, len(instance.IgnoredIPAddresses))
 	for _, ipAddress := range instance.IgnoredIPAddresses {
 		c.IgnoredIPAddresses[ipAddress] = true
@@ -507,6 +525,30 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 	}
 	c.BulkMaxRepetitions = uint32(bulkMaxRepetitions)
 
+	if instance.AdaptiveBatching != nil {
+		c.AdaptiveBatching = bool(*instance.AdaptiveBatching)
+	} else {
+		c.AdaptiveBatching = bool(initConfig.AdaptiveBatching)
+	}
+
+	if instance.MaxOidBatchSize != 0 {
+		c.MaxOidBatchSize = int(instance.MaxOidBatchSize)
+	} else {
+		c.MaxOidBatchSize = c.OidBatchSize
+	}
+	if c.MaxOidBatchSize < c.OidBatchSize {
+		return nil, fmt.Errorf("max oid batch size (%d) cannot be lower than oid batch size (%d)", c.MaxOidBatchSize, c.OidBatchSize)
+	}
+
+	if instance.MaxBulkMaxRepetitions != 0 {
+		c.MaxBulkMaxRepetitions = uint32(instance.MaxBulkMaxRepetitions)
+	} else {
+		c.MaxBulkMaxRepetitions = c.BulkMaxRepetitions
+	}
+	if c.MaxBulkMaxRepetitions < c.BulkMaxRepetitions {
+		return nil, fmt.Errorf("max bulk max repetitions (%d) cannot be lower than bulk max repetitions (%d)", c.MaxBulkMaxRepetitions, c.BulkMaxRepetitions)
+	}
+
 	if instance.Namespace != "" {
 		c.Namespace = instance.Namespace
 	} else if initConfig.Namespace != "" {
@@ -689,6 +731,9 @@ func (c *CheckConfig) Copy() *CheckConfig {
 	copy(newConfig.MetricTags, c.MetricTags)
 	newConfig.OidBatchSize = c.OidBatchSize
 	newConfig.BulkMaxRepetitions = c.BulkMaxRepetitions
+	newConfig.AdaptiveBatching = c.AdaptiveBatching
+	newConfig.MaxOidBatchSize = c.MaxOidBatchSize
+	newConfig.MaxBulkMaxRepetitions = c.MaxBulkMaxRepetitions
 	newConfig.Profiles = c.Profiles
 	newConfig.ProfileTags = netutils.CopyStrings(c.ProfileTags)
 	newConfig.Profile = c.Profile
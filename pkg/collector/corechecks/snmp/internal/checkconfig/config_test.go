@@ -551,6 +551,63 @@ bulk_max_repetitions: -5
 	assert.EqualError(t, err, "bulk max repetition must be a positive integer. Invalid value: -5")
 }
 
+func TestAdaptiveBatchingConfiguration(t *testing.T) {
+	profile.SetConfdPathAndCleanProfiles()
+	// TEST Default
+	// language=yaml
+	rawInstanceConfig := []byte(`
+ip_address: 1.2.3.4
+community_string: abc
+oid_batch_size: 8
+bulk_max_repetitions: 20
+`)
+	config, err := NewCheckConfig(rawInstanceConfig, []byte(``))
+	assert.Nil(t, err)
+	assert.False(t, config.AdaptiveBatching)
+	assert.Equal(t, 8, config.MaxOidBatchSize)
+	assert.Equal(t, uint32(20), config.MaxBulkMaxRepetitions)
+
+	// TEST Init config enabled, instance max values
+	// language=yaml
+	rawInstanceConfig = []byte(`
+ip_address: 1.2.3.4
+community_string: abc
+max_oid_batch_size: 30
+max_bulk_max_repetitions: 40
+`)
+	// language=yaml
+	rawInitConfig := []byte(`
+adaptive_batching: true
+`)
+	config, err = NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.True(t, config.AdaptiveBatching)
+	assert.Equal(t, 30, config.MaxOidBatchSize)
+	assert.Equal(t, uint32(40), config.MaxBulkMaxRepetitions)
+
+	// TEST Instance overrides init config
+	// language=yaml
+	rawInstanceConfig = []byte(`
+ip_address: 1.2.3.4
+community_string: abc
+adaptive_batching: "false"
+`)
+	config, err = NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.False(t, config.AdaptiveBatching)
+
+	// TEST invalid max value
+	// language=yaml
+	rawInstanceConfig = []byte(`
+ip_address: 1.2.3.4
+community_string: abc
+oid_batch_size: 10
+max_oid_batch_size: 5
+`)
+	_, err = NewCheckConfig(rawInstanceConfig, []byte(``))
+	assert.EqualError(t, err, "max oid batch size (5) cannot be lower than oid batch size (10)")
+}
+
 func TestGlobalMetricsConfigurations(t *testing.T) {
 	profile.SetConfdPathAndCleanProfiles()
 
@@ -2358,8 +2415,11 @@ func TestCheckConfig_Copy(t *testing.T) {
 		MetricTags: []profiledefinition.MetricTagConfig{
 			{Tag: "my_symbol", Symbol: profiledefinition.SymbolConfigCompat{OID: "1.2.3", Name: "mySymbol"}},
 		},
-		OidBatchSize:       10,
-		BulkMaxRepetitions: 10,
+		OidBatchSize:          10,
+		BulkMaxRepetitions:    10,
+		AdaptiveBatching:      true,
+		MaxOidBatchSize:       20,
+		MaxBulkMaxRepetitions: 30,
 		Profiles: profile.ProfileConfigMap{"f5-big-ip": profile.ProfileConfig{
 			Definition: profiledefinition.ProfileDefinition{
 				Device: profiledefinition.DeviceMeta{Vendor: "f5"},
@@ -73,9 +73,12 @@ type DeviceCheck struct {
 	diagnoses               *diagnoses.Diagnoses
 	interfaceBandwidthState report.InterfaceBandwidthState
 	cacheKey                string
+	batchTuner              *fetch.BatchTuner
+	batchSizesCacheKey      string
 }
 
 const cacheKeyPrefix = "snmp-tags"
+const batchSizesCacheKeyPrefix = "snmp-batch-sizes"
 
 // NewDeviceCheck returns a new DeviceCheck
 func NewDeviceCheck(config *checkconfig.CheckConfig, ipAddress string, sessionFactory session.Factory) (*DeviceCheck, error) {
@@ -102,9 +105,14 @@ func NewDeviceCheck(config *checkconfig.CheckConfig, ipAddress string, sessionFa
 		diagnoses:               diagnoses.NewDeviceDiagnoses(newConfig.DeviceID),
 		interfaceBandwidthState: report.MakeInterfaceBandwidthState(),
 		cacheKey:                cacheKey,
+		batchTuner:              fetch.NewBatchTuner(newConfig),
+		batchSizesCacheKey:      fmt.Sprintf("%s:%s", batchSizesCacheKeyPrefix, configHash),
 	}
 
 	d.readTagsFromCache()
+	if d.batchTuner.IsAdaptive() {
+		d.readBatchSizesFromCache()
+	}
 
 	return &d, nil
 }
@@ -297,7 +305,12 @@ func (d *DeviceCheck) getValuesAndTags() (bool, []string, *valuestore.ResultValu
 
 	tags = append(tags, d.config.ProfileTags...)
 
-	valuesStore, err := fetch.Fetch(d.session, d.config)
+	previousBatchSizes := d.batchTuner.Current()
+	valuesStore, err := fetch.Fetch(d.session, d.config, d.batchTuner)
+	if d.batchTuner.IsAdaptive() && d.batchTuner.Current() != previousBatchSizes {
+		log.Debugf("%s: batch sizes changed from %+v to %+v", d.config.IPAddress, previousBatchSizes, d.batchTuner.Current())
+		d.writeBatchSizesInCache()
+	}
 	if log.ShouldLog(seelog.DebugLvl) {
 		log.Debugf("fetched values: %v", valuestore.ResultValueStoreAsString(valuesStore))
 	}
@@ -427,6 +440,12 @@ func (d *DeviceCheck) submitTelemetryMetrics(startTime time.Time, tags []string)
 	d.sender.MonotonicCount("datadog.snmp.check_interval", time.Duration(startTime.UnixNano()).Seconds(), newTags)
 	d.sender.Gauge("datadog.snmp.check_duration", time.Since(startTime).Seconds(), newTags)
 	d.sender.Gauge("datadog.snmp.submitted_metrics", float64(d.sender.GetSubmittedMetrics()), newTags)
+
+	if d.batchTuner.IsAdaptive() {
+		batchSizes := d.batchTuner.Current()
+		d.sender.Gauge("datadog.snmp.oid_batch_size", float64(batchSizes.OidBatchSize), newTags)
+		d.sender.Gauge("datadog.snmp.bulk_max_repetitions", float64(batchSizes.BulkMaxRepetitions), newTags)
+	}
 }
 
 // GetDiagnoses collects diagnoses for diagnose CLI
@@ -480,3 +499,31 @@ func (d *DeviceCheck) writeTagsInCache() {
 		log.Errorf("SNMP tags %s: Couldn't write cache: %s", d.config.Network, err)
 	}
 }
+
+func (d *DeviceCheck) readBatchSizesFromCache() {
+	cacheValue, err := persistentcache.Read(d.batchSizesCacheKey)
+	if err != nil {
+		log.Errorf("couldn't read cache for %s: %s", d.batchSizesCacheKey, err)
+	}
+	if cacheValue == "" {
+		return
+	}
+	var batchSizes fetch.BatchSizes
+	if err = json.Unmarshal([]byte(cacheValue), &batchSizes); err != nil {
+		log.Errorf("couldn't unmarshal cache for %s: %s", d.batchSizesCacheKey, err)
+		return
+	}
+	d.batchTuner.Restore(batchSizes)
+}
+
+func (d *DeviceCheck) writeBatchSizesInCache() {
+	cacheValue, err := json.Marshal(d.batchTuner.Current())
+	if err != nil {
+		log.Errorf("SNMP batch sizes %s: Couldn't marshal cache: %s", d.config.IPAddress, err)
+		return
+	}
+
+	if err = persistentcache.Write(d.batchSizesCacheKey, string(cacheValue)); err != nil {
+		log.Errorf("SNMP batch sizes %s: Couldn't write cache: %s", d.config.IPAddress, err)
+	}
+}
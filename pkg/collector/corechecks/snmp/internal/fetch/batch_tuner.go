@@ -0,0 +1,150 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package fetch
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
+)
+
+// successesBeforeGrow is the number of consecutive successful fetches needed before growing batch sizes
+const successesBeforeGrow = 3
+
+// errTooBig is returned when the device answers with a tooBig error status
+var errTooBig = errors.New("response too big")
+
+// BatchSizes holds the oid batch size and bulk max repetitions used to fetch a device
+type BatchSizes struct {
+	OidBatchSize       int    `json:"oid_batch_size"`
+	BulkMaxRepetitions uint32 `json:"bulk_max_repetitions"`
+}
+
+// BatchTuner keeps track of the batch sizes used for a single device.
+// When adaptive batching is enabled, batch sizes are shrunk when the device
+// answers with tooBig or times out, and grown back after successful fetches.
+// When adaptive batching is disabled, the configured batch sizes are always used.
+type BatchTuner struct {
+	mu sync.Mutex
+
+	adaptive  bool
+	current   BatchSizes
+	max       BatchSizes
+	successes int
+}
+
+// NewBatchTuner creates a BatchTuner starting with the configured batch sizes
+func NewBatchTuner(config *checkconfig.CheckConfig) *BatchTuner {
+	maxOidBatchSize := config.MaxOidBatchSize
+	if maxOidBatchSize < config.OidBatchSize {
+		maxOidBatchSize = config.OidBatchSize
+	}
+	if maxOidBatchSize > gosnmp.MaxOids {
+		maxOidBatchSize = gosnmp.MaxOids
+	}
+	maxBulkMaxRepetitions := config.MaxBulkMaxRepetitions
+	if maxBulkMaxRepetitions < config.BulkMaxRepetitions {
+		maxBulkMaxRepetitions = config.BulkMaxRepetitions
+	}
+	return &BatchTuner{
+		adaptive: config.AdaptiveBatching,
+		current: BatchSizes{
+			OidBatchSize:       config.OidBatchSize,
+			BulkMaxRepetitions: config.BulkMaxRepetitions,
+		},
+		max: BatchSizes{
+			OidBatchSize:       maxOidBatchSize,
+			BulkMaxRepetitions: maxBulkMaxRepetitions,
+		},
+	}
+}
+
+// IsAdaptive returns true if batch sizes are adjusted based on device responses
+func (t *BatchTuner) IsAdaptive() bool {
+	return t.adaptive
+}
+
+// Current returns the batch sizes to use for the next requests
+func (t *BatchTuner) Current() BatchSizes {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+// Restore sets the current batch sizes, typically from previously persisted values.
+// Values are bounded by the configured maximums.
+func (t *BatchTuner) Restore(sizes BatchSizes) {
+	if !t.adaptive || sizes.OidBatchSize <= 0 || sizes.BulkMaxRepetitions == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current.OidBatchSize = min(sizes.OidBatchSize, t.max.OidBatchSize)
+	t.current.BulkMaxRepetitions = min(sizes.BulkMaxRepetitions, t.max.BulkMaxRepetitions)
+	t.successes = 0
+}
+
+// canShrink returns true if batch sizes can still be shrunk
+func (t *BatchTuner) canShrink() bool {
+	if !t.adaptive {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current.OidBatchSize > 1 || t.current.BulkMaxRepetitions > 1
+}
+
+// onError shrinks batch sizes if the error is caused by a too big response or a timeout.
+// It returns true if the batch sizes have been shrunk and the request can be retried.
+func (t *BatchTuner) onError(err error) bool {
+	if !t.adaptive || !isShrinkableError(err) {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.successes = 0
+	if t.current.OidBatchSize <= 1 && t.current.BulkMaxRepetitions <= 1 {
+		return false
+	}
+	t.current.OidBatchSize = max(t.current.OidBatchSize/2, 1)
+	t.current.BulkMaxRepetitions = max(t.current.BulkMaxRepetitions/2, 1)
+	return true
+}
+
+// onSuccess grows batch sizes after successesBeforeGrow consecutive successful fetches
+func (t *BatchTuner) onSuccess() {
+	if !t.adaptive {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.successes++
+	if t.successes < successesBeforeGrow {
+		return
+	}
+	t.successes = 0
+	if t.current.OidBatchSize < t.max.OidBatchSize {
+		t.current.OidBatchSize++
+	}
+	if t.current.BulkMaxRepetitions < t.max.BulkMaxRepetitions {
+		// grow by 25% (at least 1) to converge faster on large max repetitions
+		t.current.BulkMaxRepetitions = min(t.current.BulkMaxRepetitions+max(t.current.BulkMaxRepetitions/4, 1), t.max.BulkMaxRepetitions)
+	}
+}
+
+func isShrinkableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	// errors are wrapped using `%s` along the way, hence we can't only rely on errors.Is
+	return errors.Is(err, errTooBig) ||
+		strings.Contains(err.Error(), errTooBig.Error()) ||
+		strings.Contains(err.Error(), "timeout")
+}
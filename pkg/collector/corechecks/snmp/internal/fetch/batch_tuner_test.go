@@ -0,0 +1,160 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package fetch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/session"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/valuestore"
+)
+
+func TestBatchTuner_notAdaptive(t *testing.T) {
+	tuner := NewBatchTuner(&checkconfig.CheckConfig{
+		OidBatchSize:       10,
+		BulkMaxRepetitions: 20,
+	})
+
+	assert.False(t, tuner.onError(errTooBig))
+	for i := 0; i < successesBeforeGrow*2; i++ {
+		tuner.onSuccess()
+	}
+	tuner.Restore(BatchSizes{OidBatchSize: 1, BulkMaxRepetitions: 1})
+
+	assert.Equal(t, BatchSizes{OidBatchSize: 10, BulkMaxRepetitions: 20}, tuner.Current())
+}
+
+func TestBatchTuner_shrinkAndGrow(t *testing.T) {
+	tuner := NewBatchTuner(&checkconfig.CheckConfig{
+		OidBatchSize:          10,
+		BulkMaxRepetitions:    20,
+		AdaptiveBatching:      true,
+		MaxOidBatchSize:       11,
+		MaxBulkMaxRepetitions: 22,
+	})
+
+	assert.False(t, tuner.onError(fmt.Errorf("some other error")))
+	assert.Equal(t, BatchSizes{OidBatchSize: 10, BulkMaxRepetitions: 20}, tuner.Current())
+
+	assert.True(t, tuner.onError(fmt.Errorf("fetch column: failed getting oids: %w", errTooBig)))
+	assert.Equal(t, BatchSizes{OidBatchSize: 5, BulkMaxRepetitions: 10}, tuner.Current())
+
+	assert.True(t, tuner.onError(fmt.Errorf("fetch scalar: error getting oids: request timeout (after 3 retries)")))
+	assert.Equal(t, BatchSizes{OidBatchSize: 2, BulkMaxRepetitions: 5}, tuner.Current())
+
+	// growing only happens after successesBeforeGrow consecutive successes
+	for i := 0; i < successesBeforeGrow-1; i++ {
+		tuner.onSuccess()
+	}
+	assert.Equal(t, BatchSizes{OidBatchSize: 2, BulkMaxRepetitions: 5}, tuner.Current())
+	tuner.onSuccess()
+	assert.Equal(t, BatchSizes{OidBatchSize: 3, BulkMaxRepetitions: 6}, tuner.Current())
+
+	// growing is bounded by max values
+	for i := 0; i < successesBeforeGrow*20; i++ {
+		tuner.onSuccess()
+	}
+	assert.Equal(t, BatchSizes{OidBatchSize: 11, BulkMaxRepetitions: 22}, tuner.Current())
+}
+
+func TestBatchTuner_shrinkLimit(t *testing.T) {
+	tuner := NewBatchTuner(&checkconfig.CheckConfig{
+		OidBatchSize:       2,
+		BulkMaxRepetitions: 2,
+		AdaptiveBatching:   true,
+	})
+
+	assert.True(t, tuner.canShrink())
+	assert.True(t, tuner.onError(errTooBig))
+	assert.Equal(t, BatchSizes{OidBatchSize: 1, BulkMaxRepetitions: 1}, tuner.Current())
+	assert.False(t, tuner.canShrink())
+	assert.False(t, tuner.onError(errTooBig))
+}
+
+func TestBatchTuner_Restore(t *testing.T) {
+	tuner := NewBatchTuner(&checkconfig.CheckConfig{
+		OidBatchSize:          10,
+		BulkMaxRepetitions:    20,
+		AdaptiveBatching:      true,
+		MaxOidBatchSize:       80,
+		MaxBulkMaxRepetitions: 30,
+	})
+
+	tuner.Restore(BatchSizes{OidBatchSize: 3, BulkMaxRepetitions: 4})
+	assert.Equal(t, BatchSizes{OidBatchSize: 3, BulkMaxRepetitions: 4}, tuner.Current())
+
+	// invalid values are ignored
+	tuner.Restore(BatchSizes{})
+	assert.Equal(t, BatchSizes{OidBatchSize: 3, BulkMaxRepetitions: 4}, tuner.Current())
+
+	// values are bounded by max values, max oid batch size is bounded by gosnmp.MaxOids
+	tuner.Restore(BatchSizes{OidBatchSize: 100, BulkMaxRepetitions: 100})
+	assert.Equal(t, BatchSizes{OidBatchSize: gosnmp.MaxOids, BulkMaxRepetitions: 30}, tuner.Current())
+}
+
+func TestFetch_adaptiveBatchingRetryOnTooBig(t *testing.T) {
+	sess := session.CreateMockSession()
+
+	tooBigPacket := gosnmp.SnmpPacket{Error: gosnmp.TooBig}
+	bulkPacket := gosnmp.SnmpPacket{
+		Variables: []gosnmp.SnmpPDU{
+			{
+				Name:  "1.1.1.1",
+				Type:  gosnmp.Integer,
+				Value: 11,
+			},
+			{
+				Name:  "1.1.2.1",
+				Type:  gosnmp.Integer,
+				Value: 21,
+			},
+			{
+				Name:  "1.1.9.1",
+				Type:  gosnmp.Integer,
+				Value: 91,
+			},
+			{
+				Name:  "1.1.9.2",
+				Type:  gosnmp.Integer,
+				Value: 92,
+			},
+		},
+	}
+	sess.On("GetBulk", []string{"1.1.1", "1.1.2"}, uint32(4)).Return(&tooBigPacket, nil)
+	sess.On("GetBulk", []string{"1.1.1", "1.1.2"}, uint32(2)).Return(&bulkPacket, nil)
+
+	config := &checkconfig.CheckConfig{
+		BulkMaxRepetitions: 4,
+		OidBatchSize:       4,
+		AdaptiveBatching:   true,
+		OidConfig: checkconfig.OidConfig{
+			ColumnOids: []string{"1.1.1", "1.1.2"},
+		},
+	}
+	tuner := NewBatchTuner(config)
+	values, err := Fetch(sess, config, tuner)
+	assert.Nil(t, err)
+
+	expectedValues := &valuestore.ResultValueStore{
+		ScalarValues: valuestore.ScalarResultValuesType{},
+		ColumnValues: valuestore.ColumnResultValuesType{
+			"1.1.1": {
+				"1": valuestore.ResultValue{Value: float64(11)},
+			},
+			"1.1.2": {
+				"1": valuestore.ResultValue{Value: float64(21)},
+			},
+		},
+	}
+	assert.Equal(t, expectedValues, values)
+	assert.Equal(t, BatchSizes{OidBatchSize: 2, BulkMaxRepetitions: 2}, tuner.Current())
+	sess.AssertNotCalled(t, "GetNext", []string{"1.1.1", "1.1.2"})
+}
@@ -35,11 +35,30 @@ func (c columnFetchStrategy) String() string {
 	}
 }
 
+// maxShrinkRetries is the maximum number of times a fetch is retried with shrunk batch sizes
+const maxShrinkRetries = 2
+
 // Fetch oid values from device
 // TODO: pass only specific configs instead of the whole CheckConfig
-func Fetch(sess session.Session, config *checkconfig.CheckConfig) (*valuestore.ResultValueStore, error) {
+func Fetch(sess session.Session, config *checkconfig.CheckConfig, tuner *BatchTuner) (*valuestore.ResultValueStore, error) {
+	for attempt := 0; ; attempt++ {
+		canRetry := attempt < maxShrinkRetries && tuner.canShrink()
+		values, err := fetchValues(sess, config, tuner.Current(), canRetry)
+		if err == nil {
+			tuner.onSuccess()
+			return values, nil
+		}
+		// batch sizes are shrunk even if we can't retry, smaller values will be used for next check runs
+		if !tuner.onError(err) || !canRetry {
+			return nil, err
+		}
+		log.Debugf("fetch: retrying with smaller batch sizes %+v after error: %s", tuner.Current(), err)
+	}
+}
+
+func fetchValues(sess session.Session, config *checkconfig.CheckConfig, sizes BatchSizes, canRetry bool) (*valuestore.ResultValueStore, error) {
 	// fetch scalar values
-	scalarResults, err := fetchScalarOidsWithBatching(sess, config.OidConfig.ScalarOids, config.OidBatchSize)
+	scalarResults, err := fetchScalarOidsWithBatching(sess, config.OidConfig.ScalarOids, sizes.OidBatchSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch scalar oids with batching: %v", err)
 	}
@@ -50,11 +69,15 @@ func Fetch(sess session.Session, config *checkconfig.CheckConfig) (*valuestore.R
 		oids[value] = value
 	}
 
-	columnResults, err := fetchColumnOidsWithBatching(sess, oids, config.OidBatchSize, config.BulkMaxRepetitions, useGetBulk)
+	columnResults, err := fetchColumnOidsWithBatching(sess, oids, sizes.OidBatchSize, sizes.BulkMaxRepetitions, useGetBulk)
 	if err != nil {
+		if canRetry && isShrinkableError(err) {
+			// retrying GetBulk with smaller batch sizes is preferred over falling back to GetNext
+			return nil, fmt.Errorf("failed to fetch oids with GetBulk batching: %v", err)
+		}
 		log.Debugf("failed to fetch oids with GetBulk batching: %v", err)
 
-		columnResults, err = fetchColumnOidsWithBatching(sess, oids, config.OidBatchSize, config.BulkMaxRepetitions, useGetNext)
+		columnResults, err = fetchColumnOidsWithBatching(sess, oids, sizes.OidBatchSize, sizes.BulkMaxRepetitions, useGetNext)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch oids with GetNext batching: %v", err)
 		}
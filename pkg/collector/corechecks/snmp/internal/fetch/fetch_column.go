@@ -104,6 +104,9 @@ func getResults(sess session.Session, requestOids []string, bulkMaxRepetitions u
 			log.Debugf("fetch column: failed getting oids `%v` using GetNext: %s", requestOids, err)
 			return nil, fmt.Errorf("fetch column: failed getting oids `%v` using GetNext: %s", requestOids, err)
 		}
+		if getNextResults.Error == gosnmp.TooBig {
+			return nil, fmt.Errorf("fetch column: failed getting oids `%v` using GetNext: %w", requestOids, errTooBig)
+		}
 		results = getNextResults
 		if log.ShouldLog(seelog.DebugLvl) {
 			log.Debugf("fetch column: GetNext results: %v", gosnmplib.PacketAsString(results))
@@ -114,6 +117,9 @@ func getResults(sess session.Session, requestOids []string, bulkMaxRepetitions u
 			log.Debugf("fetch column: failed getting oids `%v` using GetBulk: %s", requestOids, err)
 			return nil, fmt.Errorf("fetch column: failed getting oids `%v` using GetBulk: %s", requestOids, err)
 		}
+		if getBulkResults.Error == gosnmp.TooBig {
+			return nil, fmt.Errorf("fetch column: failed getting oids `%v` using GetBulk: %w", requestOids, errTooBig)
+		}
 		results = getBulkResults
 		if log.ShouldLog(seelog.DebugLvl) {
 			log.Debugf("fetch column: GetBulk results: %v", gosnmplib.PacketAsString(results))
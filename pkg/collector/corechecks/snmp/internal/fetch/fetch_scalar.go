@@ -127,6 +127,9 @@ func doDoFetchScalarOids(session session.Session, oids []string) (*gosnmp.SnmpPa
 		log.Debugf("fetch scalar: error getting oids `%v`: %v", oids, err)
 		return nil, fmt.Errorf("fetch scalar: error getting oids `%v`: %v", oids, err)
 	}
+	if results.Error == gosnmp.TooBig {
+		return nil, fmt.Errorf("fetch scalar: error getting oids `%v`: %w", oids, errTooBig)
+	}
 	if log.ShouldLog(seelog.DebugLvl) {
 		log.Debugf("fetch scalar: results: %s", gosnmplib.PacketAsString(results))
 	}
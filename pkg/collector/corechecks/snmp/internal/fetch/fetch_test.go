@@ -378,7 +378,7 @@ func Test_fetchColumnOidsBatch_usingGetBulkAndGetNextFallback(t *testing.T) {
 			ColumnOids: []string{"1.1.1", "1.1.2", "1.1.3"},
 		},
 	}
-	columnValues, err := Fetch(sess, config)
+	columnValues, err := Fetch(sess, config, NewBatchTuner(config))
 	assert.Nil(t, err)
 
 	expectedColumnValues := &valuestore.ResultValueStore{
@@ -765,7 +765,7 @@ func Test_fetchValues_errors(t *testing.T) {
 			sess.On("GetBulk", []string{"1.1", "2.2"}, checkconfig.DefaultBulkMaxRepetitions).Return(&gosnmp.SnmpPacket{}, fmt.Errorf("bulk error"))
 			sess.On("GetNext", []string{"1.1", "2.2"}).Return(&gosnmp.SnmpPacket{}, fmt.Errorf("getnext error"))
 
-			_, err := Fetch(sess, &tt.config)
+			_, err := Fetch(sess, &tt.config, NewBatchTuner(&tt.config))
 
 			assert.Equal(t, tt.expectedError, err)
 		})
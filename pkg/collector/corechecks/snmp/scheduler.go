@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package snmp
+
+import (
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/devicecheck"
+)
+
+// deviceCollectionSpreadRatio is the fraction of the check interval over which
+// the collection of discovered devices is spread when `spread_device_collection` is enabled.
+// The remaining part of the interval leaves room for the slowest devices to complete.
+const deviceCollectionSpreadRatio = 0.5
+
+// scheduledDeviceCheck is a device check with the delay after which it should start,
+// relative to the beginning of the check run
+type scheduledDeviceCheck struct {
+	deviceCk *devicecheck.DeviceCheck
+	delay    time.Duration
+}
+
+// scheduleDeviceChecks assigns a start delay to each device check.
+// Devices are ordered using a stable hash of their device ID and are evenly spaced over the window,
+// this way a device is polled at the same moment of the check interval on every run,
+// and the load on the network is spread instead of polling every device at once.
+func scheduleDeviceChecks(deviceChecks []*devicecheck.DeviceCheck, window time.Duration) []scheduledDeviceCheck {
+	scheduled := make([]scheduledDeviceCheck, 0, len(deviceChecks))
+	for _, deviceCk := range deviceChecks {
+		scheduled = append(scheduled, scheduledDeviceCheck{deviceCk: deviceCk})
+	}
+	if len(scheduled) == 0 {
+		return scheduled
+	}
+
+	hashes := make(map[*devicecheck.DeviceCheck]uint64, len(scheduled))
+	for _, s := range scheduled {
+		h := fnv.New64()
+		_, _ = h.Write([]byte(s.deviceCk.GetDeviceID()))
+		hashes[s.deviceCk] = h.Sum64()
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return hashes[scheduled[i].deviceCk] < hashes[scheduled[j].deviceCk]
+	})
+
+	step := window / time.Duration(len(scheduled))
+	for i := range scheduled {
+		scheduled[i].delay = step * time.Duration(i)
+	}
+	return scheduled
+}
+
+// dispatchDeviceChecks sends each device check to the jobs channel once its delay has elapsed.
+// It returns early without dispatching remaining device checks if stop is closed.
+func dispatchDeviceChecks(scheduled []scheduledDeviceCheck, jobs chan<- *devicecheck.DeviceCheck, stop <-chan struct{}) {
+	start := time.Now()
+	for _, s := range scheduled {
+		if wait := s.delay - time.Since(start); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		jobs <- s.deviceCk
+	}
+}
@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package snmp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/devicecheck"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/session"
+)
+
+func createDeviceChecks(t *testing.T, ipAddresses ...string) []*devicecheck.DeviceCheck {
+	config := &checkconfig.CheckConfig{
+		Namespace:          "default",
+		OidBatchSize:       5,
+		BulkMaxRepetitions: 10,
+	}
+	var deviceChecks []*devicecheck.DeviceCheck
+	for _, ipAddress := range ipAddresses {
+		deviceCk, err := devicecheck.NewDeviceCheck(config, ipAddress, session.NewMockSession)
+		require.NoError(t, err)
+		deviceChecks = append(deviceChecks, deviceCk)
+	}
+	return deviceChecks
+}
+
+func Test_scheduleDeviceChecks(t *testing.T) {
+	deviceChecks := createDeviceChecks(t, "10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4")
+
+	scheduled := scheduleDeviceChecks(deviceChecks, 40*time.Second)
+	require.Len(t, scheduled, 4)
+	for i, s := range scheduled {
+		assert.Equal(t, time.Duration(i)*10*time.Second, s.delay)
+	}
+
+	// the order only depends on device IDs
+	reversed := []*devicecheck.DeviceCheck{deviceChecks[3], deviceChecks[2], deviceChecks[1], deviceChecks[0]}
+	scheduledReversed := scheduleDeviceChecks(reversed, 40*time.Second)
+	for i := range scheduled {
+		assert.Same(t, scheduled[i].deviceCk, scheduledReversed[i].deviceCk)
+	}
+}
+
+func Test_scheduleDeviceChecks_noWindow(t *testing.T) {
+	deviceChecks := createDeviceChecks(t, "10.0.0.1", "10.0.0.2")
+
+	scheduled := scheduleDeviceChecks(deviceChecks, 0)
+	require.Len(t, scheduled, 2)
+	for _, s := range scheduled {
+		assert.Equal(t, time.Duration(0), s.delay)
+	}
+
+	assert.Empty(t, scheduleDeviceChecks(nil, time.Minute))
+}
+
+func Test_dispatchDeviceChecks(t *testing.T) {
+	deviceChecks := createDeviceChecks(t, "10.0.0.1", "10.0.0.2", "10.0.0.3")
+	scheduled := scheduleDeviceChecks(deviceChecks, 30*time.Millisecond)
+
+	jobs := make(chan *devicecheck.DeviceCheck, len(scheduled))
+	start := time.Now()
+	dispatchDeviceChecks(scheduled, jobs, make(chan struct{}))
+	close(jobs)
+
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Len(t, jobs, 3)
+}
+
+func Test_dispatchDeviceChecks_stopped(t *testing.T) {
+	deviceChecks := createDeviceChecks(t, "10.0.0.1", "10.0.0.2", "10.0.0.3")
+	scheduled := scheduleDeviceChecks(deviceChecks, time.Hour)
+
+	jobs := make(chan *devicecheck.DeviceCheck, len(scheduled))
+	stop := make(chan struct{})
+	close(stop)
+	dispatchDeviceChecks(scheduled, jobs, stop)
+	close(jobs)
+
+	// only the first device check has no delay
+	assert.Len(t, jobs, 1)
+}
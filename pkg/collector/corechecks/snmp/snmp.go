@@ -44,6 +44,7 @@ type Check struct {
 	discovery                  *discovery.Discovery
 	sessionFactory             session.Factory
 	workerRunDeviceCheckErrors *atomic.Uint64
+	stop                       chan struct{}
 }
 
 // Run executes the check
@@ -66,6 +67,7 @@ func (c *Check) Run() error {
 			go c.runCheckDeviceWorker(w, &wg, jobs)
 		}
 
+		deviceChecks := make([]*devicecheck.DeviceCheck, 0, len(discoveredDevices))
 		for i := range discoveredDevices {
 			deviceCk := discoveredDevices[i]
 			hostname, err := deviceCk.GetDeviceHostname()
@@ -75,8 +77,14 @@ func (c *Check) Run() error {
 			}
 			// `interface_configs` option not supported by SNMP corecheck autodiscovery
 			deviceCk.SetSender(report.NewMetricSender(sender, hostname, nil, deviceCk.GetInterfaceBandwidthState()))
-			jobs <- deviceCk
+			deviceChecks = append(deviceChecks, deviceCk)
 		}
+
+		var spreadWindow time.Duration
+		if c.config.SpreadDeviceCollection {
+			spreadWindow = time.Duration(float64(c.config.MinCollectionInterval) * deviceCollectionSpreadRatio)
+		}
+		dispatchDeviceChecks(scheduleDeviceChecks(deviceChecks, spreadWindow), jobs, c.stop)
 		close(jobs)
 		wg.Wait() // wait for all workers to finish
 
@@ -155,6 +163,7 @@ func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigD
 	}
 
 	if c.config.IsDiscovery() {
+		c.stop = make(chan struct{})
 		c.discovery = discovery.NewDiscovery(c.config, c.sessionFactory)
 		c.discovery.Start()
 	} else {
@@ -169,6 +178,7 @@ func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigD
 // Cancel is called when check is unscheduled
 func (c *Check) Cancel() {
 	if c.discovery != nil {
+		close(c.stop)
 		c.discovery.Stop()
 		c.discovery = nil
 	}
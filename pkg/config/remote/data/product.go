@@ -27,6 +27,8 @@ const (
 	ProductAgentTask Product = "AGENT_TASK"
 	// ProductAgentConfig is to receive agent configurations, like the log level
 	ProductAgentConfig = "AGENT_CONFIG"
+	// ProductAgentPipelineRules is to receive DogStatsD mapper profiles and logs processing rules
+	ProductAgentPipelineRules Product = "AGENT_PIPELINE_RULES"
 	// ProductAgentIntegrations is to receive integrations to schedule
 	ProductAgentIntegrations = "AGENT_INTEGRATIONS"
 	// ProductContainerAutoscalingSettings receives definition of container autoscaling
@@ -109,6 +109,7 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnv("apm_config.enable_rare_sampler", "DD_APM_ENABLE_RARE_SAMPLER")
 	config.BindEnv("apm_config.disable_rare_sampler", "DD_APM_DISABLE_RARE_SAMPLER") // Deprecated
 	config.BindEnv("apm_config.max_remote_traces_per_second", "DD_APM_MAX_REMOTE_TPS")
+	config.BindEnv("apm_config.sampling_rules", "DD_APM_SAMPLING_RULES")
 	config.BindEnv("apm_config.probabilistic_sampler.enabled", "DD_APM_PROBABILISTIC_SAMPLER_ENABLED")
 	config.BindEnv("apm_config.probabilistic_sampler.sampling_percentage", "DD_APM_PROBABILISTIC_SAMPLER_SAMPLING_PERCENTAGE")
 	config.BindEnv("apm_config.probabilistic_sampler.hash_seed", "DD_APM_PROBABILISTIC_SAMPLER_HASH_SEED")
@@ -126,6 +127,7 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnv("apm_config.additional_endpoints", "DD_APM_ADDITIONAL_ENDPOINTS")
 	config.BindEnv("apm_config.replace_tags", "DD_APM_REPLACE_TAGS")
 	config.BindEnv("apm_config.analyzed_spans", "DD_APM_ANALYZED_SPANS")
+	config.BindEnv("apm_config.span_metrics", "DD_APM_SPAN_METRICS")
 	config.BindEnv("apm_config.ignore_resources", "DD_APM_IGNORE_RESOURCES", "DD_IGNORE_RESOURCE")
 	config.BindEnv("apm_config.receiver_socket", "DD_APM_RECEIVER_SOCKET")
 	config.BindEnv("apm_config.windows_pipe_name", "DD_APM_WINDOWS_PIPE_NAME")
@@ -153,6 +155,15 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnv("apm_config.obfuscation.credit_cards.enabled", "DD_APM_OBFUSCATION_CREDIT_CARDS_ENABLED")
 	config.BindEnv("apm_config.obfuscation.credit_cards.luhn", "DD_APM_OBFUSCATION_CREDIT_CARDS_LUHN")
 	config.BindEnvAndSetDefault("apm_config.debug.port", 5012, "DD_APM_DEBUG_PORT")
+	config.BindEnvAndSetDefault("apm_config.debug.trace_inspection.enabled", false, "DD_APM_DEBUG_TRACE_INSPECTION_ENABLED")
+	config.BindEnvAndSetDefault("apm_config.debug.trace_inspection.buffer_size", 100, "DD_APM_DEBUG_TRACE_INSPECTION_BUFFER_SIZE")
+	config.BindEnvAndSetDefault("apm_config.zipkin.enabled", false, "DD_APM_ZIPKIN_ENABLED")
+	config.BindEnvAndSetDefault("apm_config.jaeger.enabled", false, "DD_APM_JAEGER_ENABLED")
+	config.BindEnvAndSetDefault("apm_config.jaeger.grpc_port", 0, "DD_APM_JAEGER_GRPC_PORT")
+	config.BindEnvAndSetDefault("apm_config.spool.enabled", false, "DD_APM_SPOOL_ENABLED")
+	config.BindEnvAndSetDefault("apm_config.spool.path", "", "DD_APM_SPOOL_PATH")
+	config.BindEnvAndSetDefault("apm_config.spool.max_size_mb", 500, "DD_APM_SPOOL_MAX_SIZE_MB")
+	config.BindEnvAndSetDefault("apm_config.spool.max_age_seconds", 3600, "DD_APM_SPOOL_MAX_AGE_SECONDS")
 	config.BindEnv("apm_config.features", "DD_APM_FEATURES")
 	config.ParseEnvAsStringSlice("apm_config.features", func(s string) []string {
 		// Either commas or spaces can be used as separators.
@@ -192,6 +203,22 @@ func setupAPM(config pkgconfigmodel.Setup) {
 		return out
 	})
 
+	config.ParseEnvAsSlice("apm_config.sampling_rules", func(in string) []interface{} {
+		var out []interface{}
+		if err := json.Unmarshal([]byte(in), &out); err != nil {
+			log.Warnf(`"apm_config.sampling_rules" can not be parsed: %v`, err)
+		}
+		return out
+	})
+
+	config.ParseEnvAsSlice("apm_config.span_metrics", func(in string) []interface{} {
+		var out []interface{}
+		if err := json.Unmarshal([]byte(in), &out); err != nil {
+			log.Warnf(`"apm_config.span_metrics" can not be parsed: %v`, err)
+		}
+		return out
+	})
+
 	config.ParseEnvAsMapStringInterface("apm_config.analyzed_spans", func(in string) map[string]interface{} {
 		out, err := parseAnalyzedSpans(in)
 		if err != nil {
@@ -208,6 +235,16 @@ func setupAPM(config pkgconfigmodel.Setup) {
 		}
 		return out
 	})
+
+	config.BindEnvAndSetDefault("apm_config.extra_aggregation_tags", []string{}, "DD_APM_EXTRA_AGGREGATION_TAGS")
+	config.ParseEnvAsStringSlice("apm_config.extra_aggregation_tags", func(in string) []string {
+		var out []string
+		if err := json.Unmarshal([]byte(in), &out); err != nil {
+			log.Warnf(`"apm_config.extra_aggregation_tags" can not be parsed: %v`, err)
+		}
+		return out
+	})
+	config.BindEnvAndSetDefault("apm_config.extra_aggregation_tags_cardinality_limit", 100, "DD_APM_EXTRA_AGGREGATION_TAGS_CARDINALITY_LIMIT")
 }
 
 func parseKVList(key string) func(string) []string {
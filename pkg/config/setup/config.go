@@ -460,6 +460,7 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("network_path.collector.workers", 4)
 	config.BindEnvAndSetDefault("network_path.collector.timeout", DefaultNetworkPathTimeout)
 	config.BindEnvAndSetDefault("network_path.collector.max_ttl", DefaultNetworkPathMaxTTL)
+	config.BindEnvAndSetDefault("network_path.collector.traceroute_runs", 3)
 	config.BindEnvAndSetDefault("network_path.collector.input_chan_size", 100000)
 	config.BindEnvAndSetDefault("network_path.collector.processing_chan_size", 100000)
 	config.BindEnvAndSetDefault("network_path.collector.pathtest_contexts_limit", 100000)
@@ -508,6 +509,11 @@ func InitConfig(config pkgconfigmodel.Config) {
 		`^kubectl\.kubernetes\.io\/last-applied-configuration$`,
 		`^ad\.datadoghq\.com\/([[:alnum:]]+\.)?(checks|check_names|init_configs|instances)$`,
 	})
+	config.BindEnvAndSetDefault("cluster_agent.kubernetes_resources_collection.workloads_enabled", true)
+	config.BindEnvAndSetDefault("cluster_agent.kubernetes_resources_collection.workload_annotations_exclude", []string{
+		`^kubectl\.kubernetes\.io\/last-applied-configuration$`,
+		`^ad\.datadoghq\.com\/([[:alnum:]]+\.)?(checks|check_names|init_configs|instances)$`,
+	})
 	config.BindEnvAndSetDefault("metrics_port", "5000")
 	config.BindEnvAndSetDefault("cluster_agent.language_detection.patcher.enabled", true)
 	config.BindEnvAndSetDefault("cluster_agent.language_detection.patcher.base_backoff", "5m")
@@ -622,6 +628,9 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("jmx_max_ram_percentage", float64(25.0))
 	config.BindEnvAndSetDefault("jmx_max_restarts", int64(3))
 	config.BindEnvAndSetDefault("jmx_restart_interval", int64(5))
+	config.BindEnvAndSetDefault("jmx_process_mode", "single")
+	config.BindEnvAndSetDefault("jmx_restart_backoff_initial", 0) // value in seconds
+	config.BindEnvAndSetDefault("jmx_restart_backoff_max", 300)   // value in seconds
 	config.BindEnvAndSetDefault("jmx_thread_pool_size", 3)
 	config.BindEnvAndSetDefault("jmx_reconnection_thread_pool_size", 3)
 	config.BindEnvAndSetDefault("jmx_collection_timeout", 60)
@@ -668,6 +677,7 @@ func InitConfig(config pkgconfigmodel.Config) {
 
 	// Autoscaling product
 	config.BindEnvAndSetDefault("autoscaling.workload.enabled", false)
+	config.BindEnvAndSetDefault("autoscaling.workload.local_recommender.refresh_period", 30) // value in seconds
 
 	config.BindEnvAndSetDefault("hpa_watcher_polling_freq", 10)
 	config.BindEnvAndSetDefault("hpa_watcher_gc_period", 60*5) // 5 minutes
@@ -710,6 +720,15 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("cluster_checks.exclude_checks", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.exclude_checks_from_dispatching", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.rebalance_period", 10*time.Minute)
+	config.BindEnvAndSetDefault("cluster_checks.capacity_weighted_dispatching", false) // Weights runners by the allocatable CPU and memory of their node.
+	config.BindEnv("cluster_checks.placement_rules")
+	config.ParseEnvAsSlice("cluster_checks.placement_rules", func(in string) []interface{} {
+		var rules []interface{}
+		if err := json.Unmarshal([]byte(in), &rules); err != nil {
+			log.Errorf(`"cluster_checks.placement_rules" can not be parsed: %v`, err)
+		}
+		return rules
+	})
 
 	// Cluster check runner
 	config.BindEnvAndSetDefault("clc_runner_enabled", false)
@@ -788,6 +807,11 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.image_name", "agent")
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.image_tag", "latest")
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.cluster_agent.enabled", "true")
+	config.BindEnvAndSetDefault("admission_controller.workload_config_validation.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.workload_config_validation.endpoint", "/validate-workload-config")
+	config.BindEnvAndSetDefault("admission_controller.workload_config_validation.mode", "warn") // possible values: warn / reject
+	config.BindEnvAndSetDefault("admission_controller.workload_config_validation.known_checks", []string{})
+	config.BindEnvAndSetDefault("admission_controller.workload_config_validation.pod_owners_cache_validity", 10) // in minutes
 
 	// Declare other keys that don't have a default/env var.
 	// Mostly, keys we use IsSet() on, because IsSet always returns true if a key has a default.
@@ -882,6 +906,7 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("inventories_configuration_enabled", true)             // controls the agent configurations
 	config.BindEnvAndSetDefault("inventories_checks_configuration_enabled", true)      // controls the checks configurations
 	config.BindEnvAndSetDefault("inventories_collect_cloud_provider_account_id", true) // collect collection of `cloud_provider_account_id`
+	config.BindEnvAndSetDefault("inventories_collect_hardware", false)                 // controls the hardware inventory of the host
 	// when updating the default here also update pkg/metadata/inventories/README.md
 	config.BindEnvAndSetDefault("inventories_max_interval", 0) // 0 == default interval from inventories
 	config.BindEnvAndSetDefault("inventories_min_interval", 0) // 0 == default interval from inventories
@@ -1005,9 +1030,15 @@ func InitConfig(config pkgconfigmodel.Config) {
 	// Reverse DNS Enrichment
 	config.SetKnown("reverse_dns_enrichment.workers")
 	config.SetKnown("reverse_dns_enrichment.chan_size")
+	config.SetKnown("reverse_dns_enrichment.static_mappings")
+	config.BindEnvAndSetDefault("reverse_dns_enrichment.hosts_file.enabled", false)
+	config.BindEnvAndSetDefault("reverse_dns_enrichment.hosts_file.path", "")
+	config.BindEnvAndSetDefault("reverse_dns_enrichment.dns_server.address", "")
+	config.BindEnvAndSetDefault("reverse_dns_enrichment.dns_server.timeout", time.Duration(0))
 	config.BindEnvAndSetDefault("reverse_dns_enrichment.rate_limiter.enabled", true)
 	config.BindEnvAndSetDefault("reverse_dns_enrichment.cache.enabled", true)
 	config.BindEnvAndSetDefault("reverse_dns_enrichment.cache.entry_ttl", time.Duration(0))
+	config.BindEnvAndSetDefault("reverse_dns_enrichment.cache.negative_entry_ttl", time.Duration(0))
 	config.BindEnvAndSetDefault("reverse_dns_enrichment.cache.clean_interval", time.Duration(0))
 	config.BindEnvAndSetDefault("reverse_dns_enrichment.cache.persist_interval", time.Duration(0))
 	config.BindEnvAndSetDefault("reverse_dns_enrichment.cache.max_retries", -1)
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+const socketDialTimeout = time.Second
+
+// default locations of the container runtimes sockets and of the pods logs, replaced in tests
+var (
+	defaultDockerSocket     = "/var/run/docker.sock"
+	defaultContainerdSocket = "/var/run/containerd/containerd.sock"
+	podsLogsDirectory       = "/var/log/pods"
+)
+
+// diagnoseContainerCollection checks the access to the sources container logs are collected from: the Docker and
+// containerd sockets, and the pods logs directory.  Only one of them is required, so failures are reported as
+// warnings unless none of them is accessible.
+func diagnoseContainerCollection(cfg pkgconfigmodel.Reader) []diagnosis.Diagnosis {
+	var diagnoses []diagnosis.Diagnosis
+	accessible := false
+
+	record := func(d diagnosis.Diagnosis, ok bool) {
+		accessible = accessible || ok
+		diagnoses = append(diagnoses, d)
+	}
+
+	record(diagnoseSocket("Docker", dockerSocket(), "docker"))
+	containerdSocket := cfg.GetString("cri_socket_path")
+	if containerdSocket == "" {
+		containerdSocket = defaultContainerdSocket
+	}
+	record(diagnoseSocket("containerd", containerdSocket, ""))
+	record(diagnosePodsLogsDirectory())
+
+	if !accessible {
+		diagnoses = append(diagnoses, diagnosis.Diagnosis{
+			Result:    diagnosis.DiagnosisFail,
+			Category:  category,
+			Name:      "Container logs collection",
+			Diagnosis: "None of the container runtimes sockets nor the pods logs directory are accessible, container logs are not collected",
+			Remediation: "Please mount the container runtime socket or " + podsLogsDirectory + " in the Agent container, " +
+				"or grant the user running the Agent access to them",
+		})
+	}
+	return diagnoses
+}
+
+// dockerSocket returns the path of the Docker socket, from DOCKER_HOST when it points to a unix socket
+func dockerSocket() string {
+	if host, ok := strings.CutPrefix(os.Getenv("DOCKER_HOST"), "unix://"); ok && host != "" {
+		return host
+	}
+	return defaultDockerSocket
+}
+
+func diagnoseSocket(runtimeName string, path string, group string) (diagnosis.Diagnosis, bool) {
+	name := runtimeName + " socket access"
+
+	conn, err := net.DialTimeout("unix", path, socketDialTimeout)
+	if err != nil {
+		remediation := fmt.Sprintf("If container logs are collected from %s, please make sure %s exists and that the user running the Agent can access it", runtimeName, path)
+		if group != "" {
+			remediation += fmt.Sprintf(", e.g. by adding the `dd-agent` user to the `%s` group", group)
+		}
+		return diagnosis.Diagnosis{
+			Result:      diagnosis.DiagnosisWarning,
+			Category:    category,
+			Name:        name,
+			Diagnosis:   fmt.Sprintf("Unable to connect to the %s socket %s", runtimeName, path),
+			Remediation: remediation,
+			RawError:    err.Error(),
+		}, false
+	}
+	conn.Close()
+
+	return diagnosis.Diagnosis{
+		Result:    diagnosis.DiagnosisSuccess,
+		Category:  category,
+		Name:      name,
+		Diagnosis: fmt.Sprintf("The %s socket %s is accessible", runtimeName, path),
+	}, true
+}
+
+func diagnosePodsLogsDirectory() (diagnosis.Diagnosis, bool) {
+	name := "Pods logs directory access"
+
+	if _, err := os.ReadDir(podsLogsDirectory); err != nil {
+		return diagnosis.Diagnosis{
+			Result:    diagnosis.DiagnosisWarning,
+			Category:  category,
+			Name:      name,
+			Diagnosis: "Unable to list the pods logs directory " + podsLogsDirectory,
+			Remediation: "On Kubernetes, please mount " + podsLogsDirectory + " in the Agent container so that container logs " +
+				"are collected from files",
+			RawError: err.Error(),
+		}, false
+	}
+
+	return diagnosis.Diagnosis{
+		Result:    diagnosis.DiagnosisSuccess,
+		Category:  category,
+		Name:      name,
+		Diagnosis: "The pods logs directory " + podsLogsDirectory + " can be listed",
+	}, true
+}
@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package logs
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+func TestDiagnoseContainerCollection(t *testing.T) {
+	dir, err := os.MkdirTemp("", "sock")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	dockerSocket, containerdSocket, podsDirectory := defaultDockerSocket, defaultContainerdSocket, podsLogsDirectory
+	t.Cleanup(func() {
+		defaultDockerSocket, defaultContainerdSocket, podsLogsDirectory = dockerSocket, containerdSocket, podsDirectory
+	})
+	t.Setenv("DOCKER_HOST", "")
+	defaultDockerSocket = filepath.Join(dir, "docker.sock")
+	defaultContainerdSocket = filepath.Join(dir, "containerd.sock")
+	podsLogsDirectory = filepath.Join(dir, "pods")
+
+	cfg := configmock.New(t)
+
+	// nothing is accessible
+	assert.Equal(t, map[string]diagnosis.Result{
+		"Docker socket access":       diagnosis.DiagnosisWarning,
+		"containerd socket access":   diagnosis.DiagnosisWarning,
+		"Pods logs directory access": diagnosis.DiagnosisWarning,
+		"Container logs collection":  diagnosis.DiagnosisFail,
+	}, results(diagnoseContainerCollection(cfg)))
+
+	// the containerd socket configured with cri_socket_path is accessible
+	criSocket := filepath.Join(dir, "cri.sock")
+	listener, err := net.Listen("unix", criSocket)
+	require.NoError(t, err)
+	defer listener.Close()
+	cfg.SetWithoutSource("cri_socket_path", criSocket)
+
+	assert.Equal(t, map[string]diagnosis.Result{
+		"Docker socket access":       diagnosis.DiagnosisWarning,
+		"containerd socket access":   diagnosis.DiagnosisSuccess,
+		"Pods logs directory access": diagnosis.DiagnosisWarning,
+	}, results(diagnoseContainerCollection(cfg)))
+}
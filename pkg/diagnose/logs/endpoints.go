@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	logsConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	logshttp "github.com/DataDog/datadog-agent/pkg/logs/client/http"
+	logstcp "github.com/DataDog/datadog-agent/pkg/logs/client/tcp"
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+const tcpConnectivityTimeout = 5 * time.Second
+
+// connectivity checks, replaced in tests
+var (
+	checkHTTPConnectivity           = logshttp.CheckConnectivityDiagnose
+	checkCompressedHTTPConnectivity = logshttp.CheckCompressedConnectivityDiagnose
+	checkTCPConnectivity            = logstcp.CheckConnectivityDiagnose
+)
+
+// diagnoseEndpoints checks the connectivity to the logs endpoints over HTTP, with and without compression, and
+// over TCP.  The Agent sends logs over HTTP unless TCP is forced by the configuration, in which case TCP
+// failures are reported as failures, otherwise they are only reported as warnings since TCP is a fallback.
+func diagnoseEndpoints(cfg pkgconfigmodel.Reader) []diagnosis.Diagnosis {
+	var diagnoses []diagnosis.Diagnosis
+
+	// endpoints used when the HTTP connectivity is ok, TCP is used instead when it is forced by the configuration
+	configuredEndpoints, err := logsConfig.BuildEndpoints(cfg, logsConfig.HTTPConnectivitySuccess, "logs", logsConfig.AgentJSONIntakeProtocol, logsConfig.DefaultIntakeOrigin)
+	if err != nil {
+		return []diagnosis.Diagnosis{endpointsMisconfigurationDiagnosis(err)}
+	}
+	useHTTP := configuredEndpoints.UseHTTP
+
+	httpEndpoints, err := logsConfig.BuildHTTPEndpoints(cfg, "logs", logsConfig.AgentJSONIntakeProtocol, logsConfig.DefaultIntakeOrigin)
+	if err != nil {
+		diagnoses = append(diagnoses, endpointsMisconfigurationDiagnosis(err))
+	} else {
+		proxyReport := httpProxyReport(cfg)
+		for _, endpoint := range httpEndpoints.Endpoints {
+			diagnoses = append(diagnoses, diagnoseHTTPEndpoint(cfg, endpoint, useHTTP, proxyReport)...)
+		}
+	}
+
+	// endpoints used when the HTTP connectivity check fails
+	tcpEndpoints, err := logsConfig.BuildEndpoints(cfg, logsConfig.HTTPConnectivityFailure, "logs", logsConfig.AgentJSONIntakeProtocol, logsConfig.DefaultIntakeOrigin)
+	if err != nil {
+		diagnoses = append(diagnoses, endpointsMisconfigurationDiagnosis(err))
+	} else if !tcpEndpoints.UseHTTP {
+		for _, endpoint := range tcpEndpoints.Endpoints {
+			diagnoses = append(diagnoses, diagnoseTCPEndpoint(endpoint, !useHTTP))
+		}
+	}
+
+	return diagnoses
+}
+
+func diagnoseHTTPEndpoint(cfg pkgconfigmodel.Reader, endpoint logsConfig.Endpoint, used bool, proxyReport string) []diagnosis.Diagnosis {
+	url, err := checkHTTPConnectivity(endpoint, cfg)
+	url = scrubber.ScrubLine(url)
+	diagnoses := []diagnosis.Diagnosis{
+		connectivityDiagnosis("HTTP connectivity to "+url, url, proxyReport, used, err),
+	}
+
+	// the intake must accept compressed payloads when compression is enabled, some proxies reject them
+	if err == nil && endpoint.UseCompression {
+		_, err = checkCompressedHTTPConnectivity(endpoint, cfg)
+		d := connectivityDiagnosis("Compressed HTTP connectivity to "+url, url, proxyReport, used, err)
+		if err != nil {
+			d.Remediation = "The endpoint rejected a gzip compressed payload, please make sure proxies between the Agent and " + url +
+				" accept the `Content-Encoding: gzip` header, or set `logs_config.use_compression: false`"
+		}
+		diagnoses = append(diagnoses, d)
+	}
+	return diagnoses
+}
+
+func diagnoseTCPEndpoint(endpoint logsConfig.Endpoint, used bool) diagnosis.Diagnosis {
+	address, err := checkTCPConnectivity(endpoint, tcpConnectivityTimeout)
+	report := fmt.Sprintf("SSL: %t", endpoint.UseSSL())
+	if endpoint.ProxyAddress != "" {
+		report += "\nSOCKS5 proxy: " + scrubber.ScrubLine(endpoint.ProxyAddress)
+	}
+	return connectivityDiagnosis("TCP connectivity to "+address, address, report, used, err)
+}
+
+// connectivityDiagnosis builds the diagnosis of a connectivity check. Failures to connect to endpoints the Agent
+// does not send logs to are reported as warnings.
+func connectivityDiagnosis(name string, address string, report string, used bool, err error) diagnosis.Diagnosis {
+	d := diagnosis.Diagnosis{
+		Category: category,
+		Name:     name,
+	}
+
+	if err == nil {
+		d.Result = diagnosis.DiagnosisSuccess
+		d.Diagnosis = fmt.Sprintf("Connectivity to `%s` is Ok\n%s", address, report)
+		return d
+	}
+
+	d.Result = diagnosis.DiagnosisFail
+	d.Diagnosis = fmt.Sprintf("Connection to `%s` failed\n%s", address, report)
+	d.Remediation = "Please validate Agent configuration and firewall to access " + address
+	if !used {
+		d.Result = diagnosis.DiagnosisWarning
+		d.Diagnosis += "\nThe Agent does not currently send logs to this endpoint with this protocol"
+	}
+	d.RawError = scrubber.ScrubLine(err.Error())
+	return d
+}
+
+func endpointsMisconfigurationDiagnosis(err error) diagnosis.Diagnosis {
+	return diagnosis.Diagnosis{
+		Result:      diagnosis.DiagnosisFail,
+		Category:    category,
+		Name:        "Logs endpoints configuration",
+		Diagnosis:   "Misconfiguration of logs endpoints",
+		Remediation: "Please validate the `logs_config` section of the Agent configuration",
+		RawError:    err.Error(),
+	}
+}
+
+// httpProxyReport describes the proxies used by the HTTP transport shared with the core Agent
+func httpProxyReport(cfg pkgconfigmodel.Reader) string {
+	proxies := cfg.GetProxies()
+	if proxies == nil || (proxies.HTTPS == "" && proxies.HTTP == "") {
+		return "Proxy: none"
+	}
+
+	report := "Proxy:"
+	if proxies.HTTPS != "" {
+		report += " https=" + scrubber.ScrubLine(proxies.HTTPS)
+	}
+	if proxies.HTTP != "" {
+		report += " http=" + scrubber.ScrubLine(proxies.HTTP)
+	}
+	if len(proxies.NoProxy) > 0 {
+		report += " no_proxy=" + strconv.Itoa(len(proxies.NoProxy)) + " entries"
+	}
+	return report
+}
@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import (
+	"fmt"
+	"os"
+
+	logsConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+// default locations of the persistent and volatile journals, looked up in this order by the journald launcher,
+// replaced in tests
+var defaultJournalDirectories = []string{"/var/log/journal", "/run/log/journal"}
+
+// diagnoseJournald checks that the journal read by each journald source can be listed by the Agent
+func diagnoseJournald(sources []*logsConfig.LogsConfig) []diagnosis.Diagnosis {
+	var diagnoses []diagnosis.Diagnosis
+	for _, source := range sources {
+		if source.Type != logsConfig.JournaldType {
+			continue
+		}
+		diagnoses = append(diagnoses, diagnoseJournal(source.Path))
+	}
+	return diagnoses
+}
+
+func diagnoseJournal(path string) diagnosis.Diagnosis {
+	directories := defaultJournalDirectories
+	if path != "" {
+		directories = []string{path}
+	}
+
+	var lastErr error
+	for _, dir := range directories {
+		if _, err := os.ReadDir(dir); err != nil {
+			lastErr = err
+			continue
+		}
+		return diagnosis.Diagnosis{
+			Result:    diagnosis.DiagnosisSuccess,
+			Category:  category,
+			Name:      "Journald access " + dir,
+			Diagnosis: fmt.Sprintf("The journal %s can be read", dir),
+		}
+	}
+
+	name := "Journald access"
+	if path != "" {
+		name += " " + path
+	}
+	return diagnosis.Diagnosis{
+		Result:    diagnosis.DiagnosisFail,
+		Category:  category,
+		Name:      name,
+		Diagnosis: fmt.Sprintf("Unable to read the journal from %v", directories),
+		Remediation: "Please add the `dd-agent` user to the `systemd-journal` group, and mount the journal directory in the " +
+			"Agent container when running in a container",
+		RawError: lastErr.Error(),
+	}
+}
@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logsConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+func TestDiagnoseJournald(t *testing.T) {
+	journal := t.TempDir()
+	missing := filepath.Join(journal, "missing")
+
+	defaultDirectories := defaultJournalDirectories
+	t.Cleanup(func() { defaultJournalDirectories = defaultDirectories })
+	defaultJournalDirectories = []string{missing, journal}
+
+	diagnoses := diagnoseJournald([]*logsConfig.LogsConfig{
+		{Type: logsConfig.JournaldType},
+		{Type: logsConfig.JournaldType, Path: missing},
+		{Type: logsConfig.FileType, Path: missing},
+	})
+	require.Len(t, diagnoses, 2)
+
+	assert.Equal(t, diagnosis.DiagnosisSuccess, diagnoses[0].Result)
+	assert.Equal(t, "Journald access "+journal, diagnoses[0].Name)
+
+	assert.Equal(t, diagnosis.DiagnosisFail, diagnoses[1].Result)
+	assert.Contains(t, diagnoses[1].Remediation, "systemd-journal")
+	assert.NotEmpty(t, diagnoses[1].RawError)
+}
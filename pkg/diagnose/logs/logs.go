@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package logs provides a diagnose suite troubleshooting logs collection: connectivity to the logs endpoints
+// and access to the files, container runtimes and journal the configured sources collect logs from.
+package logs
+
+import (
+	"runtime"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers"
+	logsConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+const category = "logs"
+
+// Diagnose runs the logs diagnoses with the Agent configuration and the logs sources defined in the integration
+// configuration files
+func Diagnose() []diagnosis.Diagnosis {
+	cfg := pkgconfigsetup.Datadog()
+	if !cfg.GetBool("logs_enabled") {
+		return []diagnosis.Diagnosis{{
+			Result:      diagnosis.DiagnosisWarning,
+			Category:    category,
+			Name:        "Logs collection",
+			Diagnosis:   "Logs collection is disabled",
+			Remediation: "Set `logs_enabled: true` in the Agent configuration to collect logs",
+		}}
+	}
+
+	sources, diagnoses := getFileConfiguredSources()
+	diagnoses = append(diagnoses, diagnose(cfg, sources)...)
+	return diagnoses
+}
+
+func diagnose(cfg pkgconfigmodel.Reader, sources []*logsConfig.LogsConfig) []diagnosis.Diagnosis {
+	diagnoses := diagnoseEndpoints(cfg)
+	diagnoses = append(diagnoses, diagnoseFileSources(sources)...)
+
+	// container runtimes sockets, pods logs and the journal are only collected from on Linux
+	if runtime.GOOS == "linux" {
+		if cfg.GetBool("logs_config.container_collect_all") || hasSourceType(sources, logsConfig.DockerType, logsConfig.ContainerdType) {
+			diagnoses = append(diagnoses, diagnoseContainerCollection(cfg)...)
+		}
+		if hasSourceType(sources, logsConfig.JournaldType) {
+			diagnoses = append(diagnoses, diagnoseJournald(sources)...)
+		}
+	}
+
+	return diagnoses
+}
+
+// getFileConfiguredSources returns the logs sources defined in the integration configuration files
+func getFileConfiguredSources() ([]*logsConfig.LogsConfig, []diagnosis.Diagnosis) {
+	configs, _, err := providers.ReadConfigFiles(providers.GetAll)
+	if err != nil {
+		return nil, []diagnosis.Diagnosis{{
+			Result:      diagnosis.DiagnosisUnexpectedError,
+			Category:    category,
+			Name:        "Logs sources",
+			Diagnosis:   "Unable to read the integration configuration files, logs sources are not diagnosed",
+			Remediation: "Please validate the integration configuration files in the conf.d directory",
+			RawError:    err.Error(),
+		}}
+	}
+
+	var sources []*logsConfig.LogsConfig
+	var diagnoses []diagnosis.Diagnosis
+	for _, config := range configs {
+		if len(config.LogsConfig) == 0 {
+			continue
+		}
+		parsed, err := logsConfig.ParseYAML(config.LogsConfig)
+		if err != nil {
+			diagnoses = append(diagnoses, diagnosis.Diagnosis{
+				Result:      diagnosis.DiagnosisFail,
+				Category:    category,
+				Name:        "Logs configuration of " + config.Name,
+				Diagnosis:   "Invalid logs configuration for integration " + config.Name,
+				Remediation: "Please fix the `logs` section of the " + config.Name + " integration configuration",
+				RawError:    err.Error(),
+			})
+			continue
+		}
+		sources = append(sources, parsed...)
+	}
+	return sources, diagnoses
+}
+
+func hasSourceType(sources []*logsConfig.LogsConfig, sourceTypes ...string) bool {
+	for _, source := range sources {
+		for _, sourceType := range sourceTypes {
+			if source.Type == sourceType {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	logsConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+// rotatedFileRegexp matches the files created by common rotation schemes, e.g. app.log.1 or app.log.2.gz
+var rotatedFileRegexp = regexp.MustCompile(`\.(\d+|gz|bz2|xz|zip)$`)
+
+// diagnoseFileSources checks that the files matched by the file sources can be read by the Agent, and that their
+// directories can be listed, which is required to detect new and rotated files.
+func diagnoseFileSources(sources []*logsConfig.LogsConfig) []diagnosis.Diagnosis {
+	var diagnoses []diagnosis.Diagnosis
+	for _, source := range sources {
+		if source.Type != logsConfig.FileType || source.Path == "" {
+			continue
+		}
+		diagnoses = append(diagnoses, diagnoseFileSource(source)...)
+	}
+	return diagnoses
+}
+
+func diagnoseFileSource(source *logsConfig.LogsConfig) []diagnosis.Diagnosis {
+	name := "Logs file source " + source.Path
+
+	paths, err := filepath.Glob(source.Path)
+	if err != nil {
+		return []diagnosis.Diagnosis{{
+			Result:      diagnosis.DiagnosisFail,
+			Category:    category,
+			Name:        name,
+			Diagnosis:   fmt.Sprintf("Invalid path pattern `%s`", source.Path),
+			Remediation: "Please fix the `path` of the logs source, see https://pkg.go.dev/path/filepath#Match for the supported syntax",
+			RawError:    err.Error(),
+		}}
+	}
+	paths = excludePaths(paths, source.ExcludePaths)
+
+	var diagnoses []diagnosis.Diagnosis
+	if d, ok := diagnoseDirectories(name, source.Path); !ok {
+		diagnoses = append(diagnoses, d)
+	}
+
+	if len(paths) == 0 {
+		return append(diagnoses, diagnosis.Diagnosis{
+			Result:      diagnosis.DiagnosisWarning,
+			Category:    category,
+			Name:        name,
+			Diagnosis:   fmt.Sprintf("No file matches `%s`", source.Path),
+			Remediation: "Please check the `path` and `exclude_paths` of the logs source, files created later are collected once they exist",
+		})
+	}
+
+	var unreadable []string
+	var rotated []string
+	var lastErr error
+	for _, path := range paths {
+		if rotatedFileRegexp.MatchString(path) {
+			rotated = append(rotated, path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			unreadable = append(unreadable, path)
+			lastErr = err
+			continue
+		}
+		f.Close()
+	}
+
+	if len(unreadable) > 0 {
+		diagnoses = append(diagnoses, diagnosis.Diagnosis{
+			Result:    diagnosis.DiagnosisFail,
+			Category:  category,
+			Name:      name,
+			Diagnosis: fmt.Sprintf("%d of the %d files matching `%s` cannot be read: %s", len(unreadable), len(paths), source.Path, strings.Join(unreadable, ", ")),
+			Remediation: "Please grant the user running the Agent read access to the files, e.g. with `chmod o+r` or by adding the " +
+				"`dd-agent` user to the group owning them",
+			RawError: lastErr.Error(),
+		})
+	}
+
+	if len(rotated) > 0 {
+		diagnoses = append(diagnoses, diagnosis.Diagnosis{
+			Result:    diagnosis.DiagnosisWarning,
+			Category:  category,
+			Name:      name,
+			Diagnosis: fmt.Sprintf("`%s` matches rotated or compressed files: %s", source.Path, strings.Join(rotated, ", ")),
+			Remediation: "The Agent detects rotations by itself, collecting rotated files duplicates logs and compressed files are " +
+				"not supported, please narrow the `path` or add the rotated files to `exclude_paths`",
+		})
+	}
+
+	if len(diagnoses) == 0 {
+		diagnoses = append(diagnoses, diagnosis.Diagnosis{
+			Result:    diagnosis.DiagnosisSuccess,
+			Category:  category,
+			Name:      name,
+			Diagnosis: fmt.Sprintf("%d files matching `%s` can be read", len(paths), source.Path),
+		})
+	}
+	return diagnoses
+}
+
+// diagnoseDirectories checks that the directories containing the files matched by the pattern can be listed,
+// the Agent lists them to find new files and detect rotations
+func diagnoseDirectories(name string, pattern string) (diagnosis.Diagnosis, bool) {
+	dirs, err := filepath.Glob(filepath.Dir(pattern))
+	if err != nil || len(dirs) == 0 {
+		// missing directories are reported as files not matching the pattern
+		return diagnosis.Diagnosis{}, true
+	}
+
+	var unlistable []string
+	var lastErr error
+	for _, dir := range dirs {
+		if _, err := os.ReadDir(dir); err != nil {
+			unlistable = append(unlistable, dir)
+			lastErr = err
+		}
+	}
+	if len(unlistable) == 0 {
+		return diagnosis.Diagnosis{}, true
+	}
+
+	return diagnosis.Diagnosis{
+		Result:    diagnosis.DiagnosisFail,
+		Category:  category,
+		Name:      name,
+		Diagnosis: "Directories cannot be listed, new and rotated files are not detected: " + strings.Join(unlistable, ", "),
+		Remediation: "Please grant the user running the Agent read and execute access to the directories, e.g. with `chmod o+rx` " +
+			"or by adding the `dd-agent` user to the group owning them",
+		RawError: lastErr.Error(),
+	}, false
+}
+
+// excludePaths removes the paths matching one of the exclusion patterns, as the file launcher does
+func excludePaths(paths []string, excludePatterns []string) []string {
+	if len(excludePatterns) == 0 {
+		return paths
+	}
+
+	var filtered []string
+	for _, path := range paths {
+		excluded := false
+		for _, pattern := range excludePatterns {
+			if match, err := filepath.Match(pattern, path); err == nil && match {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			filtered = append(filtered, path)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logsConfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+func createFiles(t *testing.T, dir string, names ...string) {
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("log\n"), 0644))
+	}
+}
+
+func fileSource(path string, excludePaths ...string) *logsConfig.LogsConfig {
+	return &logsConfig.LogsConfig{Type: logsConfig.FileType, Path: path, ExcludePaths: excludePaths}
+}
+
+func TestDiagnoseFileSources(t *testing.T) {
+	dir := t.TempDir()
+	createFiles(t, dir, "app.log", "app.log.1", "app.log.2.gz", "other.log")
+
+	tests := []struct {
+		name     string
+		source   *logsConfig.LogsConfig
+		expected []diagnosis.Result
+		contains string
+	}{
+		{
+			name:     "readable files",
+			source:   fileSource(filepath.Join(dir, "*.log")),
+			expected: []diagnosis.Result{diagnosis.DiagnosisSuccess},
+			contains: "2 files matching",
+		},
+		{
+			name:     "no match",
+			source:   fileSource(filepath.Join(dir, "missing", "*.log")),
+			expected: []diagnosis.Result{diagnosis.DiagnosisWarning},
+			contains: "No file matches",
+		},
+		{
+			name:     "rotated files",
+			source:   fileSource(filepath.Join(dir, "app.log*")),
+			expected: []diagnosis.Result{diagnosis.DiagnosisWarning},
+			contains: "app.log.1, " + filepath.Join(dir, "app.log.2.gz"),
+		},
+		{
+			name:     "rotated files excluded",
+			source:   fileSource(filepath.Join(dir, "app.log*"), filepath.Join(dir, "app.log.*")),
+			expected: []diagnosis.Result{diagnosis.DiagnosisSuccess},
+			contains: "1 files matching",
+		},
+		{
+			name:     "invalid pattern",
+			source:   fileSource(filepath.Join(dir, "[*.log")),
+			expected: []diagnosis.Result{diagnosis.DiagnosisFail},
+			contains: "Invalid path pattern",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnoses := diagnoseFileSources([]*logsConfig.LogsConfig{tt.source})
+			var res []diagnosis.Result
+			for _, d := range diagnoses {
+				res = append(res, d.Result)
+			}
+			assert.Equal(t, tt.expected, res)
+			assert.Contains(t, diagnoses[len(diagnoses)-1].Diagnosis, tt.contains)
+		})
+	}
+}
+
+func TestDiagnoseFileSourcesPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("file permissions are not enforced")
+	}
+
+	dir := t.TempDir()
+	createFiles(t, dir, "readable.log", "unreadable.log")
+	require.NoError(t, os.Chmod(filepath.Join(dir, "unreadable.log"), 0200))
+
+	diagnoses := diagnoseFileSources([]*logsConfig.LogsConfig{fileSource(filepath.Join(dir, "*.log"))})
+	require.Len(t, diagnoses, 1)
+	assert.Equal(t, diagnosis.DiagnosisFail, diagnoses[0].Result)
+	assert.Contains(t, diagnoses[0].Diagnosis, "1 of the 2 files")
+	assert.Contains(t, diagnoses[0].Remediation, "dd-agent")
+
+	subdir := filepath.Join(dir, "private")
+	require.NoError(t, os.Mkdir(subdir, 0755))
+	createFiles(t, subdir, "app.log")
+	require.NoError(t, os.Chmod(subdir, 0311))
+	t.Cleanup(func() { os.Chmod(subdir, 0755) })
+
+	diagnoses = diagnoseFileSources([]*logsConfig.LogsConfig{fileSource(filepath.Join(subdir, "app.log"))})
+	require.Len(t, diagnoses, 1)
+	assert.Equal(t, diagnosis.DiagnosisFail, diagnoses[0].Result)
+	assert.Contains(t, diagnoses[0].Diagnosis, "Directories cannot be listed")
+}
+
+func TestDiagnoseFileSourcesIgnoresOtherSources(t *testing.T) {
+	sources := []*logsConfig.LogsConfig{
+		{Type: logsConfig.TCPType, Port: 10514},
+		{Type: logsConfig.DockerType},
+	}
+	assert.Empty(t, diagnoseFileSources(sources))
+}
@@ -30,6 +30,7 @@ import (
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/diagnose/connectivity"
 	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/logs"
 	"github.com/DataDog/datadog-agent/pkg/diagnose/ports"
 )
 
@@ -485,6 +486,7 @@ func buildSuites(diagCfg diagnosis.Config, checkDatadog func() []diagnosis.Diagn
 		RegisterConnectivityAutodiscovery,
 		RegisterConnectivityDatadogEventPlatform,
 		RegisterPortConflict,
+		RegisterLogs,
 	)
 }
 
@@ -527,3 +529,8 @@ func RegisterPortConflict(catalog *diagnosis.Catalog) {
 		catalog.Register("port-conflict", func() []diagnosis.Diagnosis { return ports.DiagnosePortSuite() })
 	}
 }
+
+// RegisterLogs registers the logs diagnose suite.
+func RegisterLogs(catalog *diagnosis.Catalog) {
+	catalog.Register("logs", logs.Diagnose)
+}
@@ -0,0 +1,68 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+// Package blockdevice regroups collecting information about the physical block devices of the host.
+// Only the information exposed by sysfs is collected, SMART health data is not: reading it requires sending
+// commands to the devices.
+package blockdevice
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/gohai/utils"
+)
+
+// Device holds information about a physical block device
+type Device struct {
+	// Name is the kernel name of the device (eg. sda, nvme0n1)
+	Name utils.Value[string] `json:"name"`
+	// Vendor is the vendor of the device
+	Vendor utils.Value[string] `json:"vendor"`
+	// Model is the model of the device
+	Model utils.Value[string] `json:"model"`
+	// Serial is the serial number of the device
+	Serial utils.Value[string] `json:"serial"`
+	// FirmwareVersion is the firmware revision of the device
+	FirmwareVersion utils.Value[string] `json:"firmware_version"`
+	// WWID is the world wide identifier of the device
+	WWID utils.Value[string] `json:"wwid"`
+	// SizeBytes is the size of the device in bytes
+	SizeBytes utils.Value[uint64] `json:"size"`
+	// Rotational is whether the device is a rotational disk
+	Rotational utils.Value[bool] `json:"rotational"`
+	// Removable is whether the device is removable
+	Removable utils.Value[bool] `json:"removable"`
+	// State is the state of the device reported by its driver (eg. running, live, offline), it is not the SMART
+	// health status of the device
+	State utils.Value[string] `json:"state"`
+}
+
+// Info holds the list of physical block devices of the host
+type Info []Device
+
+// CollectInfo returns the list of physical block devices of the host. Virtual devices (loop, ram, device mapper,
+// ...) are ignored.
+func CollectInfo() (Info, error) {
+	return getBlockDevices()
+}
+
+// AsJSON returns an interface which can be marshalled to a JSON and contains the value of non-errored fields.
+func (devices Info) AsJSON() (interface{}, []string, error) {
+	results := make([]interface{}, 0, len(devices))
+	warnings := []string{}
+
+	for _, device := range devices {
+		device := device
+		result, warns, err := utils.AsJSON(&device, false)
+		for _, warn := range warns {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", device.Name.ValueOrDefault(), warn))
+		}
+		if err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, warnings, nil
+}
@@ -0,0 +1,106 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+package blockdevice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/gohai/utils"
+)
+
+// sectorSize is the unit of the size reported by sysfs, regardless of the logical block size of the device
+const sectorSize = 512
+
+// sysfsPath is the mount point of sysfs, overridden in tests
+var sysfsPath = "/sys"
+
+func getBlockDevices() (Info, error) {
+	blockPath := filepath.Join(sysfsPath, "block")
+	entries, err := os.ReadDir(blockPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not list block devices: %w", err)
+	}
+
+	devices := Info{}
+	for _, entry := range entries {
+		devicePath := filepath.Join(blockPath, entry.Name())
+		// virtual devices have no backing device
+		if _, err := os.Stat(filepath.Join(devicePath, "device")); err != nil {
+			continue
+		}
+		devices = append(devices, getDevice(entry.Name(), devicePath))
+	}
+	return devices, nil
+}
+
+func getDevice(name string, devicePath string) Device {
+	device := Device{Name: utils.NewValue(name)}
+
+	utils.ValueStringSetter(&device.Vendor)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "device", "vendor")))
+	utils.ValueStringSetter(&device.Model)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "device", "model")))
+	utils.ValueStringSetter(&device.State)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "device", "state")))
+	utils.ValueParseSetter(&device.SizeBytes, parseSectors)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "size")))
+	utils.ValueParseSetter(&device.Rotational, strconv.ParseBool)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "queue", "rotational")))
+	utils.ValueParseSetter(&device.Removable, strconv.ParseBool)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "removable")))
+
+	// NVMe namespaces expose their identifier directly, SCSI devices through their device
+	utils.ValueStringSetter(&device.WWID)(readFirstAttribute(
+		filepath.Join(devicePath, "wwid"),
+		filepath.Join(devicePath, "device", "wwid"),
+	))
+	// NVMe controllers expose firmware_rev, SCSI devices rev
+	utils.ValueStringSetter(&device.FirmwareVersion)(readFirstAttribute(
+		filepath.Join(devicePath, "device", "firmware_rev"),
+		filepath.Join(devicePath, "device", "rev"),
+	))
+
+	// NVMe controllers expose their serial number, SCSI devices only through the unit serial number VPD page
+	serial, err := utils.ReadSysfsAttribute(filepath.Join(devicePath, "device", "serial"))
+	if err != nil {
+		serial, err = readVPDSerial(filepath.Join(devicePath, "device", "vpd_pg80"))
+	}
+	utils.ValueStringSetter(&device.Serial)(serial, err)
+
+	return device
+}
+
+// readFirstAttribute returns the first sysfs attribute which exists among paths
+func readFirstAttribute(paths ...string) (string, error) {
+	var err error
+	for _, path := range paths {
+		var value string
+		if value, err = utils.ReadSysfsAttribute(path); err == nil {
+			return value, nil
+		}
+	}
+	return "", err
+}
+
+// readVPDSerial reads the unit serial number VPD page (0x80) of a SCSI device, the serial number follows a 4 bytes
+// header whose last byte is the length of the serial number
+func readVPDSerial(path string) (string, error) {
+	page, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if len(page) < 4 || page[1] != 0x80 {
+		return "", errors.New("invalid unit serial number VPD page")
+	}
+	length := int(page[3])
+	if len(page) < 4+length {
+		return "", errors.New("truncated unit serial number VPD page")
+	}
+	return strings.TrimSpace(utils.StringFromBytes(page[4 : 4+length])), nil
+}
+
+func parseSectors(value string) (uint64, error) {
+	sectors, err := strconv.ParseUint(value, 10, 64)
+	return sectors * sectorSize, err
+}
@@ -0,0 +1,95 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+package blockdevice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSysfsFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestGetBlockDevices(t *testing.T) {
+	sysfsPath = t.TempDir()
+	t.Cleanup(func() { sysfsPath = "/sys" })
+
+	writeSysfsFiles(t, sysfsPath, map[string]string{
+		// NVMe namespace
+		"block/nvme0n1/size":                "1000215216\n",
+		"block/nvme0n1/removable":           "0\n",
+		"block/nvme0n1/wwid":                "eui.0025385b71b0a2c3\n",
+		"block/nvme0n1/queue/rotational":    "0\n",
+		"block/nvme0n1/device/model":        "Samsung SSD 970 EVO Plus 500GB          \n",
+		"block/nvme0n1/device/serial":       "S4EVNF0M123456A     \n",
+		"block/nvme0n1/device/firmware_rev": "2B2QEXM7\n",
+		"block/nvme0n1/device/state":        "live\n",
+		// SCSI disk, its serial number is only exposed through the VPD page
+		"block/sda/size":             "7814037168\n",
+		"block/sda/removable":        "0\n",
+		"block/sda/queue/rotational": "1\n",
+		"block/sda/device/vendor":    "ATA     \n",
+		"block/sda/device/model":     "WDC WD40EFRX-68N\n",
+		"block/sda/device/rev":       "0A82\n",
+		"block/sda/device/wwid":      "naa.50014ee2b5d6e2a1\n",
+		"block/sda/device/state":     "running\n",
+		"block/sda/device/vpd_pg80":  "\x00\x80\x00\x11  WD-WCC7K1234567",
+		// virtual device
+		"block/loop0/size":             "0\n",
+		"block/loop0/queue/rotational": "0\n",
+	})
+
+	devices, err := getBlockDevices()
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+
+	nvme, sda := devices[0], devices[1]
+	assert.Equal(t, "nvme0n1", nvme.Name.ValueOrDefault())
+	assert.Equal(t, "Samsung SSD 970 EVO Plus 500GB", nvme.Model.ValueOrDefault())
+	assert.Equal(t, "S4EVNF0M123456A", nvme.Serial.ValueOrDefault())
+	assert.Equal(t, "2B2QEXM7", nvme.FirmwareVersion.ValueOrDefault())
+	assert.Equal(t, "eui.0025385b71b0a2c3", nvme.WWID.ValueOrDefault())
+	assert.EqualValues(t, 1000215216*512, nvme.SizeBytes.ValueOrDefault())
+	assert.False(t, nvme.Rotational.ValueOrDefault())
+	assert.Equal(t, "live", nvme.State.ValueOrDefault())
+	assert.Error(t, nvme.Vendor.Error())
+
+	assert.Equal(t, "sda", sda.Name.ValueOrDefault())
+	assert.Equal(t, "ATA", sda.Vendor.ValueOrDefault())
+	assert.Equal(t, "WD-WCC7K1234567", sda.Serial.ValueOrDefault())
+	assert.Equal(t, "0A82", sda.FirmwareVersion.ValueOrDefault())
+	assert.Equal(t, "naa.50014ee2b5d6e2a1", sda.WWID.ValueOrDefault())
+	assert.True(t, sda.Rotational.ValueOrDefault())
+
+	marshallable, warnings, err := devices.AsJSON()
+	require.NoError(t, err)
+	require.Len(t, marshallable, 2)
+	assert.Equal(t, "true", marshallable.([]interface{})[1].(map[string]interface{})["rotational"])
+	assert.Len(t, warnings, 1)
+}
+
+func TestReadVPDSerialInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeSysfsFiles(t, dir, map[string]string{
+		"short":     "\x00\x80",
+		"truncated": "\x00\x80\x00\x10abc",
+		"page83":    "\x00\x83\x00\x03abc",
+	})
+
+	for _, name := range []string{"short", "truncated", "page83", "missing"} {
+		_, err := readVPDSerial(filepath.Join(dir, name))
+		assert.Error(t, err, name)
+	}
+}
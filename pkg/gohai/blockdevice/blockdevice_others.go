@@ -0,0 +1,13 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+//go:build !linux
+
+package blockdevice
+
+import "github.com/DataDog/datadog-agent/pkg/gohai/utils"
+
+func getBlockDevices() (Info, error) {
+	return nil, utils.ErrNotCollectable
+}
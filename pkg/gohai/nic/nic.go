@@ -0,0 +1,65 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+// Package nic regroups collecting information about the physical network adapters of the host
+package nic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/gohai/utils"
+)
+
+// ErrUnknownSpeed means the speed of the link is unknown, eg. when the link is down
+var ErrUnknownSpeed = errors.New("unknown link speed")
+
+// Adapter holds information about a physical network adapter
+type Adapter struct {
+	// Name is the name of the network interface of the adapter
+	Name utils.Value[string] `json:"name"`
+	// MacAddress is the permanent mac address of the adapter
+	MacAddress utils.Value[string] `json:"macaddress"`
+	// Driver is the name of the kernel driver of the adapter
+	Driver utils.Value[string] `json:"driver"`
+	// DriverVersion is the version of the kernel driver of the adapter
+	DriverVersion utils.Value[string] `json:"driver_version"`
+	// FirmwareVersion is the version of the firmware of the adapter
+	FirmwareVersion utils.Value[string] `json:"firmware_version"`
+	// PCIAddress is the address of the adapter on the PCI bus
+	PCIAddress utils.Value[string] `json:"pci_address"`
+	// SpeedMbps is the negotiated speed of the link in Mb/s
+	SpeedMbps utils.Value[uint64] `json:"speed" unit:"Mb/s"`
+	// Duplex is the duplex mode of the link
+	Duplex utils.Value[string] `json:"duplex"`
+}
+
+// Info holds the list of physical network adapters of the host
+type Info []Adapter
+
+// CollectInfo returns the list of physical network adapters of the host. Virtual interfaces (loopback, bridges,
+// veth, ...) are ignored.
+func CollectInfo() (Info, error) {
+	return getAdapters()
+}
+
+// AsJSON returns an interface which can be marshalled to a JSON and contains the value of non-errored fields.
+func (adapters Info) AsJSON() (interface{}, []string, error) {
+	results := make([]interface{}, 0, len(adapters))
+	warnings := []string{}
+
+	for _, adapter := range adapters {
+		adapter := adapter
+		result, warns, err := utils.AsJSON(&adapter, false)
+		for _, warn := range warns {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", adapter.Name.ValueOrDefault(), warn))
+		}
+		if err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, warnings, nil
+}
@@ -0,0 +1,110 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+package nic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/gohai/utils"
+)
+
+var (
+	// sysfsPath is the mount point of sysfs, overridden in tests
+	sysfsPath = "/sys"
+	// getDriverInfo returns the driver and firmware versions of an adapter, overridden in tests
+	getDriverInfo = ethtoolDriverInfo
+
+	pciAddressRegexp = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{2}:[0-9a-f]{2}\.[0-7]$`)
+)
+
+func getAdapters() (Info, error) {
+	netPath := filepath.Join(sysfsPath, "class", "net")
+	entries, err := os.ReadDir(netPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not list network interfaces: %w", err)
+	}
+
+	adapters := Info{}
+	for _, entry := range entries {
+		ifacePath := filepath.Join(netPath, entry.Name())
+		// virtual interfaces have no backing device
+		if _, err := os.Stat(filepath.Join(ifacePath, "device")); err != nil {
+			continue
+		}
+		adapters = append(adapters, getAdapter(entry.Name(), ifacePath))
+	}
+	return adapters, nil
+}
+
+func getAdapter(name string, ifacePath string) Adapter {
+	adapter := Adapter{Name: utils.NewValue(name)}
+
+	// bonding slaves report the address of the bond, their permanent address is only exposed by the bond
+	macAddress, err := utils.ReadSysfsAttribute(filepath.Join(ifacePath, "bonding_slave", "perm_hwaddr"))
+	if err != nil {
+		macAddress, err = utils.ReadSysfsAttribute(filepath.Join(ifacePath, "address"))
+	}
+	utils.ValueStringSetter(&adapter.MacAddress)(macAddress, err)
+
+	utils.ValueStringSetter(&adapter.Driver)(utils.ReadSysfsLink(filepath.Join(ifacePath, "device", "driver")))
+	utils.ValueStringSetter(&adapter.PCIAddress)(getPCIAddress(filepath.Join(ifacePath, "device")))
+	utils.ValueParseSetter(&adapter.SpeedMbps, parseSpeed)(utils.ReadSysfsAttribute(filepath.Join(ifacePath, "speed")))
+	utils.ValueStringSetter(&adapter.Duplex)(utils.ReadSysfsAttribute(filepath.Join(ifacePath, "duplex")))
+
+	driverVersion, firmwareVersion, err := getDriverInfo(name)
+	utils.ValueStringSetter(&adapter.DriverVersion)(driverVersion, err)
+	utils.ValueStringSetter(&adapter.FirmwareVersion)(firmwareVersion, err)
+
+	return adapter
+}
+
+// getPCIAddress returns the PCI address of a device, which is either the device itself or one of its parents,
+// eg. for virtio devices
+func getPCIAddress(devicePath string) (string, error) {
+	path, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		return "", err
+	}
+	for ; path != "/" && path != "."; path = filepath.Dir(path) {
+		if base := filepath.Base(path); pciAddressRegexp.MatchString(base) {
+			return base, nil
+		}
+	}
+	return "", errors.New("not a PCI device")
+}
+
+func parseSpeed(value string) (uint64, error) {
+	// the speed is -1 when unknown
+	speed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if speed <= 0 {
+		return 0, ErrUnknownSpeed
+	}
+	return uint64(speed), nil
+}
+
+// ethtoolDriverInfo returns the driver and firmware versions of an adapter using the ethtool ioctl
+func ethtoolDriverInfo(name string) (string, string, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM, 0)
+	if err != nil {
+		return "", "", err
+	}
+	defer unix.Close(fd)
+
+	info, err := unix.IoctlGetEthtoolDrvinfo(fd, name)
+	if err != nil {
+		return "", "", fmt.Errorf("could not get driver information: %w", err)
+	}
+	return utils.StringFromBytes(info.Version[:]), utils.StringFromBytes(info.Fw_version[:]), nil
+}
@@ -0,0 +1,86 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+package nic
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAdapters(t *testing.T) {
+	sysfsPath = t.TempDir()
+	getDriverInfo = func(name string) (string, string, error) {
+		if name == "ens3" {
+			return "", "", errors.New("operation not supported")
+		}
+		return "5.15.0", "8.50 0x8000b6e9 1.3082.0", nil
+	}
+	t.Cleanup(func() {
+		sysfsPath = "/sys"
+		getDriverInfo = ethtoolDriverInfo
+	})
+
+	dirs := []string{
+		"bus/pci/drivers/ixgbe",
+		"bus/pci/drivers/virtio_net",
+		"devices/pci0000:00/0000:00:03.0/virtio0",
+		"devices/pci0000:00/0000:3b:00.0",
+	}
+	for _, dir := range dirs {
+		require.NoError(t, os.MkdirAll(filepath.Join(sysfsPath, dir), 0755))
+	}
+	files := map[string]string{
+		"class/net/eno1/address": "3c:ec:ef:12:34:56\n",
+		"class/net/eno1/speed":   "10000\n",
+		"class/net/eno1/duplex":  "full\n",
+		"class/net/ens3/address": "52:54:00:12:34:56\n",
+		"class/net/ens3/speed":   "-1\n",
+		"class/net/lo/address":   "00:00:00:00:00:00\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(sysfsPath, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	links := map[string]string{
+		"class/net/eno1/device":                          "devices/pci0000:00/0000:3b:00.0",
+		"devices/pci0000:00/0000:3b:00.0/driver":         "bus/pci/drivers/ixgbe",
+		"class/net/ens3/device":                          "devices/pci0000:00/0000:00:03.0/virtio0",
+		"devices/pci0000:00/0000:00:03.0/virtio0/driver": "bus/pci/drivers/virtio_net",
+	}
+	for link, target := range links {
+		require.NoError(t, os.Symlink(filepath.Join(sysfsPath, target), filepath.Join(sysfsPath, link)))
+	}
+
+	adapters, err := getAdapters()
+	require.NoError(t, err)
+	require.Len(t, adapters, 2)
+
+	eno1, ens3 := adapters[0], adapters[1]
+	assert.Equal(t, "eno1", eno1.Name.ValueOrDefault())
+	assert.Equal(t, "3c:ec:ef:12:34:56", eno1.MacAddress.ValueOrDefault())
+	assert.Equal(t, "ixgbe", eno1.Driver.ValueOrDefault())
+	assert.Equal(t, "5.15.0", eno1.DriverVersion.ValueOrDefault())
+	assert.Equal(t, "8.50 0x8000b6e9 1.3082.0", eno1.FirmwareVersion.ValueOrDefault())
+	assert.Equal(t, "0000:3b:00.0", eno1.PCIAddress.ValueOrDefault())
+	assert.EqualValues(t, 10000, eno1.SpeedMbps.ValueOrDefault())
+	assert.Equal(t, "full", eno1.Duplex.ValueOrDefault())
+
+	assert.Equal(t, "ens3", ens3.Name.ValueOrDefault())
+	assert.Equal(t, "virtio_net", ens3.Driver.ValueOrDefault())
+	assert.Equal(t, "0000:00:03.0", ens3.PCIAddress.ValueOrDefault())
+	assert.ErrorIs(t, ens3.SpeedMbps.Error(), ErrUnknownSpeed)
+	assert.Error(t, ens3.FirmwareVersion.Error())
+
+	marshallable, _, err := adapters.AsJSON()
+	require.NoError(t, err)
+	require.Len(t, marshallable, 2)
+	assert.Equal(t, "10000Mb/s", marshallable.([]interface{})[0].(map[string]interface{})["speed"])
+}
@@ -0,0 +1,60 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+// Package pci regroups collecting information about the PCI devices of the host
+package pci
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/gohai/utils"
+)
+
+// Device holds information about a PCI device. Identifiers are reported as hexadecimal strings, as shown by lspci -n.
+type Device struct {
+	// Address is the address of the device on the PCI bus (domain:bus:device.function)
+	Address utils.Value[string] `json:"address"`
+	// VendorID is the identifier of the vendor of the device
+	VendorID utils.Value[string] `json:"vendor_id"`
+	// DeviceID is the identifier of the device
+	DeviceID utils.Value[string] `json:"device_id"`
+	// SubsystemVendorID is the identifier of the vendor of the board or card containing the device
+	SubsystemVendorID utils.Value[string] `json:"subsystem_vendor_id"`
+	// SubsystemDeviceID is the identifier of the board or card containing the device
+	SubsystemDeviceID utils.Value[string] `json:"subsystem_device_id"`
+	// Class is the class code of the device (class, subclass and programming interface)
+	Class utils.Value[string] `json:"class"`
+	// Revision is the revision of the device
+	Revision utils.Value[string] `json:"revision"`
+	// Driver is the name of the kernel driver bound to the device
+	Driver utils.Value[string] `json:"driver"`
+}
+
+// Info holds the list of PCI devices of the host
+type Info []Device
+
+// CollectInfo returns the list of PCI devices of the host
+func CollectInfo() (Info, error) {
+	return getDevices()
+}
+
+// AsJSON returns an interface which can be marshalled to a JSON and contains the value of non-errored fields.
+func (devices Info) AsJSON() (interface{}, []string, error) {
+	results := make([]interface{}, 0, len(devices))
+	warnings := []string{}
+
+	for _, device := range devices {
+		device := device
+		result, warns, err := utils.AsJSON(&device, false)
+		for _, warn := range warns {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", device.Address.ValueOrDefault(), warn))
+		}
+		if err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, warnings, nil
+}
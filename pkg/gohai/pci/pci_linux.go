@@ -0,0 +1,60 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+package pci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/gohai/utils"
+)
+
+// sysfsPath is the mount point of sysfs, overridden in tests
+var sysfsPath = "/sys"
+
+func getDevices() (Info, error) {
+	devicesPath := filepath.Join(sysfsPath, "bus", "pci", "devices")
+	entries, err := os.ReadDir(devicesPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not list PCI devices: %w", err)
+	}
+
+	devices := Info{}
+	for _, entry := range entries {
+		devices = append(devices, getDevice(entry.Name(), filepath.Join(devicesPath, entry.Name())))
+	}
+	return devices, nil
+}
+
+func getDevice(address string, devicePath string) Device {
+	device := Device{Address: utils.NewValue(address)}
+
+	utils.ValueParseSetter(&device.VendorID, parseID)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "vendor")))
+	utils.ValueParseSetter(&device.DeviceID, parseID)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "device")))
+	utils.ValueParseSetter(&device.SubsystemVendorID, parseID)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "subsystem_vendor")))
+	utils.ValueParseSetter(&device.SubsystemDeviceID, parseID)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "subsystem_device")))
+	utils.ValueParseSetter(&device.Class, parseID)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "class")))
+	utils.ValueParseSetter(&device.Revision, parseID)(utils.ReadSysfsAttribute(filepath.Join(devicePath, "revision")))
+
+	// the driver is empty when no driver is bound to the device
+	driver, err := utils.ReadSysfsLink(filepath.Join(devicePath, "driver"))
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	utils.ValueStringSetter(&device.Driver)(driver, err)
+
+	return device
+}
+
+// parseID removes the 0x prefix of the identifiers reported by sysfs
+func parseID(value string) (string, error) {
+	if !strings.HasPrefix(value, "0x") {
+		return "", fmt.Errorf("invalid identifier %q", value)
+	}
+	return strings.TrimPrefix(value, "0x"), nil
+}
@@ -0,0 +1,65 @@
+// This file is licensed under the MIT License.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2014-present Datadog, Inc.
+
+package pci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDevices(t *testing.T) {
+	sysfsPath = t.TempDir()
+	t.Cleanup(func() { sysfsPath = "/sys" })
+
+	files := map[string]string{
+		"bus/pci/devices/0000:00:00.0/vendor":           "0x8086\n",
+		"bus/pci/devices/0000:00:00.0/device":           "0x2020\n",
+		"bus/pci/devices/0000:00:00.0/subsystem_vendor": "0x15d9\n",
+		"bus/pci/devices/0000:00:00.0/subsystem_device": "0x095d\n",
+		"bus/pci/devices/0000:00:00.0/class":            "0x060000\n",
+		"bus/pci/devices/0000:00:00.0/revision":         "0x04\n",
+		"bus/pci/devices/0000:3b:00.0/vendor":           "0x8086\n",
+		"bus/pci/devices/0000:3b:00.0/device":           "0x10fb\n",
+		"bus/pci/devices/0000:3b:00.0/class":            "0x020000\n",
+		"bus/pci/devices/0000:3b:00.0/revision":         "invalid\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(sysfsPath, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(sysfsPath, "bus/pci/drivers/ixgbe"), 0755))
+	require.NoError(t, os.Symlink(filepath.Join(sysfsPath, "bus/pci/drivers/ixgbe"), filepath.Join(sysfsPath, "bus/pci/devices/0000:3b:00.0/driver")))
+
+	devices, err := getDevices()
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+
+	bridge, nic := devices[0], devices[1]
+	assert.Equal(t, "0000:00:00.0", bridge.Address.ValueOrDefault())
+	assert.Equal(t, "8086", bridge.VendorID.ValueOrDefault())
+	assert.Equal(t, "2020", bridge.DeviceID.ValueOrDefault())
+	assert.Equal(t, "15d9", bridge.SubsystemVendorID.ValueOrDefault())
+	assert.Equal(t, "095d", bridge.SubsystemDeviceID.ValueOrDefault())
+	assert.Equal(t, "060000", bridge.Class.ValueOrDefault())
+	assert.Equal(t, "04", bridge.Revision.ValueOrDefault())
+	assert.Equal(t, "", bridge.Driver.ValueOrDefault())
+	assert.NoError(t, bridge.Driver.Error())
+
+	assert.Equal(t, "10fb", nic.DeviceID.ValueOrDefault())
+	assert.Equal(t, "ixgbe", nic.Driver.ValueOrDefault())
+	assert.Error(t, nic.Revision.Error())
+	assert.Error(t, nic.SubsystemVendorID.Error())
+
+	marshallable, warnings, err := devices.AsJSON()
+	require.NoError(t, err)
+	require.Len(t, marshallable, 2)
+	assert.NotContains(t, marshallable.([]interface{})[1], "revision")
+	assert.Len(t, warnings, 3)
+}
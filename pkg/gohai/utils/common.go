@@ -35,7 +35,7 @@ func canBeRendered(ty reflect.Kind) bool {
 		reflect.Int: {}, reflect.Int8: {}, reflect.Int16: {}, reflect.Int32: {},
 		reflect.Int64: {}, reflect.Uint: {}, reflect.Uint8: {}, reflect.Uint16: {},
 		reflect.Uint32: {}, reflect.Uint64: {}, reflect.Uintptr: {}, reflect.Float32: {},
-		reflect.Float64: {}, reflect.String: {},
+		reflect.Float64: {}, reflect.String: {}, reflect.Bool: {},
 	}
 
 	_, ok := renderables[ty]
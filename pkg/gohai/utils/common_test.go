@@ -29,6 +29,7 @@ func TestAsJSONTypes(t *testing.T) {
 		SomeFloat32 Value[float32] `json:"my_float32"`
 		SomeFloat64 Value[float64] `json:"my_float64"`
 		SomeString  Value[string]  `json:"my_string"`
+		SomeBool    Value[bool]    `json:"my_bool"`
 	}{
 		SomeInt:     NewValue(1),
 		SomeInt8:    NewValue[int8](2),
@@ -43,6 +44,7 @@ func TestAsJSONTypes(t *testing.T) {
 		SomeFloat32: NewValue[float32](32.),
 		SomeFloat64: NewValue(64.),
 		SomeString:  NewValue("mystr"),
+		SomeBool:    NewValue(true),
 	}
 
 	marshallable, warns, err := AsJSON(info, false)
@@ -66,7 +68,8 @@ func TestAsJSONTypes(t *testing.T) {
 		"my_uint64": "10",
 		"my_float32": "%v",
 		"my_float64": "%v",
-		"my_string": "mystr"
+		"my_string": "mystr",
+		"my_bool": "true"
 	}`, float32(32.), 64.)
 	require.JSONEq(t, expected, string(marshalled))
 }
@@ -79,10 +79,24 @@ type JMXFetch struct {
 	IPCHost            string
 	Output             func(...interface{})
 	cmd                *exec.Cmd
+	startTime          time.Time
 	managed            bool
 	shutdown           chan struct{}
 	stopped            chan struct{}
 	logger             jmxlogger.Component
+
+	// ProcessName is the name of the process when JMX integrations run in several JMXFetch processes
+	ProcessName string
+	// ConfDirectory and ConfFiles are the directory and the names of the config files loaded by the process, see
+	// SetConfigFiles. The process pulls its configs from the Agent API when they are empty.
+	ConfDirectory string
+	ConfFiles     []string
+	// StatusFile is the file in which the process writes its status when it loads its configs from files
+	StatusFile string
+	// RestartBackoffInitial and RestartBackoffMax bound the delay before restarting the process when it exits,
+	// they default to jmx_restart_backoff_initial and jmx_restart_backoff_max
+	RestartBackoffInitial time.Duration
+	RestartBackoffMax     time.Duration
 }
 
 // JMXReporter supports different way of reporting the data it has fetched.
@@ -111,6 +125,9 @@ type checkInitCfg struct {
 	ToolsJarPath   string   `yaml:"tools_jar_path,omitempty"`
 	JavaBinPath    string   `yaml:"java_bin_path,omitempty"`
 	JavaOptions    string   `yaml:"java_options,omitempty"`
+	// restart backoff in seconds
+	RestartBackoffInitial int `yaml:"restart_backoff_initial,omitempty"`
+	RestartBackoffMax     int `yaml:"restart_backoff_max,omitempty"`
 }
 
 func NewJMXFetch(logger jmxlogger.Component) *JMXFetch {
@@ -123,6 +140,7 @@ func NewJMXFetch(logger jmxlogger.Component) *JMXFetch {
 func (j *JMXFetch) Monitor() {
 	limiter := newRestartLimiter(pkgconfigsetup.Datadog().GetInt("jmx_max_restarts"), float64(pkgconfigsetup.Datadog().GetInt("jmx_restart_interval")))
 	ticker := time.NewTicker(500 * time.Millisecond)
+	var restartDelay time.Duration
 
 	defer ticker.Stop()
 	defer close(j.stopped)
@@ -139,24 +157,74 @@ func (j *JMXFetch) Monitor() {
 		if !limiter.canRestart(time.Now()) {
 			msg := fmt.Sprintf("Too many JMXFetch restarts (%v) in time interval (%vs) - giving up", limiter.maxRestarts, limiter.interval)
 			log.Errorf("%s", msg)
-			s := jmxStatus.StartupError{LastError: msg, Timestamp: time.Now().Unix()}
-			jmxStatus.SetStartupError(s)
+			j.setStartupError(msg)
 			return
 		}
 
+		restartDelay = nextRestartDelay(restartDelay, j.RestartBackoffInitial, j.RestartBackoffMax, time.Since(j.startTime))
+		if restartDelay > 0 {
+			log.Warnf("JMXFetch process %sexited, restarting it in %s.", j.processLogPrefix(), restartDelay)
+		}
+
 		select {
 		case <-j.shutdown:
 			return
-		default:
+		case <-time.After(restartDelay):
 			// restart
-			log.Warnf("JMXFetch process had to be restarted.")
-			j.Start(false) //nolint:errcheck
+			log.Warnf("JMXFetch process %shad to be restarted.", j.processLogPrefix())
+			if j.ProcessName != "" {
+				jmxStatus.IncrementProcessRestarts(j.ProcessName)
+			}
+			if err := j.Start(false); err != nil {
+				j.setStartupError(err.Error())
+			}
 		}
 	}
 
 	<-j.shutdown
 }
 
+// reportStatus reports the status written by the process in its status file until it is stopped
+func (j *JMXFetch) reportStatus() {
+	period := time.Duration(pkgconfigsetup.Datadog().GetInt("jmx_check_period")) * time.Millisecond
+	if period <= 0 {
+		period = 15 * time.Second
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.shutdown:
+			return
+		case <-ticker.C:
+			status, err := readStatusFile(j.StatusFile)
+			if err != nil {
+				log.Debugf("Unable to read the status of JMXFetch process %q: %s", j.ProcessName, err)
+				continue
+			}
+			jmxStatus.SetProcessStatus(j.ProcessName, status)
+		}
+	}
+}
+
+// setStartupError reports a startup error in the status of the process
+func (j *JMXFetch) setStartupError(msg string) {
+	s := jmxStatus.StartupError{LastError: msg, Timestamp: time.Now().Unix()}
+	if j.ProcessName != "" {
+		jmxStatus.SetProcessStartupError(j.ProcessName, s)
+		return
+	}
+	jmxStatus.SetStartupError(s)
+}
+
+func (j *JMXFetch) processLogPrefix() string {
+	if j.ProcessName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%q ", j.ProcessName)
+}
+
 func (j *JMXFetch) setDefaults() {
 	if j.JavaBinPath == "" {
 		j.JavaBinPath = defaultJavaBinPath
@@ -176,6 +244,12 @@ func (j *JMXFetch) setDefaults() {
 	if j.Output == nil {
 		j.Output = j.logger.JMXInfo
 	}
+	if j.RestartBackoffInitial == 0 {
+		j.RestartBackoffInitial = time.Duration(pkgconfigsetup.Datadog().GetInt("jmx_restart_backoff_initial")) * time.Second
+	}
+	if j.RestartBackoffMax == 0 {
+		j.RestartBackoffMax = time.Duration(pkgconfigsetup.Datadog().GetInt("jmx_restart_backoff_max")) * time.Second
+	}
 	if j.JavaOptions == "" {
 		j.JavaOptions = jmxAllowAttachSelf
 	} else if !strings.Contains(j.JavaOptions, strings.TrimSpace(jmxAllowAttachSelf)) {
@@ -290,12 +364,22 @@ func (j *JMXFetch) Start(manage bool) error {
 		ipcPort = j.IPCPort
 	}
 
-	// checks are now enabled via IPC on JMXFetch
+	subprocessArgs = append(subprocessArgs, "-classpath", classpath, jmxMainClass)
+	if j.ConfDirectory != "" {
+		// the process loads its configs from files and writes its status to a file, it doesn't use IPC
+		subprocessArgs = append(subprocessArgs, "--conf_directory", j.ConfDirectory, "--check")
+		subprocessArgs = append(subprocessArgs, j.ConfFiles...)
+		if j.StatusFile != "" {
+			subprocessArgs = append(subprocessArgs, "--status_location", j.StatusFile)
+		}
+	} else {
+		// checks are now enabled via IPC on JMXFetch
+		subprocessArgs = append(subprocessArgs,
+			"--ipc_host", ipcHost,
+			"--ipc_port", fmt.Sprintf("%v", ipcPort),
+		)
+	}
 	subprocessArgs = append(subprocessArgs,
-		"-classpath", classpath,
-		jmxMainClass,
-		"--ipc_host", ipcHost,
-		"--ipc_port", fmt.Sprintf("%v", ipcPort),
 		"--check_period", fmt.Sprintf("%v", pkgconfigsetup.Datadog().GetInt("jmx_check_period")), // Period of the main loop of jmxfetch in ms
 		"--thread_pool_size", fmt.Sprintf("%v", pkgconfigsetup.Datadog().GetInt("jmx_thread_pool_size")), // Size for the JMXFetch thread pool
 		"--collection_timeout", fmt.Sprintf("%v", pkgconfigsetup.Datadog().GetInt("jmx_collection_timeout")), // Timeout for metric collection in seconds
@@ -376,6 +460,7 @@ func (j *JMXFetch) Start(manage bool) error {
 	log.Debugf("Args: %v", subprocessArgs)
 
 	err = j.cmd.Start()
+	j.startTime = time.Now()
 
 	// start synchronization channels
 	if err == nil && manage {
@@ -384,6 +469,9 @@ func (j *JMXFetch) Start(manage bool) error {
 		j.stopped = make(chan struct{})
 
 		go j.Monitor()
+		if j.ProcessName != "" && j.StatusFile != "" {
+			go j.reportStatus()
+		}
 	}
 
 	return err
@@ -454,6 +542,13 @@ func (j *JMXFetch) ConfigureFromInitConfig(initConfig integration.Data) error {
 		}
 	}
 
+	if j.RestartBackoffInitial == 0 {
+		j.RestartBackoffInitial = time.Duration(initConf.RestartBackoffInitial) * time.Second
+	}
+	if j.RestartBackoffMax == 0 {
+		j.RestartBackoffMax = time.Duration(initConf.RestartBackoffMax) * time.Second
+	}
+
 	return nil
 }
 
@@ -0,0 +1,199 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build jmx
+
+package jmxfetch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	jmxStatus "github.com/DataDog/datadog-agent/pkg/status/jmx"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// processModeSingle runs all the JMX integrations in a single JMXFetch process
+	processModeSingle = "single"
+	// processModePerIntegration runs a JMXFetch process per integration, or per process group
+	processModePerIntegration = "per_integration"
+
+	// processStatusFile is the file in which a JMXFetch process running its own configs writes its status
+	processStatusFile = "status.yaml"
+)
+
+// processNameInvalidChars matches the characters replaced in process names, which are used as directory names
+var processNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// processInstanceCfg lists the instance options selecting the JMXFetch process running the instance
+type processInstanceCfg struct {
+	ProcessGroup string `yaml:"jmx_process_group,omitempty"`
+}
+
+// processName returns the name of the JMXFetch process running an instance of a check. It is empty when all
+// the JMX integrations run in a single process.
+func processName(checkName string, instance integration.Data) string {
+	mode := pkgconfigsetup.Datadog().GetString("jmx_process_mode")
+	switch mode {
+	case processModePerIntegration:
+	case processModeSingle, "":
+		return ""
+	default:
+		log.Warnf("Unknown jmx_process_mode %q, running JMX integrations in a single process", mode)
+		return ""
+	}
+
+	name := checkName
+	var instanceConf processInstanceCfg
+	if err := yaml.Unmarshal(instance, &instanceConf); err == nil && instanceConf.ProcessGroup != "" {
+		name = instanceConf.ProcessGroup
+	}
+	// the name is used as the name of the directory holding the configs of the process
+	return processNameInvalidChars.ReplaceAllString(strings.TrimSpace(name), "_")
+}
+
+// GroupConfigsByProcess splits the configs by the JMXFetch process running them, see processName. Configs are
+// expected to have a single instance, the first one is used to select the process.
+func GroupConfigsByProcess(configs []integration.Config) map[string][]integration.Config {
+	groups := map[string][]integration.Config{}
+	for _, c := range configs {
+		var instance integration.Data
+		if len(c.Instances) > 0 {
+			instance = c.Instances[0]
+		}
+		name := processName(c.Name, instance)
+		groups[name] = append(groups[name], c)
+	}
+	return groups
+}
+
+// processDir returns the directory holding the configs and the status of a JMXFetch process, see
+// SetConfigFiles.
+func processDir(process string) string {
+	return filepath.Join(pkgconfigsetup.Datadog().GetString("run_path"), "jmxfetch", process)
+}
+
+// SetConfigFiles writes the configs run by the JMXFetch process in dir, one file per check, for the process to
+// load them from there instead of pulling them from the Agent API. The process then writes its status in dir,
+// see readStatusFile. Configs of the same check must share the same init_config, as JMXFetch reads a single
+// init_config per check.
+func (j *JMXFetch) SetConfigFiles(dir string, configs []integration.Config) error {
+	files, err := configFiles(configs)
+	if err != nil {
+		return err
+	}
+
+	confDir := filepath.Join(dir, "conf.d")
+	if err := os.RemoveAll(confDir); err != nil {
+		return err
+	}
+	// the configs can hold credentials
+	if err := os.MkdirAll(confDir, 0700); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(confDir, name), content, 0600); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	j.ConfDirectory = confDir
+	j.ConfFiles = names
+	j.StatusFile = filepath.Join(dir, processStatusFile)
+	return nil
+}
+
+// configFiles returns the content of the config files of the given configs by file name. JMXFetch names the
+// checks after their file, the instances of the configs of a check are merged in a single file.
+func configFiles(configs []integration.Config) (map[string][]byte, error) {
+	type checkFile struct {
+		InitConfig interface{}   `yaml:"init_config"`
+		Instances  []interface{} `yaml:"instances"`
+	}
+	checks := map[string]*checkFile{}
+	initConfigs := map[string]integration.Data{}
+	for _, c := range configs {
+		check, ok := checks[c.Name]
+		if !ok {
+			check = &checkFile{Instances: []interface{}{}}
+			if err := yaml.Unmarshal(c.InitConfig, &check.InitConfig); err != nil {
+				return nil, fmt.Errorf("unable to parse the init_config of %s: %w", c.Name, err)
+			}
+			checks[c.Name] = check
+			initConfigs[c.Name] = c.InitConfig
+		} else if !bytes.Equal(initConfigs[c.Name], c.InitConfig) {
+			return nil, fmt.Errorf("the configs of %s run in the same JMXFetch process have different init_config, set jmx_process_group on their instances to run them in separate processes", c.Name)
+		}
+		for _, instance := range c.Instances {
+			var i interface{}
+			if err := yaml.Unmarshal(instance, &i); err != nil {
+				return nil, fmt.Errorf("unable to parse an instance of %s: %w", c.Name, err)
+			}
+			check.Instances = append(check.Instances, i)
+		}
+	}
+
+	files := make(map[string][]byte, len(checks))
+	for name, check := range checks {
+		content, err := yaml.Marshal(check)
+		if err != nil {
+			return nil, err
+		}
+		files[name+".yaml"] = content
+	}
+	return files, nil
+}
+
+// readStatusFile reads the status written by a JMXFetch process in its status file, which holds the same
+// fields as the status reported to the Agent API, in YAML.
+func readStatusFile(path string) (jmxStatus.Status, error) {
+	var status jmxStatus.Status
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return status, err
+	}
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return status, err
+	}
+	j, err := json.Marshal(util.GetJSONSerializableMap(raw))
+	if err != nil {
+		return status, err
+	}
+	err = json.Unmarshal(j, &status)
+	return status, err
+}
+
+// nextRestartDelay returns the delay to wait before restarting a JMXFetch process that exited after running for
+// uptime. The delay doubles after each restart up to max, and is reset once a process ran for longer than max.
+func nextRestartDelay(current, initial, max, uptime time.Duration) time.Duration {
+	if initial <= 0 {
+		return 0
+	}
+	if current <= 0 || uptime > max {
+		return initial
+	}
+	current *= 2
+	if current > max {
+		current = max
+	}
+	return current
+}
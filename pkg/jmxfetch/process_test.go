@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build jmx
+
+package jmxfetch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	jmxStatus "github.com/DataDog/datadog-agent/pkg/status/jmx"
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+)
+
+func newTestConfig(name string, instance string) integration.Config {
+	return integration.Config{
+		Name:      name,
+		Instances: []integration.Data{integration.Data(instance)},
+	}
+}
+
+func TestProcessName(t *testing.T) {
+	configmock.New(t)
+
+	instance := integration.Data("host: localhost\njmx_process_group: kafka brokers\n")
+	assert.Equal(t, "", processName("kafka", instance))
+
+	pkgconfigsetup.Datadog().SetWithoutSource("jmx_process_mode", "per_integration")
+	assert.Equal(t, "kafka_brokers", processName("kafka", instance))
+	assert.Equal(t, "tomcat", processName("tomcat", integration.Data("host: localhost\n")))
+	assert.Equal(t, "tomcat", processName("tomcat", nil))
+	// the name is used as a directory name
+	assert.Equal(t, "_etc", processName("tomcat", integration.Data("jmx_process_group: ../etc\n")))
+
+	pkgconfigsetup.Datadog().SetWithoutSource("jmx_process_mode", "unknown")
+	assert.Equal(t, "", processName("tomcat", integration.Data("host: localhost\n")))
+}
+
+func TestGroupConfigsByProcess(t *testing.T) {
+	configmock.New(t)
+	pkgconfigsetup.Datadog().SetWithoutSource("jmx_process_mode", "per_integration")
+
+	groups := GroupConfigsByProcess([]integration.Config{
+		newTestConfig("kafka", "host: a\n"),
+		newTestConfig("kafka", "host: b\n"),
+		newTestConfig("tomcat", "host: a\njmx_process_group: web\n"),
+		newTestConfig("jboss", "host: a\njmx_process_group: web\n"),
+	})
+	require.Len(t, groups, 2)
+	assert.Len(t, groups["kafka"], 2)
+	assert.Len(t, groups["web"], 2)
+}
+
+func TestSetConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJMXFetch(nil)
+	tomcat := newTestConfig("tomcat", "host: a\nport: 9012\n")
+	tomcat.InitConfig = integration.Data("is_jmx: true\n")
+	tomcatB := newTestConfig("tomcat", "host: b\nport: 9012\n")
+	tomcatB.InitConfig = tomcat.InitConfig
+
+	require.NoError(t, j.SetConfigFiles(dir, []integration.Config{tomcat, tomcatB, newTestConfig("kafka", "host: a\n")}))
+	assert.Equal(t, filepath.Join(dir, "conf.d"), j.ConfDirectory)
+	assert.Equal(t, []string{"kafka.yaml", "tomcat.yaml"}, j.ConfFiles)
+	assert.Equal(t, filepath.Join(dir, "status.yaml"), j.StatusFile)
+
+	// the instances of a check are merged in the file named after the check
+	content, err := os.ReadFile(filepath.Join(j.ConfDirectory, "tomcat.yaml"))
+	require.NoError(t, err)
+	var file struct {
+		InitConfig map[string]interface{}   `yaml:"init_config"`
+		Instances  []map[string]interface{} `yaml:"instances"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &file))
+	assert.Equal(t, map[string]interface{}{"is_jmx": true}, file.InitConfig)
+	require.Len(t, file.Instances, 2)
+	assert.Equal(t, "a", file.Instances[0]["host"])
+	assert.Equal(t, "b", file.Instances[1]["host"])
+
+	// the files of the configs which are not run anymore are removed
+	require.NoError(t, j.SetConfigFiles(dir, []integration.Config{tomcat}))
+	assert.Equal(t, []string{"tomcat.yaml"}, j.ConfFiles)
+	assert.NoFileExists(t, filepath.Join(j.ConfDirectory, "kafka.yaml"))
+
+	// JMXFetch reads a single init_config per check
+	tomcatB.InitConfig = integration.Data("is_jmx: true\njava_options: -Xmx1g\n")
+	assert.Error(t, j.SetConfigFiles(dir, []integration.Config{tomcat, tomcatB}))
+}
+
+func TestReadStatusFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`timestamp: 1700000000000
+checks:
+  initialized_checks:
+    tomcat:
+    - instance_name: tomcat-a
+      metric_count: 12
+  failed_checks: {}
+errors: 0
+`), 0600))
+
+	status, err := readStatusFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000000), status.Timestamp)
+	assert.Contains(t, status.ChecksStatus.InitializedChecks, "tomcat")
+	assert.Empty(t, status.ChecksStatus.FailedChecks)
+
+	_, err = readStatusFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestNextRestartDelay(t *testing.T) {
+	// immediate restarts
+	assert.Equal(t, time.Duration(0), nextRestartDelay(0, 0, time.Minute, time.Second))
+
+	delay := nextRestartDelay(0, time.Second, 5*time.Second, time.Second)
+	assert.Equal(t, time.Second, delay)
+	for _, expected := range []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		delay = nextRestartDelay(delay, time.Second, 5*time.Second, time.Second)
+		assert.Equal(t, expected, delay)
+	}
+
+	// the delay is reset once the process ran for longer than the maximum delay
+	assert.Equal(t, time.Second, nextRestartDelay(delay, time.Second, 5*time.Second, time.Minute))
+}
+
+func TestScheduledConfigsByProcess(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("jmx_process_mode", "per_integration")
+	cfg.SetWithoutSource("run_path", t.TempDir())
+
+	s := &jmxState{
+		configs:   cache.NewBasicCache(),
+		runners:   map[string]*runner{},
+		processes: map[string]string{},
+		lock:      &sync.Mutex{},
+	}
+	s.addScheduledConfig(newTestConfig("kafka", "host: a\n"))
+	s.addScheduledConfig(newTestConfig("tomcat", "host: a\n"))
+	s.addScheduledConfig(newTestConfig("tomcat", "host: b\n"))
+
+	assert.Len(t, s.getScheduledConfigs(), 3)
+	assert.Len(t, s.processConfigs("kafka"), 1)
+	assert.Len(t, s.processConfigs("tomcat"), 2)
+	// the configs run by named processes are not pulled from the Agent API
+	assert.Empty(t, s.getSingleProcessScheduledConfigs())
+
+	// the config files are updated when a config is removed, and the process is stopped and its status
+	// removed once it doesn't run any config
+	r := s.getRunner("tomcat")
+	jmxStatus.IncrementProcessRestarts("tomcat")
+	t.Cleanup(func() { jmxStatus.RemoveProcess("tomcat") })
+
+	ids := make([]string, 0, 2)
+	for id := range s.processConfigs("tomcat") {
+		ids = append(ids, id)
+	}
+	s.unscheduleConfig(ids[0])
+	assert.Contains(t, s.runners, "tomcat")
+	assert.Contains(t, jmxStatus.GetProcessesStatus(), "tomcat")
+	assert.Equal(t, []string{"tomcat.yaml"}, r.jmxfetch.ConfFiles)
+	assert.FileExists(t, filepath.Join(processDir("tomcat"), "conf.d", "tomcat.yaml"))
+
+	s.unscheduleConfig(ids[1])
+	assert.NotContains(t, s.runners, "tomcat")
+	assert.NotContains(t, jmxStatus.GetProcessesStatus(), "tomcat")
+	assert.NoDirExists(t, processDir("tomcat"))
+	assert.Len(t, s.processConfigs("kafka"), 1)
+}
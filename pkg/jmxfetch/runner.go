@@ -13,8 +13,8 @@ import (
 	"github.com/DataDog/datadog-agent/comp/agent/jmxlogger"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	dogstatsdServer "github.com/DataDog/datadog-agent/comp/dogstatsd/server"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
-	jmxStatus "github.com/DataDog/datadog-agent/pkg/status/jmx"
 )
 
 type runner struct {
@@ -33,8 +33,7 @@ func (r *runner) startRunner() error {
 	lifecycleMgmt := true
 	err := r.jmxfetch.Start(lifecycleMgmt)
 	if err != nil {
-		s := jmxStatus.StartupError{LastError: err.Error(), Timestamp: time.Now().Unix()}
-		jmxStatus.SetStartupError(s)
+		r.jmxfetch.setStartupError(err.Error())
 		return err
 	}
 	r.started = true
@@ -48,6 +47,15 @@ func (r *runner) configureRunner(instance, initConfig integration.Data) error {
 	return r.jmxfetch.ConfigureFromInitConfig(initConfig)
 }
 
+// restartRunner restarts the JMXFetch process, for it to load its updated config files
+func (r *runner) restartRunner() error {
+	if err := r.stopRunner(); err != nil {
+		return err
+	}
+	r.started = false
+	return check.Retry(5*time.Second, 3, r.startRunner, "jmxfetch")
+}
+
 func (r *runner) stopRunner() error {
 	if r.jmxfetch != nil && r.started {
 		return r.jmxfetch.Stop()
@@ -54,16 +54,12 @@ func (s *JmxScheduler) Schedule(configs []integration.Config) {
 			}
 
 			id := fmt.Sprintf("%v_%x", c.Name, c.IntDigest())
+			process := processName(c.Name, instance)
 			log.Debugf("Scheduling jmxfetch config: %v: %q", id, c.String())
 
-			if err := state.runner.configureRunner(instance, config.InitConfig); err != nil {
-				log.Errorf("Could not configure jmxfetch for %v: %v", id, err)
-				continue
-			}
-
 			s.configs[digest] = append(s.configs[digest], id)
 
-			if err := state.scheduleConfig(id, c); err != nil {
+			if err := state.scheduleConfig(id, c, process); err != nil {
 				log.Errorf("Could not schedule jmxfetch config: %v: %v", id, err)
 			}
 		}
@@ -9,6 +9,8 @@ package jmxfetch
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	dogstatsdServer "github.com/DataDog/datadog-agent/comp/dogstatsd/server"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	jmxStatus "github.com/DataDog/datadog-agent/pkg/status/jmx"
 	"github.com/DataDog/datadog-agent/pkg/util/cache"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -23,38 +26,151 @@ import (
 type jmxState struct {
 	configs     *cache.BasicCache
 	runnerError chan struct{}
-	runner      *runner
-	lock        *sync.Mutex
+	// runners holds the runner of each JMXFetch process by process name, the runner of the single process
+	// running all the JMX integrations being the one with an empty name
+	runners map[string]*runner
+	// processes holds the name of the process running each scheduled config
+	processes map[string]string
+	dsd       dogstatsdServer.Component
+	logger    jmxlogger.Component
+	lock      *sync.Mutex
 }
 
 var state = jmxState{
 	configs:     cache.NewBasicCache(),
 	runnerError: make(chan struct{}),
-	runner:      &runner{},
+	runners:     map[string]*runner{},
+	processes:   map[string]string{},
 	lock:        &sync.Mutex{},
 }
 
-func (s *jmxState) scheduleConfig(id string, config integration.Config) error {
+// getRunner returns the runner of a JMXFetch process, creating it if needed. It must be called with the lock held.
+func (s *jmxState) getRunner(process string) *runner {
+	r, ok := s.runners[process]
+	if !ok {
+		r = &runner{}
+		r.initRunner(s.dsd, s.logger)
+		r.jmxfetch.ProcessName = process
+		s.runners[process] = r
+	}
+	return r
+}
+
+// scheduleConfig configures the JMXFetch process running the config and starts it if needed. The single
+// process pulls the config from the Agent API, the other processes load it from their config files and are
+// restarted to do so.
+func (s *jmxState) scheduleConfig(id string, config integration.Config, process string) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	if !s.runner.started {
-		err := check.Retry(5*time.Second, 3, s.runner.startRunner, "jmxfetch")
+
+	r := s.getRunner(process)
+	for _, instance := range config.Instances {
+		if err := r.configureRunner(instance, config.InitConfig); err != nil {
+			return fmt.Errorf("could not configure jmxfetch: %w", err)
+		}
+	}
+	if process != "" {
+		configs := s.processConfigs(process)
+		configs[id] = config
+		if err := r.jmxfetch.SetConfigFiles(processDir(process), sortedConfigs(configs)); err != nil {
+			return fmt.Errorf("could not write the configs of jmxfetch process %q: %w", process, err)
+		}
+		if r.started {
+			if err := r.restartRunner(); err != nil {
+				return err
+			}
+		}
+	}
+	if !r.started {
+		err := check.Retry(5*time.Second, 3, r.startRunner, "jmxfetch")
 		if err != nil {
 			return err
 		}
 	}
+	s.processes[id] = process
 	s.configs.Add(id, config)
 	return nil
 }
 
+// unscheduleConfig removes a config, stopping its JMXFetch process when the process doesn't run any other config
+// and restarting it otherwise. The single process running all the JMX integrations is never stopped.
 func (s *jmxState) unscheduleConfig(id string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.configs.Remove(id)
+
+	process, ok := s.processes[id]
+	delete(s.processes, id)
+	if !ok || process == "" {
+		return
+	}
+	r, ok := s.runners[process]
+	if !ok {
+		return
+	}
+
+	if configs := s.processConfigs(process); len(configs) > 0 {
+		if err := r.jmxfetch.SetConfigFiles(processDir(process), sortedConfigs(configs)); err != nil {
+			log.Errorf("could not write the configs of jmxfetch process %q: %s", process, err)
+			return
+		}
+		if r.started {
+			if err := r.restartRunner(); err != nil {
+				log.Errorf("failure to restart jmxfetch process %q: %s", process, err)
+			}
+		}
+		return
+	}
+
+	log.Infof("Stopping JMXFetch process %q, it doesn't run any integration anymore", process)
+	if err := r.stopRunner(); err != nil {
+		log.Errorf("failure to kill jmxfetch process %q: %s", process, err)
+	}
+	delete(s.runners, process)
+	if err := os.RemoveAll(processDir(process)); err != nil {
+		log.Warnf("could not remove the configs of jmxfetch process %q: %s", process, err)
+	}
+	jmxStatus.RemoveProcess(process)
+}
+
+// processConfigs returns the scheduled configs run by a JMXFetch process by id. It must be called with the lock
+// held.
+func (s *jmxState) processConfigs(process string) map[string]integration.Config {
+	configs := map[string]integration.Config{}
+	for id, config := range s.configs.Items() {
+		if s.processes[id] == process {
+			configs[id] = config.(integration.Config)
+		}
+	}
+	return configs
+}
+
+// sortedConfigs returns the configs sorted by id, for the config files of a process to be stable.
+func sortedConfigs(configs map[string]integration.Config) []integration.Config {
+	ids := make([]string, 0, len(configs))
+	for id := range configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	sorted := make([]integration.Config, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, configs[id])
+	}
+	return sorted
 }
 
 func (s *jmxState) addScheduledConfig(c integration.Config) {
-	s.configs.Add(fmt.Sprintf("%v_%v", c.Name, c.Digest()), c)
+	var instance integration.Data
+	if len(c.Instances) > 0 {
+		instance = c.Instances[0]
+	}
+	id := fmt.Sprintf("%v_%v", c.Name, c.Digest())
+
+	s.lock.Lock()
+	s.processes[id] = processName(c.Name, instance)
+	s.lock.Unlock()
+	s.configs.Add(id, c)
 }
 
 func (s *jmxState) getScheduledConfigs() map[string]integration.Config {
@@ -65,6 +181,15 @@ func (s *jmxState) getScheduledConfigs() map[string]integration.Config {
 	return configs
 }
 
+// getSingleProcessScheduledConfigs returns the scheduled configs run by the single JMXFetch process, which are
+// the ones it pulls from the Agent API.
+func (s *jmxState) getSingleProcessScheduledConfigs() map[string]integration.Config {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.processConfigs("")
+}
+
 func (s *jmxState) getScheduledConfigsModificationTimestamp() int64 {
 	return s.configs.GetModified()
 }
@@ -80,21 +205,43 @@ func GetScheduledConfigs() map[string]integration.Config {
 	return state.getScheduledConfigs()
 }
 
+// GetSingleProcessScheduledConfigs returns the list of scheduled jmx configs pulled by JMXFetch from the Agent API.
+// When JMX integrations run in several JMXFetch processes, the configs run by the other processes are loaded
+// from files instead, see SetConfigFiles.
+func GetSingleProcessScheduledConfigs() map[string]integration.Config {
+	return state.getSingleProcessScheduledConfigs()
+}
+
 // GetScheduledConfigsModificationTimestamp returns the last timestamp at which
 // the list of scheduled configuration got updated.
 func GetScheduledConfigsModificationTimestamp() int64 {
 	return state.getScheduledConfigsModificationTimestamp()
 }
 
-// StopJmxfetch stops the jmxfetch process if it is running
+// StopJmxfetch stops the jmxfetch processes if they are running
 func StopJmxfetch() {
-	err := state.runner.stopRunner()
-	if err != nil {
-		log.Errorf("failure to kill jmxfetch process: %s", err)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	for process, r := range state.runners {
+		err := r.stopRunner()
+		if err != nil {
+			if process == "" {
+				log.Errorf("failure to kill jmxfetch process: %s", err)
+			} else {
+				log.Errorf("failure to kill jmxfetch process %q: %s", process, err)
+			}
+		}
 	}
 }
 
 // InitRunner inits the runner and injects the dogstatsd server component
 func InitRunner(server dogstatsdServer.Component, logger jmxlogger.Component) {
-	state.runner.initRunner(server, logger)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	state.dsd = server
+	state.logger = logger
+	state.runners = map[string]*runner{}
+	state.getRunner("")
 }
@@ -8,6 +8,7 @@ package http
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"expvar"
@@ -452,6 +453,29 @@ func CheckConnectivityDiagnose(endpoint config.Endpoint, cfg pkgconfigmodel.Read
 	return destination.url, completeCheckConnectivity(ctx, destination)
 }
 
+// CheckCompressedConnectivityDiagnose checks that the endpoint accepts payloads compressed with gzip, as sent by the
+// logs pipeline when compression is enabled.
+func CheckCompressedConnectivityDiagnose(endpoint config.Endpoint, cfg pkgconfigmodel.Reader) (url string, err error) {
+	ctx, destination := prepareCheckConnectivity(endpoint, cfg)
+
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, endpoint.CompressionLevel)
+	if err != nil {
+		writer = gzip.NewWriter(&buf)
+	}
+	if _, err = writer.Write(emptyJsonPayload.Encoded); err != nil {
+		return destination.url, err
+	}
+	if err = writer.Close(); err != nil {
+		return destination.url, err
+	}
+	payload := message.Payload{Messages: []*message.Message{}, Encoded: buf.Bytes(), Encoding: "gzip"}
+
+	ctx.Start()
+	defer ctx.Stop()
+	return destination.url, destination.unconditionalSend(&payload)
+}
+
 func (d *Destination) waitForBackoff(blockedUntil time.Time) {
 	ctx, cancel := context.WithDeadline(d.destinationsContext.Context(), blockedUntil)
 	defer cancel()
@@ -166,6 +166,21 @@ func TestConnectivityCheck(t *testing.T) {
 	server.Stop()
 }
 
+func TestCompressedConnectivityCheck(t *testing.T) {
+	cfg := getNewConfig()
+	server := NewTestServer(200, cfg)
+	defer server.Stop()
+
+	url, err := CheckCompressedConnectivityDiagnose(server.Endpoint, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, server.Destination.url, url)
+	assert.Equal(t, "gzip", server.request.Header.Get("Content-Encoding"))
+
+	server.ChangeStatus(400)
+	_, err = CheckCompressedConnectivityDiagnose(server.Endpoint, cfg)
+	assert.Error(t, err)
+}
+
 func TestErrorToTag(t *testing.T) {
 	assert.Equal(t, errorToTag(nil), "none")
 	assert.Equal(t, errorToTag(errors.New("fail")), "non-retryable")
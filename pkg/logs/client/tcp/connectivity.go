@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+)
+
+// CheckConnectivityDiagnose makes a single attempt to open a connection to the endpoint, going through the socks5
+// proxy and performing the TLS handshake when they are configured, and returns the address of the endpoint.
+// Unlike NewConnection, it does not retry so it can be used to troubleshoot the connectivity.
+func CheckConnectivityDiagnose(endpoint config.Endpoint, timeout time.Duration) (address string, err error) {
+	cm := &ConnectionManager{endpoint: endpoint}
+	address = cm.address()
+
+	var conn net.Conn
+	if endpoint.ProxyAddress != "" {
+		var dialer proxy.Dialer
+		dialer, err = proxy.SOCKS5("tcp", endpoint.ProxyAddress, nil, &net.Dialer{Timeout: timeout})
+		if err != nil {
+			return address, err
+		}
+		conn, err = dialer.Dial("tcp", address)
+	} else {
+		conn, err = net.DialTimeout("tcp", address, timeout)
+	}
+	if err != nil {
+		return address, err
+	}
+	defer conn.Close()
+
+	if endpoint.UseSSL() {
+		sslConn := tls.Client(conn, &tls.Config{
+			ServerName: endpoint.Host,
+		})
+		if err = cm.handshakeWithTimeout(sslConn, timeout); err != nil {
+			return address, err
+		}
+	}
+	return address, nil
+}
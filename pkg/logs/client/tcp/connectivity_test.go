@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tcp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/client/mock"
+)
+
+func TestCheckConnectivityDiagnose(t *testing.T) {
+	l := mock.NewMockLogsIntake(t)
+
+	endpoint := AddrToEndPoint(l.Addr())
+	address, err := CheckConnectivityDiagnose(endpoint, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, l.Addr().String(), address)
+
+	// the intake is no longer listening
+	l.Close()
+	_, err = CheckConnectivityDiagnose(endpoint, time.Second)
+	assert.Error(t, err)
+}
+
+func TestCheckConnectivityDiagnoseTLSHandshakeFailure(t *testing.T) {
+	// a plain TCP intake can't complete the TLS handshake
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	host, port := AddrToHostPort(l.Addr())
+	endpoint := config.NewEndpoint("", host, port, true)
+	_, err = CheckConnectivityDiagnose(endpoint, time.Second)
+	assert.Error(t, err)
+}
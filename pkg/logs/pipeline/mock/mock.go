@@ -9,6 +9,7 @@ package mock
 import (
 	"context"
 
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
 )
@@ -43,6 +44,9 @@ func (p *mockProvider) StopSDSProcessing() error {
 	return nil
 }
 
+// ReconfigureProcessingRules does nothing
+func (p *mockProvider) ReconfigureProcessingRules(_ []*config.ProcessingRule) {}
+
 // Flush does nothing
 //
 //nolint:revive // TODO(AML) Fix revive linter
@@ -7,6 +7,7 @@ package pipeline
 
 import (
 	"context"
+	"sync"
 
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/atomic"
@@ -31,6 +32,7 @@ type Provider interface {
 	ReconfigureSDSStandardRules(standardRules []byte) (bool, error)
 	ReconfigureSDSAgentConfig(config []byte) (bool, error)
 	StopSDSProcessing() error
+	ReconfigureProcessingRules(rules []*config.ProcessingRule)
 	NextPipelineChan() chan *message.Message
 	// Flush flushes all pipeline contained in this Provider
 	Flush(ctx context.Context)
@@ -45,6 +47,11 @@ type provider struct {
 	processingRules           []*config.ProcessingRule
 	endpoints                 *config.Endpoints
 
+	// mu guards pipelines and remoteProcessingRules against the Remote
+	// Configuration updates of the processing rules
+	mu                    sync.Mutex
+	remoteProcessingRules []*config.ProcessingRule
+
 	pipelines            []*Pipeline
 	currentPipelineIndex *atomic.Uint32
 	destinationsContext  *client.DestinationsContext
@@ -90,12 +97,18 @@ func newProvider(numberOfPipelines int, auditor auditor.Auditor, diagnosticMessa
 
 // Start initializes the pipelines
 func (p *provider) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	// This requires the auditor to be started before.
 	p.outputChan = p.auditor.Channel()
 
 	for i := 0; i < p.numberOfPipelines; i++ {
 		pipeline := NewPipeline(p.outputChan, p.processingRules, p.endpoints, p.destinationsContext, p.diagnosticMessageReceiver, p.serverless, i, p.status, p.hostname, p.cfg)
 		pipeline.Start()
+		if len(p.remoteProcessingRules) > 0 {
+			pipeline.processor.ReconfigureProcessingRules(p.remoteProcessingRules)
+		}
 		p.pipelines = append(p.pipelines, pipeline)
 	}
 }
@@ -103,6 +116,9 @@ func (p *provider) Start() {
 // Stop stops all pipelines in parallel,
 // this call blocks until all pipelines are stopped
 func (p *provider) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	stopper := startstop.NewParallelStopper()
 	for _, pipeline := range p.pipelines {
 		stopper.Add(pipeline)
@@ -170,6 +186,19 @@ func (p *provider) StopSDSProcessing() error {
 	return err
 }
 
+// ReconfigureProcessingRules sets the global processing rules received through
+// Remote Configuration on all the pipelines, they are applied after the ones
+// of the configuration.
+func (p *provider) ReconfigureProcessingRules(rules []*config.ProcessingRule) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.remoteProcessingRules = rules
+	for _, pipeline := range p.pipelines {
+		pipeline.processor.ReconfigureProcessingRules(rules)
+	}
+}
+
 // NextPipelineChan returns the next pipeline input channel
 func (p *provider) NextPipelineChan() chan *message.Message {
 	pipelinesLen := len(p.pipelines)
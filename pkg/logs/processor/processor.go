@@ -7,6 +7,7 @@ package processor
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
@@ -33,6 +34,7 @@ type Processor struct {
 	// the processing rules of the SDS Scanner.
 	ReconfigChan              chan sds.ReconfigureOrder
 	processingRules           []*config.ProcessingRule
+	localProcessingRules      []*config.ProcessingRule
 	encoder                   Encoder
 	done                      chan struct{}
 	diagnosticMessageReceiver diagnostic.MessageReceiver
@@ -40,6 +42,10 @@ type Processor struct {
 	hostname                  hostnameinterface.Component
 
 	sds sdsProcessor
+
+	// rulesReconfigChan transports the global processing rules received
+	// through Remote Configuration.
+	rulesReconfigChan chan []*config.ProcessingRule
 }
 
 type sdsProcessor struct {
@@ -68,7 +74,9 @@ func New(cfg pkgconfigmodel.Reader, inputChan, outputChan chan *message.Message,
 		inputChan:                 inputChan,
 		outputChan:                outputChan, // strategy input
 		ReconfigChan:              make(chan sds.ReconfigureOrder),
+		rulesReconfigChan:         make(chan []*config.ProcessingRule, 1),
 		processingRules:           processingRules,
+		localProcessingRules:      processingRules,
 		encoder:                   encoder,
 		done:                      make(chan struct{}),
 		diagnosticMessageReceiver: diagnosticMessageReceiver,
@@ -115,6 +123,7 @@ func (p *Processor) Flush(ctx context.Context) {
 				return
 			}
 			msg := <-p.inputChan
+			p.applyProcessingRulesUpdate()
 			p.processMessage(msg)
 		}
 	}
@@ -136,6 +145,10 @@ func (p *Processor) run() {
 				return
 			}
 
+			p.mu.Lock()
+			p.applyProcessingRulesUpdate()
+			p.mu.Unlock()
+
 			// if we have to wait for an SDS configuration to start processing & forwarding
 			// the logs, that's here that we buffer the message
 			if p.sds.buffering {
@@ -161,6 +174,35 @@ func (p *Processor) run() {
 	}
 }
 
+// ReconfigureProcessingRules sets the global processing rules received through
+// Remote Configuration, they are applied after the ones of the configuration.
+// The new rules apply to the messages processed after this call. This call never
+// blocks, a previous update not applied yet by the Processor is dropped.
+func (p *Processor) ReconfigureProcessingRules(rules []*config.ProcessingRule) {
+	for {
+		select {
+		case p.rulesReconfigChan <- rules:
+			return
+		default:
+			// drop the stale update
+			select {
+			case <-p.rulesReconfigChan:
+			default:
+			}
+		}
+	}
+}
+
+// applyProcessingRulesUpdate applies the last processing rules received through
+// Remote Configuration, if any. It must be called with p.mu held.
+func (p *Processor) applyProcessingRulesUpdate() {
+	select {
+	case rules := <-p.rulesReconfigChan:
+		p.processingRules = append(slices.Clip(p.localProcessingRules), rules...)
+	default:
+	}
+}
+
 func (p *Processor) applySDSReconfiguration(order sds.ReconfigureOrder) {
 	isActive, err := p.sds.scanner.Reconfigure(order)
 	response := sds.ReconfigureResponse{
@@ -278,6 +278,57 @@ func TestMask(t *testing.T) {
 	}
 }
 
+func TestReconfigureProcessingRules(t *testing.T) {
+	assert := assert.New(t)
+
+	hostnameComponent, _ := hostnameinterface.NewMock("testHostnameFromEnvVar")
+	p := New(nil, make(chan *message.Message), make(chan *message.Message, 1),
+		[]*config.ProcessingRule{newProcessingRule(config.ExcludeAtMatch, "", "debug")},
+		JSONEncoder, diagnostic.NewBufferedMessageReceiver(nil, hostnameComponent), hostnameComponent, 0)
+	p.Start()
+	defer p.Stop()
+
+	source := sources.NewLogSource("", &config.LogsConfig{})
+	process := func(content string) bool {
+		p.inputChan <- newMessage([]byte(content), source, "")
+		select {
+		case <-p.outputChan:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}
+
+	assert.True(process("health check"))
+	assert.False(process("debug message"))
+
+	// remote rules are applied along the local ones
+	p.ReconfigureProcessingRules([]*config.ProcessingRule{newProcessingRule(config.ExcludeAtMatch, "", "health")})
+	assert.False(process("health check"))
+	assert.False(process("debug message"))
+	assert.True(process("hello"))
+
+	// and replaced by the next reconfiguration
+	p.ReconfigureProcessingRules(nil)
+	assert.True(process("health check"))
+	assert.False(process("debug message"))
+	assert.Len(p.localProcessingRules, 1)
+}
+
+func TestReconfigureProcessingRulesNotRunning(t *testing.T) {
+	hostnameComponent, _ := hostnameinterface.NewMock("testHostnameFromEnvVar")
+	p := New(nil, make(chan *message.Message), make(chan *message.Message, 1), nil,
+		JSONEncoder, diagnostic.NewBufferedMessageReceiver(nil, hostnameComponent), hostnameComponent, 0)
+
+	// updates sent while the processor is not running don't block, the last one is kept
+	p.ReconfigureProcessingRules([]*config.ProcessingRule{newProcessingRule(config.ExcludeAtMatch, "", "debug")})
+	p.ReconfigureProcessingRules([]*config.ProcessingRule{newProcessingRule(config.ExcludeAtMatch, "", "health")})
+
+	p.applyProcessingRulesUpdate()
+	assert.Len(t, p.processingRules, 1)
+	assert.Equal(t, "health", p.processingRules[0].Pattern)
+}
+
 func TestTruncate(t *testing.T) {
 	p := &Processor{}
 	source := sources.NewLogSource("", &config.LogsConfig{})
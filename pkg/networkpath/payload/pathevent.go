@@ -18,6 +18,8 @@ const (
 	ProtocolTCP Protocol = "TCP"
 	// ProtocolUDP is the UDP protocol.
 	ProtocolUDP Protocol = "UDP"
+	// ProtocolICMP is the ICMP protocol.
+	ProtocolICMP Protocol = "ICMP"
 )
 
 // PathOrigin origin of the path e.g. network_traffic, network_path_integration
@@ -42,6 +44,10 @@ type NetworkPathHop struct {
 
 	RTT       float64 `json:"rtt,omitempty"`
 	Reachable bool    `json:"reachable"`
+
+	// FlowIDs are the flows for which this hop was seen, only set when several
+	// flows are traced to discover ECMP paths. The same TTL can then have several hops.
+	FlowIDs []uint16 `json:"flow_ids,omitempty"`
 }
 
 // NetworkPathSource encapsulates information
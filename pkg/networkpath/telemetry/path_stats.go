@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package telemetry
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/networkdevice/utils"
+	"github.com/DataDog/datadog-agent/pkg/networkpath/metricsender"
+	"github.com/DataDog/datadog-agent/pkg/networkpath/payload"
+)
+
+// ProbeStats holds the RTT and loss statistics computed from several probes.
+// RTT values are in milliseconds, like NetworkPathHop.RTT.
+type ProbeStats struct {
+	ProbesSent     int
+	ProbesReceived int
+	RTTMin         float64
+	RTTAvg         float64
+	RTTMax         float64
+	// Jitter is the mean absolute difference between consecutive RTT samples
+	Jitter float64
+}
+
+// PacketLoss returns the ratio of probes that did not get a response
+func (s ProbeStats) PacketLoss() float64 {
+	if s.ProbesSent == 0 {
+		return 0
+	}
+	return float64(s.ProbesSent-s.ProbesReceived) / float64(s.ProbesSent)
+}
+
+// HopStats holds the probe statistics of the hops seen at a given TTL
+type HopStats struct {
+	ProbeStats
+	TTL       int
+	IPAddress string
+}
+
+// PathStats holds per-hop and end-to-end statistics of a path probed several times
+type PathStats struct {
+	Hops     []HopStats
+	EndToEnd ProbeStats
+	// HopCount is the number of hops to the destination in the latest probe
+	// reaching it, 0 if the destination was never reached
+	HopCount int
+}
+
+// ComputePathStats aggregates several traceroutes of the same path, each one
+// being a probe for every hop. Hops are matched by TTL; when several hops
+// answer for the same TTL (ECMP), the fastest one is used for that probe.
+func ComputePathStats(paths []payload.NetworkPath) PathStats {
+	var stats PathStats
+
+	hopSamples := make(map[int][]float64)
+	hopIPs := make(map[int]string)
+	var endToEndSamples []float64
+	for _, path := range paths {
+		probeRTTs := make(map[int]float64)
+		for _, hop := range path.Hops {
+			if !hop.Reachable {
+				continue
+			}
+			if rtt, ok := probeRTTs[hop.TTL]; !ok || hop.RTT < rtt {
+				probeRTTs[hop.TTL] = hop.RTT
+				hopIPs[hop.TTL] = hop.IPAddress
+			}
+		}
+		for ttl, rtt := range probeRTTs {
+			hopSamples[ttl] = append(hopSamples[ttl], rtt)
+		}
+
+		if len(path.Hops) == 0 {
+			continue
+		}
+		lastHop := path.Hops[len(path.Hops)-1]
+		if lastHop.Reachable {
+			endToEndSamples = append(endToEndSamples, lastHop.RTT)
+			stats.HopCount = lastHop.TTL
+		}
+	}
+
+	for ttl, samples := range hopSamples {
+		stats.Hops = append(stats.Hops, HopStats{
+			ProbeStats: computeProbeStats(len(paths), samples),
+			TTL:        ttl,
+			IPAddress:  hopIPs[ttl],
+		})
+	}
+	sort.Slice(stats.Hops, func(i, j int) bool {
+		return stats.Hops[i].TTL < stats.Hops[j].TTL
+	})
+	stats.EndToEnd = computeProbeStats(len(paths), endToEndSamples)
+
+	return stats
+}
+
+func computeProbeStats(sent int, samples []float64) ProbeStats {
+	stats := ProbeStats{
+		ProbesSent:     sent,
+		ProbesReceived: len(samples),
+	}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	stats.RTTMin = math.Inf(1)
+	var sum, jitterSum float64
+	for i, rtt := range samples {
+		sum += rtt
+		stats.RTTMin = min(stats.RTTMin, rtt)
+		stats.RTTMax = max(stats.RTTMax, rtt)
+		if i > 0 {
+			jitterSum += math.Abs(rtt - samples[i-1])
+		}
+	}
+	stats.RTTAvg = sum / float64(len(samples))
+	if len(samples) > 1 {
+		stats.Jitter = jitterSum / float64(len(samples)-1)
+	}
+	return stats
+}
+
+// SubmitNetworkPathStats submits per-hop and end-to-end latency, jitter and packet
+// loss metrics of a path, along with hop count changes compared to the previous run.
+// Jitter and packet loss are only submitted when the path was traced several times.
+// previousHopCount is the HopCount of the previous run, 0 if unknown.
+func SubmitNetworkPathStats(sender metricsender.MetricSender, path payload.NetworkPath, stats PathStats, previousHopCount int, tags []string) {
+	newTags := utils.CopyStrings(tags)
+	if path.Source.Service != "" {
+		newTags = append(newTags, "source_service:"+path.Source.Service)
+	}
+	if path.Destination.Service != "" {
+		newTags = append(newTags, "destination_service:"+path.Destination.Service)
+	}
+	newTags = pathTags(path, newTags)
+
+	submitProbeStats(sender, "datadog.network_path.path.", stats.EndToEnd, newTags)
+
+	for _, hop := range stats.Hops {
+		hopTags := append(utils.CopyStrings(newTags),
+			"hop_ttl:"+strconv.Itoa(hop.TTL),
+			"hop_ip_address:"+hop.IPAddress,
+		)
+		sort.Strings(hopTags)
+		submitProbeStats(sender, "datadog.network_path.hop.", hop.ProbeStats, hopTags)
+	}
+
+	if stats.HopCount > 0 && previousHopCount > 0 {
+		sender.Gauge("datadog.network_path.path.hop_count_changed", utils.BoolToFloat64(stats.HopCount != previousHopCount), newTags)
+		sender.Gauge("datadog.network_path.path.hop_count_delta", float64(stats.HopCount-previousHopCount), newTags)
+	}
+}
+
+func submitProbeStats(sender metricsender.MetricSender, prefix string, stats ProbeStats, tags []string) {
+	// a single probe doesn't tell anything about jitter and packet loss
+	if stats.ProbesSent > 1 {
+		sender.Gauge(prefix+"packet_loss", stats.PacketLoss(), tags)
+	}
+	if stats.ProbesReceived == 0 {
+		return
+	}
+	sender.Gauge(prefix+"rtt.min", stats.RTTMin, tags)
+	sender.Gauge(prefix+"rtt.avg", stats.RTTAvg, tags)
+	sender.Gauge(prefix+"rtt.max", stats.RTTMax, tags)
+	if stats.ProbesReceived > 1 {
+		sender.Gauge(prefix+"jitter", stats.Jitter, tags)
+	}
+}
@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/networkpath/metricsender"
+	"github.com/DataDog/datadog-agent/pkg/networkpath/payload"
+)
+
+func TestComputePathStats(t *testing.T) {
+	tests := []struct {
+		name          string
+		paths         []payload.NetworkPath
+		expectedStats PathStats
+	}{
+		{
+			name:          "no paths",
+			expectedStats: PathStats{EndToEnd: ProbeStats{}},
+		},
+		{
+			name: "destination reached by all probes",
+			paths: []payload.NetworkPath{
+				{Hops: []payload.NetworkPathHop{
+					{TTL: 1, IPAddress: "10.0.0.1", RTT: 1, Reachable: true},
+					{TTL: 2, IPAddress: "10.0.0.2", RTT: 10, Reachable: true},
+				}},
+				{Hops: []payload.NetworkPathHop{
+					{TTL: 1, IPAddress: "10.0.0.1", RTT: 3, Reachable: true},
+					{TTL: 2, IPAddress: "10.0.0.2", RTT: 14, Reachable: true},
+				}},
+				{Hops: []payload.NetworkPathHop{
+					{TTL: 1, IPAddress: "10.0.0.1", RTT: 2, Reachable: true},
+					{TTL: 2, IPAddress: "10.0.0.2", RTT: 12, Reachable: true},
+				}},
+			},
+			expectedStats: PathStats{
+				Hops: []HopStats{
+					{TTL: 1, IPAddress: "10.0.0.1", ProbeStats: ProbeStats{ProbesSent: 3, ProbesReceived: 3, RTTMin: 1, RTTAvg: 2, RTTMax: 3, Jitter: 1.5}},
+					{TTL: 2, IPAddress: "10.0.0.2", ProbeStats: ProbeStats{ProbesSent: 3, ProbesReceived: 3, RTTMin: 10, RTTAvg: 12, RTTMax: 14, Jitter: 3}},
+				},
+				EndToEnd: ProbeStats{ProbesSent: 3, ProbesReceived: 3, RTTMin: 10, RTTAvg: 12, RTTMax: 14, Jitter: 3},
+				HopCount: 2,
+			},
+		},
+		{
+			name: "lost probes and ECMP hops",
+			paths: []payload.NetworkPath{
+				{Hops: []payload.NetworkPathHop{
+					{TTL: 1, IPAddress: "10.0.0.1", RTT: 4, Reachable: true},
+					{TTL: 1, IPAddress: "10.0.0.11", RTT: 2, Reachable: true},
+					{TTL: 2, IPAddress: "unknown_hop_2"},
+					{TTL: 3, IPAddress: "10.0.0.3", RTT: 20, Reachable: true},
+				}},
+				{Hops: []payload.NetworkPathHop{
+					{TTL: 1, IPAddress: "10.0.0.1", RTT: 4, Reachable: true},
+					{TTL: 2, IPAddress: "unknown_hop_2"},
+					{TTL: 3, IPAddress: "unknown_hop_3"},
+				}},
+			},
+			expectedStats: PathStats{
+				Hops: []HopStats{
+					{TTL: 1, IPAddress: "10.0.0.1", ProbeStats: ProbeStats{ProbesSent: 2, ProbesReceived: 2, RTTMin: 2, RTTAvg: 3, RTTMax: 4, Jitter: 2}},
+					{TTL: 3, IPAddress: "10.0.0.3", ProbeStats: ProbeStats{ProbesSent: 2, ProbesReceived: 1, RTTMin: 20, RTTAvg: 20, RTTMax: 20}},
+				},
+				EndToEnd: ProbeStats{ProbesSent: 2, ProbesReceived: 1, RTTMin: 20, RTTAvg: 20, RTTMax: 20},
+				HopCount: 3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := ComputePathStats(tt.paths)
+			assert.Equal(t, tt.expectedStats.EndToEnd, stats.EndToEnd)
+			assert.Equal(t, tt.expectedStats.HopCount, stats.HopCount)
+			assert.Len(t, stats.Hops, len(tt.expectedStats.Hops))
+			for i, hop := range tt.expectedStats.Hops {
+				assert.Equal(t, hop.TTL, stats.Hops[i].TTL)
+				assert.Equal(t, hop.ProbeStats, stats.Hops[i].ProbeStats)
+			}
+		})
+	}
+}
+
+func TestProbeStats_PacketLoss(t *testing.T) {
+	assert.Equal(t, float64(0), ProbeStats{}.PacketLoss())
+	assert.Equal(t, float64(0), ProbeStats{ProbesSent: 3, ProbesReceived: 3}.PacketLoss())
+	assert.Equal(t, 0.75, ProbeStats{ProbesSent: 4, ProbesReceived: 1}.PacketLoss())
+	assert.Equal(t, float64(1), ProbeStats{ProbesSent: 2}.PacketLoss())
+}
+
+func TestSubmitNetworkPathStats(t *testing.T) {
+	path := payload.NetworkPath{
+		Origin:      payload.PathOriginNetworkPathIntegration,
+		Protocol:    payload.ProtocolTCP,
+		Source:      payload.NetworkPathSource{Service: "frontend"},
+		Destination: payload.NetworkPathDestination{Hostname: "abc", IPAddress: "10.0.0.2", Port: 443, Service: "backend"},
+	}
+	stats := PathStats{
+		Hops: []HopStats{
+			{TTL: 1, IPAddress: "10.0.0.1", ProbeStats: ProbeStats{ProbesSent: 2, ProbesReceived: 0}},
+			{TTL: 2, IPAddress: "10.0.0.2", ProbeStats: ProbeStats{ProbesSent: 2, ProbesReceived: 2, RTTMin: 10, RTTAvg: 11, RTTMax: 12, Jitter: 2}},
+		},
+		EndToEnd: ProbeStats{ProbesSent: 2, ProbesReceived: 2, RTTMin: 10, RTTAvg: 11, RTTMax: 12, Jitter: 2},
+		HopCount: 2,
+	}
+	pathTags := []string{
+		"collector:network_path_integration",
+		"destination_hostname:abc",
+		"destination_ip:10.0.0.2",
+		"destination_port:443",
+		"destination_service:backend",
+		"foo:bar",
+		"origin:network_path_integration",
+		"protocol:TCP",
+		"source_service:frontend",
+	}
+	hop1Tags := []string{
+		"collector:network_path_integration",
+		"destination_hostname:abc",
+		"destination_ip:10.0.0.2",
+		"destination_port:443",
+		"destination_service:backend",
+		"foo:bar",
+		"hop_ip_address:10.0.0.1",
+		"hop_ttl:1",
+		"origin:network_path_integration",
+		"protocol:TCP",
+		"source_service:frontend",
+	}
+	hop2Tags := []string{
+		"collector:network_path_integration",
+		"destination_hostname:abc",
+		"destination_ip:10.0.0.2",
+		"destination_port:443",
+		"destination_service:backend",
+		"foo:bar",
+		"hop_ip_address:10.0.0.2",
+		"hop_ttl:2",
+		"origin:network_path_integration",
+		"protocol:TCP",
+		"source_service:frontend",
+	}
+
+	sender := metricsender.NewMetricSenderMock()
+	SubmitNetworkPathStats(sender, path, stats, 3, []string{"foo:bar"})
+
+	expectedMetrics := []metricsender.MockReceivedMetric{
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.packet_loss", Value: 0, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.rtt.min", Value: 10, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.rtt.avg", Value: 11, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.rtt.max", Value: 12, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.jitter", Value: 2, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.hop.packet_loss", Value: 1, Tags: hop1Tags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.hop.packet_loss", Value: 0, Tags: hop2Tags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.hop.rtt.min", Value: 10, Tags: hop2Tags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.hop.rtt.avg", Value: 11, Tags: hop2Tags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.hop.rtt.max", Value: 12, Tags: hop2Tags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.hop.jitter", Value: 2, Tags: hop2Tags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.hop_count_changed", Value: 1, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.hop_count_delta", Value: -1, Tags: pathTags},
+	}
+	assert.Equal(t, expectedMetrics, sender.(*metricsender.MockMetricSender).Metrics)
+}
+
+func TestSubmitNetworkPathStats_noPreviousHopCount(t *testing.T) {
+	path := payload.NetworkPath{
+		Origin:      payload.PathOriginNetworkTraffic,
+		Protocol:    payload.ProtocolUDP,
+		Destination: payload.NetworkPathDestination{Hostname: "abc", IPAddress: "10.0.0.2"},
+	}
+	stats := PathStats{
+		EndToEnd: ProbeStats{ProbesSent: 1, ProbesReceived: 1, RTTMin: 10, RTTAvg: 10, RTTMax: 10},
+		HopCount: 2,
+	}
+
+	sender := metricsender.NewMetricSenderMock()
+	SubmitNetworkPathStats(sender, path, stats, 0, nil)
+
+	for _, metric := range sender.(*metricsender.MockMetricSender).Metrics {
+		assert.NotContains(t, metric.Name, "hop_count")
+		assert.NotContains(t, metric.Tags, "source_service:")
+	}
+}
+
+func TestSubmitNetworkPathStats_singleProbe(t *testing.T) {
+	path := payload.NetworkPath{
+		Origin:      payload.PathOriginNetworkPathIntegration,
+		Protocol:    payload.ProtocolUDP,
+		Destination: payload.NetworkPathDestination{Hostname: "abc", IPAddress: "10.0.0.2"},
+	}
+	stats := PathStats{
+		EndToEnd: ProbeStats{ProbesSent: 1, ProbesReceived: 1, RTTMin: 10, RTTAvg: 10, RTTMax: 10},
+		HopCount: 2,
+	}
+	pathTags := []string{
+		"collector:network_path_integration",
+		"destination_hostname:abc",
+		"destination_ip:10.0.0.2",
+		"destination_port:unspecified",
+		"origin:network_path_integration",
+		"protocol:UDP",
+	}
+
+	sender := metricsender.NewMetricSenderMock()
+	SubmitNetworkPathStats(sender, path, stats, 2, nil)
+
+	// jitter and packet loss are meaningless with a single probe
+	expectedMetrics := []metricsender.MockReceivedMetric{
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.rtt.min", Value: 10, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.rtt.avg", Value: 10, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.rtt.max", Value: 10, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.hop_count_changed", Value: 0, Tags: pathTags},
+		{MetricType: metrics.GaugeType, Name: "datadog.network_path.path.hop_count_delta", Value: 0, Tags: pathTags},
+	}
+	assert.Equal(t, expectedMetrics, sender.(*metricsender.MockMetricSender).Metrics)
+}
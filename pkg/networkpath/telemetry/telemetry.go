@@ -28,6 +28,27 @@ const CollectorTypeNetworkPathCollector NetworkPathCollectorType = "network_path
 
 // SubmitNetworkPathTelemetry submits Network Path related telemetry
 func SubmitNetworkPathTelemetry(sender metricsender.MetricSender, path payload.NetworkPath, checkDuration time.Duration, checkInterval time.Duration, tags []string) {
+	newTags := pathTags(path, tags)
+
+	sender.Gauge("datadog.network_path.check_duration", checkDuration.Seconds(), newTags)
+
+	if checkInterval > 0 {
+		sender.Gauge("datadog.network_path.check_interval", checkInterval.Seconds(), newTags)
+	}
+
+	sender.Gauge("datadog.network_path.path.monitored", float64(1), newTags)
+	if len(path.Hops) > 0 {
+		lastHop := path.Hops[len(path.Hops)-1]
+		if lastHop.Reachable {
+			sender.Gauge("datadog.network_path.path.hops", float64(len(path.Hops)), newTags)
+		}
+		sender.Gauge("datadog.network_path.path.reachable", float64(utils.BoolToFloat64(lastHop.Reachable)), newTags)
+		sender.Gauge("datadog.network_path.path.unreachable", float64(utils.BoolToFloat64(!lastHop.Reachable)), newTags)
+	}
+}
+
+// pathTags returns the sorted tags identifying a path, appended to the given tags
+func pathTags(path payload.NetworkPath, tags []string) []string {
 	destPortTag := "unspecified"
 	if path.Destination.Port > 0 {
 		destPortTag = strconv.Itoa(int(path.Destination.Port))
@@ -48,20 +69,5 @@ func SubmitNetworkPathTelemetry(sender metricsender.MetricSender, path payload.N
 	}...)
 
 	sort.Strings(newTags)
-
-	sender.Gauge("datadog.network_path.check_duration", checkDuration.Seconds(), newTags)
-
-	if checkInterval > 0 {
-		sender.Gauge("datadog.network_path.check_interval", checkInterval.Seconds(), newTags)
-	}
-
-	sender.Gauge("datadog.network_path.path.monitored", float64(1), newTags)
-	if len(path.Hops) > 0 {
-		lastHop := path.Hops[len(path.Hops)-1]
-		if lastHop.Reachable {
-			sender.Gauge("datadog.network_path.path.hops", float64(len(path.Hops)), newTags)
-		}
-		sender.Gauge("datadog.network_path.path.reachable", float64(utils.BoolToFloat64(lastHop.Reachable)), newTags)
-		sender.Gauge("datadog.network_path.path.unreachable", float64(utils.BoolToFloat64(!lastHop.Reachable)), newTags)
-	}
+	return newTags
 }
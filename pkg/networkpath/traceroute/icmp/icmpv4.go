@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package icmp adds an ICMP echo traceroute implementation to the agent
+package icmp
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	"golang.org/x/net/ipv4"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+type (
+	// ICMPv4 encapsulates the data needed to run
+	// an ICMPv4 echo traceroute
+	ICMPv4 struct {
+		Target net.IP
+		srcIP  net.IP // calculated internally
+		// ID is the ICMP echo identifier, it identifies
+		// the flow being traced
+		ID uint16
+		// ParisMode keeps the ICMP checksum constant for all the
+		// probes of a flow, so that load balancers hashing on the
+		// ICMP header route all the probes through the same path
+		ParisMode bool
+		MinTTL    uint8
+		MaxTTL    uint8
+		Timeout   time.Duration // timeout for each packet
+	}
+
+	// Results encapsulates a response from the ICMP
+	// traceroute
+	Results struct {
+		Source net.IP
+		Target net.IP
+		ID     uint16
+		Hops   []*Hop
+	}
+
+	// Hop encapsulates information about a single
+	// hop in an ICMP traceroute
+	Hop struct {
+		IP       net.IP
+		ICMPType layers.ICMPv4TypeCode
+		RTT      time.Duration
+		IsDest   bool
+	}
+)
+
+// TracerouteSequential runs a traceroute sequentially where a packet is
+// sent and we wait for a response before sending the next packet
+func (t *ICMPv4) TracerouteSequential() (*Results, error) {
+	addr, err := localAddrForHost(t.Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local address for target: %w", err)
+	}
+	t.srcIP = addr.IP
+
+	// Echo replies, TTL exceeded and unreachable messages
+	// are all received on the raw ICMP listener
+	icmpConn, err := net.ListenPacket("ip4:icmp", addr.IP.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ICMP listener: %w", err)
+	}
+	defer icmpConn.Close()
+	// RawConn is necessary to set the TTL and ID fields
+	rawIcmpConn, err := ipv4.NewRawConn(icmpConn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get raw ICMP listener: %w", err)
+	}
+
+	hops := make([]*Hop, 0, t.MaxTTL-t.MinTTL+1)
+
+	for i := int(t.MinTTL); i <= int(t.MaxTTL); i++ {
+		// the sequence number is used to match responses to
+		// the probe that was sent for a given TTL
+		seqNum := uint16(i)
+		hop, err := t.sendAndReceive(rawIcmpConn, i, seqNum, t.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run traceroute: %w", err)
+		}
+		hops = append(hops, hop)
+		log.Tracef("Discovered hop: %+v", hop)
+		// if we've reached our destination,
+		// we're done
+		if hop.IsDest {
+			break
+		}
+	}
+
+	return &Results{
+		Source: t.srcIP,
+		Target: t.Target,
+		ID:     t.ID,
+		Hops:   hops,
+	}, nil
+}
+
+func (t *ICMPv4) sendAndReceive(rawIcmpConn *ipv4.RawConn, ttl int, seqNum uint16, timeout time.Duration) (*Hop, error) {
+	icmpHeader, icmpPacket, err := createRawICMPEcho(t.srcIP, t.Target, t.ID, seqNum, ttl, t.ParisMode)
+	if err != nil {
+		log.Errorf("failed to create ICMP packet with TTL: %d, error: %s", ttl, err.Error())
+		return nil, err
+	}
+
+	if err := rawIcmpConn.WriteTo(icmpHeader, icmpPacket, nil); err != nil {
+		log.Errorf("failed to send ICMP echo request: %s", err.Error())
+		return nil, err
+	}
+
+	start := time.Now()
+	hopIP, icmpType, end, err := listenPackets(rawIcmpConn, timeout, t.srcIP, t.Target, t.ID, seqNum)
+	if err != nil {
+		log.Errorf("failed to listen for packets: %s", err.Error())
+		return nil, err
+	}
+
+	rtt := time.Duration(0)
+	if !hopIP.Equal(net.IP{}) {
+		rtt = end.Sub(start)
+	}
+
+	return &Hop{
+		IP:       hopIP,
+		ICMPType: icmpType,
+		RTT:      rtt,
+		IsDest:   hopIP.Equal(t.Target),
+	}, nil
+}
@@ -0,0 +1,235 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package icmp
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"golang.org/x/net/ipv4"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// IPProtoICMP is the ICMP protocol number
+	IPProtoICMP = 1
+
+	// parisChecksumTarget is the one's complement sum of the sequence
+	// number and the payload of the probes sent in Paris mode, keeping
+	// the ICMP checksum constant for a given echo identifier
+	parisChecksumTarget uint16 = 0xbeef
+)
+
+type (
+	// icmpResponse encapsulates the data from
+	// an ICMP response packet needed for matching
+	icmpResponse struct {
+		SrcIP    net.IP
+		DstIP    net.IP
+		TypeCode layers.ICMPv4TypeCode
+		// InnerSrcIP and InnerDstIP are the addresses of the probe,
+		// they are the addresses of the response for an echo reply
+		InnerSrcIP net.IP
+		InnerDstIP net.IP
+		InnerID    uint16
+		InnerSeq   uint16
+	}
+
+	rawConnWrapper interface {
+		SetReadDeadline(t time.Time) error
+		ReadFrom(b []byte) (*ipv4.Header, []byte, *ipv4.ControlMessage, error)
+		WriteTo(h *ipv4.Header, p []byte, cm *ipv4.ControlMessage) error
+	}
+)
+
+func localAddrForHost(destIP net.IP) (*net.UDPAddr, error) {
+	// this is a quick way to get the local address for connecting to the host
+	// using UDP as the network type to avoid actually creating a connection to
+	// the host, the port is irrelevant for ICMP
+	conn, err := net.Dial("udp4", net.JoinHostPort(destIP.String(), "33434"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	localAddr := conn.LocalAddr()
+
+	localUDPAddr, ok := localAddr.(*net.UDPAddr)
+	if !ok {
+		return nil, fmt.Errorf("invalid address type for %s: want %T, got %T", localAddr, localUDPAddr, localAddr)
+	}
+
+	return localUDPAddr, nil
+}
+
+// onesComplementAdd adds two 16-bit words using one's complement arithmetic
+func onesComplementAdd(a, b uint16) uint16 {
+	sum := uint32(a) + uint32(b)
+	return uint16(sum&0xffff + sum>>16)
+}
+
+// echoPayload returns the payload of an echo request. In Paris mode, the
+// payload compensates the sequence number so that the ICMP checksum, which
+// is part of the flow identifier for load balancers, stays constant.
+func echoPayload(seqNum uint16, parisMode bool) []byte {
+	payload := make([]byte, 2)
+	if parisMode {
+		binary.BigEndian.PutUint16(payload, onesComplementAdd(parisChecksumTarget, ^seqNum))
+	}
+	return payload
+}
+
+// createRawICMPEcho creates an ICMP echo request packet with the specified parameters
+func createRawICMPEcho(sourceIP net.IP, destIP net.IP, id uint16, seqNum uint16, ttl int, parisMode bool) (*ipv4.Header, []byte, error) {
+	ipLayer := &layers.IPv4{
+		Version:  4,
+		Length:   20,
+		TTL:      uint8(ttl),
+		Id:       uint16(41821),
+		Protocol: layers.IPProtocolICMPv4,
+		DstIP:    destIP,
+		SrcIP:    sourceIP,
+	}
+
+	icmpLayer := &layers.ICMPv4{
+		TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0),
+		Id:       id,
+		Seq:      seqNum,
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	err := gopacket.SerializeLayers(buf, opts,
+		ipLayer,
+		icmpLayer,
+		gopacket.Payload(echoPayload(seqNum, parisMode)),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize packet: %w", err)
+	}
+	packet := buf.Bytes()
+
+	var ipHdr ipv4.Header
+	if err := ipHdr.Parse(packet[:20]); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse IP header: %w", err)
+	}
+
+	return &ipHdr, packet[20:], nil
+}
+
+// listenPackets listens for an ICMP response matching the passed in probe
+// information. If no matching packet is received within the timeout, a blank
+// response is returned.
+func listenPackets(conn rawConnWrapper, timeout time.Duration, localIP net.IP, remoteIP net.IP, id uint16, seqNum uint16) (net.IP, layers.ICMPv4TypeCode, time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	buf := make([]byte, 1024)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Trace("timed out waiting for responses")
+			return net.IP{}, 0, time.Time{}, nil
+		default:
+		}
+		now := time.Now()
+		err := conn.SetReadDeadline(now.Add(time.Millisecond * 100))
+		if err != nil {
+			return net.IP{}, 0, time.Time{}, fmt.Errorf("failed to read: %w", err)
+		}
+		header, packet, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if nerr, ok := err.(*net.OpError); ok {
+				if nerr.Timeout() {
+					continue
+				}
+			}
+			return net.IP{}, 0, time.Time{}, err
+		}
+		// once we have a packet, take a timestamp to know when
+		// the response was received, if it matches, we will
+		// return this timestamp
+		received := time.Now()
+		icmpResponse, err := parseICMP(header, packet)
+		if err != nil {
+			log.Tracef("failed to parse ICMP packet: %s", err.Error())
+			continue
+		}
+		if icmpMatch(localIP, remoteIP, id, seqNum, icmpResponse) {
+			return icmpResponse.SrcIP, icmpResponse.TypeCode, received, nil
+		}
+	}
+}
+
+// parseICMP takes in an IPv4 header and payload and tries to convert to an ICMP
+// message, it returns all the fields from the packet we need to validate it's the response
+// we're looking for
+func parseICMP(header *ipv4.Header, payload []byte) (*icmpResponse, error) {
+	icmpResponse := icmpResponse{}
+
+	if header.Protocol != IPProtoICMP || header.Version != 4 ||
+		header.Src == nil || header.Dst == nil {
+		return nil, fmt.Errorf("invalid IP header for ICMP packet: %+v", header)
+	}
+	icmpResponse.SrcIP = header.Src
+	icmpResponse.DstIP = header.Dst
+
+	var icmpv4Layer layers.ICMPv4
+	decoded := []gopacket.LayerType{}
+	icmpParser := gopacket.NewDecodingLayerParser(layers.LayerTypeICMPv4, &icmpv4Layer)
+	icmpParser.IgnoreUnsupported = true // ignore unsupported layers, we will decode them in the next step
+	if err := icmpParser.DecodeLayers(payload, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode ICMP packet: %w", err)
+	}
+	// since we ignore unsupported layers, we need to check if we actually decoded
+	// anything
+	if len(decoded) < 1 {
+		return nil, fmt.Errorf("failed to decode ICMP packet, no layers decoded")
+	}
+	icmpResponse.TypeCode = icmpv4Layer.TypeCode
+
+	switch icmpv4Layer.TypeCode.Type() {
+	case layers.ICMPv4TypeEchoReply:
+		// the echo reply comes from the target itself
+		icmpResponse.InnerSrcIP = header.Dst
+		icmpResponse.InnerDstIP = header.Src
+		icmpResponse.InnerID = icmpv4Layer.Id
+		icmpResponse.InnerSeq = icmpv4Layer.Seq
+	case layers.ICMPv4TypeTimeExceeded, layers.ICMPv4TypeDestinationUnreachable:
+		// a separate parser is needed to decode the inner IP and ICMP headers because
+		// gopacket doesn't support this type of nesting in a single decoder
+		var innerIPLayer layers.IPv4
+		var innerICMPLayer layers.ICMPv4
+		innerIPParser := gopacket.NewDecodingLayerParser(layers.LayerTypeIPv4, &innerIPLayer, &innerICMPLayer)
+		innerIPParser.IgnoreUnsupported = true
+		if err := innerIPParser.DecodeLayers(icmpv4Layer.Payload, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode inner ICMP payload: %w", err)
+		}
+		if len(decoded) < 2 {
+			return nil, fmt.Errorf("failed to decode inner ICMP payload, not an ICMP probe")
+		}
+		icmpResponse.InnerSrcIP = innerIPLayer.SrcIP
+		icmpResponse.InnerDstIP = innerIPLayer.DstIP
+		icmpResponse.InnerID = innerICMPLayer.Id
+		icmpResponse.InnerSeq = innerICMPLayer.Seq
+	default:
+		return nil, fmt.Errorf("unexpected ICMP type: %s", icmpv4Layer.TypeCode)
+	}
+
+	return &icmpResponse, nil
+}
+
+func icmpMatch(localIP net.IP, remoteIP net.IP, id uint16, seqNum uint16, response *icmpResponse) bool {
+	return localIP.Equal(response.InnerSrcIP) &&
+		remoteIP.Equal(response.InnerDstIP) &&
+		id == response.InnerID &&
+		seqNum == response.InnerSeq
+}
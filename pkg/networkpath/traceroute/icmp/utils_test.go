@@ -0,0 +1,173 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package icmp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+var (
+	srcIP = net.ParseIP("10.0.0.1")
+	dstIP = net.ParseIP("192.168.1.1")
+	hopIP = net.ParseIP("172.16.0.254")
+)
+
+type mockRawConn struct {
+	header  *ipv4.Header
+	payload []byte
+	reads   int
+}
+
+func (m *mockRawConn) SetReadDeadline(_ time.Time) error {
+	return nil
+}
+
+func (m *mockRawConn) ReadFrom(_ []byte) (*ipv4.Header, []byte, *ipv4.ControlMessage, error) {
+	m.reads++
+	if m.header == nil {
+		time.Sleep(10 * time.Millisecond)
+		return nil, nil, nil, &net.OpError{Err: timeoutErr{}}
+	}
+	return m.header, m.payload, nil, nil
+}
+
+func (m *mockRawConn) WriteTo(_ *ipv4.Header, _ []byte, _ *ipv4.ControlMessage) error {
+	return nil
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string { return "timeout" }
+func (timeoutErr) Timeout() bool { return true }
+
+func icmpChecksum(t *testing.T, packet []byte) uint16 {
+	var icmpLayer layers.ICMPv4
+	require.NoError(t, icmpLayer.DecodeFromBytes(packet, gopacket.NilDecodeFeedback))
+	return icmpLayer.Checksum
+}
+
+func TestCreateRawICMPEcho(t *testing.T) {
+	header, packet, err := createRawICMPEcho(srcIP, dstIP, 4242, 1, 3, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, header.TTL)
+	assert.Equal(t, IPProtoICMP, header.Protocol)
+	assert.True(t, dstIP.Equal(header.Dst))
+
+	// In Paris mode, the checksum is the same for all the probes of a flow
+	parisChecksum := icmpChecksum(t, packet)
+	for seq := uint16(2); seq <= 30; seq++ {
+		_, packet, err := createRawICMPEcho(srcIP, dstIP, 4242, seq, int(seq), true)
+		require.NoError(t, err)
+		assert.Equal(t, parisChecksum, icmpChecksum(t, packet), "seq %d", seq)
+	}
+
+	// but it is different for another flow
+	_, packet, err = createRawICMPEcho(srcIP, dstIP, 4243, 1, 1, true)
+	require.NoError(t, err)
+	assert.NotEqual(t, parisChecksum, icmpChecksum(t, packet))
+
+	// Without Paris mode, the checksum changes with the sequence number
+	_, first, err := createRawICMPEcho(srcIP, dstIP, 4242, 1, 1, false)
+	require.NoError(t, err)
+	_, second, err := createRawICMPEcho(srcIP, dstIP, 4242, 2, 2, false)
+	require.NoError(t, err)
+	assert.NotEqual(t, icmpChecksum(t, first), icmpChecksum(t, second))
+}
+
+func createTimeExceeded(t *testing.T, from net.IP, id uint16, seq uint16) (*ipv4.Header, []byte) {
+	probeHeader, probePacket, err := createRawICMPEcho(srcIP, dstIP, id, seq, 1, true)
+	require.NoError(t, err)
+	probeHeader.TotalLen = 20 + len(probePacket)
+	innerIP, err := probeHeader.Marshal()
+	require.NoError(t, err)
+
+	icmpLayer := &layers.ICMPv4{
+		TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeTimeExceeded, layers.ICMPv4CodeTTLExceeded),
+	}
+	buf := gopacket.NewSerializeBuffer()
+	err = gopacket.SerializeLayers(buf, gopacket.SerializeOptions{ComputeChecksums: true},
+		icmpLayer,
+		gopacket.Payload(append(innerIP, probePacket[:8]...)),
+	)
+	require.NoError(t, err)
+
+	return &ipv4.Header{Version: 4, Protocol: IPProtoICMP, Src: from, Dst: srcIP}, buf.Bytes()
+}
+
+func createEchoReply(t *testing.T, id uint16, seq uint16) (*ipv4.Header, []byte) {
+	icmpLayer := &layers.ICMPv4{
+		TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoReply, 0),
+		Id:       id,
+		Seq:      seq,
+	}
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{ComputeChecksums: true},
+		icmpLayer,
+		gopacket.Payload(echoPayload(seq, true)),
+	)
+	require.NoError(t, err)
+
+	return &ipv4.Header{Version: 4, Protocol: IPProtoICMP, Src: dstIP, Dst: srcIP}, buf.Bytes()
+}
+
+func Test_parseICMP(t *testing.T) {
+	header, payload := createTimeExceeded(t, hopIP, 4242, 5)
+	response, err := parseICMP(header, payload)
+	require.NoError(t, err)
+	assert.True(t, hopIP.Equal(response.SrcIP))
+	assert.Equal(t, uint8(layers.ICMPv4TypeTimeExceeded), response.TypeCode.Type())
+	assert.True(t, srcIP.Equal(response.InnerSrcIP))
+	assert.True(t, dstIP.Equal(response.InnerDstIP))
+	assert.Equal(t, uint16(4242), response.InnerID)
+	assert.Equal(t, uint16(5), response.InnerSeq)
+	assert.True(t, icmpMatch(srcIP, dstIP, 4242, 5, response))
+	assert.False(t, icmpMatch(srcIP, dstIP, 4243, 5, response))
+
+	header, payload = createEchoReply(t, 4242, 7)
+	response, err = parseICMP(header, payload)
+	require.NoError(t, err)
+	assert.True(t, dstIP.Equal(response.SrcIP))
+	assert.True(t, icmpMatch(srcIP, dstIP, 4242, 7, response))
+	assert.False(t, icmpMatch(srcIP, dstIP, 4242, 6, response))
+
+	_, err = parseICMP(&ipv4.Header{Version: 4, Protocol: 6, Src: dstIP, Dst: srcIP}, payload)
+	assert.ErrorContains(t, err, "invalid IP header for ICMP packet")
+}
+
+func Test_listenPackets(t *testing.T) {
+	// matching response
+	header, payload := createTimeExceeded(t, hopIP, 4242, 5)
+	conn := &mockRawConn{header: header, payload: payload}
+	ip, typeCode, received, err := listenPackets(conn, 500*time.Millisecond, srcIP, dstIP, 4242, 5)
+	require.NoError(t, err)
+	assert.True(t, hopIP.Equal(ip))
+	assert.Equal(t, uint8(layers.ICMPv4TypeTimeExceeded), typeCode.Type())
+	assert.False(t, received.IsZero())
+
+	// no matching response before the timeout
+	conn = &mockRawConn{header: header, payload: payload}
+	ip, _, received, err = listenPackets(conn, 100*time.Millisecond, srcIP, dstIP, 4242, 6)
+	require.NoError(t, err)
+	assert.True(t, ip.Equal(net.IP{}))
+	assert.True(t, received.IsZero())
+	assert.Greater(t, conn.reads, 1)
+
+	// no response at all
+	conn = &mockRawConn{}
+	ip, _, _, err = listenPackets(conn, 100*time.Millisecond, srcIP, dstIP, 4242, 5)
+	require.NoError(t, err)
+	assert.True(t, ip.Equal(net.IP{}))
+}
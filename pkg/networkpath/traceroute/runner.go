@@ -24,6 +24,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/networkpath/payload"
+	"github.com/DataDog/datadog-agent/pkg/networkpath/traceroute/icmp"
 	"github.com/DataDog/datadog-agent/pkg/networkpath/traceroute/tcp"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
@@ -47,6 +48,11 @@ const (
 	DefaultDelay = 50 //msec
 	// DefaultOutputFormat defines the default output format
 	DefaultOutputFormat = "json"
+	// DefaultICMPIdentifier defines the ICMP echo identifier
+	// of the first flow in Paris mode
+	DefaultICMPIdentifier = 12345
+	// MaxNumPaths defines the maximum number of flows traced in a single test
+	MaxNumPaths = 16
 
 	tracerouteRunnerModuleName = "traceroute_runner__"
 )
@@ -155,6 +161,13 @@ func (r *Runner) RunTraceroute(ctx context.Context, cfg Config) (payload.Network
 			tracerouteRunnerTelemetry.failedRuns.Inc()
 			return payload.NetworkPath{}, err
 		}
+	case payload.ProtocolICMP:
+		log.Tracef("Running ICMP traceroute for: %+v", cfg)
+		pathResult, err = r.runICMP(cfg, hname, dest, maxTTL, timeout)
+		if err != nil {
+			tracerouteRunnerTelemetry.failedRuns.Inc()
+			return payload.NetworkPath{}, err
+		}
 	default:
 		log.Errorf("Invalid protocol for: %+v", cfg)
 		tracerouteRunnerTelemetry.failedRuns.Inc()
@@ -166,13 +179,16 @@ func (r *Runner) RunTraceroute(ctx context.Context, cfg Config) (payload.Network
 
 func (r *Runner) runUDP(cfg Config, hname string, dest net.IP, maxTTL uint8, timeout time.Duration) (payload.NetworkPath, error) {
 	destPort, srcPort, useSourcePort := getPorts(cfg.DestPort)
+	if cfg.ParisMode {
+		destPort, srcPort, useSourcePort = getParisPorts(cfg.DestPort)
+	}
 
 	dt := &probev4.UDPv4{
 		Target:     dest,
 		SrcPort:    srcPort,
 		DstPort:    destPort,
 		UseSrcPort: useSourcePort,
-		NumPaths:   uint16(DefaultNumPaths),
+		NumPaths:   getNumPaths(cfg.NumPaths),
 		MinTTL:     uint8(DefaultMinTTL), // TODO: what's a good value?
 		MaxTTL:     maxTTL,
 		Delay:      time.Duration(DefaultDelay) * time.Millisecond, // TODO: what's a good value?
@@ -200,31 +216,119 @@ func (r *Runner) runTCP(cfg Config, hname string, target net.IP, maxTTL uint8, t
 		destPort = 80 // TODO: is this the default we want?
 	}
 
-	tr := tcp.TCPv4{
-		Target:   target,
-		DestPort: destPort,
-		NumPaths: 1,
-		MinTTL:   uint8(DefaultMinTTL),
-		MaxTTL:   maxTTL,
-		Delay:    time.Duration(DefaultDelay) * time.Millisecond,
-		Timeout:  timeout,
+	numPaths := getNumPaths(cfg.NumPaths)
+	results := make([]*tcp.Results, 0, numPaths)
+	for i := uint16(0); i < numPaths; i++ {
+		// Each flow uses its own source port, in Paris mode the source
+		// ports are the same for every test, otherwise each flow binds
+		// an ephemeral port
+		var sourcePort uint16
+		if cfg.ParisMode {
+			sourcePort = DefaultSourcePort + i
+		}
+
+		tr := tcp.TCPv4{
+			Target:     target,
+			SourcePort: sourcePort,
+			DestPort:   destPort,
+			NumPaths:   1,
+			MinTTL:     uint8(DefaultMinTTL),
+			MaxTTL:     maxTTL,
+			Delay:      time.Duration(DefaultDelay) * time.Millisecond,
+			Timeout:    timeout,
+		}
+
+		flowResults, err := tr.TracerouteSequential()
+		if err != nil {
+			return payload.NetworkPath{}, err
+		}
+		results = append(results, flowResults)
 	}
 
-	results, err := tr.TracerouteSequential()
+	pathResult, err := r.processTCPResults(results, hname, cfg.DestHostname, destPort, target)
 	if err != nil {
 		return payload.NetworkPath{}, err
 	}
+	log.Tracef("TCP Results: %+v", pathResult)
 
-	pathResult, err := r.processTCPResults(results, hname, cfg.DestHostname, destPort, target)
+	return pathResult, nil
+}
+
+func (r *Runner) runICMP(cfg Config, hname string, target net.IP, maxTTL uint8, timeout time.Duration) (payload.NetworkPath, error) {
+	// Each flow uses its own echo identifier, in Paris mode the
+	// identifiers are the same for every test
+	identifier := uint16(rand.Intn(math.MaxUint16))
+	if cfg.ParisMode {
+		identifier = DefaultICMPIdentifier
+	}
+
+	numPaths := getNumPaths(cfg.NumPaths)
+	results := make([]*icmp.Results, 0, numPaths)
+	for i := uint16(0); i < numPaths; i++ {
+		tr := icmp.ICMPv4{
+			Target:    target,
+			ID:        identifier + i,
+			ParisMode: cfg.ParisMode,
+			MinTTL:    uint8(DefaultMinTTL),
+			MaxTTL:    maxTTL,
+			Timeout:   timeout,
+		}
+
+		flowResults, err := tr.TracerouteSequential()
+		if err != nil {
+			return payload.NetworkPath{}, err
+		}
+		results = append(results, flowResults)
+	}
+
+	pathResult, err := r.processICMPResults(results, hname, cfg.DestHostname, target)
 	if err != nil {
 		return payload.NetworkPath{}, err
 	}
-	log.Tracef("TCP Results: %+v", pathResult)
+	log.Tracef("ICMP Results: %+v", pathResult)
 
 	return pathResult, nil
 }
 
-func (r *Runner) processTCPResults(res *tcp.Results, hname string, destinationHost string, destinationPort uint16, destinationIP net.IP) (payload.NetworkPath, error) {
+func (r *Runner) processICMPResults(res []*icmp.Results, hname string, destinationHost string, destinationIP net.IP) (payload.NetworkPath, error) {
+	traceroutePath := payload.NetworkPath{
+		AgentVersion: version.AgentVersion,
+		PathtraceID:  payload.NewPathtraceID(),
+		Protocol:     payload.ProtocolICMP,
+		Timestamp:    time.Now().UnixMilli(),
+		Source: payload.NetworkPathSource{
+			Hostname:  hname,
+			NetworkID: r.networkID,
+		},
+		Destination: payload.NetworkPathDestination{
+			Hostname:           destinationHost,
+			IPAddress:          destinationIP.String(),
+			ReverseDNSHostname: getReverseDNSForIP(destinationIP),
+		},
+	}
+
+	flows := make([]flowHops, 0, len(res))
+	for _, flowResults := range res {
+		if r.gatewayLookup != nil {
+			src := util.AddressFromNetIP(flowResults.Source)
+			dst := util.AddressFromNetIP(flowResults.Target)
+
+			traceroutePath.Source.Via = r.gatewayLookup.LookupWithIPs(src, dst, r.nsIno)
+		}
+
+		flow := flowHops{flowID: flowResults.ID}
+		for i, hop := range flowResults.Hops {
+			flow.hops = append(flow.hops, newHop(i+1, hop.IP, hop.RTT))
+		}
+		flows = append(flows, flow)
+	}
+
+	traceroutePath.Hops = resolveHopHostnames(mergeFlowHops(flows))
+
+	return traceroutePath, nil
+}
+
+func (r *Runner) processTCPResults(res []*tcp.Results, hname string, destinationHost string, destinationPort uint16, destinationIP net.IP) (payload.NetworkPath, error) {
 	traceroutePath := payload.NetworkPath{
 		AgentVersion: version.AgentVersion,
 		PathtraceID:  payload.NewPathtraceID(),
@@ -251,35 +355,25 @@ func (r *Runner) processTCPResults(res *tcp.Results, hname string, destinationHo
 	// might be worth also looking in to sharing a router between
 	// the gateway lookup and here or exposing a local IP lookup
 	// function
-	if r.gatewayLookup != nil {
-		src := util.AddressFromNetIP(res.Source)
-		dst := util.AddressFromNetIP(res.Target)
-
-		traceroutePath.Source.Via = r.gatewayLookup.LookupWithIPs(src, dst, r.nsIno)
-	}
-
-	for i, hop := range res.Hops {
-		ttl := i + 1
-		isReachable := false
-		hopname := fmt.Sprintf("unknown_hop_%d", ttl)
-		hostname := hopname
+	flows := make([]flowHops, 0, len(res))
+	for _, flowResults := range res {
+		if r.gatewayLookup != nil {
+			src := util.AddressFromNetIP(flowResults.Source)
+			dst := util.AddressFromNetIP(flowResults.Target)
 
-		if !hop.IP.Equal(net.IP{}) {
-			isReachable = true
-			hopname = hop.IP.String()
-			hostname = getHostname(hop.IP.String())
+			traceroutePath.Source.Via = r.gatewayLookup.LookupWithIPs(src, dst, r.nsIno)
 		}
 
-		npHop := payload.NetworkPathHop{
-			TTL:       ttl,
-			IPAddress: hopname,
-			Hostname:  hostname,
-			RTT:       float64(hop.RTT.Microseconds()) / float64(1000),
-			Reachable: isReachable,
+		// the source port identifies the flow
+		flow := flowHops{flowID: flowResults.SourcePort}
+		for i, hop := range flowResults.Hops {
+			flow.hops = append(flow.hops, newHop(i+1, hop.IP, hop.RTT))
 		}
-		traceroutePath.Hops = append(traceroutePath.Hops, npHop)
+		flows = append(flows, flow)
 	}
 
+	traceroutePath.Hops = resolveHopHostnames(mergeFlowHops(flows))
+
 	return traceroutePath, nil
 }
 
@@ -306,6 +400,7 @@ func (r *Runner) processUDPResults(res *results.Results, hname string, destinati
 		},
 	}
 
+	flows := make([]flowHops, 0, len(res.Flows))
 	flowIDs := make([]int, 0, len(res.Flows))
 	for flowID := range res.Flows {
 		flowIDs = append(flowIDs, int(flowID))
@@ -346,6 +441,8 @@ func (r *Runner) processUDPResults(res *results.Results, hname string, destinati
 				break
 			}
 		}
+		flow := flowHops{flowID: uint16(flowID)}
+
 		// add edges
 		if len(nodes) <= 1 {
 			// no edges to add if there is only one node
@@ -381,14 +478,16 @@ func (r *Runner) processUDPResults(res *results.Results, hname string, destinati
 			hop := payload.NetworkPathHop{
 				TTL:       idx,
 				IPAddress: ip,
-				Hostname:  getHostname(cur.node),
 				RTT:       durationMs,
 				Reachable: isReachable,
 			}
-			traceroutePath.Hops = append(traceroutePath.Hops, hop)
+			flow.hops = append(flow.hops, hop)
 		}
+		flows = append(flows, flow)
 	}
 
+	traceroutePath.Hops = resolveHopHostnames(mergeFlowHops(flows))
+
 	return traceroutePath, nil
 }
 
@@ -409,6 +508,24 @@ func getPorts(configDestPort uint16) (uint16, uint16, bool) {
 	return destPort, srcPort, useSourcePort
 }
 
+// getParisPorts returns the ports used in Paris mode, they are
+// the same for every test so that the traced flows are stable
+func getParisPorts(configDestPort uint16) (uint16, uint16, bool) {
+	destPort := configDestPort
+	if destPort == 0 {
+		destPort = DefaultDestPort
+	}
+	// flows are identified by their source port
+	return destPort, DefaultSourcePort, true
+}
+
+func getNumPaths(configNumPaths uint16) uint16 {
+	if configNumPaths == 0 {
+		return DefaultNumPaths
+	}
+	return min(configNumPaths, MaxNumPaths)
+}
+
 func createGatewayLookup(telemetryComp telemetryComponent.Component) (network.GatewayLookup, uint32, error) {
 	rootNs, err := rootNsLookup()
 	if err != nil {
@@ -22,3 +22,21 @@ func TestGetPorts(t *testing.T) {
 	assert.GreaterOrEqual(t, sourcePort, uint16(DefaultSourcePort))
 	assert.True(t, useSourcePort)
 }
+
+func TestGetParisPorts(t *testing.T) {
+	destPort, sourcePort, useSourcePort := getParisPorts(0)
+	assert.Equal(t, uint16(DefaultDestPort), destPort)
+	assert.Equal(t, uint16(DefaultSourcePort), sourcePort)
+	assert.True(t, useSourcePort)
+
+	destPort, sourcePort, useSourcePort = getParisPorts(53)
+	assert.Equal(t, uint16(53), destPort)
+	assert.Equal(t, uint16(DefaultSourcePort), sourcePort)
+	assert.True(t, useSourcePort)
+}
+
+func TestGetNumPaths(t *testing.T) {
+	assert.Equal(t, uint16(DefaultNumPaths), getNumPaths(0))
+	assert.Equal(t, uint16(4), getNumPaths(4))
+	assert.Equal(t, uint16(MaxNumPaths), getNumPaths(100))
+}
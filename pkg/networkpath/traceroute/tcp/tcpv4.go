@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"strconv"
 	"time"
 
 	"golang.org/x/net/ipv4"
@@ -22,15 +23,19 @@ type (
 	// TCPv4 encapsulates the data needed to run
 	// a TCPv4 traceroute
 	TCPv4 struct {
-		Target   net.IP
-		srcIP    net.IP // calculated internally
-		srcPort  uint16 // calculated internally
-		DestPort uint16
-		NumPaths uint16
-		MinTTL   uint8
-		MaxTTL   uint8
-		Delay    time.Duration // delay between sending packets (not applicable if we go the serial send/receive route)
-		Timeout  time.Duration // full timeout for all packets
+		Target  net.IP
+		srcIP   net.IP // calculated internally
+		srcPort uint16 // calculated internally
+		// SourcePort is the source port to use, an ephemeral
+		// port is bound for the duration of the traceroute if
+		// not set
+		SourcePort uint16
+		DestPort   uint16
+		NumPaths   uint16
+		MinTTL     uint8
+		MaxTTL     uint8
+		Delay      time.Duration // delay between sending packets (not applicable if we go the serial send/receive route)
+		Timeout    time.Duration // full timeout for all packets
 	}
 
 	// Results encapsulates a response from the TCP
@@ -67,7 +72,17 @@ func (t *TCPv4) TracerouteSequential() (*Results, error) {
 		return nil, fmt.Errorf("failed to get local address for target: %w", err)
 	}
 	t.srcIP = addr.IP
-	t.srcPort = addr.AddrPort().Port()
+	t.srcPort = t.SourcePort
+	if t.srcPort == 0 {
+		// The port is bound so that the kernel doesn't assign it
+		// to another connection while the probes are in flight
+		portListener, port, err := reserveLocalPort(t.srcIP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind a source port: %w", err)
+		}
+		defer portListener.Close()
+		t.srcPort = port
+	}
 
 	// So far I haven't had success trying to simply create a socket
 	// using syscalls directly, but in theory doing so would allow us
@@ -93,7 +108,7 @@ func (t *TCPv4) TracerouteSequential() (*Results, error) {
 		return nil, fmt.Errorf("failed to create TCP listener: %w", err)
 	}
 	defer tcpConn.Close()
-	log.Tracef("Listening for TCP on: %s\n", addr.IP.String()+":"+addr.AddrPort().String())
+	log.Tracef("Listening for TCP on: %s\n", net.JoinHostPort(addr.IP.String(), strconv.Itoa(int(t.srcPort))))
 	// RawConn is necessary to set the TTL and ID fields
 	rawTCPConn, err := ipv4.NewRawConn(tcpConn)
 	if err != nil {
@@ -86,6 +86,23 @@ func localAddrForHost(destIP net.IP, destPort uint16) (*net.UDPAddr, error) {
 	return localUDPAddr, nil
 }
 
+// reserveLocalPort binds an ephemeral TCP port on the given local
+// address, the port stays reserved until the listener is closed
+func reserveLocalPort(srcIP net.IP) (net.Listener, uint16, error) {
+	listener, err := net.Listen("tcp4", net.JoinHostPort(srcIP.String(), "0"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tcpAddr, ok := listener.Addr().(*net.TCPAddr)
+	if !ok {
+		listener.Close()
+		return nil, 0, fmt.Errorf("invalid address type for %s: want %T, got %T", listener.Addr(), tcpAddr, listener.Addr())
+	}
+
+	return listener, uint16(tcpAddr.Port), nil
+}
+
 // createRawTCPSyn creates a TCP packet with the specified parameters
 func createRawTCPSyn(sourceIP net.IP, sourcePort uint16, destIP net.IP, destPort uint16, seqNum uint32, ttl int) (*ipv4.Header, []byte, error) {
 	ipLayer := &layers.IPv4{
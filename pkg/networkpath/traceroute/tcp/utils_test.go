@@ -472,3 +472,18 @@ func structFieldCount(v interface{}) int {
 
 	return val.NumField()
 }
+
+func TestReserveLocalPort(t *testing.T) {
+	listener, port, err := reserveLocalPort(net.ParseIP("127.0.0.1"))
+	require.NoError(t, err)
+	assert.NotZero(t, port)
+
+	// the port can't be bound by another socket until it's released
+	_, err = net.Listen("tcp4", listener.Addr().String())
+	assert.Error(t, err)
+
+	require.NoError(t, listener.Close())
+	other, err := net.Listen("tcp4", listener.Addr().String())
+	require.NoError(t, err)
+	other.Close()
+}
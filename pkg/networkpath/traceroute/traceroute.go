@@ -35,6 +35,13 @@ type (
 		// Protocol is the protocol to use
 		// for traceroute, default is UDP
 		Protocol payload.Protocol
+		// NumPaths is the number of flows traced for each
+		// test, more than one flow allows discovering ECMP paths
+		NumPaths uint16
+		// ParisMode keeps the flow identifiers (ports, ICMP
+		// identifier and checksum) constant between tests so
+		// that the same paths are traced on load balanced networks
+		ParisMode bool
 	}
 
 	// Traceroute defines an interface for running
@@ -8,11 +8,21 @@ package traceroute
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/networkpath/payload"
 )
 
+// flowHops holds the hops discovered by a single flow of a traceroute
+type flowHops struct {
+	flowID uint16
+	hops   []payload.NetworkPathHop
+}
+
 var lookupAddrFn = net.DefaultResolver.LookupAddr
 
 func getReverseDNSForIP(destIP net.IP) string {
@@ -41,3 +51,88 @@ func getHostname(ipAddr string) string {
 	currHost = strings.TrimRight(currHost, ".")
 	return currHost
 }
+
+// newHop builds the hop for a TTL, an empty IP means no response was received
+func newHop(ttl int, ip net.IP, rtt time.Duration) payload.NetworkPathHop {
+	if ip.Equal(net.IP{}) {
+		return payload.NetworkPathHop{
+			TTL:       ttl,
+			IPAddress: fmt.Sprintf("unknown_hop_%d", ttl),
+		}
+	}
+
+	return payload.NetworkPathHop{
+		TTL:       ttl,
+		IPAddress: ip.String(),
+		RTT:       float64(rtt.Microseconds()) / float64(1000),
+		Reachable: true,
+	}
+}
+
+// mergeFlowHops merges the hops discovered by several flows, a TTL can then have
+// several hops when flows are load balanced on different paths (ECMP).
+// Hops seen by several flows are merged, keeping the lowest RTT, and an unreachable
+// hop is only kept if no flow received a response for this TTL.
+func mergeFlowHops(flows []flowHops) []payload.NetworkPathHop {
+	if len(flows) == 0 {
+		return nil
+	}
+	if len(flows) == 1 {
+		return flows[0].hops
+	}
+
+	type hopKey struct {
+		ttl int
+		ip  string
+	}
+
+	var merged []payload.NetworkPathHop
+	hopIndexes := make(map[hopKey]int)
+	reachableTTLs := make(map[int]struct{})
+	for _, flow := range flows {
+		for _, hop := range flow.hops {
+			if hop.Reachable {
+				reachableTTLs[hop.TTL] = struct{}{}
+			}
+
+			key := hopKey{ttl: hop.TTL, ip: hop.IPAddress}
+			if idx, found := hopIndexes[key]; found {
+				merged[idx].FlowIDs = append(merged[idx].FlowIDs, flow.flowID)
+				if hop.Reachable && hop.RTT < merged[idx].RTT {
+					merged[idx].RTT = hop.RTT
+				}
+				continue
+			}
+
+			hop.FlowIDs = []uint16{flow.flowID}
+			hopIndexes[key] = len(merged)
+			merged = append(merged, hop)
+		}
+	}
+
+	hops := make([]payload.NetworkPathHop, 0, len(merged))
+	for _, hop := range merged {
+		if _, found := reachableTTLs[hop.TTL]; found && !hop.Reachable {
+			continue
+		}
+		hops = append(hops, hop)
+	}
+	sort.SliceStable(hops, func(i, j int) bool {
+		return hops[i].TTL < hops[j].TTL
+	})
+
+	return hops
+}
+
+// resolveHopHostnames sets the reverse DNS of reachable hops,
+// lookups are done once per hop after flows are merged
+func resolveHopHostnames(hops []payload.NetworkPathHop) []payload.NetworkPathHop {
+	for i := range hops {
+		if !hops[i].Reachable {
+			hops[i].Hostname = hops[i].IPAddress
+			continue
+		}
+		hops[i].Hostname = getHostname(hops[i].IPAddress)
+	}
+	return hops
+}
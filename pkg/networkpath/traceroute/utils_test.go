@@ -10,8 +10,11 @@ import (
 	"errors"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/networkpath/payload"
 )
 
 func Test_getReverseDnsForIP(t *testing.T) {
@@ -53,3 +56,76 @@ func Test_getHostname(t *testing.T) {
 		assert.Equal(t, "1.2.3.4", getHostname("1.2.3.4"))
 	})
 }
+
+func Test_newHop(t *testing.T) {
+	assert.Equal(t, payload.NetworkPathHop{
+		TTL:       2,
+		IPAddress: "10.0.0.1",
+		RTT:       1.5,
+		Reachable: true,
+	}, newHop(2, net.ParseIP("10.0.0.1"), 1500*time.Microsecond))
+
+	assert.Equal(t, payload.NetworkPathHop{
+		TTL:       3,
+		IPAddress: "unknown_hop_3",
+	}, newHop(3, net.IP{}, 0))
+}
+
+func Test_mergeFlowHops(t *testing.T) {
+	assert.Nil(t, mergeFlowHops(nil))
+
+	// a single flow is returned as is
+	singleFlow := []payload.NetworkPathHop{
+		{TTL: 1, IPAddress: "10.0.0.1", RTT: 1, Reachable: true},
+		{TTL: 2, IPAddress: "unknown_hop_2"},
+	}
+	assert.Equal(t, singleFlow, mergeFlowHops([]flowHops{{flowID: 1, hops: singleFlow}}))
+
+	// flows load balanced on two paths at TTL 2
+	flows := []flowHops{
+		{
+			flowID: 12345,
+			hops: []payload.NetworkPathHop{
+				{TTL: 1, IPAddress: "10.0.0.1", RTT: 2, Reachable: true},
+				{TTL: 2, IPAddress: "10.1.0.1", RTT: 5, Reachable: true},
+				{TTL: 3, IPAddress: "unknown_hop_3"},
+				{TTL: 4, IPAddress: "8.8.8.8", RTT: 10, Reachable: true},
+			},
+		},
+		{
+			flowID: 12346,
+			hops: []payload.NetworkPathHop{
+				{TTL: 1, IPAddress: "10.0.0.1", RTT: 1, Reachable: true},
+				{TTL: 2, IPAddress: "10.2.0.1", RTT: 6, Reachable: true},
+				{TTL: 3, IPAddress: "10.3.0.1", RTT: 8, Reachable: true},
+				{TTL: 4, IPAddress: "8.8.8.8", RTT: 12, Reachable: true},
+			},
+		},
+	}
+
+	expected := []payload.NetworkPathHop{
+		{TTL: 1, IPAddress: "10.0.0.1", RTT: 1, Reachable: true, FlowIDs: []uint16{12345, 12346}},
+		{TTL: 2, IPAddress: "10.1.0.1", RTT: 5, Reachable: true, FlowIDs: []uint16{12345}},
+		{TTL: 2, IPAddress: "10.2.0.1", RTT: 6, Reachable: true, FlowIDs: []uint16{12346}},
+		{TTL: 3, IPAddress: "10.3.0.1", RTT: 8, Reachable: true, FlowIDs: []uint16{12346}},
+		{TTL: 4, IPAddress: "8.8.8.8", RTT: 10, Reachable: true, FlowIDs: []uint16{12345, 12346}},
+	}
+	assert.Equal(t, expected, mergeFlowHops(flows))
+}
+
+func Test_resolveHopHostnames(t *testing.T) {
+	defer func() { lookupAddrFn = net.DefaultResolver.LookupAddr }()
+	lookupAddrFn = func(_ context.Context, addr string) ([]string, error) {
+		if addr == "10.0.0.1" {
+			return []string{"router.local."}, nil
+		}
+		return nil, errors.New("unexpected lookup")
+	}
+
+	hops := resolveHopHostnames([]payload.NetworkPathHop{
+		{TTL: 1, IPAddress: "10.0.0.1", Reachable: true},
+		{TTL: 2, IPAddress: "unknown_hop_2"},
+	})
+	assert.Equal(t, "router.local", hops[0].Hostname)
+	assert.Equal(t, "unknown_hop_2", hops[1].Hostname)
+}
@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// AgentPipelineRulesConfig is a deserialized agent pipeline rules configuration file
+// along with the associated metadata
+type AgentPipelineRulesConfig struct {
+	Config   AgentPipelineRulesData
+	Metadata Metadata
+}
+
+// AgentPipelineRulesData is the content of an agent pipeline rules configuration file.
+// A file carries either DogStatsD mapper profiles or logs processing rules, so that the
+// component applying it is the only one reporting its apply status.
+type AgentPipelineRulesData struct {
+	// DogstatsdMapperProfiles has the format of the dogstatsd_mapper_profiles setting
+	DogstatsdMapperProfiles json.RawMessage `json:"dogstatsd_mapper_profiles,omitempty"`
+	// LogsProcessingRules has the format of the logs_config.processing_rules setting
+	LogsProcessingRules json.RawMessage `json:"logs_processing_rules,omitempty"`
+}
+
+// ParseConfigAgentPipelineRules parses an agent pipeline rules config
+func ParseConfigAgentPipelineRules(data []byte, metadata Metadata) (AgentPipelineRulesConfig, error) {
+	var d AgentPipelineRulesData
+
+	err := json.Unmarshal(data, &d)
+	if err != nil {
+		return AgentPipelineRulesConfig{}, fmt.Errorf("Unexpected AGENT_PIPELINE_RULES received through remote-config: %s", err)
+	}
+
+	hasProfiles := len(d.DogstatsdMapperProfiles) > 0
+	hasRules := len(d.LogsProcessingRules) > 0
+	if hasProfiles == hasRules {
+		return AgentPipelineRulesConfig{}, errors.New("AGENT_PIPELINE_RULES configurations must contain either dogstatsd_mapper_profiles or logs_processing_rules")
+	}
+
+	return AgentPipelineRulesConfig{
+		Config:   d,
+		Metadata: metadata,
+	}, nil
+}
+
+// ApplyAgentPipelineRules calls apply, in the order of their path, for every AGENT_PIPELINE_RULES
+// update whose section returned by getSection is set, and reports their apply status: acknowledged
+// when apply succeeds, in error otherwise. Updates that can't be parsed are reported in error,
+// updates for other sections are left untouched.
+func ApplyAgentPipelineRules(updates map[string]RawConfig, applyStatus func(cfgPath string, status ApplyStatus), getSection func(AgentPipelineRulesData) json.RawMessage, apply func(cfgPath string, section json.RawMessage, metadata Metadata) error) {
+	paths := make([]string, 0, len(updates))
+	for path := range updates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		cfg, err := ParseConfigAgentPipelineRules(updates[path].Config, updates[path].Metadata)
+		if err == nil {
+			section := getSection(cfg.Config)
+			if len(section) == 0 {
+				continue
+			}
+			err = apply(path, section, cfg.Metadata)
+		}
+
+		if err != nil {
+			applyStatus(path, ApplyStatus{
+				State: ApplyStateError,
+				Error: err.Error(),
+			})
+			continue
+		}
+		applyStatus(path, ApplyStatus{State: ApplyStateAcknowledged})
+	}
+}
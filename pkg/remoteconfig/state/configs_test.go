@@ -7,6 +7,7 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -112,3 +113,52 @@ func TestASMData(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyAgentPipelineRules(t *testing.T) {
+	updates := map[string]RawConfig{
+		"datadog/2/AGENT_PIPELINE_RULES/mapper/config": {
+			Config:   []byte(`{"dogstatsd_mapper_profiles":[{"name":"airflow","prefix":"airflow."}]}`),
+			Metadata: Metadata{Version: 3},
+		},
+		"datadog/2/AGENT_PIPELINE_RULES/logs/config": {
+			Config: []byte(`{"logs_processing_rules":[{"type":"exclude_at_match","name":"health","pattern":"health"}]}`),
+		},
+		"datadog/2/AGENT_PIPELINE_RULES/both/config": {
+			Config: []byte(`{"dogstatsd_mapper_profiles":[],"logs_processing_rules":[]}`),
+		},
+		"datadog/2/AGENT_PIPELINE_RULES/invalid/config": {
+			Config: []byte(`{"dogstatsd_mapper_profiles":[`),
+		},
+		"datadog/2/AGENT_PIPELINE_RULES/rejected/config": {
+			Config: []byte(`{"dogstatsd_mapper_profiles":[{"name":"rejected"}]}`),
+		},
+	}
+
+	statuses := map[string]ApplyStatus{}
+	var applied []string
+	ApplyAgentPipelineRules(updates,
+		func(cfgPath string, status ApplyStatus) { statuses[cfgPath] = status },
+		func(d AgentPipelineRulesData) json.RawMessage { return d.DogstatsdMapperProfiles },
+		func(cfgPath string, section json.RawMessage, metadata Metadata) error {
+			applied = append(applied, cfgPath)
+			if cfgPath == "datadog/2/AGENT_PIPELINE_RULES/rejected/config" {
+				return errors.New("missing prefix")
+			}
+			require.JSONEq(t, `[{"name":"airflow","prefix":"airflow."}]`, string(section))
+			require.Equal(t, uint64(3), metadata.Version)
+			return nil
+		},
+	)
+
+	require.Equal(t, []string{
+		"datadog/2/AGENT_PIPELINE_RULES/mapper/config",
+		"datadog/2/AGENT_PIPELINE_RULES/rejected/config",
+	}, applied)
+
+	require.Len(t, statuses, 4)
+	require.Equal(t, ApplyStateAcknowledged, statuses["datadog/2/AGENT_PIPELINE_RULES/mapper/config"].State)
+	require.Equal(t, ApplyStatus{State: ApplyStateError, Error: "missing prefix"}, statuses["datadog/2/AGENT_PIPELINE_RULES/rejected/config"])
+	require.Equal(t, ApplyStateError, statuses["datadog/2/AGENT_PIPELINE_RULES/invalid/config"].State)
+	require.Equal(t, ApplyStateError, statuses["datadog/2/AGENT_PIPELINE_RULES/both/config"].State)
+	require.NotContains(t, statuses, "datadog/2/AGENT_PIPELINE_RULES/logs/config")
+}
@@ -12,6 +12,7 @@ var validProducts = map[string]struct{}{
 	ProductAgentConfig:                  {},
 	ProductAgentFailover:                {},
 	ProductAgentTask:                    {},
+	ProductAgentPipelineRules:           {},
 	ProductAgentIntegrations:            {},
 	ProductAPMSampling:                  {},
 	ProductCWSDD:                        {},
@@ -48,6 +49,8 @@ const (
 	ProductAgentIntegrations = "AGENT_INTEGRATIONS"
 	// ProductAgentTask is to receive agent task instruction, like a flare
 	ProductAgentTask = "AGENT_TASK"
+	// ProductAgentPipelineRules is to receive DogStatsD mapper profiles and logs processing rules
+	ProductAgentPipelineRules = "AGENT_PIPELINE_RULES"
 	// ProductAPMSampling is the apm sampling product
 	ProductAPMSampling = "APM_SAMPLING"
 	// ProductCWSDD is the cloud workload security product managed by datadog employees
@@ -25,6 +25,13 @@ type StartupError struct {
 	Timestamp int64
 }
 
+// ProcessStatus holds the status of a JMXFetch process when JMX checks run in several processes
+type ProcessStatus struct {
+	Status       Status       `json:"status"`
+	StartupError StartupError `json:"startup_error"`
+	Restarts     int          `json:"restarts"`
+}
+
 // GetStartupError retrieves latest JMX startup error
 func GetStartupError() StartupError {
 	lastJMXStartupErrorMutex.RLock()
@@ -37,6 +44,22 @@ func GetStartupError() StartupError {
 func PopulateStatus(stats map[string]interface{}) {
 	stats["JMXStatus"] = getJMXStatus()
 	stats["JMXStartupError"] = GetStartupError()
+	if processes := GetProcessesStatus(); len(processes) > 0 {
+		stats["JMXProcesses"] = processes
+	}
+}
+
+// GetProcessesStatus returns the status of the JMXFetch processes by process name, it is empty when JMX checks run
+// in a single process
+func GetProcessesStatus() map[string]ProcessStatus {
+	processesStatusMutex.RLock()
+	defer processesStatusMutex.RUnlock()
+
+	processes := make(map[string]ProcessStatus, len(processesStatus))
+	for name, p := range processesStatus {
+		processes[name] = p
+	}
+	return processes
 }
 
 func getJMXStatus() Status {
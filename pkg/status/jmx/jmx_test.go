@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package jmx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessesStatus(t *testing.T) {
+	stats := map[string]interface{}{}
+	PopulateStatus(stats)
+	assert.NotContains(t, stats, "JMXProcesses")
+
+	SetProcessStatus("kafka", Status{ChecksStatus: jmxCheckStatus{InitializedChecks: map[string]interface{}{"kafka": []interface{}{}}}})
+	SetProcessStartupError("kafka", StartupError{LastError: "java not found", Timestamp: 1})
+	IncrementProcessRestarts("kafka")
+	IncrementProcessRestarts("kafka")
+	t.Cleanup(func() { RemoveProcess("kafka") })
+
+	processes := GetProcessesStatus()
+	require.Contains(t, processes, "kafka")
+	assert.Equal(t, 2, processes["kafka"].Restarts)
+	assert.Equal(t, "java not found", processes["kafka"].StartupError.LastError)
+
+	var b bytes.Buffer
+	require.NoError(t, Provider{}.Text(false, &b))
+	assert.Contains(t, b.String(), "JMXFetch processes")
+	assert.Contains(t, b.String(), "restarts: 2")
+	assert.Contains(t, b.String(), "startup error: java not found")
+	assert.Contains(t, b.String(), "initialized checks: kafka")
+
+	b.Reset()
+	require.NoError(t, Provider{}.HTML(false, &b))
+	assert.Contains(t, b.String(), "Process kafka")
+
+	RemoveProcess("kafka")
+	assert.Empty(t, GetProcessesStatus())
+}
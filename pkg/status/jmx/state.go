@@ -13,6 +13,10 @@ var (
 	lastJMXStatusMutex       sync.RWMutex
 	lastJMXStartupError      StartupError
 	lastJMXStartupErrorMutex sync.RWMutex
+
+	// processesStatus holds the status of the JMXFetch processes when JMX checks run in several processes
+	processesStatus      = map[string]ProcessStatus{}
+	processesStatusMutex sync.RWMutex
 )
 
 // SetStatus sets the last JMX Status
@@ -30,3 +34,41 @@ func SetStartupError(s StartupError) {
 
 	lastJMXStartupError = s
 }
+
+// SetProcessStatus sets the last status reported by a JMXFetch process
+func SetProcessStatus(process string, s Status) {
+	processesStatusMutex.Lock()
+	defer processesStatusMutex.Unlock()
+
+	p := processesStatus[process]
+	p.Status = s
+	processesStatus[process] = p
+}
+
+// SetProcessStartupError sets the last startup error of a JMXFetch process
+func SetProcessStartupError(process string, s StartupError) {
+	processesStatusMutex.Lock()
+	defer processesStatusMutex.Unlock()
+
+	p := processesStatus[process]
+	p.StartupError = s
+	processesStatus[process] = p
+}
+
+// IncrementProcessRestarts counts a restart of a JMXFetch process
+func IncrementProcessRestarts(process string) {
+	processesStatusMutex.Lock()
+	defer processesStatusMutex.Unlock()
+
+	p := processesStatus[process]
+	p.Restarts++
+	processesStatus[process] = p
+}
+
+// RemoveProcess removes the status of a JMXFetch process that was stopped
+func RemoveProcess(process string) {
+	processesStatusMutex.Lock()
+	defer processesStatusMutex.Unlock()
+
+	delete(processesStatus, process)
+}
@@ -21,9 +21,11 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/trace/event"
 	"github.com/DataDog/datadog-agent/pkg/trace/filters"
 	"github.com/DataDog/datadog-agent/pkg/trace/info"
+	"github.com/DataDog/datadog-agent/pkg/trace/inspector"
 	"github.com/DataDog/datadog-agent/pkg/trace/log"
 	"github.com/DataDog/datadog-agent/pkg/trace/remoteconfighandler"
 	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+	"github.com/DataDog/datadog-agent/pkg/trace/spanmetrics"
 	"github.com/DataDog/datadog-agent/pkg/trace/stats"
 	"github.com/DataDog/datadog-agent/pkg/trace/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/trace/timing"
@@ -81,6 +83,7 @@ type Concentrator interface {
 type Agent struct {
 	Receiver              *api.HTTPReceiver
 	OTLPReceiver          *api.OTLPReceiver
+	JaegerReceiver        *api.JaegerReceiver
 	Concentrator          Concentrator
 	ClientStatsAggregator *stats.ClientStatsAggregator
 	Blacklister           *filters.Blacklister
@@ -91,6 +94,8 @@ type Agent struct {
 	NoPrioritySampler     *sampler.NoPrioritySampler
 	ProbabilisticSampler  *sampler.ProbabilisticSampler
 	EventProcessor        *event.Processor
+	SpanMetrics           *spanmetrics.Generator // set by the caller, see the spanmetrics package
+	TraceInspector        *inspector.Inspector
 	TraceWriter           TraceWriter
 	StatsWriter           *writer.DatadogStatsWriter
 	RemoteConfigHandler   *remoteconfighandler.RemoteConfigHandler
@@ -163,7 +168,14 @@ func NewAgent(ctx context.Context, conf *config.AgentConfig, telemetryCollector
 	}
 	agnt.Receiver = api.NewHTTPReceiver(conf, dynConf, in, agnt, telemetryCollector, statsd, timing)
 	agnt.OTLPReceiver = api.NewOTLPReceiver(in, conf, statsd, timing)
+	agnt.Receiver.SetOTLPReceiver(agnt.OTLPReceiver)
+	agnt.JaegerReceiver = api.NewJaegerReceiver(agnt.OTLPReceiver, conf, statsd, timing)
 	agnt.RemoteConfigHandler = remoteconfighandler.New(conf, agnt.PrioritySampler, agnt.RareSampler, agnt.ErrorsSampler)
+	if conf.TraceInspection.Enabled {
+		agnt.TraceInspector = inspector.New(conf.TraceInspection.BufferSize)
+		agnt.DebugServer.AddRoute(inspector.RoutePrefix, agnt.TraceInspector.Handler())
+		agnt.DebugServer.AddRoute(inspector.RoutePrefix+"/", agnt.TraceInspector.Handler())
+	}
 	agnt.TraceWriter = writer.NewTraceWriter(conf, agnt.PrioritySampler, agnt.ErrorsSampler, agnt.RareSampler, telemetryCollector, statsd, timing, comp)
 	return agnt
 }
@@ -182,6 +194,7 @@ func (a *Agent) Run() {
 		a.ProbabilisticSampler,
 		a.EventProcessor,
 		a.OTLPReceiver,
+		a.JaegerReceiver,
 		a.RemoteConfigHandler,
 		a.DebugServer,
 	} {
@@ -241,6 +254,7 @@ func (a *Agent) loop() {
 	log.Info("Exiting...")
 
 	a.OTLPReceiver.Stop() // Stop OTLPReceiver before Receiver to avoid sending to closed channel
+	a.JaegerReceiver.Stop()
 	if err := a.Receiver.Stop(); err != nil {
 		log.Error(err)
 	}
@@ -344,6 +358,7 @@ func (a *Agent) Process(p *api.Payload) {
 		}
 
 		// Extra sanitization steps of the trace.
+		obfuscated := 0
 		for _, span := range chunk.Spans {
 			for k, v := range a.conf.GlobalTags {
 				if k == tagOrigin {
@@ -355,7 +370,13 @@ func (a *Agent) Process(p *api.Payload) {
 			if a.SpanModifier != nil {
 				a.SpanModifier.ModifySpan(chunk, span)
 			}
-			a.obfuscateSpan(span)
+			if a.TraceInspector != nil {
+				if a.obfuscateSpanInspected(span) {
+					obfuscated++
+				}
+			} else {
+				a.obfuscateSpan(span)
+			}
 			a.Truncate(span)
 			if p.ClientComputedTopLevel {
 				traceutil.UpdateTracerTopLevel(span)
@@ -376,8 +397,19 @@ func (a *Agent) Process(p *api.Payload) {
 		if !p.ClientComputedStats {
 			statsInput.Traces = append(statsInput.Traces, *pt.Clone())
 		}
+		// Span metrics are generated before sampling to account for all the spans.
+		a.SpanMetrics.Process(pt.TraceChunk, pt.Root)
+
+		var inspected *inspector.Trace
+		if a.TraceInspector != nil {
+			inspected = inspector.NewTrace(pt.TraceChunk, root, pt.TracerEnv)
+			inspected.Sampling.ObfuscatedSpans = obfuscated
+		}
 
-		keep, numEvents := a.sample(now, ts, pt)
+		keep, numEvents, by := a.sample(now, ts, pt)
+		if inspected != nil {
+			a.inspectTrace(inspected, pt, keep, by)
+		}
 		if !keep && len(pt.TraceChunk.Spans) == 0 {
 			// The entire trace was dropped and no spans were kept.
 			p.RemoveChunk(i)
@@ -546,12 +578,13 @@ func (a *Agent) ProcessStats(in *pb.ClientStatsPayload, lang, tracerVersion stri
 	a.ClientStatsAggregator.In <- a.processStats(in, lang, tracerVersion)
 }
 
-// sample performs all sampling on the processedTrace modifying it as needed and returning if the trace should be kept and the number of events in the trace
-func (a *Agent) sample(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, numEvents int) {
+// sample performs all sampling on the processedTrace modifying it as needed and returning if the trace should be kept,
+// the number of events in the trace and the sampler which made the decision
+func (a *Agent) sample(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, numEvents int, by string) {
 	// We have a `keep` that is different from pt's `DroppedTrace` field as `DroppedTrace` will be sent to intake.
 	// For example: We want to maintain the overall trace level sampling decision for a trace with Analytics Events
 	// where a trace might be marked as DroppedTrace true, but we still sent analytics events in that ProcessedTrace.
-	keep, checkAnalyticsEvents := a.traceSampling(now, ts, pt)
+	keep, checkAnalyticsEvents, by := a.traceSampling(now, ts, pt)
 
 	var events []*pb.Span
 	if checkAnalyticsEvents {
@@ -569,7 +602,7 @@ func (a *Agent) sample(now time.Time, ts *info.TagStats, pt *traceutil.Processed
 		}
 	}
 
-	return keep, len(events)
+	return keep, len(events), by
 }
 
 // isManualUserDrop returns true if and only if the ProcessedTrace is marked as Priority User Drop
@@ -591,10 +624,10 @@ func isManualUserDrop(pt *traceutil.ProcessedTrace) bool {
 }
 
 // traceSampling reports whether the chunk should be kept as a trace, setting "DroppedTrace" on the chunk
-func (a *Agent) traceSampling(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool) {
-	sampled, check := a.runSamplers(now, ts, *pt)
+func (a *Agent) traceSampling(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool, by string) {
+	sampled, check, by := a.runSamplers(now, ts, *pt)
 	pt.TraceChunk.DroppedTrace = !sampled
-	return sampled, check
+	return sampled, check, by
 }
 
 // getAnalyzedEvents returns any sampled analytics events in the ProcessedTrace
@@ -606,29 +639,29 @@ func (a *Agent) getAnalyzedEvents(pt *traceutil.ProcessedTrace, ts *info.TagStat
 }
 
 // runSamplers runs the agent's configured samplers on pt and returns the sampling decision along
-// with the sampling rate.
+// with the sampler which made it.
 //
 // The rare sampler is run first, catching all rare traces early. If the probabilistic sampler is
 // enabled, it is run on the trace, followed by the error sampler. Otherwise, If the trace has a
 // priority set, the sampling priority is used with the Priority Sampler. When there is no priority
 // set, the NoPrioritySampler is run. Finally, if the trace has not been sampled by the other
 // samplers, the error sampler is run.
-func (a *Agent) runSamplers(now time.Time, ts *info.TagStats, pt traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool) {
+func (a *Agent) runSamplers(now time.Time, ts *info.TagStats, pt traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool, by string) {
 	// run this early to make sure the signature gets counted by the RareSampler.
 	rare := a.RareSampler.Sample(now, pt.TraceChunk, pt.TracerEnv)
 
 	if a.conf.ProbabilisticSamplerEnabled {
 		if rare {
-			return true, true
+			return true, true, samplerRare
 		}
 		if a.ProbabilisticSampler.Sample(pt.Root) {
 			pt.TraceChunk.Tags[tagDecisionMaker] = probabilitySampling
-			return true, true
+			return true, true, samplerProbabilistic
 		}
 		if traceContainsError(pt.TraceChunk.Spans) {
-			return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true
+			return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true, samplerErrors
 		}
-		return false, true
+		return false, true, samplerProbabilistic
 	}
 
 	priority, hasPriority := sampler.GetSamplingPriority(pt.TraceChunk)
@@ -642,31 +675,34 @@ func (a *Agent) runSamplers(now time.Time, ts *info.TagStats, pt traceutil.Proce
 		// Note that we DON'T skip single span sampling. We only do this for historical
 		// reasons and analytics events are deprecated so hopefully this can all go away someday.
 		if isManualUserDrop(&pt) {
-			return false, false
+			return false, false, samplerUserDrop
 		}
 	} else { // This path to be deleted once manualUserDrop detection is available on all tracers for P < 1.
 		if priority < 0 {
-			return false, false
+			return false, false, samplerUserDrop
 		}
 	}
 
 	if rare {
-		return true, true
+		return true, true, samplerRare
 	}
 
 	if hasPriority {
 		if a.PrioritySampler.Sample(now, pt.TraceChunk, pt.Root, pt.TracerEnv, pt.ClientDroppedP0sWeight) {
-			return true, true
+			return true, true, prioritySamplerName(pt.Root)
 		}
 	} else if a.NoPrioritySampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv) {
-		return true, true
+		return true, true, samplerNoPriority
 	}
 
 	if traceContainsError(pt.TraceChunk.Spans) {
-		return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true
+		return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true, samplerErrors
 	}
 
-	return false, true
+	if hasPriority {
+		return false, true, prioritySamplerName(pt.Root)
+	}
+	return false, true, samplerNoPriority
 }
 
 func traceContainsError(trace pb.Trace) bool {
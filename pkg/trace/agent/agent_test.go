@@ -34,10 +34,13 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/trace/event"
 	"github.com/DataDog/datadog-agent/pkg/trace/filters"
 	"github.com/DataDog/datadog-agent/pkg/trace/info"
+	"github.com/DataDog/datadog-agent/pkg/trace/inspector"
 	"github.com/DataDog/datadog-agent/pkg/trace/log"
 	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+	"github.com/DataDog/datadog-agent/pkg/trace/spanmetrics"
 	"github.com/DataDog/datadog-agent/pkg/trace/stats"
 	"github.com/DataDog/datadog-agent/pkg/trace/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/trace/teststatsd"
 	"github.com/DataDog/datadog-agent/pkg/trace/testutil"
 	"github.com/DataDog/datadog-agent/pkg/trace/timing"
 	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
@@ -502,6 +505,133 @@ func TestProcess(t *testing.T) {
 		assert.NotContains(t, payload.TracerPayload.Chunks[0].Spans[1].Meta, "irrelevant")
 	})
 
+	t.Run("SpanMetrics", func(t *testing.T) {
+		cfg := config.New()
+		cfg.Endpoints[0].APIKey = "test"
+		cfg.SpanMetrics = []*config.SpanMetricRule{{
+			Name:    "checkout.value",
+			Query:   "service:web-store",
+			Measure: "metric:checkout.value",
+		}}
+		ctx, cancel := context.WithCancel(context.Background())
+		agnt := NewTestAgent(ctx, cfg, telemetry.NewNoopCollector())
+		defer cancel()
+		statsd := &teststatsd.Client{}
+		agnt.SpanMetrics = spanmetrics.NewGenerator(cfg.SpanMetrics, statsd)
+
+		span := &pb.Span{
+			TraceID:  1,
+			SpanID:   1,
+			Service:  "web-store",
+			Duration: (500 * time.Millisecond).Nanoseconds(),
+			Metrics:  map[string]float64{"checkout.value": 42},
+		}
+		c := spansToChunk(span)
+		c.Priority = int32(sampler.PriorityUserDrop)
+		agnt.Process(&api.Payload{
+			TracerPayload: testutil.TracerPayloadWithChunk(c),
+			Source:        agnt.Receiver.Stats.GetTagStats(info.Tags{}),
+		})
+
+		// the metric is generated even though the trace is dropped by sampling
+		assert.Empty(t, agnt.TraceWriter.(*mockTraceWriter).payloads)
+		assert.Equal(t, []teststatsd.MetricsArgs{{Name: "checkout.value", Value: 42, Rate: 1}}, statsd.DistributionCalls)
+	})
+
+	t.Run("TraceInspection", func(t *testing.T) {
+		cfg := config.New()
+		cfg.Endpoints[0].APIKey = "test"
+		cfg.TraceInspection = config.TraceInspectionConfig{Enabled: true, BufferSize: 10}
+		ctx, cancel := context.WithCancel(context.Background())
+		agnt := NewTestAgent(ctx, cfg, telemetry.NewNoopCollector())
+		defer cancel()
+		require.NotNil(t, agnt.TraceInspector)
+
+		now := time.Now()
+		kept := spansToChunk(&pb.Span{
+			TraceID:  1,
+			SpanID:   1,
+			Service:  "web",
+			Resource: "SELECT name FROM people WHERE age = 42",
+			Type:     "sql",
+			Start:    now.Add(-time.Second).UnixNano(),
+			Duration: (500 * time.Millisecond).Nanoseconds(),
+		})
+		kept.Priority = int32(sampler.PriorityUserKeep)
+		dropped := spansToChunk(&pb.Span{
+			TraceID:  2,
+			SpanID:   1,
+			Service:  "web",
+			Resource: "GET /",
+			Start:    now.Add(-time.Second).UnixNano(),
+			Duration: (500 * time.Millisecond).Nanoseconds(),
+			Error:    1,
+		})
+		dropped.Priority = int32(sampler.PriorityUserDrop)
+		tp := testutil.TracerPayloadWithChunk(kept)
+		tp.Chunks = append(tp.Chunks, dropped)
+		agnt.Process(&api.Payload{
+			TracerPayload: tp,
+			Source:        agnt.Receiver.Stats.GetTagStats(info.Tags{}),
+		})
+
+		traces := agnt.TraceInspector.Traces(inspector.Filter{}, 0)
+		require.Len(t, traces, 2)
+		assert.Equal(t, "GET /", traces[0].Resource)
+		assert.Equal(t, inspector.Decision{Sampler: samplerUserDrop, Errored: true}, traces[0].Sampling)
+		assert.Equal(t, "SELECT name FROM people WHERE age = ?", traces[1].Resource)
+		assert.Equal(t, inspector.Decision{Kept: true, Sampler: samplerPriority, ObfuscatedSpans: 1}, traces[1].Sampling)
+	})
+
+	t.Run("TraceInspectionSamplingRates", func(t *testing.T) {
+		cfg := config.New()
+		cfg.Endpoints[0].APIKey = "test"
+		cfg.TraceInspection = config.TraceInspectionConfig{Enabled: true, BufferSize: 10}
+		cfg.SamplingRules = []*config.SamplingRule{{Service: "batch", SampleRate: 1}}
+		ctx, cancel := context.WithCancel(context.Background())
+		agnt := NewTestAgent(ctx, cfg, telemetry.NewNoopCollector())
+		defer cancel()
+
+		now := time.Now()
+		byPriority := spansToChunk(&pb.Span{
+			TraceID:  1,
+			SpanID:   1,
+			Service:  "web",
+			Resource: "GET /",
+			Start:    now.Add(-time.Second).UnixNano(),
+			Duration: (500 * time.Millisecond).Nanoseconds(),
+			Metrics:  map[string]float64{sampler.KeyPrioritySamplingRate: 0.25},
+		})
+		byPriority.Priority = int32(sampler.PriorityAutoKeep)
+		byRule := spansToChunk(&pb.Span{
+			TraceID:  2,
+			SpanID:   1,
+			Service:  "batch",
+			Resource: "run",
+			Start:    now.Add(-time.Second).UnixNano(),
+			Duration: (500 * time.Millisecond).Nanoseconds(),
+			Metrics:  map[string]float64{sampler.KeyAgentPrioritySamplingRate: 0.5},
+		})
+		byRule.Priority = int32(sampler.PriorityAutoKeep)
+		tp := testutil.TracerPayloadWithChunk(byPriority)
+		tp.Chunks = append(tp.Chunks, byRule)
+		agnt.Process(&api.Payload{
+			TracerPayload: tp,
+			Source:        agnt.Receiver.Stats.GetTagStats(info.Tags{}),
+		})
+
+		traces := agnt.TraceInspector.Traces(inspector.Filter{}, 0)
+		require.Len(t, traces, 2)
+		assert.Equal(t, "run", traces[0].Resource)
+		assert.Equal(t, samplerAgentRule, traces[0].Sampling.Sampler)
+		assert.True(t, traces[0].Sampling.Kept)
+		assert.Equal(t, 1.0, traces[0].Sampling.Rate)
+		assert.Equal(t, "GET /", traces[1].Resource)
+		assert.Equal(t, samplerPriority, traces[1].Sampling.Sampler)
+		assert.True(t, traces[1].Sampling.Kept)
+		assert.Equal(t, 0.25, traces[1].Sampling.Rate)
+	})
+
 	t.Run("chunking", func(t *testing.T) {
 		cfg := config.New()
 		cfg.Endpoints[0].APIKey = "test"
@@ -1258,7 +1388,7 @@ func TestSampling(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			a := configureAgent(tt.agentConfig)
 			for _, tc := range tt.testCases {
-				sampled, _ := a.traceSampling(time.Now(), &info.TagStats{}, &tc.trace)
+				sampled, _, _ := a.traceSampling(time.Now(), &info.TagStats{}, &tc.trace)
 				assert.EqualValues(t, tc.wantSampled, sampled)
 			}
 		})
@@ -1337,12 +1467,12 @@ func TestSample(t *testing.T) {
 			conf:              cfg,
 		}
 		t.Run(name, func(t *testing.T) {
-			keep, _ := a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
+			keep, _, _ := a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
 			assert.Equal(t, tt.keep, keep)
 			assert.Equal(t, !tt.keep, tt.trace.TraceChunk.DroppedTrace)
 			cfg.Features["error_rare_sample_tracer_drop"] = struct{}{}
 			defer delete(cfg.Features, "error_rare_sample_tracer_drop")
-			keep, _ = a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
+			keep, _, _ = a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
 			assert.Equal(t, tt.keepWithFeature, keep)
 			assert.Equal(t, !tt.keepWithFeature, tt.trace.TraceChunk.DroppedTrace)
 		})
@@ -1373,7 +1503,7 @@ func TestSampleManualUserDropNoAnalyticsEvents(t *testing.T) {
 		EventProcessor:    newEventProcessor(cfg, statsd),
 		conf:              cfg,
 	}
-	keep, _ := a.sample(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
+	keep, _, _ := a.sample(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
 	assert.False(t, keep)
 	assert.Empty(t, pt.Root.Metrics["_dd.analyzed"])
 }
@@ -2001,7 +2131,7 @@ func TestSampleWithPriorityNone(t *testing.T) {
 	}
 	// before := traceutil.CopyTraceChunk(pt.TraceChunk)
 	before := pt.TraceChunk.ShallowCopy()
-	keep, numEvents := agnt.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
+	keep, numEvents, _ := agnt.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
 	assert.True(t, keep) // Score Sampler should keep the trace.
 	assert.False(t, pt.TraceChunk.DroppedTrace)
 	assert.Equal(t, before, pt.TraceChunk)
@@ -2360,7 +2490,7 @@ func TestSingleSpanPlusAnalyticsEvents(t *testing.T) {
 	var b bytes.Buffer
 	oldLogger := log.SetLogger(log.NewBufferLogger(&b))
 	defer func() { log.SetLogger(oldLogger) }()
-	keep, numEvents := traceAgent.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), payload)
+	keep, numEvents, _ := traceAgent.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), payload)
 	assert.Equal(t, "[WARN] Detected both analytics events AND single span sampling in the same trace. Single span sampling wins because App Analytics is deprecated.", b.String())
 	assert.False(t, keep) //The sampling decision was FALSE but the trace itself is marked as not dropped
 	assert.False(t, payload.TraceChunk.DroppedTrace)
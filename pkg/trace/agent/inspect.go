@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	"maps"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/inspector"
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+)
+
+// Names of the samplers reported with the sampling decisions of inspected traces.
+const (
+	samplerRare          = "rare"
+	samplerProbabilistic = "probabilistic"
+	samplerPriority      = "priority"
+	samplerAgentRule     = "agent_rule"
+	samplerNoPriority    = "no_priority"
+	samplerErrors        = "errors"
+	samplerUserDrop      = "user_drop"
+)
+
+// samplerRateKeys holds the root span metrics holding the sampling rate applied by each sampler, by
+// order of precedence.
+var samplerRateKeys = map[string][]string{
+	samplerProbabilistic: {sampler.KeyProbabilisticSamplingRate},
+	samplerPriority:      {sampler.KeyAgentPrioritySamplingRate, sampler.KeyRulePrioritySamplingRate, sampler.KeyPrioritySamplingRate},
+	samplerAgentRule:     {sampler.KeyAgentRuleSamplingRate},
+	samplerNoPriority:    {sampler.KeyNoPrioritySamplingRate},
+	samplerErrors:        {sampler.KeyErrorsSamplingRate},
+}
+
+// prioritySamplerName returns the name of the sampler behind a decision of the priority sampler on
+// the trace rooted at root: an agent sampling rule when one matched the trace, the priority otherwise.
+func prioritySamplerName(root *pb.Span) string {
+	if _, ok := root.Metrics[sampler.KeyAgentRuleSamplingRate]; ok {
+		return samplerAgentRule
+	}
+	return samplerPriority
+}
+
+// obfuscateSpanInspected obfuscates span like obfuscateSpan, and reports whether the obfuscation
+// modified it.
+func (a *Agent) obfuscateSpanInspected(span *pb.Span) bool {
+	resource, meta := span.Resource, maps.Clone(span.Meta)
+	a.obfuscateSpan(span)
+	return span.Resource != resource || !maps.Equal(span.Meta, meta)
+}
+
+// inspectTrace records the trace t in the trace inspector along with its sampling decision, made by
+// the sampler by.
+func (a *Agent) inspectTrace(t *inspector.Trace, pt *traceutil.ProcessedTrace, keep bool, by string) {
+	t.Sampling.Kept = keep
+	t.Sampling.Sampler = by
+	t.Sampling.Rare = by == samplerRare
+	t.Sampling.DecisionMaker = pt.TraceChunk.Tags[tagDecisionMaker]
+	for _, k := range samplerRateKeys[by] {
+		if rate, ok := pt.Root.Metrics[k]; ok {
+			t.Sampling.Rate = rate
+			break
+		}
+	}
+	a.TraceInspector.Record(t)
+}
@@ -97,6 +97,9 @@ type HTTPReceiver struct {
 	statsProcessor      StatsProcessor
 	containerIDProvider IDProvider
 
+	// otlp processes the spans received in foreign formats once translated to OTLP, see handleForeignTraces
+	otlp *OTLPReceiver
+
 	telemetryCollector telemetry.TelemetryCollector
 	telemetryForwarder *TelemetryForwarder
 
@@ -175,6 +178,12 @@ func NewHTTPReceiver(
 	}
 }
 
+// SetOTLPReceiver sets the OTLPReceiver processing the spans received by the Zipkin and Jaeger endpoints.
+// It must be called before Start when any of these endpoints is enabled.
+func (r *HTTPReceiver) SetOTLPReceiver(otlp *OTLPReceiver) {
+	r.otlp = otlp
+}
+
 // timeoutMiddleware sets a timeout for a handler. This lets us have different
 // timeout values for each handler
 func timeoutMiddleware(timeout time.Duration, h http.Handler) http.Handler {
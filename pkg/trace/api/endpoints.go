@@ -157,4 +157,14 @@ var endpoints = []Endpoint{
 		Pattern: "/tracer_flare/v1",
 		Handler: func(r *HTTPReceiver) http.Handler { return r.tracerFlareHandler() },
 	},
+	{
+		Pattern:   "/api/v2/spans",
+		Handler:   func(r *HTTPReceiver) http.Handler { return http.HandlerFunc(r.handleZipkinSpans) },
+		IsEnabled: func(cfg *config.AgentConfig) bool { return cfg.ZipkinReceiver.Enabled },
+	},
+	{
+		Pattern:   "/api/traces",
+		Handler:   func(r *HTTPReceiver) http.Handler { return http.HandlerFunc(r.handleJaegerThrift) },
+		IsEnabled: func(cfg *config.AgentConfig) bool { return cfg.JaegerReceiver.Enabled },
+	},
 }
@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/api/apiutil"
+	"github.com/DataDog/datadog-agent/pkg/trace/api/internal/header"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+// handleForeignTraces handles a payload of spans sent in a foreign format, such as Zipkin or Jaeger.
+// The payload is converted to OTLP by decode, and the spans are then processed like OTLP spans.
+func (r *HTTPReceiver) handleForeignTraces(w http.ResponseWriter, req *http.Request, intake spanIntake, decode func([]byte) (ptrace.Traces, error)) {
+	defer req.Body.Close()
+	if req.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	select {
+	// Wait for the semaphore to become available, allowing the handler to
+	// decode its payload.
+	case r.recvsem <- struct{}{}:
+	case <-time.After(time.Duration(r.conf.DecoderTimeout) * time.Millisecond):
+		// this payload can not be accepted
+		io.Copy(io.Discard, req.Body) //nolint:errcheck
+		if h := req.Header.Get(header.SendRealHTTPStatus); h != "" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		} else {
+			w.WriteHeader(r.rateLimiterResponse)
+		}
+		return
+	}
+	defer func() { <-r.recvsem }()
+
+	defer r.timing.Since("datadog.trace_agent.receiver."+intake.endpointVersion+"_process_ms", time.Now())
+
+	tags := []string{"handler:" + intake.endpointVersion}
+	body, err := readForeignPayload(req, r.conf.MaxRequestBytes)
+	if err != nil {
+		httpDecodingError(err, tags, w, r.statsd)
+		return
+	}
+	traces, err := decode(body)
+	if err != nil {
+		log.Errorf("Cannot decode %s traces payload: %v", intake.endpointVersion, err)
+		httpDecodingError(err, tags, w, r.statsd)
+		return
+	}
+
+	_ = r.statsd.Count("datadog.trace_agent.receiver."+intake.endpointVersion+".payload", 1, tags, 1)
+	r.otlp.receiveTraces(req.Context(), req.Header, traces, intake)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// readForeignPayload reads the body of a request, up to maxBytes, decompressing it if needed.
+func readForeignPayload(req *http.Request, maxBytes int64) ([]byte, error) {
+	var rd io.Reader = apiutil.NewLimitedReader(req.Body, maxBytes)
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(rd)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		rd = apiutil.NewLimitedReader(gz, maxBytes)
+	}
+	return io.ReadAll(rd)
+}
+
+// otlpTraceID returns the OTLP trace ID made of the given high and low 64 bits.
+func otlpTraceID(high, low uint64) pcommon.TraceID {
+	var tid pcommon.TraceID
+	binary.BigEndian.PutUint64(tid[:8], high)
+	binary.BigEndian.PutUint64(tid[8:], low)
+	return tid
+}
+
+// otlpSpanID returns the OTLP span ID of the given 64 bits span ID.
+func otlpSpanID(id uint64) pcommon.SpanID {
+	var sid pcommon.SpanID
+	binary.BigEndian.PutUint64(sid[:], id)
+	return sid
+}
+
+// rangeProtoFields calls fn for each field of the protobuf message b, in order, until fn returns
+// an error. The value of the varint and fixed size fields is passed in v, the content of the
+// length-delimited fields (strings, bytes, embedded messages and packed fields) in data.
+func rangeProtoFields(b []byte, fn func(num protowire.Number, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		var (
+			v    uint64
+			data []byte
+		)
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var v32 uint32
+			v32, n = protowire.ConsumeFixed32(b)
+			v = uint64(v32)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			data, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -86,6 +86,7 @@ func (r *HTTPReceiver) makeInfoHandler() (hash string, handler http.HandlerFunc)
 		Config                 reducedConfig `json:"config"`
 		PeerTags               []string      `json:"peer_tags"`
 		SpanKindsStatsComputed []string      `json:"span_kinds_stats_computed"`
+		ExtraAggregationTags   []string      `json:"extra_aggregation_tags,omitempty"`
 	}{
 		Version:                r.conf.AgentVersion,
 		GitCommit:              r.conf.GitCommit,
@@ -111,7 +112,8 @@ func (r *HTTPReceiver) makeInfoHandler() (hash string, handler http.HandlerFunc)
 			AnalyzedSpansByService: r.conf.AnalyzedSpansByService,
 			Obfuscation:            oconf,
 		},
-		PeerTags: r.conf.ConfiguredPeerTags(),
+		PeerTags:             r.conf.ConfiguredPeerTags(),
+		ExtraAggregationTags: r.conf.ExtraAggregationTags,
 	}, "", "\t")
 	if err != nil {
 		panic(fmt.Errorf("Error making /info handler: %v", err))
@@ -0,0 +1,396 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv117 "go.opentelemetry.io/collector/semconv/v1.17.0"
+	semconv "go.opentelemetry.io/collector/semconv/v1.6.1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+	"github.com/DataDog/datadog-agent/pkg/trace/timing"
+)
+
+var (
+	// jaegerThriftIntake is the intake of the Jaeger batches sent as Thrift over HTTP.
+	jaegerThriftIntake = spanIntake{endpointVersion: "jaeger_thrift", tracerVersionPrefix: "jaeger"}
+	// jaegerGRPCIntake is the intake of the Jaeger batches sent over gRPC.
+	jaegerGRPCIntake = spanIntake{endpointVersion: "jaeger_grpc", tracerVersionPrefix: "jaeger"}
+)
+
+// handleJaegerThrift handles a Jaeger batch encoded as binary Thrift, as sent to the Jaeger collector.
+func (r *HTTPReceiver) handleJaegerThrift(w http.ResponseWriter, req *http.Request) {
+	switch mediaType := getMediaType(req); mediaType {
+	case "application/x-thrift", "application/vnd.apache.thrift.binary":
+	default:
+		httpFormatError(w, Version(jaegerThriftIntake.endpointVersion), fmt.Errorf("unsupported media type: %q", mediaType), r.statsd)
+		return
+	}
+	r.handleForeignTraces(w, req, jaegerThriftIntake, func(body []byte) (ptrace.Traces, error) {
+		batch, err := decodeJaegerThriftBatch(body)
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		return batch.toTraces(), nil
+	})
+}
+
+// JaegerReceiver implements the gRPC API of the Jaeger collector. The Thrift over HTTP API is served
+// by the HTTPReceiver.
+type JaegerReceiver struct {
+	wg      sync.WaitGroup // waits for a graceful shutdown
+	grpcsrv *grpc.Server   // the running GRPC server on a started receiver, if enabled
+	conf    *config.AgentConfig
+	otlp    *OTLPReceiver // processes the spans once translated to OTLP
+	statsd  statsd.ClientInterface
+	timing  timing.Reporter
+}
+
+// NewJaegerReceiver returns a new JaegerReceiver which hands the incoming traces to otlp.
+func NewJaegerReceiver(otlp *OTLPReceiver, cfg *config.AgentConfig, statsd statsd.ClientInterface, timing timing.Reporter) *JaegerReceiver {
+	return &JaegerReceiver{
+		conf:   cfg,
+		otlp:   otlp,
+		statsd: statsd,
+		timing: timing,
+	}
+}
+
+// Start starts the gRPC server of the JaegerReceiver, if configured.
+func (j *JaegerReceiver) Start() {
+	port := j.conf.JaegerReceiver.GRPCPort
+	if port == 0 {
+		return
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", j.conf.ReceiverHost, port))
+	if err != nil {
+		log.Criticalf("Error starting Jaeger gRPC server: %v", err)
+		return
+	}
+	j.grpcsrv = grpc.NewServer(
+		grpc.MaxRecvMsgSize(10*1024*1024),
+		grpc.ForceServerCodec(jaegerCodec{}),
+	)
+	j.grpcsrv.RegisterService(&jaegerCollectorServiceDesc, j)
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		if err := j.grpcsrv.Serve(ln); err != nil {
+			log.Criticalf("Error starting Jaeger gRPC server: %v", err)
+		}
+	}()
+	log.Infof("Listening for Jaeger traces on gRPC port %s:%d", j.conf.ReceiverHost, port)
+}
+
+// Stop stops any running server.
+func (j *JaegerReceiver) Stop() {
+	if j.grpcsrv != nil {
+		go j.grpcsrv.Stop()
+	}
+	j.wg.Wait()
+}
+
+// postSpans implements the PostSpans method of the Jaeger collector service.
+func (j *JaegerReceiver) postSpans(ctx context.Context, batch *jaegerBatch) {
+	defer j.timing.Since("datadog.trace_agent.receiver.jaeger_grpc_process_ms", time.Now())
+	tags := []string{"handler:" + jaegerGRPCIntake.endpointVersion}
+	_ = j.statsd.Count("datadog.trace_agent.receiver.jaeger_grpc.payload", 1, tags, 1)
+	md, _ := metadata.FromIncomingContext(ctx)
+	j.otlp.receiveTraces(ctx, headerFromMetadata(md), batch.toTraces(), jaegerGRPCIntake)
+}
+
+// headerFromMetadata returns the gRPC metadata md as an http.Header. The metadata keys are lowercase,
+// they are canonicalized to be found by http.Header.Get.
+func headerFromMetadata(md metadata.MD) http.Header {
+	header := make(http.Header, len(md))
+	for k, v := range md {
+		header[http.CanonicalHeaderKey(k)] = v
+	}
+	return header
+}
+
+// jaegerCollectorServer is the server of the Jaeger collector gRPC service.
+type jaegerCollectorServer interface {
+	postSpans(ctx context.Context, batch *jaegerBatch)
+}
+
+// jaegerCollectorServiceDesc describes the jaeger.api_v2.CollectorService gRPC service, defined in
+// https://github.com/jaegertracing/jaeger-idl/blob/main/proto/api_v2/collector.proto.
+var jaegerCollectorServiceDesc = grpc.ServiceDesc{
+	ServiceName: "jaeger.api_v2.CollectorService",
+	HandlerType: (*jaegerCollectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PostSpans",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				batch := &jaegerBatch{}
+				if err := dec(batch); err != nil {
+					return nil, err
+				}
+				srv.(jaegerCollectorServer).postSpans(ctx, batch)
+				return struct{}{}, nil
+			},
+		},
+	},
+	Metadata: "api_v2/collector.proto",
+}
+
+// jaegerCodec is the gRPC codec of the Jaeger collector service. It decodes the PostSpansRequest messages
+// into a jaegerBatch, and encodes the empty PostSpansResponse messages.
+type jaegerCodec struct{}
+
+// Marshal implements encoding.Codec.
+func (jaegerCodec) Marshal(any) ([]byte, error) {
+	return nil, nil
+}
+
+// Unmarshal implements encoding.Codec.
+func (jaegerCodec) Unmarshal(data []byte, v any) error {
+	batch, ok := v.(*jaegerBatch)
+	if !ok {
+		return fmt.Errorf("jaeger codec: unexpected message type %T", v)
+	}
+	return decodeJaegerPostSpansRequest(data, batch)
+}
+
+// Name implements encoding.Codec.
+func (jaegerCodec) Name() string {
+	return "proto"
+}
+
+// jaegerBatch is a batch of Jaeger spans reported by a process, decoded from Thrift or protobuf.
+type jaegerBatch struct {
+	process struct {
+		serviceName string
+		tags        []jaegerTag
+	}
+	spans []jaegerSpan
+}
+
+// jaegerSpan is a Jaeger span.
+type jaegerSpan struct {
+	traceIDHigh, traceIDLow uint64
+	spanID, parentSpanID    uint64
+	operationName           string
+	references              []jaegerSpanRef
+	start                   pcommon.Timestamp
+	duration                time.Duration
+	tags                    []jaegerTag
+	logs                    []jaegerLog
+}
+
+// jaegerRefType is the type of a reference between two Jaeger spans.
+type jaegerRefType int32
+
+const (
+	jaegerChildOf jaegerRefType = iota
+	jaegerFollowsFrom
+)
+
+// jaegerSpanRef is a reference from a Jaeger span to another span.
+type jaegerSpanRef struct {
+	refType                 jaegerRefType
+	traceIDHigh, traceIDLow uint64
+	spanID                  uint64
+}
+
+// jaegerLog is a timestamped event of a Jaeger span.
+type jaegerLog struct {
+	timestamp pcommon.Timestamp
+	fields    []jaegerTag
+}
+
+// jaegerTagType is the type of the value of a Jaeger tag.
+type jaegerTagType int32
+
+const (
+	jaegerTagString jaegerTagType = iota
+	jaegerTagDouble
+	jaegerTagBool
+	jaegerTagLong
+	jaegerTagBinary
+)
+
+// jaegerTag is a Jaeger tag, its value is held by the field of its type.
+type jaegerTag struct {
+	key     string
+	vType   jaegerTagType
+	vStr    string
+	vDouble float64
+	vBool   bool
+	vLong   int64
+	vBinary []byte
+}
+
+// toTraces converts the batch to OTLP, the same way the OpenTelemetry Collector Jaeger receiver does.
+func (b *jaegerBatch) toTraces() ptrace.Traces {
+	traces := ptrace.NewTraces()
+	if len(b.spans) == 0 {
+		return traces
+	}
+	rs := traces.ResourceSpans().AppendEmpty()
+	rattrs := rs.Resource().Attributes()
+	if b.process.serviceName != "" {
+		rattrs.PutStr(semconv.AttributeServiceName, b.process.serviceName)
+	}
+	putJaegerTags(b.process.tags, rattrs)
+	if hostname, ok := rattrs.Get("hostname"); ok {
+		if _, ok := rattrs.Get(semconv.AttributeHostName); !ok {
+			rattrs.PutStr(semconv.AttributeHostName, hostname.AsString())
+			rattrs.Remove("hostname")
+		}
+	}
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	spans.EnsureCapacity(len(b.spans))
+	for i := range b.spans {
+		b.spans[i].toSpan(spans.AppendEmpty())
+	}
+	return traces
+}
+
+// parentID returns the ID of the parent of the span: its parent span ID when set, or else the
+// span it references in the same trace, CHILD_OF references first.
+func (s *jaegerSpan) parentID() uint64 {
+	if s.parentSpanID != 0 {
+		return s.parentSpanID
+	}
+	var followsFrom uint64
+	for _, ref := range s.references {
+		if ref.traceIDHigh != s.traceIDHigh || ref.traceIDLow != s.traceIDLow {
+			continue
+		}
+		if ref.refType == jaegerChildOf {
+			return ref.spanID
+		}
+		if followsFrom == 0 && ref.refType == jaegerFollowsFrom {
+			followsFrom = ref.spanID
+		}
+	}
+	return followsFrom
+}
+
+func (s *jaegerSpan) toSpan(dest ptrace.Span) {
+	dest.SetTraceID(otlpTraceID(s.traceIDHigh, s.traceIDLow))
+	dest.SetSpanID(otlpSpanID(s.spanID))
+	dest.SetName(s.operationName)
+	dest.SetStartTimestamp(s.start)
+	dest.SetEndTimestamp(s.start + pcommon.Timestamp(s.duration))
+	parentID := s.parentID()
+	if parentID != 0 {
+		dest.SetParentSpanID(otlpSpanID(parentID))
+	}
+
+	attrs := dest.Attributes()
+	putJaegerTags(s.tags, attrs)
+	if kind, ok := attrs.Get("span.kind"); ok {
+		dest.SetKind(jaegerSpanKind(kind.AsString()))
+		attrs.Remove("span.kind")
+	}
+	setJaegerSpanStatus(attrs, dest.Status())
+
+	events := dest.Events()
+	events.EnsureCapacity(len(s.logs))
+	for _, l := range s.logs {
+		event := events.AppendEmpty()
+		event.SetTimestamp(l.timestamp)
+		putJaegerTags(l.fields, event.Attributes())
+		if name, ok := event.Attributes().Get("event"); ok {
+			event.SetName(name.AsString())
+			event.Attributes().Remove("event")
+		}
+	}
+
+	links := dest.Links()
+	for _, ref := range s.references {
+		if ref.spanID == parentID && ref.refType == jaegerChildOf {
+			continue
+		}
+		link := links.AppendEmpty()
+		link.SetTraceID(otlpTraceID(ref.traceIDHigh, ref.traceIDLow))
+		link.SetSpanID(otlpSpanID(ref.spanID))
+		refType := semconv117.AttributeOpentracingRefTypeFollowsFrom
+		if ref.refType == jaegerChildOf {
+			refType = semconv117.AttributeOpentracingRefTypeChildOf
+		}
+		link.Attributes().PutStr(semconv117.AttributeOpentracingRefType, refType)
+	}
+}
+
+func putJaegerTags(tags []jaegerTag, dest pcommon.Map) {
+	dest.EnsureCapacity(dest.Len() + len(tags))
+	for _, tag := range tags {
+		switch tag.vType {
+		case jaegerTagString:
+			dest.PutStr(tag.key, tag.vStr)
+		case jaegerTagDouble:
+			dest.PutDouble(tag.key, tag.vDouble)
+		case jaegerTagBool:
+			dest.PutBool(tag.key, tag.vBool)
+		case jaegerTagLong:
+			dest.PutInt(tag.key, tag.vLong)
+		case jaegerTagBinary:
+			dest.PutEmptyBytes(tag.key).FromRaw(tag.vBinary)
+		default:
+			dest.PutStr(tag.key, fmt.Sprintf("<Unknown Jaeger TagType %d>", tag.vType))
+		}
+	}
+}
+
+func jaegerSpanKind(kind string) ptrace.SpanKind {
+	switch kind {
+	case "client":
+		return ptrace.SpanKindClient
+	case "server":
+		return ptrace.SpanKindServer
+	case "producer":
+		return ptrace.SpanKindProducer
+	case "consumer":
+		return ptrace.SpanKindConsumer
+	case "internal":
+		return ptrace.SpanKindInternal
+	}
+	return ptrace.SpanKindUnspecified
+}
+
+// setJaegerSpanStatus sets the status of a span from its error and otel.status_* tags, which are removed.
+// A true error tag takes precedence over the otel.status_code tag.
+func setJaegerSpanStatus(attrs pcommon.Map, status ptrace.Status) {
+	isError := false
+	if v, ok := attrs.Get("error"); ok && v.Type() == pcommon.ValueTypeBool && v.Bool() {
+		isError = true
+		status.SetCode(ptrace.StatusCodeError)
+		attrs.Remove("error")
+	}
+	code, hasCode := attrs.Get(semconv.OtelStatusCode)
+	if !isError && hasCode {
+		switch strings.ToUpper(code.AsString()) {
+		case "OK":
+			status.SetCode(ptrace.StatusCodeOk)
+		case "ERROR":
+			status.SetCode(ptrace.StatusCodeError)
+		}
+	}
+	if isError || hasCode {
+		if desc, ok := attrs.Get(semconv.OtelStatusDescription); ok {
+			status.SetMessage(desc.AsString())
+			attrs.Remove(semconv.OtelStatusDescription)
+		}
+	}
+	attrs.Remove(semconv.OtelStatusCode)
+}
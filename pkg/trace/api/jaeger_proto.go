@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// jaegerProtoTagTypes maps the value types of the protobuf Jaeger tags to jaegerTagType.
+var jaegerProtoTagTypes = [...]jaegerTagType{
+	0: jaegerTagString,
+	1: jaegerTagBool,
+	2: jaegerTagLong,
+	3: jaegerTagDouble,
+	4: jaegerTagBinary,
+}
+
+// decodeJaegerPostSpansRequest decodes the jaeger.api_v2.PostSpansRequest protobuf message b into batch,
+// as defined in https://github.com/jaegertracing/jaeger-idl/blob/main/proto/api_v2/collector.proto.
+func decodeJaegerPostSpansRequest(b []byte, batch *jaegerBatch) error {
+	return rangeProtoFields(b, func(num protowire.Number, _ uint64, data []byte) error {
+		if num != 1 {
+			return nil
+		}
+		return rangeProtoFields(data, func(num protowire.Number, _ uint64, data []byte) error {
+			switch num {
+			case 1:
+				batch.spans = append(batch.spans, jaegerSpan{})
+				return decodeJaegerProtoSpan(data, &batch.spans[len(batch.spans)-1])
+			case 2:
+				return rangeProtoFields(data, func(num protowire.Number, _ uint64, data []byte) error {
+					switch num {
+					case 1:
+						batch.process.serviceName = string(data)
+					case 2:
+						return appendJaegerProtoTag(data, &batch.process.tags)
+					}
+					return nil
+				})
+			}
+			return nil
+		})
+	})
+}
+
+func decodeJaegerProtoSpan(b []byte, span *jaegerSpan) error {
+	return rangeProtoFields(b, func(num protowire.Number, _ uint64, data []byte) error {
+		var err error
+		switch num {
+		case 1:
+			span.traceIDHigh, span.traceIDLow, err = decodeJaegerProtoTraceID(data)
+		case 2:
+			span.spanID, err = decodeJaegerProtoSpanID(data)
+		case 3:
+			span.operationName = string(data)
+		case 4:
+			var ref jaegerSpanRef
+			err = rangeProtoFields(data, func(num protowire.Number, v uint64, data []byte) error {
+				var err error
+				switch num {
+				case 1:
+					ref.traceIDHigh, ref.traceIDLow, err = decodeJaegerProtoTraceID(data)
+				case 2:
+					ref.spanID, err = decodeJaegerProtoSpanID(data)
+				case 3:
+					ref.refType = jaegerRefType(v)
+				}
+				return err
+			})
+			span.references = append(span.references, ref)
+		case 6:
+			var start time.Duration
+			start, err = decodeProtoDuration(data)
+			span.start = pcommon.Timestamp(start)
+		case 7:
+			span.duration, err = decodeProtoDuration(data)
+		case 8:
+			err = appendJaegerProtoTag(data, &span.tags)
+		case 9:
+			var log jaegerLog
+			err = rangeProtoFields(data, func(num protowire.Number, _ uint64, data []byte) error {
+				switch num {
+				case 1:
+					ts, err := decodeProtoDuration(data)
+					log.timestamp = pcommon.Timestamp(ts)
+					return err
+				case 2:
+					return appendJaegerProtoTag(data, &log.fields)
+				}
+				return nil
+			})
+			span.logs = append(span.logs, log)
+		}
+		return err
+	})
+}
+
+func appendJaegerProtoTag(b []byte, tags *[]jaegerTag) error {
+	var tag jaegerTag
+	err := rangeProtoFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			tag.key = string(data)
+		case 2:
+			if v >= uint64(len(jaegerProtoTagTypes)) {
+				return fmt.Errorf("unknown Jaeger tag type %d", v)
+			}
+			tag.vType = jaegerProtoTagTypes[v]
+		case 3:
+			tag.vStr = string(data)
+		case 4:
+			tag.vBool = v != 0
+		case 5:
+			tag.vLong = int64(v)
+		case 6:
+			tag.vDouble = math.Float64frombits(v)
+		case 7:
+			tag.vBinary = data
+		}
+		return nil
+	})
+	*tags = append(*tags, tag)
+	return err
+}
+
+// decodeJaegerProtoTraceID decodes a Jaeger trace ID, made of its high and low 64 bits in big endian.
+func decodeJaegerProtoTraceID(b []byte) (high, low uint64, err error) {
+	if len(b) != 16 {
+		return 0, 0, fmt.Errorf("invalid Jaeger trace ID length %d", len(b))
+	}
+	return binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:]), nil
+}
+
+// decodeJaegerProtoSpanID decodes a Jaeger span ID, encoded in big endian.
+func decodeJaegerProtoSpanID(b []byte) (uint64, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid Jaeger span ID length %d", len(b))
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// decodeProtoDuration decodes a google.protobuf.Duration or a google.protobuf.Timestamp, which share the
+// same encoding, into nanoseconds.
+func decodeProtoDuration(b []byte) (time.Duration, error) {
+	var seconds, nanos int64
+	err := rangeProtoFields(b, func(num protowire.Number, v uint64, _ []byte) error {
+		switch num {
+		case 1:
+			seconds = int64(v)
+		case 2:
+			nanos = int64(int32(v))
+		}
+		return nil
+	})
+	return time.Duration(seconds)*time.Second + time.Duration(nanos), err
+}
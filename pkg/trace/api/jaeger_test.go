@@ -0,0 +1,309 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/testutil"
+	"github.com/DataDog/datadog-agent/pkg/trace/timing"
+)
+
+// thriftWriter encodes values with the Thrift binary protocol.
+type thriftWriter struct {
+	bytes.Buffer
+}
+
+func (w *thriftWriter) field(typ byte, id int16) {
+	w.WriteByte(typ)
+	binary.Write(w, binary.BigEndian, id) //nolint:errcheck
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(thriftI32, id)
+	binary.Write(w, binary.BigEndian, v) //nolint:errcheck
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(thriftI64, id)
+	binary.Write(w, binary.BigEndian, v) //nolint:errcheck
+}
+
+func (w *thriftWriter) str(id int16, s string) {
+	w.field(thriftString, id)
+	binary.Write(w, binary.BigEndian, int32(len(s))) //nolint:errcheck
+	w.WriteString(s)
+}
+
+// structList starts a field holding a list of n structs.
+func (w *thriftWriter) structList(id int16, n int) {
+	w.field(thriftList, id)
+	w.WriteByte(thriftStruct)
+	binary.Write(w, binary.BigEndian, int32(n)) //nolint:errcheck
+}
+
+func (w *thriftWriter) stop() {
+	w.WriteByte(thriftStop)
+}
+
+// tag writes a jaeger.Tag struct holding the given value.
+func (w *thriftWriter) tag(key string, value interface{}) {
+	w.str(1, key)
+	switch v := value.(type) {
+	case string:
+		w.i32(2, 0)
+		w.str(3, v)
+	case float64:
+		w.i32(2, 1)
+		w.field(thriftDouble, 4)
+		binary.Write(w, binary.BigEndian, math.Float64bits(v)) //nolint:errcheck
+	case bool:
+		w.i32(2, 2)
+		w.field(thriftBool, 5)
+		if v {
+			w.WriteByte(1)
+		} else {
+			w.WriteByte(0)
+		}
+	case int64:
+		w.i32(2, 3)
+		w.i64(6, v)
+	default:
+		panic(fmt.Sprintf("unsupported tag value %T", value))
+	}
+	w.stop()
+}
+
+type testJaegerSpan struct {
+	spanID, parentID int64
+	operationName    string
+	start, duration  int64
+	tags             [][2]interface{}
+}
+
+// encodeJaegerThriftBatch encodes a jaeger.Batch of spans from the given service with the Thrift binary protocol.
+func encodeJaegerThriftBatch(service string, traceID int64, spans []testJaegerSpan) []byte {
+	w := &thriftWriter{}
+	w.field(thriftStruct, 1)
+	w.str(1, service)
+	w.structList(2, 1)
+	w.tag("hostname", "web-1")
+	w.stop()
+	w.structList(2, len(spans))
+	for _, s := range spans {
+		w.i64(1, traceID)
+		w.i64(2, 0)
+		w.i64(3, s.spanID)
+		w.i64(4, s.parentID)
+		w.str(5, s.operationName)
+		w.i32(7, 1)
+		w.i64(8, s.start)
+		w.i64(9, s.duration)
+		w.structList(10, len(s.tags))
+		for _, t := range s.tags {
+			w.tag(t[0].(string), t[1])
+		}
+		w.structList(11, 1)
+		w.i64(1, s.start)
+		w.structList(2, 1)
+		w.tag("event", "retry")
+		w.stop()
+		w.stop()
+	}
+	w.stop()
+	return w.Bytes()
+}
+
+func TestJaegerThrift(t *testing.T) {
+	conf := newForeignTracesTestConfig(t)
+	conf.JaegerReceiver.Enabled = true
+	r := newForeignTracesTestReceiver(conf)
+	server := httptest.NewServer(r.buildMux())
+	defer server.Close()
+
+	start := time.Now().UnixMicro()
+	body := encodeJaegerThriftBatch("checkout", 1, []testJaegerSpan{
+		{
+			spanID:        1,
+			operationName: "GET /cart",
+			start:         start,
+			duration:      1000,
+			tags: [][2]interface{}{
+				{"span.kind", "server"},
+				{"http.method", "GET"},
+			},
+		},
+		{
+			spanID:        2,
+			parentID:      1,
+			operationName: "GET",
+			start:         start,
+			duration:      100,
+			tags: [][2]interface{}{
+				{"span.kind", "client"},
+				{"error", true},
+				{"peer.service", "redis"},
+				{"db.rows", int64(3)},
+				{"cache.ratio", 0.5},
+			},
+		},
+	})
+
+	resp, err := http.Post(server.URL+"/api/traces", "application/x-thrift", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	p := receivePayload(t, r.out)
+	assert.Equal(t, "jaeger_thrift", p.Source.EndpointVersion)
+	require.Len(t, p.TracerPayload.Chunks, 1)
+	require.Len(t, p.TracerPayload.Chunks[0].Spans, 2)
+	for _, span := range p.TracerPayload.Chunks[0].Spans {
+		assert.Equal(t, "checkout", span.Service)
+		assert.Equal(t, uint64(1), span.TraceID)
+		switch span.SpanID {
+		case 1:
+			assert.Equal(t, "server", span.Meta["span.kind"])
+			assert.Equal(t, int32(0), span.Error)
+			assert.Equal(t, int64(1000*time.Microsecond), span.Duration)
+		case 2:
+			assert.Equal(t, "client", span.Meta["span.kind"])
+			assert.Equal(t, "redis", span.Meta["peer.service"])
+			assert.Equal(t, int32(1), span.Error)
+			assert.Equal(t, uint64(1), span.ParentID)
+			assert.Equal(t, float64(3), span.Metrics["db.rows"])
+			assert.Equal(t, 0.5, span.Metrics["cache.ratio"])
+			assert.Contains(t, span.Meta["events"], `"name":"retry"`)
+		default:
+			assert.Failf(t, "unexpected span", "%v", span)
+		}
+	}
+
+	resp, err = http.Post(server.URL+"/api/traces", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	for name, body := range map[string][]byte{
+		"truncated":    body[:len(body)/2],
+		"invalid list": {thriftList, 0, 2, thriftStruct, 0x7f, 0xff, 0xff, 0xff},
+		"unknown type": {0x42, 0, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/api/traces", "application/x-thrift", bytes.NewReader(body))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+// rawCodec passes the messages as is, for the tests to send hand encoded protobuf messages.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error)   { return v.([]byte), nil }
+func (rawCodec) Unmarshal(_ []byte, _ any) error { return nil }
+func (rawCodec) Name() string                    { return "proto" }
+
+func appendProtoBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendProtoVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// encodeJaegerPostSpansRequest encodes a jaeger.api_v2.PostSpansRequest with a span from the given service.
+func encodeJaegerPostSpansRequest(service string, traceIDLow, spanID uint64, start time.Time, tags map[string]string) []byte {
+	traceID := make([]byte, 16)
+	binary.BigEndian.PutUint64(traceID[8:], traceIDLow)
+	sid := make([]byte, 8)
+	binary.BigEndian.PutUint64(sid, spanID)
+
+	var span []byte
+	span = appendProtoBytes(span, 1, traceID)
+	span = appendProtoBytes(span, 2, sid)
+	span = appendProtoBytes(span, 3, []byte("reserve"))
+	var ts []byte
+	ts = appendProtoVarint(ts, 1, uint64(start.Unix()))
+	ts = appendProtoVarint(ts, 2, uint64(start.Nanosecond()))
+	span = appendProtoBytes(span, 6, ts)
+	span = appendProtoBytes(span, 7, appendProtoVarint(nil, 2, uint64(time.Millisecond)))
+	for k, v := range tags {
+		var kv []byte
+		kv = appendProtoBytes(kv, 1, []byte(k))
+		kv = appendProtoBytes(kv, 3, []byte(v))
+		span = appendProtoBytes(span, 8, kv)
+	}
+	errTag := appendProtoBytes(nil, 1, []byte("error"))
+	errTag = appendProtoVarint(errTag, 2, 1)
+	errTag = appendProtoVarint(errTag, 4, 1)
+	span = appendProtoBytes(span, 8, errTag)
+
+	var batch []byte
+	batch = appendProtoBytes(batch, 1, span)
+	batch = appendProtoBytes(batch, 2, appendProtoBytes(nil, 1, []byte(service)))
+	return appendProtoBytes(nil, 1, batch)
+}
+
+func TestJaegerGRPC(t *testing.T) {
+	conf := newForeignTracesTestConfig(t)
+	conf.JaegerReceiver.GRPCPort = testutil.FreeTCPPort(t)
+	out := make(chan *Payload, 1)
+	j := NewJaegerReceiver(NewOTLPReceiver(out, conf, &statsd.NoOpClient{}, &timing.NoopReporter{}), conf, &statsd.NoOpClient{}, &timing.NoopReporter{})
+	j.Start()
+	defer j.Stop()
+
+	conn, err := grpc.NewClient(fmt.Sprintf("%s:%d", conf.ReceiverHost, conf.JaegerReceiver.GRPCPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	req := encodeJaegerPostSpansRequest("inventory", 2, 3, time.Now(), map[string]string{"span.kind": "producer"})
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "datadog-meta-lang", "java")
+	var resp []byte
+	err = conn.Invoke(ctx, "/jaeger.api_v2.CollectorService/PostSpans", req, &resp, grpc.ForceCodec(rawCodec{}))
+	require.NoError(t, err)
+
+	p := receivePayload(t, out)
+	assert.Equal(t, "jaeger_grpc", p.Source.EndpointVersion)
+	assert.Equal(t, "java", p.Source.Lang)
+	require.Len(t, p.TracerPayload.Chunks, 1)
+	span := p.TracerPayload.Chunks[0].Spans[0]
+	assert.Equal(t, "inventory", span.Service)
+	assert.Equal(t, "producer", span.Meta["span.kind"])
+	assert.Equal(t, uint64(2), span.TraceID)
+	assert.Equal(t, uint64(3), span.SpanID)
+	assert.Equal(t, int64(time.Millisecond), span.Duration)
+	assert.Equal(t, int32(1), span.Error)
+
+	err = conn.Invoke(ctx, "/jaeger.api_v2.CollectorService/PostSpans", []byte{0x0a, 0x05, 0x0a}, &resp, grpc.ForceCodec(rawCodec{}))
+	assert.Error(t, err)
+}
+
+func TestHeaderFromMetadata(t *testing.T) {
+	h := headerFromMetadata(metadata.Pairs("datadog-meta-lang", "go", "x-datadog-reported-languages", "go"))
+	assert.Equal(t, "go", h.Get("Datadog-Meta-Lang"))
+	assert.Equal(t, []string{"go"}, h["Datadog-Meta-Lang"])
+	assert.Equal(t, "go", h.Get("X-Datadog-Reported-Languages"))
+}
@@ -0,0 +1,322 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Thrift binary protocol field types.
+const (
+	thriftStop   byte = 0
+	thriftBool   byte = 2
+	thriftByte   byte = 3
+	thriftDouble byte = 4
+	thriftI16    byte = 6
+	thriftI32    byte = 8
+	thriftI64    byte = 10
+	thriftString byte = 11
+	thriftStruct byte = 12
+	thriftMap    byte = 13
+	thriftSet    byte = 14
+	thriftList   byte = 15
+)
+
+// thriftMaxSkipDepth limits the nesting of the unknown fields skipped by thriftReader.
+const thriftMaxSkipDepth = 64
+
+var errThriftShortBuffer = errors.New("thrift: unexpected end of payload")
+
+// thriftReader reads values encoded with the Thrift binary protocol. After the first error, all the
+// reads return zero values and the error is reported by err.
+type thriftReader struct {
+	buf []byte
+	err error
+}
+
+// next consumes the n next bytes of the buffer.
+func (r *thriftReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.buf) {
+		r.err = errThriftShortBuffer
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *thriftReader) readByte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *thriftReader) readBool() bool { return r.readByte() == 1 }
+
+func (r *thriftReader) readI16() int16 {
+	if b := r.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *thriftReader) readI32() int32 {
+	if b := r.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *thriftReader) readI64() int64 {
+	if b := r.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (r *thriftReader) readDouble() float64 {
+	return math.Float64frombits(uint64(r.readI64()))
+}
+
+func (r *thriftReader) readBinary() []byte {
+	return r.next(int(r.readI32()))
+}
+
+func (r *thriftReader) readString() string {
+	return string(r.readBinary())
+}
+
+// readList reads the header of a list of elements of type typ and returns its size.
+func (r *thriftReader) readList(typ byte) int {
+	elemType, size := r.readByte(), int(r.readI32())
+	if r.err != nil {
+		return 0
+	}
+	if elemType != typ {
+		r.err = fmt.Errorf("thrift: unexpected list element type %d, expected %d", elemType, typ)
+		return 0
+	}
+	if size < 0 || size > len(r.buf) {
+		// each element takes at least a byte
+		r.err = fmt.Errorf("thrift: invalid list size %d", size)
+		return 0
+	}
+	return size
+}
+
+// readStruct reads the fields of a struct, calling field for each of them. field returns false when it
+// does not read the field, in which case it is skipped.
+func (r *thriftReader) readStruct(field func(id int16, typ byte) bool) {
+	for r.err == nil {
+		typ := r.readByte()
+		if typ == thriftStop {
+			return
+		}
+		id := r.readI16()
+		if r.err != nil {
+			return
+		}
+		if !field(id, typ) {
+			r.skip(typ, 0)
+		}
+	}
+}
+
+// skip skips a value of type typ.
+func (r *thriftReader) skip(typ byte, depth int) {
+	if depth > thriftMaxSkipDepth {
+		r.err = errors.New("thrift: maximum nesting depth exceeded")
+		return
+	}
+	switch typ {
+	case thriftBool, thriftByte:
+		r.next(1)
+	case thriftI16:
+		r.next(2)
+	case thriftI32:
+		r.next(4)
+	case thriftDouble, thriftI64:
+		r.next(8)
+	case thriftString:
+		r.readBinary()
+	case thriftStruct:
+		for r.err == nil {
+			ftyp := r.readByte()
+			if ftyp == thriftStop {
+				return
+			}
+			r.readI16()
+			r.skip(ftyp, depth+1)
+		}
+	case thriftMap:
+		ktyp, vtyp, size := r.readByte(), r.readByte(), int(r.readI32())
+		if size < 0 || size > len(r.buf) {
+			r.err = fmt.Errorf("thrift: invalid map size %d", size)
+			return
+		}
+		for i := 0; i < size && r.err == nil; i++ {
+			r.skip(ktyp, depth+1)
+			r.skip(vtyp, depth+1)
+		}
+	case thriftSet, thriftList:
+		etyp, size := r.readByte(), int(r.readI32())
+		if size < 0 || size > len(r.buf) {
+			r.err = fmt.Errorf("thrift: invalid list size %d", size)
+			return
+		}
+		for i := 0; i < size && r.err == nil; i++ {
+			r.skip(etyp, depth+1)
+		}
+	default:
+		r.err = fmt.Errorf("thrift: unknown field type %d", typ)
+	}
+}
+
+// decodeJaegerThriftBatch decodes a jaeger.Batch encoded with the Thrift binary protocol, as defined in
+// https://github.com/jaegertracing/jaeger-idl/blob/main/thrift/jaeger.thrift.
+func decodeJaegerThriftBatch(b []byte) (*jaegerBatch, error) {
+	r := &thriftReader{buf: b}
+	batch := &jaegerBatch{}
+	r.readStruct(func(id int16, typ byte) bool {
+		switch {
+		case id == 1 && typ == thriftStruct:
+			r.readStruct(func(id int16, typ byte) bool {
+				switch {
+				case id == 1 && typ == thriftString:
+					batch.process.serviceName = r.readString()
+				case id == 2 && typ == thriftList:
+					batch.process.tags = r.readJaegerTags()
+				default:
+					return false
+				}
+				return true
+			})
+		case id == 2 && typ == thriftList:
+			batch.spans = make([]jaegerSpan, r.readList(thriftStruct))
+			for i := range batch.spans {
+				r.readJaegerSpan(&batch.spans[i])
+			}
+		default:
+			return false
+		}
+		return true
+	})
+	if r.err != nil {
+		return nil, r.err
+	}
+	return batch, nil
+}
+
+func (r *thriftReader) readJaegerSpan(span *jaegerSpan) {
+	var start, duration int64 // in microseconds
+	r.readStruct(func(id int16, typ byte) bool {
+		switch {
+		case id == 1 && typ == thriftI64:
+			span.traceIDLow = uint64(r.readI64())
+		case id == 2 && typ == thriftI64:
+			span.traceIDHigh = uint64(r.readI64())
+		case id == 3 && typ == thriftI64:
+			span.spanID = uint64(r.readI64())
+		case id == 4 && typ == thriftI64:
+			span.parentSpanID = uint64(r.readI64())
+		case id == 5 && typ == thriftString:
+			span.operationName = r.readString()
+		case id == 6 && typ == thriftList:
+			span.references = make([]jaegerSpanRef, r.readList(thriftStruct))
+			for i := range span.references {
+				r.readJaegerSpanRef(&span.references[i])
+			}
+		case id == 8 && typ == thriftI64:
+			start = r.readI64()
+		case id == 9 && typ == thriftI64:
+			duration = r.readI64()
+		case id == 10 && typ == thriftList:
+			span.tags = r.readJaegerTags()
+		case id == 11 && typ == thriftList:
+			span.logs = make([]jaegerLog, r.readList(thriftStruct))
+			for i := range span.logs {
+				r.readJaegerLog(&span.logs[i])
+			}
+		default:
+			return false
+		}
+		return true
+	})
+	span.start = pcommon.Timestamp(start * int64(time.Microsecond))
+	span.duration = time.Duration(duration) * time.Microsecond
+}
+
+func (r *thriftReader) readJaegerSpanRef(ref *jaegerSpanRef) {
+	r.readStruct(func(id int16, typ byte) bool {
+		switch {
+		case id == 1 && typ == thriftI32:
+			ref.refType = jaegerRefType(r.readI32())
+		case id == 2 && typ == thriftI64:
+			ref.traceIDLow = uint64(r.readI64())
+		case id == 3 && typ == thriftI64:
+			ref.traceIDHigh = uint64(r.readI64())
+		case id == 4 && typ == thriftI64:
+			ref.spanID = uint64(r.readI64())
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+func (r *thriftReader) readJaegerLog(log *jaegerLog) {
+	r.readStruct(func(id int16, typ byte) bool {
+		switch {
+		case id == 1 && typ == thriftI64:
+			log.timestamp = pcommon.Timestamp(r.readI64() * int64(time.Microsecond))
+		case id == 2 && typ == thriftList:
+			log.fields = r.readJaegerTags()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+func (r *thriftReader) readJaegerTags() []jaegerTag {
+	tags := make([]jaegerTag, r.readList(thriftStruct))
+	for i := range tags {
+		tag := &tags[i]
+		r.readStruct(func(id int16, typ byte) bool {
+			switch {
+			case id == 1 && typ == thriftString:
+				tag.key = r.readString()
+			case id == 2 && typ == thriftI32:
+				// the Thrift tag types share the values of jaegerTagType
+				tag.vType = jaegerTagType(r.readI32())
+			case id == 3 && typ == thriftString:
+				tag.vStr = r.readString()
+			case id == 4 && typ == thriftDouble:
+				tag.vDouble = r.readDouble()
+			case id == 5 && typ == thriftBool:
+				tag.vBool = r.readBool()
+			case id == 6 && typ == thriftI64:
+				tag.vLong = r.readI64()
+			case id == 7 && typ == thriftString:
+				tag.vBinary = r.readBinary()
+			default:
+				return false
+			}
+			return true
+		})
+	}
+	return tags
+}
@@ -44,6 +44,17 @@ const keyStatsComputed = "_dd.stats_computed"
 
 var _ (ptraceotlp.GRPCServer) = (*OTLPReceiver)(nil)
 
+// spanIntake describes the intake which received the spans converted by the OTLPReceiver.
+type spanIntake struct {
+	// endpointVersion is the endpoint version reported in the receiver stats.
+	endpointVersion string
+	// tracerVersionPrefix prefixes the SDK version in the tracer version of the payloads.
+	tracerVersionPrefix string
+}
+
+// otlpIntake is the intake of the spans received over OTLP.
+var otlpIntake = spanIntake{endpointVersion: "opentelemetry_grpc_v1", tracerVersionPrefix: "otlp"}
+
 // OTLPReceiver implements an OpenTelemetry Collector receiver which accepts incoming
 // data on two ports for both plain HTTP and gRPC.
 type OTLPReceiver struct {
@@ -141,9 +152,14 @@ func fastHeaderGet(h http.Header, canonicalKey string) string {
 
 // processRequest processes the incoming request in.
 func (o *OTLPReceiver) processRequest(ctx context.Context, header http.Header, in ptraceotlp.ExportRequest) {
-	for i := 0; i < in.Traces().ResourceSpans().Len(); i++ {
-		rspans := in.Traces().ResourceSpans().At(i)
-		o.ReceiveResourceSpans(ctx, rspans, header)
+	o.receiveTraces(ctx, header, in.Traces(), otlpIntake)
+}
+
+// receiveTraces processes the traces received by the given intake.
+func (o *OTLPReceiver) receiveTraces(ctx context.Context, header http.Header, traces ptrace.Traces, intake spanIntake) {
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rspans := traces.ResourceSpans().At(i)
+		o.receiveResourceSpans(ctx, rspans, header, intake)
 	}
 }
 
@@ -187,6 +203,12 @@ func (o *OTLPReceiver) SetOTelAttributeTranslator(attrstrans *attributes.Transla
 
 // ReceiveResourceSpans processes the given rspans and returns the source that it identified from processing them.
 func (o *OTLPReceiver) ReceiveResourceSpans(ctx context.Context, rspans ptrace.ResourceSpans, httpHeader http.Header) source.Source {
+	return o.receiveResourceSpans(ctx, rspans, httpHeader, otlpIntake)
+}
+
+// receiveResourceSpans processes the given rspans received by the given intake and returns the source that it
+// identified from processing them.
+func (o *OTLPReceiver) receiveResourceSpans(ctx context.Context, rspans ptrace.ResourceSpans, httpHeader http.Header, intake spanIntake) source.Source {
 	// each rspans is coming from a different resource and should be considered
 	// a separate payload; typically there is only one item in this slice
 	src, srcok := o.conf.OTLPReceiver.AttributesTranslator.ResourceToSource(ctx, rspans.Resource(), traceutil.SignalTypeSet)
@@ -223,8 +245,8 @@ func (o *OTLPReceiver) ReceiveResourceSpans(ctx context.Context, rspans ptrace.R
 			LangVersion:     fastHeaderGet(httpHeader, header.LangVersion),
 			Interpreter:     fastHeaderGet(httpHeader, header.LangInterpreter),
 			LangVendor:      fastHeaderGet(httpHeader, header.LangInterpreterVendor),
-			TracerVersion:   fmt.Sprintf("otlp-%s", rattr[string(semconv.AttributeTelemetrySDKVersion)]),
-			EndpointVersion: "opentelemetry_grpc_v1",
+			TracerVersion:   fmt.Sprintf("%s-%s", intake.tracerVersionPrefix, rattr[string(semconv.AttributeTelemetrySDKVersion)]),
+			EndpointVersion: intake.endpointVersion,
 		},
 		Stats: info.NewStats(),
 	}
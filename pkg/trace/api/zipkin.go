@@ -0,0 +1,353 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/collector/semconv/v1.6.1"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// zipkinIntake is the intake of the Zipkin v2 spans.
+var zipkinIntake = spanIntake{endpointVersion: "zipkin_v2", tracerVersionPrefix: "zipkin"}
+
+// handleZipkinSpans handles a list of Zipkin v2 spans, encoded in JSON or protobuf.
+func (r *HTTPReceiver) handleZipkinSpans(w http.ResponseWriter, req *http.Request) {
+	var decode func([]byte) ([]zipkinSpan, error)
+	switch mediaType := getMediaType(req); mediaType {
+	case "application/json":
+		decode = decodeZipkinJSON
+	case "application/x-protobuf", "application/protobuf":
+		decode = decodeZipkinProto
+	default:
+		httpFormatError(w, Version(zipkinIntake.endpointVersion), fmt.Errorf("unsupported media type: %q", mediaType), r.statsd)
+		return
+	}
+	r.handleForeignTraces(w, req, zipkinIntake, func(body []byte) (ptrace.Traces, error) {
+		spans, err := decode(body)
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		return zipkinToTraces(spans), nil
+	})
+}
+
+// zipkinSpan is a Zipkin v2 span, as defined in https://github.com/openzipkin/zipkin-api.
+type zipkinSpan struct {
+	TraceID        zipkinTraceID      `json:"traceId"`
+	ParentID       zipkinID           `json:"parentId"`
+	ID             zipkinID           `json:"id"`
+	Kind           string             `json:"kind"`
+	Name           string             `json:"name"`
+	Timestamp      uint64             `json:"timestamp"` // in microseconds
+	Duration       uint64             `json:"duration"`  // in microseconds
+	LocalEndpoint  zipkinEndpoint     `json:"localEndpoint"`
+	RemoteEndpoint zipkinEndpoint     `json:"remoteEndpoint"`
+	Annotations    []zipkinAnnotation `json:"annotations"`
+	Tags           map[string]string  `json:"tags"`
+}
+
+// zipkinEndpoint is the network context of a node in the service graph.
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+	IPv4        string `json:"ipv4"`
+	IPv6        string `json:"ipv6"`
+	Port        int32  `json:"port"`
+}
+
+// zipkinAnnotation is a timestamped event of a Zipkin span.
+type zipkinAnnotation struct {
+	Timestamp uint64 `json:"timestamp"` // in microseconds
+	Value     string `json:"value"`
+}
+
+// zipkinTraceID is a 64 or 128 bits Zipkin trace ID, encoded as lowercase hex in JSON.
+type zipkinTraceID struct {
+	High, Low uint64
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *zipkinTraceID) UnmarshalText(b []byte) error {
+	if len(b) == 0 || len(b) > 32 {
+		return fmt.Errorf("invalid Zipkin trace ID %q", b)
+	}
+	var err error
+	if len(b) > 16 {
+		if id.High, err = strconv.ParseUint(string(b[:len(b)-16]), 16, 64); err != nil {
+			return fmt.Errorf("invalid Zipkin trace ID %q", b)
+		}
+		b = b[len(b)-16:]
+	}
+	if id.Low, err = strconv.ParseUint(string(b), 16, 64); err != nil {
+		return fmt.Errorf("invalid Zipkin trace ID %q", b)
+	}
+	return nil
+}
+
+// zipkinID is a 64 bits Zipkin span ID, encoded as lowercase hex in JSON.
+type zipkinID uint64
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *zipkinID) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	v, err := strconv.ParseUint(string(b), 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Zipkin span ID %q", b)
+	}
+	*id = zipkinID(v)
+	return nil
+}
+
+// decodeZipkinJSON decodes a list of Zipkin v2 spans encoded in JSON.
+func decodeZipkinJSON(b []byte) ([]zipkinSpan, error) {
+	var spans []zipkinSpan
+	if err := json.Unmarshal(b, &spans); err != nil {
+		return nil, err
+	}
+	return spans, nil
+}
+
+// zipkinProtoKinds maps the span kinds of the protobuf Zipkin spans to their JSON value.
+var zipkinProtoKinds = [...]string{"", "CLIENT", "SERVER", "PRODUCER", "CONSUMER"}
+
+// decodeZipkinProto decodes a list of Zipkin v2 spans encoded in protobuf, as defined in
+// https://github.com/openzipkin/zipkin-api/blob/master/zipkin.proto.
+func decodeZipkinProto(b []byte) ([]zipkinSpan, error) {
+	var spans []zipkinSpan
+	err := rangeProtoFields(b, func(num protowire.Number, _ uint64, data []byte) error {
+		if num != 1 {
+			return nil
+		}
+		spans = append(spans, zipkinSpan{})
+		return decodeZipkinProtoSpan(data, &spans[len(spans)-1])
+	})
+	return spans, err
+}
+
+func decodeZipkinProtoSpan(b []byte, span *zipkinSpan) error {
+	return rangeProtoFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		var err error
+		switch num {
+		case 1:
+			switch len(data) {
+			case 8:
+				span.TraceID.Low = binary.BigEndian.Uint64(data)
+			case 16:
+				span.TraceID.High, span.TraceID.Low = binary.BigEndian.Uint64(data[:8]), binary.BigEndian.Uint64(data[8:])
+			default:
+				err = fmt.Errorf("invalid Zipkin trace ID length %d", len(data))
+			}
+		case 2:
+			err = decodeZipkinProtoID(data, &span.ParentID)
+		case 3:
+			err = decodeZipkinProtoID(data, &span.ID)
+		case 4:
+			if v < uint64(len(zipkinProtoKinds)) {
+				span.Kind = zipkinProtoKinds[v]
+			}
+		case 5:
+			span.Name = string(data)
+		case 6:
+			span.Timestamp = v
+		case 7:
+			span.Duration = v
+		case 8:
+			err = decodeZipkinProtoEndpoint(data, &span.LocalEndpoint)
+		case 9:
+			err = decodeZipkinProtoEndpoint(data, &span.RemoteEndpoint)
+		case 10:
+			var a zipkinAnnotation
+			err = rangeProtoFields(data, func(num protowire.Number, v uint64, data []byte) error {
+				switch num {
+				case 1:
+					a.Timestamp = v
+				case 2:
+					a.Value = string(data)
+				}
+				return nil
+			})
+			span.Annotations = append(span.Annotations, a)
+		case 11:
+			var key, value string
+			err = rangeProtoFields(data, func(num protowire.Number, _ uint64, data []byte) error {
+				switch num {
+				case 1:
+					key = string(data)
+				case 2:
+					value = string(data)
+				}
+				return nil
+			})
+			if span.Tags == nil {
+				span.Tags = make(map[string]string)
+			}
+			span.Tags[key] = value
+		}
+		return err
+	})
+}
+
+func decodeZipkinProtoID(b []byte, id *zipkinID) error {
+	if len(b) != 8 {
+		return fmt.Errorf("invalid Zipkin span ID length %d", len(b))
+	}
+	*id = zipkinID(binary.BigEndian.Uint64(b))
+	return nil
+}
+
+func decodeZipkinProtoEndpoint(b []byte, e *zipkinEndpoint) error {
+	return rangeProtoFields(b, func(num protowire.Number, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			e.ServiceName = string(data)
+		case 2:
+			e.IPv4 = net.IP(data).String()
+		case 3:
+			e.IPv6 = net.IP(data).String()
+		case 4:
+			if v > math.MaxUint16 {
+				return fmt.Errorf("invalid Zipkin endpoint port %d", v)
+			}
+			e.Port = int32(v)
+		}
+		return nil
+	})
+}
+
+// zipkinStatusCodes maps the values of the otel.status_code tag set by the OpenTelemetry Zipkin exporters
+// to a status code.
+var zipkinStatusCodes = map[string]ptrace.StatusCode{
+	"STATUS_CODE_UNSET": ptrace.StatusCodeUnset,
+	"STATUS_CODE_OK":    ptrace.StatusCodeOk,
+	"STATUS_CODE_ERROR": ptrace.StatusCodeError,
+	"Unset":             ptrace.StatusCodeUnset,
+	"Ok":                ptrace.StatusCodeOk,
+	"Error":             ptrace.StatusCodeError,
+}
+
+// zipkinToTraces converts the spans to OTLP, the same way the OpenTelemetry Collector Zipkin receiver
+// does. The spans are grouped in resources by local service name, and in scopes by instrumentation
+// library.
+func zipkinToTraces(spans []zipkinSpan) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	type scopeKey struct{ service, library, version string }
+	resources := make(map[string]ptrace.ResourceSpans)
+	scopes := make(map[scopeKey]ptrace.SpanSlice)
+	for i := range spans {
+		span := &spans[i]
+		service := span.LocalEndpoint.ServiceName
+		rs, ok := resources[service]
+		if !ok {
+			rs = traces.ResourceSpans().AppendEmpty()
+			if service != "" {
+				rs.Resource().Attributes().PutStr(semconv.AttributeServiceName, service)
+			}
+			resources[service] = rs
+		}
+		key := scopeKey{service: service, library: span.Tags[semconv.OtelLibraryName], version: span.Tags[semconv.OtelLibraryVersion]}
+		ss, ok := scopes[key]
+		if !ok {
+			scope := rs.ScopeSpans().AppendEmpty()
+			scope.Scope().SetName(key.library)
+			scope.Scope().SetVersion(key.version)
+			ss = scope.Spans()
+			scopes[key] = ss
+		}
+		span.toSpan(ss.AppendEmpty())
+	}
+	return traces
+}
+
+func (s *zipkinSpan) toSpan(dest ptrace.Span) {
+	dest.SetTraceID(otlpTraceID(s.TraceID.High, s.TraceID.Low))
+	dest.SetSpanID(otlpSpanID(uint64(s.ID)))
+	if s.ParentID != 0 && s.ParentID != s.ID {
+		dest.SetParentSpanID(otlpSpanID(uint64(s.ParentID)))
+	}
+	dest.SetName(s.Name)
+	start := pcommon.Timestamp(s.Timestamp * uint64(time.Microsecond))
+	dest.SetStartTimestamp(start)
+	dest.SetEndTimestamp(start + pcommon.Timestamp(s.Duration*uint64(time.Microsecond)))
+
+	tags := make(map[string]string, len(s.Tags))
+	for k, v := range s.Tags {
+		tags[k] = v
+	}
+	delete(tags, semconv.OtelLibraryName)
+	delete(tags, semconv.OtelLibraryVersion)
+	switch s.Kind {
+	case "CLIENT":
+		dest.SetKind(ptrace.SpanKindClient)
+	case "SERVER":
+		dest.SetKind(ptrace.SpanKindServer)
+	case "PRODUCER":
+		dest.SetKind(ptrace.SpanKindProducer)
+	case "CONSUMER":
+		dest.SetKind(ptrace.SpanKindConsumer)
+	default:
+		if tags["span.kind"] == "internal" {
+			dest.SetKind(ptrace.SpanKindInternal)
+		}
+		delete(tags, "span.kind")
+	}
+	if code, ok := tags[semconv.OtelStatusCode]; ok {
+		dest.Status().SetCode(zipkinStatusCodes[code])
+		dest.Status().SetMessage(tags[semconv.OtelStatusDescription])
+		delete(tags, semconv.OtelStatusCode)
+		delete(tags, semconv.OtelStatusDescription)
+	}
+	if v, ok := tags["error"]; ok {
+		// the error tag holds the error message, if any
+		dest.Status().SetCode(ptrace.StatusCodeError)
+		if v == "true" {
+			delete(tags, "error")
+		}
+	}
+
+	attrs := dest.Attributes()
+	attrs.EnsureCapacity(len(tags) + 6)
+	for k, v := range tags {
+		attrs.PutStr(k, v)
+	}
+	putZipkinEndpoint(attrs, s.LocalEndpoint, semconv.AttributeNetHostIP, semconv.AttributeNetHostPort)
+	putZipkinEndpoint(attrs, s.RemoteEndpoint, semconv.AttributeNetPeerIP, semconv.AttributeNetPeerPort)
+	if s.RemoteEndpoint.ServiceName != "" {
+		attrs.PutStr(semconv.AttributePeerService, s.RemoteEndpoint.ServiceName)
+	}
+
+	events := dest.Events()
+	events.EnsureCapacity(len(s.Annotations))
+	for _, a := range s.Annotations {
+		event := events.AppendEmpty()
+		event.SetTimestamp(pcommon.Timestamp(a.Timestamp * uint64(time.Microsecond)))
+		event.SetName(a.Value)
+	}
+}
+
+// putZipkinEndpoint sets the IP and port of the endpoint e in the given attributes.
+func putZipkinEndpoint(attrs pcommon.Map, e zipkinEndpoint, ipKey, portKey string) {
+	if e.IPv4 != "" {
+		attrs.PutStr(ipKey, e.IPv4)
+	}
+	if e.IPv6 != "" {
+		attrs.PutStr(ipKey, e.IPv6)
+	}
+	if e.Port > 0 {
+		attrs.PutInt(portKey, int64(e.Port))
+	}
+}
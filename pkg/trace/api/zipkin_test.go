@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/timing"
+)
+
+const zipkinTestPayload = `[
+  {
+    "traceId": "5af7183fb1d4cf5f",
+    "id": "352bff9a74ca9ad2",
+    "name": "get /api",
+    "kind": "SERVER",
+    "timestamp": 1556604172355737,
+    "duration": 1431,
+    "localEndpoint": {"serviceName": "frontend"},
+    "tags": {"http.method": "GET", "http.status_code": "200"}
+  },
+  {
+    "traceId": "5af7183fb1d4cf5f",
+    "id": "6b221d5bc9e6496c",
+    "parentId": "352bff9a74ca9ad2",
+    "name": "query",
+    "kind": "CLIENT",
+    "timestamp": 1556604172355800,
+    "duration": 500,
+    "localEndpoint": {"serviceName": "frontend"},
+    "remoteEndpoint": {"serviceName": "postgres", "port": 5432},
+    "tags": {"error": "connection refused"}
+  }
+]`
+
+func newForeignTracesTestConfig(t *testing.T) *config.AgentConfig {
+	conf := newTestReceiverConfig()
+	attributesTranslator, err := attributes.NewTranslator(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	conf.OTLPReceiver.AttributesTranslator = attributesTranslator
+	return conf
+}
+
+// newForeignTracesTestReceiver returns an HTTPReceiver processing the spans received in foreign formats with an OTLPReceiver.
+func newForeignTracesTestReceiver(conf *config.AgentConfig) *HTTPReceiver {
+	r := newTestReceiverFromConfig(conf)
+	r.SetOTLPReceiver(NewOTLPReceiver(r.out, conf, &statsd.NoOpClient{}, &timing.NoopReporter{}))
+	return r
+}
+
+func receivePayload(t *testing.T, out chan *Payload) *Payload {
+	select {
+	case p := <-out:
+		return p
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no payload received")
+	}
+	return nil
+}
+
+func spansByName(p *Payload) map[string]*pb.Span {
+	spans := map[string]*pb.Span{}
+	for _, chunk := range p.TracerPayload.Chunks {
+		for _, span := range chunk.Spans {
+			spans[span.Resource] = span
+		}
+	}
+	return spans
+}
+
+func TestZipkinSpans(t *testing.T) {
+	conf := newForeignTracesTestConfig(t)
+	conf.ZipkinReceiver.Enabled = true
+	r := newForeignTracesTestReceiver(conf)
+	server := httptest.NewServer(r.buildMux())
+	defer server.Close()
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err := gz.Write([]byte(zipkinTestPayload))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	for name, body := range map[string]*bytes.Buffer{
+		"plain": bytes.NewBufferString(zipkinTestPayload),
+		"gzip":  &gzipped,
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/api/v2/spans", body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if name == "gzip" {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+			p := receivePayload(t, r.out)
+			assert.Equal(t, "zipkin_v2", p.Source.EndpointVersion)
+			require.Len(t, p.TracerPayload.Chunks, 1)
+			require.Len(t, p.TracerPayload.Chunks[0].Spans, 2)
+
+			spans := spansByName(p)
+			server, client := spans["GET"], spans["query"]
+			require.NotNil(t, server)
+			require.NotNil(t, client)
+
+			assert.Equal(t, "frontend", server.Service)
+			assert.Equal(t, "server", server.Meta["span.kind"])
+			assert.Equal(t, "web", server.Type)
+			assert.Equal(t, int32(0), server.Error)
+
+			assert.Equal(t, "frontend", client.Service)
+			assert.Equal(t, "client", client.Meta["span.kind"])
+			assert.Equal(t, "postgres", client.Meta["peer.service"])
+			assert.Equal(t, int32(1), client.Error)
+			assert.Equal(t, server.SpanID, client.ParentID)
+		})
+	}
+}
+
+func TestZipkinSpansErrors(t *testing.T) {
+	conf := newForeignTracesTestConfig(t)
+	conf.ZipkinReceiver.Enabled = true
+	server := httptest.NewServer(newForeignTracesTestReceiver(conf).buildMux())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/api/v2/spans", "text/plain", bytes.NewBufferString(zipkinTestPayload))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/api/v2/spans", "application/json", bytes.NewBufferString("[{"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/v2/spans")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	// the endpoint is disabled by default
+	disabled := httptest.NewServer(newForeignTracesTestReceiver(newForeignTracesTestConfig(t)).buildMux())
+	defer disabled.Close()
+	resp, err = http.Post(disabled.URL+"/api/v2/spans", "application/json", bytes.NewBufferString(zipkinTestPayload))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestZipkinProtobuf(t *testing.T) {
+	conf := newForeignTracesTestConfig(t)
+	conf.ZipkinReceiver.Enabled = true
+	r := newForeignTracesTestReceiver(conf)
+	server := httptest.NewServer(r.buildMux())
+	defer server.Close()
+
+	id := func(v uint64) []byte { return binary.BigEndian.AppendUint64(nil, v) }
+	var remote []byte
+	remote = appendProtoBytes(remote, 1, []byte("redis"))
+	remote = appendProtoBytes(remote, 2, []byte{10, 0, 0, 1})
+	remote = appendProtoVarint(remote, 4, 6379)
+	var tag []byte
+	tag = appendProtoBytes(tag, 1, []byte("otel.status_code"))
+	tag = appendProtoBytes(tag, 2, []byte("STATUS_CODE_ERROR"))
+	var span []byte
+	span = appendProtoBytes(span, 1, append(id(5), id(6)...))
+	span = appendProtoBytes(span, 2, id(7))
+	span = appendProtoBytes(span, 3, id(8))
+	span = appendProtoVarint(span, 4, 1) // CLIENT
+	span = appendProtoBytes(span, 5, []byte("get"))
+	span = protowire.AppendTag(span, 6, protowire.Fixed64Type)
+	span = protowire.AppendFixed64(span, uint64(time.Now().UnixMicro()))
+	span = appendProtoVarint(span, 7, 250)
+	span = appendProtoBytes(span, 8, appendProtoBytes(nil, 1, []byte("cart")))
+	span = appendProtoBytes(span, 9, remote)
+	span = appendProtoBytes(span, 11, tag)
+	body := appendProtoBytes(nil, 1, span)
+
+	resp, err := http.Post(server.URL+"/api/v2/spans", "application/x-protobuf", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	p := receivePayload(t, r.out)
+	require.Len(t, p.TracerPayload.Chunks, 1)
+	s := p.TracerPayload.Chunks[0].Spans[0]
+	assert.Equal(t, "cart", s.Service)
+	assert.Equal(t, uint64(6), s.TraceID)
+	assert.Equal(t, uint64(8), s.SpanID)
+	assert.Equal(t, uint64(7), s.ParentID)
+	assert.Equal(t, int64(250*time.Microsecond), s.Duration)
+	assert.Equal(t, "client", s.Meta["span.kind"])
+	assert.Equal(t, "redis", s.Meta["peer.service"])
+	assert.Equal(t, "10.0.0.1", s.Meta["net.peer.ip"])
+	assert.Equal(t, float64(6379), s.Metrics["net.peer.port"])
+	assert.Equal(t, int32(1), s.Error)
+
+	resp, err = http.Post(server.URL+"/api/v2/spans", "application/x-protobuf", bytes.NewReader(body[:len(body)-3]))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -79,6 +79,24 @@ type OTLP struct {
 	AttributesTranslator *attributes.Translator `mapstructure:"-"`
 }
 
+// ZipkinReceiverConfig holds the configuration for the Zipkin receiver, which accepts Zipkin v2 spans
+// on the /api/v2/spans endpoint of the trace receiver.
+type ZipkinReceiverConfig struct {
+	// Enabled reports whether the /api/v2/spans endpoint is served.
+	Enabled bool
+}
+
+// JaegerReceiverConfig holds the configuration for the Jaeger collector receiver, which accepts Jaeger
+// batches as Thrift over HTTP on the /api/traces endpoint of the trace receiver, and over gRPC.
+type JaegerReceiverConfig struct {
+	// Enabled reports whether the /api/traces endpoint is served.
+	Enabled bool
+
+	// GRPCPort specifies the port of the Jaeger collector gRPC server.
+	// If unset (or 0), the gRPC server will be off.
+	GRPCPort int
+}
+
 // ObfuscationConfig holds the configuration for obfuscating sensitive data
 // for various span types.
 type ObfuscationConfig struct {
@@ -183,6 +201,47 @@ type ReplaceRule struct {
 	Repl string `mapstructure:"repl"`
 }
 
+// SamplingRule specifies the sampling rate of the traces whose root span matches it. Patterns may
+// contain * wildcards matching any sequence of characters, and empty patterns match all values.
+type SamplingRule struct {
+	// Service is the pattern matching the service of the root span.
+	Service string `mapstructure:"service"`
+
+	// Name is the pattern matching the operation name of the root span.
+	Name string `mapstructure:"name"`
+
+	// Resource is the pattern matching the resource of the root span.
+	Resource string `mapstructure:"resource"`
+
+	// Tags maps tag keys to the patterns their values must match on the root span.
+	Tags map[string]string `mapstructure:"tags"`
+
+	// SampleRate is the rate at which the matching traces are kept, between 0 and 1.
+	SampleRate float64 `mapstructure:"sample_rate"`
+
+	// MaxPerSecond is the maximum number of matching traces kept per second, 0 means no limit.
+	MaxPerSecond float64 `mapstructure:"max_per_second"`
+}
+
+// SpanMetricRule specifies a custom metric generated from the spans received by the agent, before
+// sampling.
+type SpanMetricRule struct {
+	// Name specifies the name of the generated metric.
+	Name string `mapstructure:"name"`
+
+	// Query selects the spans the metric is generated from. It is a space separated list of
+	// key:value terms which must all match, a term prefixed by "-" must not match.
+	Query string `mapstructure:"query"`
+
+	// Measure specifies the value of the metric: "count" (the default) counts the matching spans,
+	// "duration" generates a distribution of their durations in seconds, and "metric:<key>" a
+	// distribution of the numeric span metric <key>.
+	Measure string `mapstructure:"measure"`
+
+	// GroupBy specifies the span tags or fields the metric is tagged with.
+	GroupBy []string `mapstructure:"group_by"`
+}
+
 // WriterConfig specifies configuration for an API writer.
 type WriterConfig struct {
 	// ConnectionLimit specifies the maximum number of concurrent outgoing
@@ -198,6 +257,33 @@ type WriterConfig struct {
 	FlushPeriodSeconds float64 `mapstructure:"flush_period_seconds"`
 }
 
+// TraceInspectionConfig specifies the configuration of the buffer of recently received traces
+// exposed on the debug server.
+type TraceInspectionConfig struct {
+	// Enabled reports whether the received traces are kept for inspection.
+	Enabled bool
+
+	// BufferSize is the number of most recently received traces kept for inspection.
+	BufferSize int
+}
+
+// SpoolConfig specifies the configuration of the disk spool keeping the trace and stats payloads which
+// could not be sent to the intake, to retry them later.
+type SpoolConfig struct {
+	// Enabled reports whether payloads are spooled to disk instead of being dropped.
+	Enabled bool
+
+	// Path is the directory holding the spooled payloads.
+	Path string
+
+	// MaxSize is the maximum size in bytes of the payloads spooled for each endpoint. The oldest
+	// payloads are dropped to make room for new ones.
+	MaxSize int64
+
+	// MaxAge is the maximum time a payload is kept in the spool before it expires.
+	MaxAge time.Duration
+}
+
 // FargateOrchestratorName is a Fargate orchestrator name.
 type FargateOrchestratorName string
 
@@ -298,6 +384,12 @@ type AgentConfig struct {
 	PeerTagsAggregation    bool          // enables/disables stats aggregation for peer entity tags, used by Concentrator and ClientStatsAggregator
 	ComputeStatsBySpanKind bool          // enables/disables the computing of stats based on a span's `span.kind` field
 	PeerTags               []string      // additional tags to use for peer entity stats aggregation
+	// ExtraAggregationTags are span tags used as additional stats aggregation dimensions, used by Concentrator
+	// and ClientStatsAggregator
+	ExtraAggregationTags []string
+	// ExtraAggregationTagsCardinalityLimit is the maximum number of values of each extra aggregation tag in a
+	// stats flush. Once reached, new values are aggregated in an overflow bucket. 0 means no limit.
+	ExtraAggregationTagsCardinalityLimit int
 
 	// Sampler configuration
 	ExtraSampleRate float64
@@ -306,6 +398,10 @@ type AgentConfig struct {
 	MaxEPS          float64
 	MaxRemoteTPS    float64
 
+	// SamplingRules is the ordered list of sampling rules applied by the priority sampler to the traces
+	// whose sampling priority was set automatically by the tracers.
+	SamplingRules []*SamplingRule
+
 	// Rare Sampler configuration
 	RareSamplerEnabled        bool
 	RareSamplerTPS            int
@@ -340,6 +436,7 @@ type AgentConfig struct {
 	SynchronousFlushing     bool // Mode where traces are only submitted when FlushAsync is called, used for Serverless Extension
 	StatsWriter             *WriterConfig
 	TraceWriter             *WriterConfig
+	Spool                   SpoolConfig   // disk spool of the payloads the writers could not send
 	ConnectionResetInterval time.Duration // frequency at which outgoing connections are reset. 0 means no reset is performed
 	// MaxSenderRetries is the maximum number of retries that a sender will perform
 	// before giving up. Note that the sender may not perform all MaxSenderRetries if
@@ -379,6 +476,9 @@ type AgentConfig struct {
 	// GlobalTags list metadata that will be added to all spans
 	GlobalTags map[string]string
 
+	// SpanMetrics specifies the custom metrics generated from the received spans.
+	SpanMetrics []*SpanMetricRule
+
 	// transaction analytics
 	AnalyzedRateByServiceLegacy map[string]float64
 	AnalyzedSpansByService      map[string]map[string]float64
@@ -407,6 +507,12 @@ type AgentConfig struct {
 	// OTLPReceiver holds the configuration for OpenTelemetry receiver.
 	OTLPReceiver *OTLP
 
+	// ZipkinReceiver holds the configuration for the Zipkin receiver.
+	ZipkinReceiver ZipkinReceiverConfig
+
+	// JaegerReceiver holds the configuration for the Jaeger collector receiver.
+	JaegerReceiver JaegerReceiverConfig
+
 	// ProfilingProxy specifies settings for the profiling proxy.
 	ProfilingProxy ProfilingProxyConfig
 
@@ -445,6 +551,10 @@ type AgentConfig struct {
 	// DebugServerPort defines the port used by the debug server
 	DebugServerPort int
 
+	// TraceInspection holds the configuration of the inspection of the recently received traces on
+	// the debug server.
+	TraceInspection TraceInspectionConfig
+
 	// Install Signature
 	InstallSignature InstallSignatureConfig
 
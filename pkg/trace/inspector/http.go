@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+// RoutePrefix is the path of the debug server endpoint listing the traces. A trace is fetched at
+// RoutePrefix/<id>.
+const RoutePrefix = "/debug/traces"
+
+// Handler returns the handler serving the inspected traces:
+//   - GET /debug/traces lists the summaries of the traces, most recent first. They can be filtered
+//     with the service, resource, error, priority and kept query parameters, and limited with limit.
+//   - GET /debug/traces/<id> returns a trace along with its spans.
+func (in *Inspector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch id := strings.Trim(strings.TrimPrefix(req.URL.Path, RoutePrefix), "/"); id {
+		case "":
+			in.serveTraces(w, req)
+		default:
+			in.serveTrace(w, id)
+		}
+	})
+}
+
+func (in *Inspector) serveTraces(w http.ResponseWriter, req *http.Request) {
+	f, limit, err := parseFilter(req.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, in.Traces(f, limit))
+}
+
+func (in *Inspector) serveTrace(w http.ResponseWriter, id string) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid trace id %q", id), http.StatusBadRequest)
+		return
+	}
+	t := in.Get(n)
+	if t == nil {
+		http.Error(w, fmt.Sprintf("trace %d not found", n), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, t)
+}
+
+// parseFilter returns the filter and the limit of a request listing traces.
+func parseFilter(q url.Values) (f Filter, limit int, err error) {
+	f.Service = q.Get("service")
+	f.Resource = q.Get("resource")
+	if f.Error, err = parseBool(q, "error"); err != nil {
+		return f, 0, err
+	}
+	if f.Kept, err = parseBool(q, "kept"); err != nil {
+		return f, 0, err
+	}
+	if v := q.Get("priority"); v != "" {
+		p, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return f, 0, fmt.Errorf("invalid priority %q", v)
+		}
+		priority := int32(p)
+		f.Priority = &priority
+	}
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			return f, 0, fmt.Errorf("invalid limit %q", v)
+		}
+	}
+	return f, limit, nil
+}
+
+func parseBool(q url.Values, key string) (*bool, error) {
+	v := q.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return &b, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("Error writing inspected traces: %v", err)
+	}
+}
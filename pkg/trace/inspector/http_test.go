@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package inspector
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+)
+
+func TestHandler(t *testing.T) {
+	in := New(10)
+	in.Record(testTrace("web", "GET /users", sampler.PriorityAutoKeep, false))
+	in.Record(testTrace("web", "POST /checkout", sampler.PriorityUserKeep, true))
+	in.Record(testTrace("db", "SELECT ?", sampler.PriorityAutoDrop, false))
+	h := in.Handler()
+
+	get := func(t *testing.T, url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	t.Run("list", func(t *testing.T) {
+		rec := get(t, "/debug/traces?service=web&error=true")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var traces []Trace
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &traces))
+		require.Len(t, traces, 1)
+		assert.Equal(t, uint64(2), traces[0].ID)
+		assert.Equal(t, "POST /checkout", traces[0].Resource)
+		assert.Nil(t, traces[0].Spans)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		rec := get(t, "/debug/traces/?limit=2")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var traces []Trace
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &traces))
+		assert.Len(t, traces, 2)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		rec := get(t, "/debug/traces?priority=-1")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "[]\n", rec.Body.String())
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		rec := get(t, "/debug/traces/3")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var tr Trace
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tr))
+		assert.Equal(t, "db", tr.Service)
+		require.NotNil(t, tr.Priority)
+		assert.Equal(t, int32(sampler.PriorityAutoDrop), *tr.Priority)
+		assert.Len(t, tr.Spans, 2)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for url, code := range map[string]int{
+			"/debug/traces?error=maybe": http.StatusBadRequest,
+			"/debug/traces?kept=maybe":  http.StatusBadRequest,
+			"/debug/traces?priority=x":  http.StatusBadRequest,
+			"/debug/traces?limit=-1":    http.StatusBadRequest,
+			"/debug/traces/abc":         http.StatusBadRequest,
+			"/debug/traces/42":          http.StatusNotFound,
+		} {
+			assert.Equal(t, code, get(t, url).Code, url)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/traces", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
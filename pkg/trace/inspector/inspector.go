@@ -0,0 +1,226 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package inspector keeps the most recently received traces along with their sampling decision, to
+// inspect them locally through the debug server of the agent.
+package inspector
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+)
+
+// Decision describes the sampling decision made by the agent for a trace.
+type Decision struct {
+	// Kept reports whether the trace was kept.
+	Kept bool `json:"kept"`
+	// Sampler is the sampler which made the decision.
+	Sampler string `json:"sampler"`
+	// Rate is the sampling rate applied by the sampler, if any.
+	Rate float64 `json:"rate,omitempty"`
+	// DecisionMaker is the mechanism which set the sampling priority of the trace, as reported
+	// by the tracer.
+	DecisionMaker string `json:"decision_maker,omitempty"`
+	// Rare reports whether the trace was kept by the rare sampler.
+	Rare bool `json:"rare"`
+	// Errored reports whether the trace contains an error.
+	Errored bool `json:"errored"`
+	// ObfuscatedSpans is the number of spans modified by obfuscation.
+	ObfuscatedSpans int `json:"obfuscated_spans"`
+}
+
+// Span is a span of an inspected trace.
+type Span struct {
+	TraceID  uint64             `json:"trace_id"`
+	SpanID   uint64             `json:"span_id"`
+	ParentID uint64             `json:"parent_id"`
+	Service  string             `json:"service"`
+	Name     string             `json:"name"`
+	Resource string             `json:"resource"`
+	Type     string             `json:"type,omitempty"`
+	Start    int64              `json:"start"`
+	Duration int64              `json:"duration"`
+	Error    int32              `json:"error"`
+	Meta     map[string]string  `json:"meta,omitempty"`
+	Metrics  map[string]float64 `json:"metrics,omitempty"`
+}
+
+// Trace is a received trace chunk.
+type Trace struct {
+	// ID identifies the trace in the inspector.
+	ID         uint64    `json:"id"`
+	ReceivedAt time.Time `json:"received_at"`
+
+	TraceID  uint64 `json:"trace_id"`
+	Service  string `json:"service"`
+	Name     string `json:"name"`
+	Resource string `json:"resource"`
+	Env      string `json:"env,omitempty"`
+	// Priority is the sampling priority of the trace, nil if the trace has none.
+	Priority  *int32 `json:"priority,omitempty"`
+	SpanCount int    `json:"span_count"`
+
+	Sampling Decision `json:"sampling"`
+
+	// Spans holds the spans of the trace. They are omitted when listing traces.
+	Spans []Span `json:"spans,omitempty"`
+}
+
+// NewTrace returns the trace of the spans of chunk, as processed by the agent before sampling.
+func NewTrace(chunk *pb.TraceChunk, root *pb.Span, env string) *Trace {
+	t := &Trace{
+		TraceID:   root.TraceID,
+		Service:   root.Service,
+		Name:      root.Name,
+		Resource:  root.Resource,
+		Env:       env,
+		SpanCount: len(chunk.Spans),
+		Spans:     make([]Span, 0, len(chunk.Spans)),
+	}
+	if p, ok := sampler.GetSamplingPriority(chunk); ok {
+		priority := int32(p)
+		t.Priority = &priority
+	}
+	for _, s := range chunk.Spans {
+		if s.Error != 0 {
+			t.Sampling.Errored = true
+		}
+		t.Spans = append(t.Spans, Span{
+			TraceID:  s.TraceID,
+			SpanID:   s.SpanID,
+			ParentID: s.ParentID,
+			Service:  s.Service,
+			Name:     s.Name,
+			Resource: s.Resource,
+			Type:     s.Type,
+			Start:    s.Start,
+			Duration: s.Duration,
+			Error:    s.Error,
+			Meta:     copyMap(s.Meta),
+			Metrics:  copyMap(s.Metrics),
+		})
+	}
+	return t
+}
+
+func copyMap[V any](m map[string]V) map[string]V {
+	if len(m) == 0 {
+		return nil
+	}
+	c := make(map[string]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// summary returns a copy of t without its spans.
+func (t *Trace) summary() *Trace {
+	s := *t
+	s.Spans = nil
+	return &s
+}
+
+// Filter selects traces. Empty fields match all traces.
+type Filter struct {
+	// Service matches the service of the root span.
+	Service string
+	// Resource matches the traces whose root span resource contains it.
+	Resource string
+	// Error matches the traces with or without errors.
+	Error *bool
+	// Priority matches the sampling priority of the traces.
+	Priority *int32
+	// Kept matches the traces kept or dropped by sampling.
+	Kept *bool
+}
+
+func (f *Filter) matches(t *Trace) bool {
+	if f.Service != "" && t.Service != f.Service {
+		return false
+	}
+	if f.Resource != "" && !strings.Contains(t.Resource, f.Resource) {
+		return false
+	}
+	if f.Error != nil && t.Sampling.Errored != *f.Error {
+		return false
+	}
+	if f.Priority != nil && (t.Priority == nil || *t.Priority != *f.Priority) {
+		return false
+	}
+	if f.Kept != nil && t.Sampling.Kept != *f.Kept {
+		return false
+	}
+	return true
+}
+
+// Inspector is a ring buffer of the most recently received traces.
+type Inspector struct {
+	mu     sync.RWMutex
+	traces []*Trace // ring buffer, traces[next] is the oldest trace once full
+	next   int
+	lastID uint64
+}
+
+// New returns an inspector keeping the size most recently received traces.
+func New(size int) *Inspector {
+	if size <= 0 {
+		size = 1
+	}
+	return &Inspector{traces: make([]*Trace, 0, size)}
+}
+
+// Record adds t to the inspector, replacing the oldest trace once the inspector is full.
+func (in *Inspector) Record(t *Trace) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.lastID++
+	t.ID = in.lastID
+	if t.ReceivedAt.IsZero() {
+		t.ReceivedAt = time.Now()
+	}
+	if len(in.traces) < cap(in.traces) {
+		in.traces = append(in.traces, t)
+		return
+	}
+	in.traces[in.next] = t
+	in.next = (in.next + 1) % len(in.traces)
+}
+
+// Traces returns the summaries of the traces matching f, most recent first. At most limit traces
+// are returned, all of them if limit is not positive.
+func (in *Inspector) Traces(f Filter, limit int) []*Trace {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	out := []*Trace{}
+	for i := range in.traces {
+		// walk the ring buffer from the most recent trace
+		t := in.traces[(in.next-1-i+2*len(in.traces))%len(in.traces)]
+		if !f.matches(t) {
+			continue
+		}
+		out = append(out, t.summary())
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// Get returns the trace with the given ID, or nil if it is not in the inspector anymore.
+func (in *Inspector) Get(id uint64) *Trace {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	for _, t := range in.traces {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
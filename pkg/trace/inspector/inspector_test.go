@@ -0,0 +1,121 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package inspector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+)
+
+func testTrace(service, resource string, priority sampler.SamplingPriority, errored bool) *Trace {
+	root := &pb.Span{TraceID: 1, SpanID: 1, Service: service, Name: "http.request", Resource: resource}
+	child := &pb.Span{TraceID: 1, SpanID: 2, ParentID: 1, Service: service, Meta: map[string]string{"k": "v"}}
+	if errored {
+		child.Error = 1
+	}
+	return NewTrace(&pb.TraceChunk{Priority: int32(priority), Spans: []*pb.Span{root, child}}, root, "prod")
+}
+
+func TestNewTrace(t *testing.T) {
+	root := &pb.Span{TraceID: 42, SpanID: 1, Service: "web", Name: "http.request", Resource: "GET /", Metrics: map[string]float64{"m": 1}}
+	child := &pb.Span{TraceID: 42, SpanID: 2, ParentID: 1, Service: "db", Error: 1, Meta: map[string]string{"k": "v"}}
+	chunk := &pb.TraceChunk{Priority: int32(sampler.PriorityAutoKeep), Spans: []*pb.Span{root, child}}
+
+	tr := NewTrace(chunk, root, "prod")
+	priority := int32(sampler.PriorityAutoKeep)
+	assert.Equal(t, &Trace{
+		TraceID:   42,
+		Service:   "web",
+		Name:      "http.request",
+		Resource:  "GET /",
+		Env:       "prod",
+		Priority:  &priority,
+		SpanCount: 2,
+		Sampling:  Decision{Errored: true},
+		Spans: []Span{
+			{TraceID: 42, SpanID: 1, Service: "web", Name: "http.request", Resource: "GET /", Metrics: map[string]float64{"m": 1}},
+			{TraceID: 42, SpanID: 2, ParentID: 1, Service: "db", Error: 1, Meta: map[string]string{"k": "v"}},
+		},
+	}, tr)
+
+	// the trace does not share the maps of the spans
+	child.Meta["k"] = "changed"
+	assert.Equal(t, "v", tr.Spans[1].Meta["k"])
+
+	chunk.Priority = int32(sampler.PriorityNone)
+	assert.Nil(t, NewTrace(chunk, root, "").Priority)
+}
+
+func TestInspector(t *testing.T) {
+	in := New(3)
+	assert.Empty(t, in.Traces(Filter{}, 0))
+
+	for _, s := range []string{"a", "b", "c", "d"} {
+		in.Record(testTrace(s, "GET /"+s, sampler.PriorityAutoKeep, false))
+	}
+
+	// the oldest trace was replaced, the most recent is listed first
+	var services []string
+	var ids []uint64
+	for _, tr := range in.Traces(Filter{}, 0) {
+		services = append(services, tr.Service)
+		ids = append(ids, tr.ID)
+		assert.Nil(t, tr.Spans)
+		assert.Equal(t, 2, tr.SpanCount)
+		assert.False(t, tr.ReceivedAt.IsZero())
+	}
+	assert.Equal(t, []string{"d", "c", "b"}, services)
+	assert.Equal(t, []uint64{4, 3, 2}, ids)
+	assert.Len(t, in.Traces(Filter{}, 2), 2)
+
+	assert.Nil(t, in.Get(1))
+	tr := in.Get(3)
+	require.NotNil(t, tr)
+	assert.Equal(t, "c", tr.Service)
+	assert.Len(t, tr.Spans, 2)
+}
+
+func TestInspectorFilter(t *testing.T) {
+	in := New(10)
+	for _, tr := range []*Trace{
+		testTrace("web", "GET /users", sampler.PriorityAutoKeep, false),
+		testTrace("web", "POST /checkout", sampler.PriorityUserKeep, true),
+		testTrace("db", "SELECT ?", sampler.PriorityAutoDrop, false),
+		NewTrace(&pb.TraceChunk{Priority: int32(sampler.PriorityNone), Spans: []*pb.Span{{Service: "legacy"}}}, &pb.Span{Service: "legacy"}, ""),
+	} {
+		tr.Sampling.Kept = tr.Service != "db"
+		in.Record(tr)
+	}
+
+	yes, no := true, false
+	keep := int32(sampler.PriorityUserKeep)
+	for name, tt := range map[string]struct {
+		filter   Filter
+		services []string
+	}{
+		"all":      {Filter{}, []string{"legacy", "db", "web", "web"}},
+		"service":  {Filter{Service: "web"}, []string{"web", "web"}},
+		"resource": {Filter{Resource: "checkout"}, []string{"web"}},
+		"error":    {Filter{Error: &yes}, []string{"web"}},
+		"no-error": {Filter{Error: &no, Service: "web"}, []string{"web"}},
+		"priority": {Filter{Priority: &keep}, []string{"web"}},
+		"dropped":  {Filter{Kept: &no}, []string{"db"}},
+		"none":     {Filter{Service: "web", Resource: "users", Error: &yes}, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var services []string
+			for _, tr := range in.Traces(tt.filter, 0) {
+				services = append(services, tr.Service)
+			}
+			assert.Equal(t, tt.services, services)
+		})
+	}
+}
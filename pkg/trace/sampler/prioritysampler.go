@@ -27,9 +27,12 @@ import (
 )
 
 const (
-	deprecatedRateKey = "_sampling_priority_rate_v1"
-	agentRateKey      = "_dd.agent_psr"
-	ruleRateKey       = "_dd.rule_psr"
+	// KeyPrioritySamplingRate is a metric key holding the rate applied by the priority sampler.
+	KeyPrioritySamplingRate = "_sampling_priority_rate_v1"
+	// KeyAgentPrioritySamplingRate is a metric key holding the agent rate used by the tracer to set the sampling priority.
+	KeyAgentPrioritySamplingRate = "_dd.agent_psr"
+	// KeyRulePrioritySamplingRate is a metric key holding the rate of the tracer sampling rule that set the sampling priority.
+	KeyRulePrioritySamplingRate = "_dd.rule_psr"
 )
 
 // PrioritySampler computes priority rates per tracerEnv, service to apply in a feedback loop with trace-agent clients.
@@ -46,6 +49,10 @@ type PrioritySampler struct {
 	rateByService *RateByService
 	catalog       *serviceKeyCatalog
 	exit          chan struct{}
+
+	// rules are the sampling rules configured on the agent, applied to the traces whose priority
+	// was set automatically.
+	rules samplingRules
 }
 
 // NewPrioritySampler returns an initialized Sampler
@@ -55,6 +62,7 @@ func NewPrioritySampler(conf *config.AgentConfig, dynConf *DynamicConfig, statsd
 		sampler:       newSampler(conf.ExtraSampleRate, conf.TargetTPS, []string{"sampler:priority"}, statsd),
 		rateByService: &dynConf.RateByService,
 		catalog:       newServiceLookup(conf.MaxCatalogEntries),
+		rules:         newSamplingRules(conf.SamplingRules),
 		exit:          make(chan struct{}),
 	}
 	return s
@@ -124,6 +132,17 @@ func (s *PrioritySampler) Sample(now time.Time, trace *pb.TraceChunk, root *pb.S
 	// Update sampler state by counting this trace
 	s.countSignature(now, root, signature, clientDroppedP0sWeight)
 
+	// The first matching agent sampling rule overrides the automatic decision of the tracer. As
+	// the traces dropped by the tracer before being sent never reach it, a rule can only lower the
+	// rate applied by a tracer which drops traces, see samplingRule.sample.
+	if rule := s.rules.match(root); rule != nil {
+		sampled = rule.sample(now, trace, root)
+		if sampled {
+			s.sampler.countSample()
+		}
+		return sampled
+	}
+
 	if sampled {
 		s.applyRate(root, signature)
 		s.sampler.countSample()
@@ -136,22 +155,22 @@ func (s *PrioritySampler) applyRate(root *pb.Span, signature Signature) float64
 		return 1.0
 	}
 	// recent tracers annotate roots with applied priority rate
-	// agentRateKey is set when the agent computed rate is applied
-	if rate, ok := getMetric(root, agentRateKey); ok {
+	// KeyAgentPrioritySamplingRate is set when the agent computed rate is applied
+	if rate, ok := getMetric(root, KeyAgentPrioritySamplingRate); ok {
 		return rate
 	}
-	// ruleRateKey is set when a tracer rule rate is applied
-	if rate, ok := getMetric(root, ruleRateKey); ok {
+	// KeyRulePrioritySamplingRate is set when a tracer rule rate is applied
+	if rate, ok := getMetric(root, KeyRulePrioritySamplingRate); ok {
 		return rate
 	}
 	// slow path used by older tracer versions
-	// dd-trace-go used to set the rate in deprecatedRateKey
-	if rate, ok := getMetric(root, deprecatedRateKey); ok {
+	// dd-trace-go used to set the rate in KeyPrioritySamplingRate
+	if rate, ok := getMetric(root, KeyPrioritySamplingRate); ok {
 		return rate
 	}
 	rate := s.sampler.getSignatureSampleRate(signature)
 
-	setMetric(root, deprecatedRateKey, rate)
+	setMetric(root, KeyPrioritySamplingRate, rate)
 
 	return rate
 }
@@ -170,5 +189,15 @@ func (s *PrioritySampler) countSignature(now time.Time, root *pb.Span, signature
 // agents to pick the right service rate.
 func (s *PrioritySampler) ratesByService() map[ServiceSignature]float64 {
 	rates, defaultRate := s.sampler.getAllSignatureSampleRates()
-	return s.catalog.ratesByService(s.agentEnv, rates, defaultRate)
+	byService := s.catalog.ratesByService(s.agentEnv, rates, defaultRate)
+	if len(s.rules) == 0 {
+		return byService
+	}
+	// the rates of the sampling rules applying to whole services are applied by the tracers
+	for sig := range byService {
+		if rate, ok := s.rules.serviceRate(sig); ok {
+			byService[sig] = rate
+		}
+	}
+	return byService
 }
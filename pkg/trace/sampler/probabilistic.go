@@ -30,8 +30,8 @@ const (
 	bitMaskHashBuckets      = numProbabilisticBuckets - 1
 	percentageScaleFactor   = numProbabilisticBuckets / 100.0
 
-	// probRateKey indicates the percentage sampling rate configured for the probabilistic sampler
-	probRateKey = "_dd.prob_sr"
+	// KeyProbabilisticSamplingRate indicates the percentage sampling rate configured for the probabilistic sampler
+	KeyProbabilisticSamplingRate = "_dd.prob_sr"
 )
 
 // ProbabilisticSampler is a sampler that overrides all other samplers,
@@ -141,7 +141,7 @@ func (ps *ProbabilisticSampler) Sample(root *trace.Span) bool {
 	keep := hash&bitMaskHashBuckets < ps.scaledSamplingPercentage
 	if keep {
 		ps.tracesKept.Add(1)
-		setMetric(root, probRateKey, ps.samplingPercentage)
+		setMetric(root, KeyProbabilisticSamplingRate, ps.samplingPercentage)
 	}
 	return keep
 }
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sampler
+
+import (
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+)
+
+// KeyAgentRuleSamplingRate is set on the root span of the traces sampled by an agent sampling rule, to the
+// rate of the rule.
+const KeyAgentRuleSamplingRate = "_dd.agent_rule_psr"
+
+// samplingRule is a sampling rule configured on the agent.
+type samplingRule struct {
+	*config.SamplingRule
+	// limiter caps the number of traces kept by the rule, nil if the rule has no limit.
+	limiter *rate.Limiter
+}
+
+// samplingRules is an ordered list of sampling rules, the first matching rule applies.
+type samplingRules []*samplingRule
+
+// newSamplingRules returns the sampling rules of the given configuration.
+func newSamplingRules(rules []*config.SamplingRule) samplingRules {
+	var rs samplingRules
+	for _, r := range rules {
+		sr := &samplingRule{SamplingRule: r}
+		if r.MaxPerSecond > 0 {
+			sr.limiter = rate.NewLimiter(rate.Limit(r.MaxPerSecond), int(max(1, r.MaxPerSecond)))
+		}
+		rs = append(rs, sr)
+	}
+	return rs
+}
+
+// match returns the first rule matching the root span, or nil if none does.
+func (rs samplingRules) match(root *pb.Span) *samplingRule {
+	for _, r := range rs {
+		if r.matches(root) {
+			return r
+		}
+	}
+	return nil
+}
+
+// serviceRate returns the rate of the first rule applying to all the traces of the service
+// signature sig, i.e. a rule matching its service and env only. ok is false if there is none.
+func (rs samplingRules) serviceRate(sig ServiceSignature) (rate float64, ok bool) {
+	for _, r := range rs {
+		if r.Name != "" || r.Resource != "" || !globMatch(r.Service, sig.Name) {
+			continue
+		}
+		serviceWide := true
+		for k, v := range r.Tags {
+			if k != "env" || !globMatch(v, sig.Env) {
+				serviceWide = false
+				break
+			}
+		}
+		if serviceWide {
+			return r.SampleRate, true
+		}
+	}
+	return 0, false
+}
+
+// matches reports whether the rule matches the root span of a trace.
+func (r *samplingRule) matches(root *pb.Span) bool {
+	if !globMatch(r.Service, root.Service) || !globMatch(r.Name, root.Name) || !globMatch(r.Resource, root.Resource) {
+		return false
+	}
+	for k, pattern := range r.Tags {
+		v, ok := root.Meta[k]
+		if !ok {
+			m, ok := root.Metrics[k]
+			if !ok {
+				return false
+			}
+			v = strconv.FormatFloat(m, 'f', -1, 64)
+		}
+		if !globMatch(pattern, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// sample returns the sampling decision of the rule for the trace, updating the trace priority to
+// reflect it.
+//
+// The decision is made on the trace ID with SampleByRate, like the tracers do with the rates by
+// service, so it is consistent with the decision the tracer already made: when the rule rate is lower
+// than the tracer rate, the traces the rule keeps are a subset of the ones the tracer kept, and the
+// effective rate is the rule rate rather than the product of both rates. A rule rate higher than the
+// tracer rate is only effective if the tracer sends the traces it dropped, since the agent cannot
+// recover the ones dropped before being sent.
+func (r *samplingRule) sample(now time.Time, trace *pb.TraceChunk, root *pb.Span) bool {
+	sampled := SampleByRate(root.TraceID, r.SampleRate)
+	if sampled && r.limiter != nil {
+		sampled = r.limiter.AllowN(now, 1)
+	}
+	if sampled {
+		trace.Priority = int32(PriorityAutoKeep)
+	} else {
+		trace.Priority = int32(PriorityAutoDrop)
+	}
+	setMetric(root, KeyAgentRuleSamplingRate, r.SampleRate)
+	return sampled
+}
+
+// globMatch reports whether s matches pattern. An empty pattern matches all values.
+func globMatch(pattern, s string) bool {
+	return pattern == "" || traceutil.GlobMatch(pattern, s)
+}
@@ -0,0 +1,217 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sampler
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+)
+
+func TestSamplingRulesMatch(t *testing.T) {
+	rules := newSamplingRules([]*config.SamplingRule{
+		{Resource: "GET /health*", SampleRate: 0},
+		{Service: "web-*", Name: "http.request", Tags: map[string]string{"http.status_code": "5*", "tier": "gold"}, SampleRate: 1},
+		{Service: "web-*", SampleRate: 0.5},
+		{Service: "db", Tags: map[string]string{"env": "prod"}, SampleRate: 0.2},
+	})
+	for _, tt := range []struct {
+		name string
+		root *pb.Span
+		rule int // index of the matching rule, -1 for none
+	}{
+		{"resource", &pb.Span{Service: "web-store", Resource: "GET /healthz"}, 0},
+		{
+			"tags",
+			&pb.Span{Service: "web-store", Name: "http.request", Meta: map[string]string{"tier": "gold"}, Metrics: map[string]float64{"http.status_code": 503}},
+			1,
+		},
+		{
+			"missing-tag",
+			&pb.Span{Service: "web-store", Name: "http.request", Metrics: map[string]float64{"http.status_code": 503}},
+			2,
+		},
+		{"service", &pb.Span{Service: "web-cart", Name: "grpc.request"}, 2},
+		{"env", &pb.Span{Service: "db", Meta: map[string]string{"env": "prod"}}, 3},
+		{"none", &pb.Span{Service: "db", Meta: map[string]string{"env": "staging"}}, -1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rules.match(tt.root)
+			if tt.rule < 0 {
+				assert.Nil(t, r)
+				return
+			}
+			assert.Same(t, rules[tt.rule], r)
+		})
+	}
+}
+
+func TestSamplingRulesServiceRate(t *testing.T) {
+	rules := newSamplingRules([]*config.SamplingRule{
+		{Service: "web-*", Resource: "GET /health", SampleRate: 0},
+		{Service: "web-*", Tags: map[string]string{"tier": "gold"}, SampleRate: 1},
+		{Service: "web-*", Tags: map[string]string{"env": "prod"}, SampleRate: 0.5},
+		{Service: "web-*", SampleRate: 0.2},
+		{SampleRate: 0.1},
+	})
+	for _, tt := range []struct {
+		sig  ServiceSignature
+		rate float64
+	}{
+		{ServiceSignature{Name: "web-store", Env: "prod"}, 0.5},
+		{ServiceSignature{Name: "web-store", Env: "staging"}, 0.2},
+		{ServiceSignature{Name: "db", Env: "prod"}, 0.1},
+		{ServiceSignature{}, 0.1},
+	} {
+		rate, ok := rules.serviceRate(tt.sig)
+		assert.True(t, ok, tt.sig)
+		assert.Equal(t, tt.rate, rate, tt.sig)
+	}
+
+	_, ok := samplingRules(nil).serviceRate(ServiceSignature{Name: "web"})
+	assert.False(t, ok)
+}
+
+func TestSamplingRuleSample(t *testing.T) {
+	now := time.Now()
+	t.Run("rate", func(t *testing.T) {
+		r := newSamplingRules([]*config.SamplingRule{{SampleRate: 0.5}})[0]
+		var kept int
+		for i := uint64(0); i < 1000; i++ {
+			root := &pb.Span{TraceID: i * 7919}
+			chunk := &pb.TraceChunk{Priority: int32(PriorityAutoKeep), Spans: []*pb.Span{root}}
+			sampled := r.sample(now, chunk, root)
+			assert.Equal(t, SampleByRate(root.TraceID, 0.5), sampled)
+			if sampled {
+				kept++
+				assert.Equal(t, int32(PriorityAutoKeep), chunk.Priority)
+			} else {
+				assert.Equal(t, int32(PriorityAutoDrop), chunk.Priority)
+			}
+			assert.Equal(t, 0.5, root.Metrics[KeyAgentRuleSamplingRate])
+		}
+		assert.InDelta(t, 500, kept, 60)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		r := newSamplingRules([]*config.SamplingRule{{SampleRate: 1, MaxPerSecond: 2}})[0]
+		var kept int
+		for i := uint64(0); i < 10; i++ {
+			root := &pb.Span{TraceID: i}
+			if r.sample(now, &pb.TraceChunk{Spans: []*pb.Span{root}}, root) {
+				kept++
+			}
+		}
+		assert.Equal(t, 2, kept)
+		root := &pb.Span{TraceID: 42}
+		assert.True(t, r.sample(now.Add(time.Second), &pb.TraceChunk{Spans: []*pb.Span{root}}, root))
+	})
+}
+
+func TestPrioritySamplerRules(t *testing.T) {
+	conf := &config.AgentConfig{
+		ExtraSampleRate: 1.0,
+		TargetTPS:       10,
+		SamplingRules: []*config.SamplingRule{
+			{Service: "web", Resource: "GET /health", SampleRate: 0},
+			{Service: "db", SampleRate: 0.25},
+		},
+	}
+	dynConf := NewDynamicConfig()
+	s := NewPrioritySampler(conf, dynConf, &statsd.NoOpClient{})
+
+	trace := func(service, resource string, priority SamplingPriority) (*pb.TraceChunk, *pb.Span) {
+		root := &pb.Span{TraceID: 1, SpanID: 1, Service: service, Resource: resource}
+		return &pb.TraceChunk{Priority: int32(priority), Spans: []*pb.Span{root}}, root
+	}
+
+	now := time.Now()
+	// the rule applies to automatic decisions
+	chunk, root := trace("web", "GET /health", PriorityAutoKeep)
+	assert.False(t, s.Sample(now, chunk, root, "prod", 0))
+	assert.Equal(t, int32(PriorityAutoDrop), chunk.Priority)
+	assert.Equal(t, 0.0, root.Metrics[KeyAgentRuleSamplingRate])
+
+	// but not to manual decisions
+	chunk, root = trace("web", "GET /health", PriorityUserKeep)
+	assert.True(t, s.Sample(now, chunk, root, "prod", 0))
+	assert.NotContains(t, root.Metrics, KeyAgentRuleSamplingRate)
+
+	// traces matching no rule follow the tracer decision
+	chunk, root = trace("web", "GET /users", PriorityAutoKeep)
+	assert.True(t, s.Sample(now, chunk, root, "prod", 0))
+	assert.NotContains(t, root.Metrics, KeyAgentRuleSamplingRate)
+
+	chunk, root = trace("db", "SELECT ?", PriorityAutoKeep)
+	s.Sample(now, chunk, root, "prod", 0)
+
+	// the rates of the rules applying to whole services are sent to the tracers
+	s.Sample(now.Add(bucketDuration), chunk, root, "prod", 0)
+	rates := dynConf.RateByService.GetNewState("").Rates
+	require.Contains(t, rates, "service:db,env:prod")
+	assert.Equal(t, 0.25, rates["service:db,env:prod"])
+	require.Contains(t, rates, "service:web,env:prod")
+	assert.Equal(t, 1.0, rates["service:web,env:prod"])
+}
+
+func TestPrioritySamplerRulesEffectiveRate(t *testing.T) {
+	conf := &config.AgentConfig{
+		ExtraSampleRate: 1.0,
+		TargetTPS:       10,
+		SamplingRules: []*config.SamplingRule{
+			{Service: "web", Resource: "GET /cart", SampleRate: 0.2},
+			{Service: "web", Resource: "GET /search", SampleRate: 0.8},
+		},
+	}
+	const (
+		tracerRate = 0.5
+		n          = 20000
+	)
+	now := time.Now()
+
+	// effectiveRate returns the rate at which the traces of the given resource are kept after being
+	// sampled at tracerRate by the tracer, which drops the traces it does not keep if sendDropped is
+	// false, and then by the agent.
+	effectiveRate := func(resource string, sendDropped bool) float64 {
+		s := NewPrioritySampler(conf, NewDynamicConfig(), &statsd.NoOpClient{})
+		rng := rand.New(rand.NewSource(42))
+		var kept int
+		for i := 0; i < n; i++ {
+			root := &pb.Span{
+				TraceID:  rng.Uint64(),
+				SpanID:   1,
+				Service:  "web",
+				Resource: resource,
+				Metrics:  map[string]float64{KeyAgentPrioritySamplingRate: tracerRate},
+			}
+			priority := PriorityAutoDrop
+			if SampleByRate(root.TraceID, tracerRate) {
+				priority = PriorityAutoKeep
+			} else if !sendDropped {
+				continue
+			}
+			chunk := &pb.TraceChunk{Priority: int32(priority), Spans: []*pb.Span{root}}
+			if s.Sample(now, chunk, root, "prod", 0) {
+				kept++
+			}
+		}
+		return float64(kept) / n
+	}
+
+	// a rule lowering the tracer rate applies its own rate, not the product of both rates
+	assert.InDelta(t, 0.2, effectiveRate("GET /cart", false), 0.02)
+	assert.InDelta(t, 0.2, effectiveRate("GET /cart", true), 0.02)
+	// a rule can only raise the tracer rate when the tracer sends the traces it dropped
+	assert.InDelta(t, tracerRate, effectiveRate("GET /search", false), 0.02)
+	assert.InDelta(t, 0.8, effectiveRate("GET /search", true), 0.02)
+}
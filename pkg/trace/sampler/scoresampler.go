@@ -15,8 +15,10 @@ import (
 )
 
 const (
-	errorsRateKey     = "_dd.errors_sr"
-	noPriorityRateKey = "_dd.no_p_sr"
+	// KeyErrorsSamplingRate is a metric key holding the rate applied by the errors sampler.
+	KeyErrorsSamplingRate = "_dd.errors_sr"
+	// KeyNoPrioritySamplingRate is a metric key holding the rate applied by the no priority sampler.
+	KeyNoPrioritySamplingRate = "_dd.no_p_sr"
 	// shrinkCardinality is the max Signature cardinality before shrinking
 	shrinkCardinality = 200
 )
@@ -43,7 +45,7 @@ type ScoreSampler struct {
 // no priority set.
 func NewNoPrioritySampler(conf *config.AgentConfig, statsd statsd.ClientInterface) *NoPrioritySampler {
 	s := newSampler(conf.ExtraSampleRate, conf.TargetTPS, []string{"sampler:no_priority"}, statsd)
-	return &NoPrioritySampler{ScoreSampler{Sampler: s, samplingRateKey: noPriorityRateKey}}
+	return &NoPrioritySampler{ScoreSampler{Sampler: s, samplingRateKey: KeyNoPrioritySamplingRate}}
 }
 
 // NewErrorsSampler returns an initialized Sampler dedicate to errors. It behaves
@@ -51,7 +53,7 @@ func NewNoPrioritySampler(conf *config.AgentConfig, statsd statsd.ClientInterfac
 // for reporting).
 func NewErrorsSampler(conf *config.AgentConfig, statsd statsd.ClientInterface) *ErrorsSampler {
 	s := newSampler(conf.ExtraSampleRate, conf.ErrorTPS, []string{"sampler:error"}, statsd)
-	return &ErrorsSampler{ScoreSampler{Sampler: s, samplingRateKey: errorsRateKey, disabled: conf.ErrorTPS == 0}}
+	return &ErrorsSampler{ScoreSampler{Sampler: s, samplingRateKey: KeyErrorsSamplingRate, disabled: conf.ErrorTPS == 0}}
 }
 
 // Sample counts an incoming trace and tells if it is a sample which has to be kept
@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package spanmetrics generates user defined metrics from the spans received by the agent.
+//
+// The metrics are generated from all the received spans, before sampling, so that values carried by
+// spans (e.g. the amount of a purchase) can be counted and aggregated accurately. Spans dropped by the
+// tracers themselves are never received; counts are weighted by the sampling rate of the trace to
+// account for them, but distributions only measure the received spans.
+//
+// The metrics are user metrics: they must be sent with a statsd client which does not carry the tags
+// of the agent's own metrics.
+package spanmetrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+	"github.com/DataDog/datadog-agent/pkg/trace/sampler"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+)
+
+// measure specifies the value of a generated metric.
+type measure int
+
+const (
+	// measureCount counts the matching spans.
+	measureCount measure = iota
+	// measureDuration is a distribution of the durations of the matching spans, in seconds.
+	measureDuration
+	// measureMetric is a distribution of a numeric metric of the matching spans.
+	measureMetric
+)
+
+// metricMeasurePrefix prefixes the span metric key of a measure.
+const metricMeasurePrefix = "metric:"
+
+// rule is a compiled config.SpanMetricRule.
+type rule struct {
+	name      string
+	query     query
+	measure   measure
+	metricKey string // span metric measured by measureMetric
+	groupBy   []string
+}
+
+// Generator generates custom metrics from spans according to a set of rules.
+type Generator struct {
+	rules  []*rule
+	statsd statsd.ClientInterface
+}
+
+// NewGenerator returns a Generator for the given rules, sending the metrics to statsd. Invalid rules
+// are logged and ignored. It returns nil if there are no valid rules; a nil Generator generates no
+// metrics.
+func NewGenerator(rules []*config.SpanMetricRule, statsd statsd.ClientInterface) *Generator {
+	var compiled []*rule
+	for _, r := range rules {
+		c, err := compileRule(r)
+		if err != nil {
+			log.Errorf("Ignoring invalid span metric rule %q: %v", r.Name, err)
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	if len(compiled) == 0 {
+		return nil
+	}
+	return &Generator{rules: compiled, statsd: statsd}
+}
+
+// compileRule validates r and parses its query and measure.
+func compileRule(r *config.SpanMetricRule) (*rule, error) {
+	if r.Name == "" {
+		return nil, errors.New(`all rules must have a "name"`)
+	}
+	q, err := parseQuery(r.Query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+	c := &rule{name: r.Name, query: q}
+	switch m := strings.TrimSpace(r.Measure); {
+	case m == "", m == "count":
+		c.measure = measureCount
+	case m == "duration":
+		c.measure = measureDuration
+	case strings.HasPrefix(m, metricMeasurePrefix) && len(m) > len(metricMeasurePrefix):
+		c.measure = measureMetric
+		c.metricKey = m[len(metricMeasurePrefix):]
+	default:
+		return nil, fmt.Errorf(`invalid measure %q: expected "count", "duration" or "metric:<key>"`, m)
+	}
+	for _, k := range r.GroupBy {
+		if k = strings.TrimSpace(k); k != "" {
+			c.groupBy = append(c.groupBy, k)
+		}
+	}
+	return c, nil
+}
+
+// Process generates the metrics of the rules matching the spans of the trace chunk, whose root span
+// is root.
+func (g *Generator) Process(chunk *pb.TraceChunk, root *pb.Span) {
+	if g == nil {
+		return
+	}
+	weight := weight(root)
+	for _, s := range chunk.Spans {
+		for _, r := range g.rules {
+			if !r.query.matches(s) {
+				continue
+			}
+			switch r.measure {
+			case measureCount:
+				_ = g.statsd.Count(r.name, weightedCount(weight), r.tags(s), 1)
+			case measureDuration:
+				_ = g.statsd.Distribution(r.name, time.Duration(s.Duration).Seconds(), r.tags(s), 1)
+			case measureMetric:
+				// spans without the metric are not measured
+				if v, ok := s.Metrics[r.metricKey]; ok {
+					_ = g.statsd.Distribution(r.name, v, r.tags(s), 1)
+				}
+			}
+		}
+	}
+}
+
+// weight returns the weight of the trace whose root span is root, i.e. the inverse of its sampling rate.
+func weight(root *pb.Span) float64 {
+	rate := sampler.GetGlobalRate(root)
+	if rate <= 0 || rate > 1 {
+		return 1
+	}
+	return 1 / rate
+}
+
+// weightedCount returns the count of a span of the given weight. statsd counts being integers, the
+// fractional part of the weight is counted with the matching probability, to be accurate on average.
+func weightedCount(weight float64) int64 {
+	n := math.Floor(weight)
+	if rand.Float64() < weight-n {
+		n++
+	}
+	return int64(n)
+}
+
+// tags returns the group by tags of the span s. Tags the span does not have are omitted.
+func (r *rule) tags(s *pb.Span) []string {
+	if len(r.groupBy) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(r.groupBy))
+	for _, k := range r.groupBy {
+		if v, ok := spanValue(s, k); ok && v != "" {
+			tags = append(tags, traceutil.NormalizeTag(k+":"+v))
+		}
+	}
+	return tags
+}
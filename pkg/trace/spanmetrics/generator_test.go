@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package spanmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/teststatsd"
+)
+
+func TestNewGenerator(t *testing.T) {
+	assert.Nil(t, NewGenerator(nil, &teststatsd.Client{}))
+	assert.Nil(t, NewGenerator([]*config.SpanMetricRule{
+		{Query: "service:web"},
+		{Name: "bad.query", Query: "service"},
+		{Name: "bad.measure", Measure: "sum"},
+		{Name: "bad.metric", Measure: "metric:"},
+	}, &teststatsd.Client{}))
+
+	g := NewGenerator([]*config.SpanMetricRule{
+		{Name: "bad.query", Query: `resource:"GET`},
+		{Name: "requests"},
+		{Name: "latency", Measure: "duration", GroupBy: []string{" service ", ""}},
+		{Name: "cart.items", Measure: "metric:cart.items"},
+	}, &teststatsd.Client{})
+	assert.Equal(t, []*rule{
+		{name: "requests", measure: measureCount},
+		{name: "latency", measure: measureDuration, groupBy: []string{"service"}},
+		{name: "cart.items", measure: measureMetric, metricKey: "cart.items"},
+	}, g.rules)
+}
+
+func TestGeneratorProcess(t *testing.T) {
+	statsd := &teststatsd.Client{}
+	g := NewGenerator([]*config.SpanMetricRule{
+		{Name: "checkout.count", Query: `service:web-store resource:"POST /checkout"`, GroupBy: []string{"customer.tier", "error"}},
+		{Name: "checkout.duration", Query: `resource:"POST /checkout"`, Measure: "duration"},
+		{Name: "checkout.value", Query: "service:web-store", Measure: "metric:checkout.value", GroupBy: []string{"Currency"}},
+	}, statsd)
+
+	chunk := &pb.TraceChunk{Spans: []*pb.Span{
+		{
+			Service:  "web-store",
+			Resource: "POST /checkout",
+			Duration: int64(1500 * time.Millisecond),
+			Meta:     map[string]string{"customer.tier": "gold", "Currency": "EUR"},
+			Metrics:  map[string]float64{"checkout.value": 42.5},
+		},
+		{
+			Service:  "web-store",
+			Resource: "POST /checkout",
+			Duration: int64(250 * time.Millisecond),
+			Error:    1,
+		},
+		{
+			Service:  "payments",
+			Resource: "POST /checkout",
+			Duration: int64(time.Second),
+		},
+		{
+			Service:  "web-store",
+			Resource: "GET /cart",
+		},
+	}}
+	g.Process(chunk, chunk.Spans[0])
+
+	assert.Equal(t, []teststatsd.MetricsArgs{
+		{Name: "checkout.count", Value: 1, Tags: []string{"customer.tier:gold", "error:false"}, Rate: 1},
+		{Name: "checkout.count", Value: 1, Tags: []string{"error:true"}, Rate: 1},
+	}, statsd.CountCalls)
+	assert.Equal(t, []teststatsd.MetricsArgs{
+		{Name: "checkout.duration", Value: 1.5, Rate: 1},
+		{Name: "checkout.value", Value: 42.5, Tags: []string{"currency:eur"}, Rate: 1},
+		{Name: "checkout.duration", Value: 0.25, Rate: 1},
+		{Name: "checkout.duration", Value: 1, Rate: 1},
+	}, statsd.DistributionCalls)
+
+	// a nil generator generates no metrics
+	var nilGenerator *Generator
+	assert.NotPanics(t, func() { nilGenerator.Process(chunk, chunk.Spans[0]) })
+}
+
+func TestGeneratorProcessWeight(t *testing.T) {
+	statsd := &teststatsd.Client{}
+	g := NewGenerator([]*config.SpanMetricRule{
+		{Name: "checkout.count", Query: "service:web-store"},
+		{Name: "checkout.value", Query: "service:web-store", Measure: "metric:checkout.value"},
+	}, statsd)
+
+	// the trace was sampled at 25%, each received span stands for 4 spans
+	root := &pb.Span{Service: "web-store", Metrics: map[string]float64{"_sample_rate": 0.25, "checkout.value": 10}}
+	child := &pb.Span{Service: "web-store", ParentID: 1}
+	g.Process(&pb.TraceChunk{Spans: []*pb.Span{root, child}}, root)
+
+	assert.Equal(t, []teststatsd.MetricsArgs{
+		{Name: "checkout.count", Value: 4, Rate: 1},
+		{Name: "checkout.count", Value: 4, Rate: 1},
+	}, statsd.CountCalls)
+	assert.Equal(t, []teststatsd.MetricsArgs{{Name: "checkout.value", Value: 10, Rate: 1}}, statsd.DistributionCalls)
+}
+
+func TestWeight(t *testing.T) {
+	for _, tt := range []struct {
+		metrics map[string]float64
+		weight  float64
+	}{
+		{nil, 1},
+		{map[string]float64{"_sample_rate": 0.5}, 2},
+		{map[string]float64{"_sample_rate": 1}, 1},
+		{map[string]float64{"_sample_rate": 0}, 1},
+		{map[string]float64{"_sample_rate": 2}, 1},
+	} {
+		assert.Equal(t, tt.weight, weight(&pb.Span{Metrics: tt.metrics}), "%v", tt.metrics)
+	}
+}
+
+func TestWeightedCount(t *testing.T) {
+	assert.EqualValues(t, 1, weightedCount(1))
+	assert.EqualValues(t, 4, weightedCount(4))
+	var total int64
+	for i := 0; i < 10000; i++ {
+		n := weightedCount(2.5)
+		assert.True(t, n == 2 || n == 3, n)
+		total += n
+	}
+	assert.InDelta(t, 25000, total, 1000)
+}
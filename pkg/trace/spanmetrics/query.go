@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package spanmetrics
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+)
+
+// term is a key:value condition of a query.
+type term struct {
+	key     string
+	pattern string // value pattern, where * matches any sequence of characters
+	negate  bool
+}
+
+// query is a list of terms which must all match a span.
+type query []term
+
+// parseQuery parses a space separated list of key:value terms. Values can be double quoted to
+// contain spaces, and a term prefixed by "-" must not match. An empty query matches all spans.
+func parseQuery(s string) (query, error) {
+	var q query
+	for i := 0; i < len(s); {
+		if s[i] == ' ' {
+			i++
+			continue
+		}
+		var t term
+		if s[i] == '-' {
+			t.negate = true
+			i++
+		}
+		colon := strings.IndexByte(s[i:], ':')
+		if colon <= 0 {
+			return nil, fmt.Errorf("invalid term at position %d: expected key:value", i)
+		}
+		t.key = s[i : i+colon]
+		if strings.IndexByte(t.key, ' ') >= 0 {
+			return nil, fmt.Errorf("invalid term %q: expected key:value", t.key)
+		}
+		i += colon + 1
+		if i < len(s) && s[i] == '"' {
+			v, n, err := unquote(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			t.pattern = v
+			i += n
+		} else {
+			end := strings.IndexByte(s[i:], ' ')
+			if end < 0 {
+				end = len(s) - i
+			}
+			t.pattern = s[i : i+end]
+			i += end
+		}
+		if t.pattern == "" {
+			return nil, fmt.Errorf("invalid term %q: empty value", t.key)
+		}
+		q = append(q, t)
+	}
+	return q, nil
+}
+
+// unquote returns the double quoted string at the start of s, and the number of bytes it spans.
+// A double quote inside the string is escaped with a backslash.
+func unquote(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, errors.New("unterminated quoted value")
+}
+
+// matches reports whether the span s matches all the terms of the query.
+func (q query) matches(s *pb.Span) bool {
+	for _, t := range q {
+		v, ok := spanValue(s, t.key)
+		if (ok && traceutil.GlobMatch(t.pattern, v)) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// spanValue returns the value of the field or tag key of the span s. The fields are service, name,
+// resource, type and error, any other key is looked up in the tags, then in the numeric metrics.
+func spanValue(s *pb.Span, key string) (string, bool) {
+	switch key {
+	case "service":
+		return s.Service, true
+	case "name", "operation_name":
+		return s.Name, true
+	case "resource", "resource_name":
+		return s.Resource, true
+	case "type":
+		return s.Type, s.Type != ""
+	case "error":
+		return strconv.FormatBool(s.Error != 0), true
+	}
+	if v, ok := s.Meta[key]; ok {
+		return v, true
+	}
+	if v, ok := s.Metrics[key]; ok {
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
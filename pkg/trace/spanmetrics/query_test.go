@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package spanmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+)
+
+func TestParseQuery(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		out query
+		err bool
+	}{
+		{in: "", out: nil},
+		{in: "service:web", out: query{{key: "service", pattern: "web"}}},
+		{
+			in: `  service:web  -env:staging resource:"POST /checkout" http.url:*/cart*`,
+			out: query{
+				{key: "service", pattern: "web"},
+				{key: "env", pattern: "staging", negate: true},
+				{key: "resource", pattern: "POST /checkout"},
+				{key: "http.url", pattern: "*/cart*"},
+			},
+		},
+		{in: `msg:"say \"hi\""`, out: query{{key: "msg", pattern: `say "hi"`}}},
+		{in: "service", err: true},
+		{in: ":web", err: true},
+		{in: "service:", err: true},
+		{in: `resource:""`, err: true},
+		{in: `resource:"POST /checkout`, err: true},
+	} {
+		t.Run(tt.in, func(t *testing.T) {
+			q, err := parseQuery(tt.in)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.out, q)
+		})
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	span := &pb.Span{
+		Service:  "web-store",
+		Name:     "http.request",
+		Resource: "POST /checkout",
+		Error:    1,
+		Meta:     map[string]string{"env": "prod", "customer.tier": "gold"},
+		Metrics:  map[string]float64{"cart.items": 3},
+	}
+	for q, match := range map[string]bool{
+		"":                                   true,
+		"service:web-store":                  true,
+		"service:web":                        false,
+		"service:web*":                       true,
+		"name:http.request error:true":       true,
+		`resource:"POST /check*"`:            true,
+		"env:prod customer.tier:gold":        true,
+		"env:prod -customer.tier:gold":       false,
+		"-customer.tier:silver":              true,
+		"cart.items:3":                       true,
+		"missing:*":                          false,
+		"-missing:*":                         true,
+		"customer.tier:*":                    true,
+		"resource_name:*checkout":            true,
+		"operation_name:http.request type:*": false,
+	} {
+		parsed, err := parseQuery(q)
+		require.NoError(t, err, q)
+		assert.Equal(t, match, parsed.matches(span), q)
+	}
+}
@@ -31,15 +31,16 @@ type Aggregation struct {
 
 // BucketsAggregationKey specifies the key by which a bucket is aggregated.
 type BucketsAggregationKey struct {
-	Service      string
-	Name         string
-	Resource     string
-	Type         string
-	SpanKind     string
-	StatusCode   uint32
-	Synthetics   bool
-	PeerTagsHash uint64
-	IsTraceRoot  pb.Trilean
+	Service       string
+	Name          string
+	Resource      string
+	Type          string
+	SpanKind      string
+	StatusCode    uint32
+	Synthetics    bool
+	PeerTagsHash  uint64
+	IsTraceRoot   pb.Trilean
+	ExtraTagsHash uint64
 }
 
 // PayloadAggregationKey specifies the key by which a payload is aggregated.
@@ -82,21 +83,23 @@ func NewAggregationFromSpan(s *StatSpan, origin string, aggKey PayloadAggregatio
 	agg := Aggregation{
 		PayloadAggregationKey: aggKey,
 		BucketsAggregationKey: BucketsAggregationKey{
-			Resource:     s.resource,
-			Service:      s.service,
-			Name:         s.name,
-			SpanKind:     s.spanKind,
-			Type:         s.typ,
-			StatusCode:   s.statusCode,
-			Synthetics:   synthetics,
-			IsTraceRoot:  isTraceRoot,
-			PeerTagsHash: peerTagsHash(s.matchingPeerTags),
+			Resource:      s.resource,
+			Service:       s.service,
+			Name:          s.name,
+			SpanKind:      s.spanKind,
+			Type:          s.typ,
+			StatusCode:    s.statusCode,
+			Synthetics:    synthetics,
+			IsTraceRoot:   isTraceRoot,
+			PeerTagsHash:  tagsHash(s.matchingPeerTags),
+			ExtraTagsHash: tagsHash(s.extraTags),
 		},
 	}
 	return agg
 }
 
-func peerTagsHash(tags []string) uint64 {
+// tagsHash returns a hash of the tags, which doesn't depend on their order.
+func tagsHash(tags []string) uint64 {
 	if len(tags) == 0 {
 		return 0
 	}
@@ -117,14 +120,15 @@ func peerTagsHash(tags []string) uint64 {
 func NewAggregationFromGroup(g *pb.ClientGroupedStats) Aggregation {
 	return Aggregation{
 		BucketsAggregationKey: BucketsAggregationKey{
-			Resource:     g.Resource,
-			Service:      g.Service,
-			Name:         g.Name,
-			SpanKind:     g.SpanKind,
-			StatusCode:   g.HTTPStatusCode,
-			Synthetics:   g.Synthetics,
-			PeerTagsHash: peerTagsHash(g.PeerTags),
-			IsTraceRoot:  g.IsTraceRoot,
+			Resource:      g.Resource,
+			Service:       g.Service,
+			Name:          g.Name,
+			SpanKind:      g.SpanKind,
+			StatusCode:    g.HTTPStatusCode,
+			Synthetics:    g.Synthetics,
+			PeerTagsHash:  tagsHash(g.PeerTags),
+			IsTraceRoot:   g.IsTraceRoot,
+			ExtraTagsHash: tagsHash(g.ExtraAggregationTags),
 		},
 	}
 }
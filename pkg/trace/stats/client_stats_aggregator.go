@@ -54,6 +54,10 @@ type ClientStatsAggregator struct {
 	agentHostname string
 	agentVersion  string
 
+	// extraTags selects the extra aggregation tags of the client stats
+	extraTags      *extraTagsLimiter
+	extraTagsReset time.Time
+
 	exit chan struct{}
 	done chan struct{}
 
@@ -72,6 +76,7 @@ func NewClientStatsAggregator(conf *config.AgentConfig, writer Writer, statsd st
 		agentHostname: conf.Hostname,
 		agentVersion:  conf.AgentVersion,
 		oldestTs:      alignAggTs(time.Now().Add(bucketDuration - oldestBucketStart)),
+		extraTags:     newExtraTagsLimiter(conf.ExtraAggregationTags, conf.ExtraAggregationTagsCardinalityLimit),
 		exit:          make(chan struct{}),
 		done:          make(chan struct{}),
 		statsd:        statsd,
@@ -115,6 +120,15 @@ func (a *ClientStatsAggregator) flushOnTime(now time.Time) {
 		}
 	}
 	a.oldestTs = flushTs
+
+	// the cardinality of the extra aggregation tags is limited per client stats bucket duration
+	if now.Sub(a.extraTagsReset) >= clientBucketDuration {
+		a.extraTags.reset()
+		a.extraTagsReset = now
+		if n := a.extraTags.swapOverflows(); n > 0 {
+			_ = a.statsd.Count("datadog.trace_agent.client_stats_aggregator.extra_aggregation_tags_overflow", n, nil, 1)
+		}
+	}
 }
 
 func (a *ClientStatsAggregator) flushAll() {
@@ -154,6 +168,12 @@ func (a *ClientStatsAggregator) add(now time.Time, p *pb.ClientStatsPayload) {
 			}
 			a.buckets[ts.Unix()] = b
 		}
+		for _, gs := range clientBucket.Stats {
+			if gs != nil {
+				// only the configured extra aggregation tags are kept
+				gs.ExtraAggregationTags = a.extraTags.fromTags(gs.ExtraAggregationTags)
+			}
+		}
 		b.aggregateStatsBucket(clientBucket, payloadAggKey)
 	}
 }
@@ -230,6 +250,7 @@ func (b *bucket) aggregateStatsBucket(sb *pb.ClientStatsBucket, payloadAggKey Pa
 				errors:             gs.Errors,
 				duration:           gs.Duration,
 				peerTags:           gs.PeerTags,
+				extraTags:          gs.ExtraAggregationTags,
 				okDistributionRaw:  gs.OkSummary,    // store encoded version only
 				errDistributionRaw: gs.ErrorSummary, // store encoded version only
 			}
@@ -331,21 +352,22 @@ func exporGroupedStats(aggrKey BucketsAggregationKey, stats *aggregatedStats) (*
 		}
 	}
 	return &pb.ClientGroupedStats{
-		Service:        aggrKey.Service,
-		Name:           aggrKey.Name,
-		SpanKind:       aggrKey.SpanKind,
-		Resource:       aggrKey.Resource,
-		HTTPStatusCode: aggrKey.StatusCode,
-		Type:           aggrKey.Type,
-		Synthetics:     aggrKey.Synthetics,
-		IsTraceRoot:    aggrKey.IsTraceRoot,
-		PeerTags:       stats.peerTags,
-		TopLevelHits:   stats.topLevelHits,
-		Hits:           stats.hits,
-		Errors:         stats.errors,
-		Duration:       stats.duration,
-		OkSummary:      okSummary,
-		ErrorSummary:   errSummary,
+		Service:              aggrKey.Service,
+		Name:                 aggrKey.Name,
+		SpanKind:             aggrKey.SpanKind,
+		Resource:             aggrKey.Resource,
+		HTTPStatusCode:       aggrKey.StatusCode,
+		Type:                 aggrKey.Type,
+		Synthetics:           aggrKey.Synthetics,
+		IsTraceRoot:          aggrKey.IsTraceRoot,
+		PeerTags:             stats.peerTags,
+		ExtraAggregationTags: stats.extraTags,
+		TopLevelHits:         stats.topLevelHits,
+		Hits:                 stats.hits,
+		Errors:               stats.errors,
+		Duration:             stats.duration,
+		OkSummary:            okSummary,
+		ErrorSummary:         errSummary,
 	}, nil
 }
 
@@ -372,7 +394,10 @@ func newBucketAggregationKey(b *pb.ClientGroupedStats) BucketsAggregationKey {
 		IsTraceRoot: b.IsTraceRoot,
 	}
 	if tags := b.GetPeerTags(); len(tags) > 0 {
-		k.PeerTagsHash = peerTagsHash(tags)
+		k.PeerTagsHash = tagsHash(tags)
+	}
+	if tags := b.GetExtraAggregationTags(); len(tags) > 0 {
+		k.ExtraTagsHash = tagsHash(tags)
 	}
 	return k
 }
@@ -382,6 +407,7 @@ type aggregatedStats struct {
 	// aggregated counts
 	hits, topLevelHits, errors, duration uint64
 	peerTags                             []string
+	extraTags                            []string
 
 	// aggregated DDSketches
 	okDistribution, errDistribution *ddsketch.DDSketch
@@ -105,6 +105,8 @@ func getTestStatsWithStart(t *testing.T, start time.Time, incPeerTags bool) *pb.
 			s.PeerTags = nil
 		}
 		s.DBType = ""
+		// extra aggregation tags are only kept when configured
+		s.ExtraAggregationTags = nil
 		s.OkSummary = encodeTestSketch(t, generateTestSketch(t))
 		s.ErrorSummary = encodeTestSketch(t, generateTestSketch(t))
 		stats = append(stats, s)
@@ -436,6 +438,50 @@ func TestCountAggregation(t *testing.T) {
 	}
 }
 
+func TestCountAggregationExtraTags(t *testing.T) {
+	assert := assert.New(t)
+	a := NewClientStatsAggregator(&config.AgentConfig{
+		DefaultEnv:                           "agentEnv",
+		Hostname:                             "agentHostname",
+		ExtraAggregationTags:                 []string{"tenant"},
+		ExtraAggregationTagsCardinalityLimit: 2,
+	}, noopStatsWriter{}, &statsd.NoOpClient{})
+	a.Start()
+	a.flushTicker.Stop()
+	msw := &mockStatsWriter{}
+	a.writer = msw
+	testTime := time.Unix(time.Now().Unix(), 0)
+
+	k := BucketsAggregationKey{Service: "s", Name: "test.op"}
+	for _, tc := range []struct {
+		tags []string
+		hits uint64
+	}{
+		{[]string{"tenant:a", "other:x"}, 1},
+		{[]string{"tenant:a"}, 2},
+		{[]string{"tenant:b"}, 4},
+		{[]string{"tenant:c"}, 8},
+		{nil, 16},
+	} {
+		p := payloadWithCounts(testTime, k, "", "test-version", "", "", tc.hits, 0, 10)
+		p.Stats[0].Stats[0].ExtraAggregationTags = tc.tags
+		a.add(testTime, p)
+	}
+	a.flushOnTime(testTime.Add(oldestBucketStart + time.Nanosecond))
+	require.Len(t, msw.payloads, 1)
+
+	hits := map[string]uint64{}
+	for _, gs := range msw.payloads[0].Stats[0].Stats[0].Stats {
+		assert.LessOrEqual(len(gs.ExtraAggregationTags), 1)
+		tag := ""
+		if len(gs.ExtraAggregationTags) == 1 {
+			tag = gs.ExtraAggregationTags[0]
+		}
+		hits[tag] += gs.Hits
+	}
+	assert.Equal(map[string]uint64{"tenant:a": 3, "tenant:b": 4, "tenant:_overflow": 8, "": 16}, hits)
+}
+
 func TestCountAggregationPeerTags(t *testing.T) {
 	type tt struct {
 		k        BucketsAggregationKey
@@ -740,20 +786,21 @@ func deepCopyGroupedStats(s []*pb.ClientGroupedStats) []*pb.ClientGroupedStats {
 		}
 
 		stats[i] = &pb.ClientGroupedStats{
-			Service:        b.GetService(),
-			Name:           b.GetName(),
-			Resource:       b.GetResource(),
-			HTTPStatusCode: b.GetHTTPStatusCode(),
-			Type:           b.GetType(),
-			DBType:         b.GetDBType(),
-			Hits:           b.GetHits(),
-			Errors:         b.GetErrors(),
-			Duration:       b.GetDuration(),
-			Synthetics:     b.GetSynthetics(),
-			TopLevelHits:   b.GetTopLevelHits(),
-			SpanKind:       b.GetSpanKind(),
-			PeerTags:       b.GetPeerTags(),
-			IsTraceRoot:    b.GetIsTraceRoot(),
+			Service:              b.GetService(),
+			Name:                 b.GetName(),
+			Resource:             b.GetResource(),
+			HTTPStatusCode:       b.GetHTTPStatusCode(),
+			Type:                 b.GetType(),
+			DBType:               b.GetDBType(),
+			Hits:                 b.GetHits(),
+			Errors:               b.GetErrors(),
+			Duration:             b.GetDuration(),
+			Synthetics:           b.GetSynthetics(),
+			TopLevelHits:         b.GetTopLevelHits(),
+			SpanKind:             b.GetSpanKind(),
+			PeerTags:             b.GetPeerTags(),
+			IsTraceRoot:          b.GetIsTraceRoot(),
+			ExtraAggregationTags: b.GetExtraAggregationTags(),
 		}
 		if b.OkSummary != nil {
 			stats[i].OkSummary = make([]byte, len(b.OkSummary))
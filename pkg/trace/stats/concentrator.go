@@ -51,8 +51,10 @@ type Concentrator struct {
 func NewConcentrator(conf *config.AgentConfig, writer Writer, now time.Time, statsd statsd.ClientInterface) *Concentrator {
 	bsize := conf.BucketInterval.Nanoseconds()
 	sc := NewSpanConcentrator(&SpanConcentratorConfig{
-		ComputeStatsBySpanKind: conf.ComputeStatsBySpanKind,
-		BucketInterval:         bsize,
+		ComputeStatsBySpanKind:               conf.ComputeStatsBySpanKind,
+		BucketInterval:                       bsize,
+		ExtraAggregationTags:                 conf.ExtraAggregationTags,
+		ExtraAggregationTagsCardinalityLimit: conf.ExtraAggregationTagsCardinalityLimit,
 	}, now)
 	c := Concentrator{
 		spanConcentrator: sc,
@@ -172,6 +174,9 @@ func (c *Concentrator) Flush(force bool) *pb.StatsPayload {
 
 func (c *Concentrator) flushNow(now int64, force bool) *pb.StatsPayload {
 	sb := c.spanConcentrator.Flush(now, force)
+	if n := c.spanConcentrator.extraTags.swapOverflows(); n > 0 {
+		_ = c.statsd.Count("datadog.trace_agent.concentrator.extra_aggregation_tags_overflow", n, nil, 1)
+	}
 	return &pb.StatsPayload{Stats: sb, AgentHostname: c.agentHostname, AgentEnv: c.agentEnv, AgentVersion: c.agentVersion}
 }
 
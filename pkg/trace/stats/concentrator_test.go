@@ -757,6 +757,49 @@ func TestPeerTags(t *testing.T) {
 	})
 }
 
+func TestExtraAggregationTags(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	c := NewTestConcentratorWithCfg(now, &config.AgentConfig{
+		BucketInterval:                       time.Duration(testBucketInterval),
+		AgentVersion:                         "0.99.0",
+		DefaultEnv:                           "env",
+		Hostname:                             "hostname",
+		ExtraAggregationTags:                 []string{"tenant"},
+		ExtraAggregationTagsCardinalityLimit: 2,
+	})
+
+	var spans []*pb.Span
+	for i, tenant := range []string{"a", "b", "a", "c", "d", ""} {
+		meta := map[string]string{"region": "us1"}
+		if tenant != "" {
+			meta["tenant"] = tenant
+		}
+		spans = append(spans, testSpan(now, uint64(i+1), 0, 50, 0, "A1", "resource1", 0, meta))
+	}
+	traceutil.ComputeTopLevel(spans)
+	c.addNow(toProcessedTrace(spans, "none", "", "", "", ""), "", nil)
+	stats := c.flushNow(now.UnixNano()+int64(c.spanConcentrator.bufferLen)*testBucketInterval, false)
+
+	hits := map[string]uint64{}
+	for _, st := range stats.Stats[0].Stats[0].Stats {
+		assert.LessOrEqual(len(st.ExtraAggregationTags), 1)
+		tag := ""
+		if len(st.ExtraAggregationTags) == 1 {
+			tag = st.ExtraAggregationTags[0]
+		}
+		hits[tag] += st.Hits
+	}
+	assert.Equal(map[string]uint64{"tenant:a": 2, "tenant:b": 1, "tenant:_overflow": 2, "": 1}, hits)
+
+	// the cardinality limit is reset on each flush
+	spans = []*pb.Span{testSpan(now, 10, 0, 50, 0, "A1", "resource1", 0, map[string]string{"tenant": "c"})}
+	traceutil.ComputeTopLevel(spans)
+	c.addNow(toProcessedTrace(spans, "none", "", "", "", ""), "", nil)
+	stats = c.flushNow(now.UnixNano()+int64(c.spanConcentrator.bufferLen)*testBucketInterval, true)
+	assert.Equal([]string{"tenant:c"}, stats.Stats[0].Stats[0].Stats[0].ExtraAggregationTags)
+}
+
 // TestComputeStatsThroughSpanKindCheck ensures that we generate stats for spans that have an eligible span.kind.
 func TestComputeStatsThroughSpanKindCheck(t *testing.T) {
 	assert := assert.New(t)
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package stats
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+// extraTagOverflowValue replaces the value of an extra aggregation tag once the cardinality limit of the tag
+// is reached.
+const extraTagOverflowValue = "_overflow"
+
+// extraTagsLimiter selects the extra aggregation tags of spans and client stats, and caps the number of values
+// of each tag between two resets.
+type extraTagsLimiter struct {
+	keys  []string // sorted configured tag keys
+	limit int      // maximum number of values per tag, 0 means no limit
+
+	mu     sync.Mutex
+	values map[string]map[string]struct{} // values seen for each tag since the last reset
+
+	overflows *atomic.Int64 // number of tag values replaced by the overflow value
+}
+
+// newExtraTagsLimiter returns a limiter for the given tag keys, or nil if there are none. A nil limiter
+// never returns any tag.
+func newExtraTagsLimiter(keys []string, limit int) *extraTagsLimiter {
+	seen := make(map[string]struct{}, len(keys))
+	var sorted []string
+	for _, k := range keys {
+		k = strings.TrimSpace(k)
+		if _, ok := seen[k]; ok || k == "" {
+			continue
+		}
+		seen[k] = struct{}{}
+		sorted = append(sorted, k)
+	}
+	if len(sorted) == 0 {
+		return nil
+	}
+	sort.Strings(sorted)
+	return &extraTagsLimiter{
+		keys:      sorted,
+		limit:     limit,
+		values:    make(map[string]map[string]struct{}, len(sorted)),
+		overflows: atomic.NewInt64(0),
+	}
+}
+
+// fromMeta returns the extra aggregation tags of a span, as key:value sorted by key.
+func (l *extraTagsLimiter) fromMeta(meta map[string]string) []string {
+	if l == nil {
+		return nil
+	}
+	var tags []string
+	for _, k := range l.keys {
+		if v, ok := meta[k]; ok && v != "" {
+			tags = append(tags, k+":"+l.limitValue(k, v))
+		}
+	}
+	return tags
+}
+
+// fromTags returns the extra aggregation tags of client stats, keeping only the configured tags. The tags
+// are returned as key:value sorted by key.
+func (l *extraTagsLimiter) fromTags(tags []string) []string {
+	if l == nil || len(tags) == 0 {
+		return nil
+	}
+	var out []string
+	for _, t := range tags {
+		k, v, ok := strings.Cut(t, ":")
+		if !ok || v == "" {
+			continue
+		}
+		i := sort.SearchStrings(l.keys, k)
+		if i == len(l.keys) || l.keys[i] != k {
+			continue
+		}
+		out = append(out, k+":"+l.limitValue(k, v))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// limitValue returns the value of the tag k, or the overflow value if the tag already reached its
+// cardinality limit.
+func (l *extraTagsLimiter) limitValue(k, v string) string {
+	if l.limit <= 0 {
+		return v
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	values, ok := l.values[k]
+	if !ok {
+		values = make(map[string]struct{})
+		l.values[k] = values
+	}
+	if _, ok := values[v]; ok {
+		return v
+	}
+	if len(values) >= l.limit {
+		l.overflows.Inc()
+		return extraTagOverflowValue
+	}
+	values[v] = struct{}{}
+	return v
+}
+
+// reset forgets the values seen so far, starting a new cardinality window.
+func (l *extraTagsLimiter) reset() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	clear(l.values)
+}
+
+// swapOverflows returns the number of values replaced by the overflow value since the last call.
+func (l *extraTagsLimiter) swapOverflows() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.overflows.Swap(0)
+}
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraTagsLimiter(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		l := newExtraTagsLimiter(nil, 10)
+		assert.Nil(t, l)
+		assert.Nil(t, l.fromMeta(map[string]string{"tenant": "a"}))
+		assert.Nil(t, l.fromTags([]string{"tenant:a"}))
+		l.reset()
+		assert.Zero(t, l.swapOverflows())
+	})
+
+	t.Run("from meta", func(t *testing.T) {
+		l := newExtraTagsLimiter([]string{"tenant", " region", "tenant", ""}, 0)
+		assert.Equal(t, []string{"region", "tenant"}, l.keys)
+		assert.Equal(t, []string{"region:eu", "tenant:a"}, l.fromMeta(map[string]string{"tenant": "a", "region": "eu", "other": "x"}))
+		assert.Equal(t, []string{"tenant:a"}, l.fromMeta(map[string]string{"tenant": "a", "region": ""}))
+		assert.Nil(t, l.fromMeta(nil))
+	})
+
+	t.Run("from tags", func(t *testing.T) {
+		l := newExtraTagsLimiter([]string{"tenant", "region"}, 0)
+		assert.Equal(t, []string{"region:eu", "tenant:a"}, l.fromTags([]string{"tenant:a", "other:x", "region:eu", "invalid", "region:"}))
+		assert.Nil(t, l.fromTags([]string{"other:x"}))
+	})
+
+	t.Run("cardinality limit", func(t *testing.T) {
+		l := newExtraTagsLimiter([]string{"tenant", "region"}, 2)
+		for _, tenant := range []string{"a", "b", "a"} {
+			assert.Equal(t, []string{"tenant:" + tenant}, l.fromMeta(map[string]string{"tenant": tenant}))
+		}
+		assert.Equal(t, []string{"tenant:_overflow"}, l.fromMeta(map[string]string{"tenant": "c"}))
+		assert.Equal(t, []string{"tenant:_overflow"}, l.fromTags([]string{"tenant:d"}))
+		// the limit applies to each tag separately
+		assert.Equal(t, []string{"region:eu", "tenant:b"}, l.fromMeta(map[string]string{"tenant": "b", "region": "eu"}))
+		assert.EqualValues(t, 2, l.swapOverflows())
+		assert.Zero(t, l.swapOverflows())
+
+		l.reset()
+		assert.Equal(t, []string{"tenant:c"}, l.fromMeta(map[string]string{"tenant": "c"}))
+	})
+}
@@ -23,6 +23,11 @@ type SpanConcentratorConfig struct {
 	ComputeStatsBySpanKind bool
 	// BucketInterval the size of our pre-aggregation per bucket
 	BucketInterval int64
+	// ExtraAggregationTags are span tags used as additional aggregation dimensions
+	ExtraAggregationTags []string
+	// ExtraAggregationTagsCardinalityLimit is the maximum number of values of each extra aggregation tag
+	// between two flushes, the other values are aggregated together. 0 means no limit.
+	ExtraAggregationTagsCardinalityLimit int
 }
 
 // StatSpan holds all the required fields from a span needed to calculate stats
@@ -42,6 +47,7 @@ type StatSpan struct {
 	statusCode       uint32
 	isTopLevel       bool
 	matchingPeerTags []string
+	extraTags        []string
 }
 
 func matchingPeerTags(meta map[string]string, peerTagKeys []string) []string {
@@ -91,6 +97,9 @@ type SpanConcentrator struct {
 	// This only applies to past buckets. Stats buckets in the future are allowed with no restriction.
 	bufferLen int
 
+	// extraTags selects the extra aggregation tags of the spans
+	extraTags *extraTagsLimiter
+
 	// mu protects the buckets field
 	mu      sync.Mutex
 	buckets map[int64]*RawBucket
@@ -103,6 +112,7 @@ func NewSpanConcentrator(cfg *SpanConcentratorConfig, now time.Time) *SpanConcen
 		bsize:                  cfg.BucketInterval,
 		oldestTs:               alignTs(now.UnixNano(), cfg.BucketInterval),
 		bufferLen:              defaultBufferLen,
+		extraTags:              newExtraTagsLimiter(cfg.ExtraAggregationTags, cfg.ExtraAggregationTagsCardinalityLimit),
 		mu:                     sync.Mutex{},
 		buckets:                make(map[int64]*RawBucket),
 	}
@@ -154,6 +164,7 @@ func (sc *SpanConcentrator) NewStatSpan(
 		statusCode:       getStatusCode(meta, metrics),
 		isTopLevel:       isTopLevel,
 		matchingPeerTags: matchingPeerTags(meta, peerTags),
+		extraTags:        sc.extraTags.fromMeta(meta),
 	}, true
 }
 
@@ -235,6 +246,8 @@ func (sc *SpanConcentrator) Flush(now int64, force bool) []*pb.ClientStatsPayloa
 		sc.oldestTs = newOldestTs
 	}
 	sc.mu.Unlock()
+	// the cardinality of the extra aggregation tags is limited per flush
+	sc.extraTags.reset()
 	sb := make([]*pb.ClientStatsPayload, 0, len(m))
 	for k, s := range m {
 		p := &pb.ClientStatsPayload{
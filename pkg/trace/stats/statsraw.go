@@ -39,6 +39,7 @@ type groupedStats struct {
 	okDistribution  *ddsketch.DDSketch
 	errDistribution *ddsketch.DDSketch
 	peerTags        []string
+	extraTags       []string
 }
 
 // round a float to an int, uniformly choosing
@@ -63,21 +64,22 @@ func (s *groupedStats) export(a Aggregation) (*pb.ClientGroupedStats, error) {
 		return &pb.ClientGroupedStats{}, err
 	}
 	return &pb.ClientGroupedStats{
-		Service:        a.Service,
-		Name:           a.Name,
-		Resource:       a.Resource,
-		HTTPStatusCode: a.StatusCode,
-		Type:           a.Type,
-		Hits:           round(s.hits),
-		Errors:         round(s.errors),
-		Duration:       round(s.duration),
-		TopLevelHits:   round(s.topLevelHits),
-		OkSummary:      okSummary,
-		ErrorSummary:   errSummary,
-		Synthetics:     a.Synthetics,
-		SpanKind:       a.SpanKind,
-		PeerTags:       s.peerTags,
-		IsTraceRoot:    a.IsTraceRoot,
+		Service:              a.Service,
+		Name:                 a.Name,
+		Resource:             a.Resource,
+		HTTPStatusCode:       a.StatusCode,
+		Type:                 a.Type,
+		Hits:                 round(s.hits),
+		Errors:               round(s.errors),
+		Duration:             round(s.duration),
+		TopLevelHits:         round(s.topLevelHits),
+		OkSummary:            okSummary,
+		ErrorSummary:         errSummary,
+		Synthetics:           a.Synthetics,
+		SpanKind:             a.SpanKind,
+		PeerTags:             s.peerTags,
+		IsTraceRoot:          a.IsTraceRoot,
+		ExtraAggregationTags: s.extraTags,
 	}, nil
 }
 
@@ -170,6 +172,7 @@ func (sb *RawBucket) add(s *StatSpan, weight float64, aggr Aggregation) {
 	if gs, ok = sb.data[aggr]; !ok {
 		gs = newGroupedStats()
 		gs.peerTags = s.matchingPeerTags
+		gs.extraTags = s.extraTags
 		sb.data[aggr] = gs
 	}
 	if s.isTopLevel {
@@ -44,14 +44,16 @@ type Client struct {
 	mu sync.RWMutex
 	statsd.NoOpClient
 
-	GaugeErr       error
-	GaugeCalls     []MetricsArgs
-	CountErr       error
-	CountCalls     []MetricsArgs
-	HistogramErr   error
-	HistogramCalls []MetricsArgs
-	TimingErr      error
-	TimingCalls    []MetricsArgs
+	GaugeErr          error
+	GaugeCalls        []MetricsArgs
+	CountErr          error
+	CountCalls        []MetricsArgs
+	HistogramErr      error
+	HistogramCalls    []MetricsArgs
+	TimingErr         error
+	TimingCalls       []MetricsArgs
+	DistributionErr   error
+	DistributionCalls []MetricsArgs
 }
 
 // Reset resets client's internal records.
@@ -66,6 +68,8 @@ func (c *Client) Reset() {
 	c.HistogramCalls = c.HistogramCalls[:0]
 	c.TimingErr = nil
 	c.TimingCalls = c.TimingCalls[:0]
+	c.DistributionErr = nil
+	c.DistributionCalls = c.DistributionCalls[:0]
 }
 
 // Gauge records a call to a Gauge operation and replies with GaugeErr
@@ -106,6 +110,14 @@ func (c *Client) Timing(name string, value time.Duration, tags []string, rate fl
 	return c.TimingErr
 }
 
+// Distribution records a call to a Distribution operation and replies with DistributionErr
+func (c *Client) Distribution(name string, value float64, tags []string, rate float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.DistributionCalls = append(c.DistributionCalls, MetricsArgs{Name: name, Value: value, Tags: tags, Rate: rate})
+	return c.DistributionErr
+}
+
 // GetCountSummaries computes summaries for all names supplied as parameters to Count calls.
 func (c *Client) GetCountSummaries() map[string]*CountSummary {
 	result := map[string]*CountSummary{}
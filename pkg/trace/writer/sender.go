@@ -16,7 +16,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -54,11 +56,44 @@ func newSenders(cfg *config.AgentConfig, r eventRecorder, path string, climit, q
 			apiKey:     endpoint.APIKey,
 			recorder:   r,
 			userAgent:  fmt.Sprintf("Datadog Trace Agent/%s/%s", cfg.AgentVersion, cfg.GitCommit),
+			spool:      newEndpointSpool(cfg, path, i),
 		}, statsd)
 	}
 	return senders
 }
 
+// newEndpointSpool returns the spool of the payloads sent to path on the i-th endpoint, or nil if
+// spooling is disabled or the spool can't be created. Each endpoint has its own directory, named after
+// its host, in a directory named after the kind of payloads sent to path (traces or stats).
+func newEndpointSpool(cfg *config.AgentConfig, path string, i int) *spool {
+	if !cfg.Spool.Enabled {
+		return nil
+	}
+	name := spoolEndpointName(cfg.Endpoints[i].Host)
+	for _, e := range cfg.Endpoints[:i] {
+		if spoolEndpointName(e.Host) == name {
+			// several API keys on the same host
+			name = fmt.Sprintf("%s_%d", name, i)
+			break
+		}
+	}
+	dir := filepath.Join(cfg.Spool.Path, filepath.Base(path), name)
+	sp, err := newSpool(dir, cfg.Spool.MaxSize, cfg.Spool.MaxAge)
+	if err != nil {
+		log.Errorf("Error creating the payload spool in %s, payloads which can't be sent will be dropped: %v", dir, err)
+		return nil
+	}
+	return sp
+}
+
+// spoolEndpointName returns the name of the spool directory of the endpoint with the given host.
+func spoolEndpointName(host string) string {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return strings.NewReplacer(":", "_", "/", "_").Replace(host)
+}
+
 // eventRecorder implementations are able to take note of events happening in
 // the sender.
 type eventRecorder interface {
@@ -80,13 +115,28 @@ const (
 	// eventTypeDropped specifies that a payload had to be dropped to make room
 	// in the queue.
 	eventTypeDropped
+	// eventTypeSpooled specifies that a payload which could not be sent was stored
+	// in the spool to be retried later.
+	eventTypeSpooled
+	// eventTypeReplayed specifies that a spooled payload was successfully sent.
+	eventTypeReplayed
+	// eventTypeExpired specifies that a spooled payload was dropped because it
+	// exceeded the maximum age of the spool.
+	eventTypeExpired
+	// eventTypeReplayFailed specifies that a spooled payload failed to be sent with
+	// a retriable error, and was kept in the spool.
+	eventTypeReplayFailed
 )
 
 var eventTypeStrings = map[eventType]string{
-	eventTypeRetry:    "eventTypeRetry",
-	eventTypeSent:     "eventTypeSent",
-	eventTypeRejected: "eventTypeRejected",
-	eventTypeDropped:  "eventTypeDropped",
+	eventTypeRetry:        "eventTypeRetry",
+	eventTypeSent:         "eventTypeSent",
+	eventTypeRejected:     "eventTypeRejected",
+	eventTypeDropped:      "eventTypeDropped",
+	eventTypeSpooled:      "eventTypeSpooled",
+	eventTypeReplayed:     "eventTypeReplayed",
+	eventTypeExpired:      "eventTypeExpired",
+	eventTypeReplayFailed: "eventTypeReplayFailed",
 }
 
 // String implements fmt.Stringer.
@@ -102,9 +152,9 @@ type eventData struct {
 	// count specfies the number of payloads that this events refers to.
 	count int
 	// duration specifies the time it took to complete this event. It
-	// is set for eventType{Sent,Retry,Rejected}.
+	// is set for eventType{Sent,Retry,Rejected,Replayed,ReplayFailed}.
 	duration time.Duration
-	// err specifies the error that may have occurred on events eventType{Retry,Rejected}.
+	// err specifies the error that may have occurred on events eventType{Retry,Rejected,ReplayFailed}.
 	err error
 	// connectionFill specifies the percentage of allowed connections used.
 	// At 100% (1.0) the writer will become blocking.
@@ -136,6 +186,9 @@ type senderConfig struct {
 	recorder eventRecorder
 	// userAgent is the computed user agent we'll use when communicating with Datadog
 	userAgent string
+	// spool specifies the spool storing the payloads which could not be sent. When nil,
+	// these payloads are dropped.
+	spool *spool
 }
 
 // sender is responsible for sending payloads to a given URL. It uses a size-limited
@@ -164,6 +217,10 @@ func newSender(cfg *senderConfig, statsd statsd.ClientInterface) *sender {
 	for i := 0; i < cfg.maxConns; i++ {
 		go s.loop()
 	}
+	if cfg.spool != nil {
+		cfg.spool.wg.Add(1)
+		go s.replayLoop()
+	}
 	return &s
 }
 
@@ -191,6 +248,10 @@ func (s *sender) Stop() {
 	s.closed = true
 	s.mu.Unlock()
 	close(s.queue)
+	if s.cfg.spool != nil {
+		close(s.cfg.spool.stop)
+		s.cfg.spool.wg.Wait()
+	}
 }
 
 // WaitForInflight blocks until all in progress payloads are sent,
@@ -264,8 +325,10 @@ func (s *sender) sendOnce(p *payload) bool {
 		s.mu.RLock()
 		defer s.mu.RUnlock()
 		if s.closed {
-			s.releasePayload(p, eventTypeDropped, stats)
 			// sender is stopped
+			if !s.spoolPayload(p, stats) {
+				s.releasePayload(p, eventTypeDropped, stats)
+			}
 			return true
 		}
 
@@ -276,6 +339,10 @@ func (s *sender) sendOnce(p *payload) bool {
 			log.Warnf("Retried payload %d times: %s", r, err.Error())
 		}
 		if p.retries.Load() >= s.maxRetries {
+			if s.spoolPayload(p, stats) {
+				log.Debugf("Spooled payload after %d retries, due to: %v.", p.retries.Load(), err)
+				return true
+			}
 			log.Warnf("Dropping Payload after %d retries, due to: %v.\n", p.retries.Load(), err)
 			// queue is full; since this is the oldest payload, we drop it
 			s.releasePayload(p, eventTypeDropped, stats)
@@ -285,6 +352,9 @@ func (s *sender) sendOnce(p *payload) bool {
 		return false
 	case nil:
 		s.releasePayload(p, eventTypeSent, stats)
+		if s.cfg.spool != nil {
+			s.cfg.spool.notifyReady()
+		}
 	default:
 		// this is a fatal error, we have to drop this payload
 		log.Warnf("Dropping Payload due to non-retryable error: %v.\n", err)
@@ -293,6 +363,111 @@ func (s *sender) sendOnce(p *payload) bool {
 	return true
 }
 
+// spoolPayload stores the payload p in the spool to send it later, and releases it. It returns false
+// if p could not be spooled, in which case it is not released.
+func (s *sender) spoolPayload(p *payload, data *eventData) bool {
+	if s.cfg.spool == nil {
+		return false
+	}
+	dropped, err := s.cfg.spool.write(p)
+	if err != nil {
+		log.Warnf("Error spooling payload: %v", err)
+		return false
+	}
+	for _, f := range dropped {
+		// the oldest payloads are dropped to make room in the spool
+		s.recordEvent(eventTypeDropped, &eventData{bytes: int(f.size), count: 1})
+	}
+	s.releasePayload(p, eventTypeSpooled, data)
+	return true
+}
+
+// replayLoop replays the spool each time a payload is sent successfully, and periodically
+// otherwise, until the sender is stopped.
+func (s *sender) replayLoop() {
+	sp := s.cfg.spool
+	defer sp.wg.Done()
+	tick := time.NewTicker(spoolReplayInterval)
+	defer tick.Stop()
+	for {
+		s.replaySpool()
+		select {
+		case <-sp.ready:
+		case <-tick.C:
+		case <-sp.stop:
+			return
+		}
+	}
+}
+
+// replaySpool sends the spooled payloads, oldest first, until the spool is empty or a payload
+// fails with a retriable error.
+func (s *sender) replaySpool() {
+	sp := s.cfg.spool
+	for {
+		select {
+		case <-sp.stop:
+			return
+		default:
+		}
+		p, f, expired, err := sp.oldest()
+		for _, e := range expired {
+			s.recordEvent(eventTypeExpired, &eventData{bytes: int(e.size), count: 1})
+		}
+		if err != nil {
+			log.Warnf("Dropping unreadable spooled payload %s: %v", f.name, err)
+			s.recordEvent(eventTypeDropped, &eventData{bytes: int(f.size), count: 1})
+			continue
+		}
+		if p == nil {
+			return
+		}
+		req, err := p.httpRequest(s.cfg.url)
+		if err != nil {
+			log.Errorf("http.Request: %s", err)
+			sp.remove(f)
+			ppool.Put(p)
+			continue
+		}
+		start := time.Now()
+		err = s.do(req)
+		stats := &eventData{
+			bytes:    p.body.Len(),
+			count:    1,
+			duration: time.Since(start),
+			err:      err,
+		}
+		ppool.Put(p)
+		switch err.(type) {
+		case *retriableError:
+			// the intake still can't be reached, the payload is retried on the next replay
+			s.recordEvent(eventTypeReplayFailed, stats)
+			return
+		case nil:
+			sp.remove(f)
+			s.recordEvent(eventTypeReplayed, stats)
+		default:
+			log.Warnf("Dropping spooled payload due to non-retryable error: %v.", err)
+			sp.remove(f)
+			s.recordEvent(eventTypeRejected, stats)
+		}
+	}
+}
+
+// spooledPayloads returns the number of payloads and bytes stored in the spools of the senders,
+// and whether any of them has a spool.
+func spooledPayloads(senders []*sender) (count int, size int64, ok bool) {
+	for _, s := range senders {
+		if s.cfg.spool != nil {
+			c, sz := s.cfg.spool.stats()
+			count += c
+			size += sz
+			ok = true
+		}
+	}
+	return count, size, ok
+}
+
 // waitForSenders blocks until all senders have sent their inflight payloads
 func waitForSenders(senders []*sender) {
 	var wg sync.WaitGroup
@@ -286,6 +286,8 @@ func useBackoffDuration(d time.Duration) func() {
 type mockRecorder struct {
 	mu                             sync.RWMutex
 	retry, sent, dropped, rejected []*eventData
+	spooled, replayed, expired     []*eventData
+	replayFailed                   []*eventData
 }
 
 // data returns all call data for the given eventType.
@@ -301,6 +303,14 @@ func (r *mockRecorder) data(t eventType) []*eventData {
 		return r.dropped
 	case eventTypeRejected:
 		return r.rejected
+	case eventTypeSpooled:
+		return r.spooled
+	case eventTypeReplayed:
+		return r.replayed
+	case eventTypeExpired:
+		return r.expired
+	case eventTypeReplayFailed:
+		return r.replayFailed
 	default:
 		panic("unknown event")
 	}
@@ -319,5 +329,13 @@ func (r *mockRecorder) recordEvent(t eventType, data *eventData) {
 		r.dropped = append(r.dropped, data)
 	case eventTypeRejected:
 		r.rejected = append(r.rejected, data)
+	case eventTypeSpooled:
+		r.spooled = append(r.spooled, data)
+	case eventTypeReplayed:
+		r.replayed = append(r.replayed, data)
+	case eventTypeExpired:
+		r.expired = append(r.expired, data)
+	case eventTypeReplayFailed:
+		r.replayFailed = append(r.replayFailed, data)
 	}
 }
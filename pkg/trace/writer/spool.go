@@ -0,0 +1,250 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+const (
+	// spoolFileExt is the extension of the files holding spooled payloads.
+	spoolFileExt = ".payload"
+	// spoolReplayInterval is the frequency at which the spool is replayed when no payload
+	// was sent successfully in the meantime.
+	spoolReplayInterval = backoffMaxDuration
+)
+
+// spoolFile is a payload stored in the spool.
+type spoolFile struct {
+	name    string    // file name, ordering the files by spooling time
+	size    int64     // file size in bytes
+	created time.Time // spooling time
+}
+
+// spool stores the payloads a sender could not send in a directory, to replay them in order once
+// the intake can be reached again. The size of the directory and the age of the payloads are
+// bounded, the oldest payloads are dropped first.
+type spool struct {
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+
+	mu    sync.Mutex // guards files, size and seq
+	files []spoolFile
+	size  int64
+	seq   uint64
+
+	ready chan struct{} // signals that the intake can be reached
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// newSpool returns a spool storing the payloads in dir, loading the payloads spooled by a
+// previous run of the agent.
+func newSpool(dir string, maxSize int64, maxAge time.Duration) (*spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &spool{
+		dir:     dir,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		ready:   make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasSuffix(name, spoolFileExt) {
+			// leftover of an interrupted write
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		created, ok := parseSpoolFileName(name)
+		if !ok {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		s.files = append(s.files, spoolFile{name: name, size: info.Size(), created: created})
+		s.size += info.Size()
+	}
+	sort.Slice(s.files, func(i, j int) bool { return s.files[i].name < s.files[j].name })
+	return s, nil
+}
+
+// spoolFileName returns the name of a file spooled at t. Names sort in spooling order.
+func spoolFileName(t time.Time, seq uint64) string {
+	return fmt.Sprintf("%020d-%010d%s", t.UnixNano(), seq, spoolFileExt)
+}
+
+// parseSpoolFileName returns the spooling time of a file, see spoolFileName.
+func parseSpoolFileName(name string) (time.Time, bool) {
+	ts, _, ok := strings.Cut(name, "-")
+	if !ok {
+		return time.Time{}, false
+	}
+	ns, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, ns), true
+}
+
+// stats returns the number of payloads in the spool and their size in bytes.
+func (s *spool) stats() (count int, size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.files), s.size
+}
+
+// write stores the payload p in the spool. It returns the payloads that were dropped to make
+// room for it, or an error if p could not be stored.
+func (s *spool) write(p *payload) (dropped []spoolFile, err error) {
+	headers, err := json.Marshal(p.headers)
+	if err != nil {
+		return nil, err
+	}
+	size := int64(len(headers) + 1 + p.body.Len())
+	if size > s.maxSize {
+		return nil, fmt.Errorf("payload of %d bytes exceeds the spool size of %d bytes", size, s.maxSize)
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	name := spoolFileName(now, s.seq)
+	s.seq++
+	s.mu.Unlock()
+
+	// write to a temporary file first, for an interrupted write not to be replayed
+	path := filepath.Join(s.dir, name)
+	if err := writeSpoolFile(path+".tmp", headers, p.body.Bytes()); err != nil {
+		os.Remove(path + ".tmp")
+		return nil, err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = append(s.files, spoolFile{name: name, size: size, created: now})
+	s.size += size
+	for s.size > s.maxSize && len(s.files) > 1 {
+		dropped = append(dropped, s.files[0])
+		s.removeOldestLocked()
+	}
+	return dropped, nil
+}
+
+// writeSpoolFile writes a spooled payload: its JSON encoded headers on the first line, followed by
+// its body.
+func writeSpoolFile(path string, headers []byte, body []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	w.Write(headers)
+	w.WriteByte('\n')
+	w.Write(body)
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// oldest returns the oldest payload of the spool and its spooling information. Payloads older
+// than the maximum age are removed from the spool and returned as expired. It returns a nil payload
+// when the spool is empty.
+func (s *spool) oldest() (p *payload, f spoolFile, expired []spoolFile, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.files) > 0 {
+		f = s.files[0]
+		if s.maxAge > 0 && time.Since(f.created) > s.maxAge {
+			expired = append(expired, f)
+			s.removeOldestLocked()
+			continue
+		}
+		p, err = readSpoolFile(filepath.Join(s.dir, f.name))
+		if err != nil {
+			// unreadable payloads would block the replay
+			s.removeOldestLocked()
+		}
+		return p, f, expired, err
+	}
+	return nil, f, expired, nil
+}
+
+// readSpoolFile reads a payload written by writeSpoolFile.
+func readSpoolFile(path string) (*payload, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	headers, body, ok := bytes.Cut(data, []byte{'\n'})
+	if !ok {
+		return nil, io.ErrUnexpectedEOF
+	}
+	p := newPayload(nil)
+	if err := json.Unmarshal(headers, &p.headers); err != nil {
+		ppool.Put(p)
+		return nil, err
+	}
+	p.body.Write(body)
+	return p, nil
+}
+
+// remove removes the file f from the spool, once its payload was replayed.
+func (s *spool) remove(f spoolFile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.files) > 0 && s.files[0].name == f.name {
+		s.removeOldestLocked()
+	}
+}
+
+// removeOldestLocked removes the oldest file of the spool. s.mu must be held.
+func (s *spool) removeOldestLocked() {
+	f := s.files[0]
+	if err := os.Remove(filepath.Join(s.dir, f.name)); err != nil && !os.IsNotExist(err) {
+		log.Debugf("Error removing spooled payload %s: %v", f.name, err)
+	}
+	s.files = s.files[1:]
+	s.size -= f.size
+}
+
+// notifyReady signals that a payload was sent successfully, and the spool can be replayed.
+func (s *spool) notifyReady() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,203 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package writer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+func newSpoolTestPayload(body string) *payload {
+	p := newPayload(map[string]string{"Content-Type": "application/x-protobuf"})
+	p.body.WriteString(body)
+	return p
+}
+
+func TestSpool(t *testing.T) {
+	t.Run("ordered", func(t *testing.T) {
+		dir := t.TempDir()
+		sp, err := newSpool(dir, 1024, time.Hour)
+		require.NoError(t, err)
+		for _, body := range []string{"a", "b", "c"} {
+			dropped, err := sp.write(newSpoolTestPayload(body))
+			require.NoError(t, err)
+			assert.Empty(t, dropped)
+		}
+
+		// an interrupted write is ignored when the spool is loaded again
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000001-0000000000.payload.tmp"), []byte("x"), 0600))
+		sp, err = newSpool(dir, 1024, time.Hour)
+		require.NoError(t, err)
+		count, size := sp.stats()
+		assert.Equal(t, 3, count)
+		assert.NotZero(t, size)
+		assert.NoFileExists(t, filepath.Join(dir, "00000000000000000001-0000000000.payload.tmp"))
+
+		for _, body := range []string{"a", "b", "c"} {
+			p, f, expired, err := sp.oldest()
+			require.NoError(t, err)
+			assert.Empty(t, expired)
+			assert.Equal(t, body, p.body.String())
+			assert.Equal(t, "application/x-protobuf", p.headers["Content-Type"])
+			sp.remove(f)
+		}
+		p, _, _, err := sp.oldest()
+		require.NoError(t, err)
+		assert.Nil(t, p)
+		count, size = sp.stats()
+		assert.Zero(t, count)
+		assert.Zero(t, size)
+	})
+
+	t.Run("max-size", func(t *testing.T) {
+		// each payload takes ~55 bytes with its headers
+		sp, err := newSpool(t.TempDir(), 120, time.Hour)
+		require.NoError(t, err)
+
+		_, err = sp.write(newSpoolTestPayload(string(make([]byte, 200))))
+		assert.Error(t, err)
+
+		var dropped []spoolFile
+		for _, body := range []string{"first payload", "second payload", "third payload"} {
+			d, err := sp.write(newSpoolTestPayload(body))
+			require.NoError(t, err)
+			dropped = append(dropped, d...)
+		}
+		require.Len(t, dropped, 1)
+		count, size := sp.stats()
+		assert.Equal(t, 2, count)
+		assert.LessOrEqual(t, size, int64(120))
+
+		p, _, _, err := sp.oldest()
+		require.NoError(t, err)
+		assert.Equal(t, "second payload", p.body.String())
+	})
+
+	t.Run("max-age", func(t *testing.T) {
+		sp, err := newSpool(t.TempDir(), 1024, time.Minute)
+		require.NoError(t, err)
+		_, err = sp.write(newSpoolTestPayload("old"))
+		require.NoError(t, err)
+		sp.files[0].created = time.Now().Add(-time.Hour)
+		_, err = sp.write(newSpoolTestPayload("new"))
+		require.NoError(t, err)
+
+		p, _, expired, err := sp.oldest()
+		require.NoError(t, err)
+		assert.Len(t, expired, 1)
+		assert.Equal(t, "new", p.body.String())
+		count, _ := sp.stats()
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestSpoolEndpointName(t *testing.T) {
+	assert.Equal(t, "trace.agent.datadoghq.com", spoolEndpointName("https://trace.agent.datadoghq.com"))
+	assert.Equal(t, "localhost_8126", spoolEndpointName("http://localhost:8126"))
+}
+
+func TestSenderSpool(t *testing.T) {
+	defer useBackoffDuration(0)()
+
+	var (
+		up       atomic.Bool
+		mu       sync.Mutex
+		received []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mu.Lock()
+		received = append(received, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	url, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	sp, err := newSpool(t.TempDir(), 1024, time.Hour)
+	require.NoError(t, err)
+	var recorder mockRecorder
+	s := newSender(&senderConfig{
+		client:     config.New().NewHTTPClient(),
+		url:        url,
+		maxConns:   1,
+		maxQueued:  1,
+		maxRetries: 2,
+		apiKey:     testAPIKey,
+		recorder:   &recorder,
+		spool:      sp,
+	}, &statsd.NoOpClient{})
+
+	// the intake can't be reached, the payloads are spooled once all retries failed
+	for _, body := range []string{"1", "2", "3"} {
+		s.Push(newSpoolTestPayload(body))
+	}
+	require.Eventually(t, func() bool { return len(recorder.data(eventTypeSpooled)) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, recorder.data(eventTypeDropped))
+
+	// the spooled payloads are replayed in order once a payload is sent
+	up.Store(true)
+	s.Push(newSpoolTestPayload("4"))
+	require.Eventually(t, func() bool { return len(recorder.data(eventTypeReplayed)) == 3 }, 5*time.Second, 10*time.Millisecond)
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"4", "1", "2", "3"}, received)
+	count, _ := sp.stats()
+	assert.Zero(t, count)
+}
+
+func TestSenderSpoolReplayFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	url, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	sp, err := newSpool(t.TempDir(), 1024, time.Hour)
+	require.NoError(t, err)
+	_, err = sp.write(newSpoolTestPayload("1"))
+	require.NoError(t, err)
+	var recorder mockRecorder
+	s := newSender(&senderConfig{
+		client:     config.New().NewHTTPClient(),
+		url:        url,
+		maxConns:   1,
+		maxQueued:  1,
+		maxRetries: 2,
+		apiKey:     testAPIKey,
+		recorder:   &recorder,
+		spool:      sp,
+	}, &statsd.NoOpClient{})
+
+	// the spool is replayed on start, the payload is kept in the spool when the intake can't be reached
+	require.Eventually(t, func() bool { return len(recorder.data(eventTypeReplayFailed)) == 1 }, 5*time.Second, 10*time.Millisecond)
+	s.Stop()
+	assert.Empty(t, recorder.data(eventTypeRetry))
+	assert.Empty(t, recorder.data(eventTypeReplayed))
+	count, _ := sp.stats()
+	assert.Equal(t, 1, count)
+}
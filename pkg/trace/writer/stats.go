@@ -354,6 +354,10 @@ func (w *DatadogStatsWriter) report() {
 	_ = w.statsd.Count("datadog.trace_agent.stats_writer.retries", w.stats.Retries.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.stats_writer.splits", w.stats.Splits.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.stats_writer.errors", w.stats.Errors.Swap(0), nil, 1)
+	if count, size, ok := spooledPayloads(w.senders); ok {
+		_ = w.statsd.Gauge("datadog.trace_agent.stats_writer.spool.payloads", float64(count), nil, 1)
+		_ = w.statsd.Gauge("datadog.trace_agent.stats_writer.spool.bytes", float64(size), nil, 1)
+	}
 }
 
 // recordEvent implements eventRecorder.
@@ -381,5 +385,26 @@ func (w *DatadogStatsWriter) recordEvent(t eventType, data *eventData) {
 		w.easylog.Warn("Stats writer queue full. Payload dropped (%.2fKB).", float64(data.bytes)/1024)
 		_ = w.statsd.Count("datadog.trace_agent.stats_writer.dropped", 1, nil, 1)
 		_ = w.statsd.Count("datadog.trace_agent.stats_writer.dropped_bytes", int64(data.bytes), nil, 1)
+
+	case eventTypeSpooled:
+		w.easylog.Warn("Stats payload spooled to disk (%.2fKB).", float64(data.bytes)/1024)
+		_ = w.statsd.Count("datadog.trace_agent.stats_writer.spool.spooled", 1, nil, 1)
+		_ = w.statsd.Count("datadog.trace_agent.stats_writer.spool.spooled_bytes", int64(data.bytes), nil, 1)
+
+	case eventTypeReplayed:
+		log.Debugf("Replayed spooled stats payload; time: %s, bytes: %d", data.duration, data.bytes)
+		w.stats.Bytes.Add(int64(data.bytes))
+		w.stats.Payloads.Inc()
+		_ = w.statsd.Count("datadog.trace_agent.stats_writer.spool.replayed", 1, nil, 1)
+		_ = w.statsd.Count("datadog.trace_agent.stats_writer.spool.replayed_bytes", int64(data.bytes), nil, 1)
+
+	case eventTypeReplayFailed:
+		log.Debugf("Failed to replay spooled stats payload, keeping it in the spool; error: %s", data.err)
+		_ = w.statsd.Count("datadog.trace_agent.stats_writer.spool.replay_failed", 1, nil, 1)
+
+	case eventTypeExpired:
+		w.easylog.Warn("Spooled stats payload expired (%.2fKB).", float64(data.bytes)/1024)
+		_ = w.statsd.Count("datadog.trace_agent.stats_writer.spool.expired", 1, nil, 1)
+		_ = w.statsd.Count("datadog.trace_agent.stats_writer.spool.expired_bytes", int64(data.bytes), nil, 1)
 	}
 }
@@ -316,6 +316,10 @@ func (w *TraceWriter) report() {
 	_ = w.statsd.Count("datadog.trace_agent.trace_writer.traces", w.stats.Traces.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.trace_writer.events", w.stats.Events.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.trace_writer.spans", w.stats.Spans.Swap(0), nil, 1)
+	if count, size, ok := spooledPayloads(w.senders); ok {
+		_ = w.statsd.Gauge("datadog.trace_agent.trace_writer.spool.payloads", float64(count), nil, 1)
+		_ = w.statsd.Gauge("datadog.trace_agent.trace_writer.spool.bytes", float64(size), nil, 1)
+	}
 }
 
 var _ eventRecorder = (*TraceWriter)(nil)
@@ -348,5 +352,26 @@ func (w *TraceWriter) recordEvent(t eventType, data *eventData) {
 		w.easylog.Warn("Trace Payload dropped (%.2fKB).", float64(data.bytes)/1024)
 		_ = w.statsd.Count("datadog.trace_agent.trace_writer.dropped", 1, nil, 1)
 		_ = w.statsd.Count("datadog.trace_agent.trace_writer.dropped_bytes", int64(data.bytes), nil, 1)
+
+	case eventTypeSpooled:
+		w.easylog.Warn("Trace payload spooled to disk (%.2fKB).", float64(data.bytes)/1024)
+		_ = w.statsd.Count("datadog.trace_agent.trace_writer.spool.spooled", 1, nil, 1)
+		_ = w.statsd.Count("datadog.trace_agent.trace_writer.spool.spooled_bytes", int64(data.bytes), nil, 1)
+
+	case eventTypeReplayed:
+		log.Debugf("Replayed spooled trace payload; time: %s, bytes: %d", data.duration, data.bytes)
+		w.stats.Bytes.Add(int64(data.bytes))
+		w.stats.Payloads.Inc()
+		_ = w.statsd.Count("datadog.trace_agent.trace_writer.spool.replayed", 1, nil, 1)
+		_ = w.statsd.Count("datadog.trace_agent.trace_writer.spool.replayed_bytes", int64(data.bytes), nil, 1)
+
+	case eventTypeReplayFailed:
+		log.Debugf("Failed to replay spooled trace payload, keeping it in the spool; error: %s", data.err)
+		_ = w.statsd.Count("datadog.trace_agent.trace_writer.spool.replay_failed", 1, nil, 1)
+
+	case eventTypeExpired:
+		w.easylog.Warn("Spooled trace payload expired (%.2fKB).", float64(data.bytes)/1024)
+		_ = w.statsd.Count("datadog.trace_agent.trace_writer.spool.expired", 1, nil, 1)
+		_ = w.statsd.Count("datadog.trace_agent.trace_writer.spool.expired_bytes", int64(data.bytes), nil, 1)
 	}
 }
@@ -36,3 +36,16 @@ func SetupMock(t *testing.T, hypervisorUUID, productUUID, boardAssetTag, boardVe
 	dmiBoardAssetTagPath = setTestFile(boardAssetTag, "board_asset_tag")
 	dmiBoardVendorPath = setTestFile(boardVendor, "board_vendor")
 }
+
+// SetupHardwareInfoMock mocks the DMI attributes returned by GetHardwareInfo
+func SetupHardwareInfoMock(t *testing.T, info HardwareInfo) {
+	tempDir := t.TempDir()
+	t.Cleanup(func() { dmiIDPath = "/sys/devices/virtual/dmi/id" })
+
+	for attribute, value := range info.attributes() {
+		if *value != "" {
+			_ = os.WriteFile(filepath.Join(tempDir, attribute), []byte(*value+"\n"), os.ModePerm)
+		}
+	}
+	dmiIDPath = tempDir
+}
@@ -10,6 +10,7 @@ package dmi
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -18,6 +19,7 @@ var (
 	dmiProductUUIDPath   = "/sys/devices/virtual/dmi/id/product_uuid"
 	dmiBoardAssetTagPath = "/sys/devices/virtual/dmi/id/board_asset_tag"
 	dmiBoardVendorPath   = "/sys/devices/virtual/dmi/id/board_vendor"
+	dmiIDPath            = "/sys/devices/virtual/dmi/id"
 )
 
 func readFile(path string) string {
@@ -47,3 +49,12 @@ func GetProductUUID() string {
 func GetHypervisorUUID() string {
 	return readFile(hypervisorUUIDPath)
 }
+
+// GetHardwareInfo returns the DMI information of the system, board, chassis and BIOS
+func GetHardwareInfo() HardwareInfo {
+	var info HardwareInfo
+	for attribute, value := range info.attributes() {
+		*value = readFile(filepath.Join(dmiIDPath, attribute))
+	}
+	return info
+}
@@ -36,3 +36,20 @@ func TestGetSystemSpecificHosttMetadata(t *testing.T) {
 	assert.Equal(t, "", GetBoardAssetTag())
 	assert.Equal(t, "", GetBoardVendor())
 }
+
+func TestGetHardwareInfo(t *testing.T) {
+	info := HardwareInfo{
+		SystemVendor: "Supermicro",
+		ProductName:  "SYS-1029P-WTR",
+		BoardName:    "X11DDW-L",
+		ChassisType:  "23",
+		BIOSVersion:  "3.4",
+		BIOSDate:     "10/21/2020",
+	}
+	SetupHardwareInfoMock(t, info)
+
+	assert.Equal(t, info, GetHardwareInfo())
+
+	dmiIDPath = "does not exist"
+	assert.Equal(t, HardwareInfo{}, GetHardwareInfo())
+}
@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dmi
+
+// HardwareInfo holds the DMI information of the system, board, chassis and BIOS of the host. Serial numbers are
+// only readable by root, fields that can't be read are empty.
+type HardwareInfo struct {
+	SystemVendor    string
+	ProductName     string
+	ProductVersion  string
+	ProductFamily   string
+	ProductSKU      string
+	ProductSerial   string
+	BoardName       string
+	BoardVersion    string
+	BoardSerial     string
+	ChassisVendor   string
+	ChassisType     string
+	ChassisSerial   string
+	ChassisAssetTag string
+	BIOSVendor      string
+	BIOSVersion     string
+	BIOSDate        string
+}
+
+// attributes returns the fields of the info by name of the sysfs attribute exposing them on Linux
+func (info *HardwareInfo) attributes() map[string]*string {
+	return map[string]*string{
+		"sys_vendor":        &info.SystemVendor,
+		"product_name":      &info.ProductName,
+		"product_version":   &info.ProductVersion,
+		"product_family":    &info.ProductFamily,
+		"product_sku":       &info.ProductSKU,
+		"product_serial":    &info.ProductSerial,
+		"board_name":        &info.BoardName,
+		"board_version":     &info.BoardVersion,
+		"board_serial":      &info.BoardSerial,
+		"chassis_vendor":    &info.ChassisVendor,
+		"chassis_type":      &info.ChassisType,
+		"chassis_serial":    &info.ChassisSerial,
+		"chassis_asset_tag": &info.ChassisAssetTag,
+		"bios_vendor":       &info.BIOSVendor,
+		"bios_version":      &info.BIOSVersion,
+		"bios_date":         &info.BIOSDate,
+	}
+}
@@ -14,6 +14,7 @@ var (
 	boardVendor    = ""
 	productUUID    = ""
 	hypervisorUUID = ""
+	hardwareInfo   = HardwareInfo{}
 )
 
 // GetBoardAssetTag returns an empty string on Windows
@@ -35,3 +36,8 @@ func GetProductUUID() string {
 func GetHypervisorUUID() string {
 	return hypervisorUUID
 }
+
+// GetHardwareInfo returns an empty HardwareInfo on Windows
+func GetHardwareInfo() HardwareInfo {
+	return hardwareInfo
+}
@@ -25,3 +25,10 @@ func SetupMock(t *testing.T, testHypervisorUUID, testProductUUID, testBoardAsset
 	productUUID = testProductUUID
 	hypervisorUUID = testHypervisorUUID
 }
+
+// SetupHardwareInfoMock mocks the DMI attributes returned by GetHardwareInfo
+func SetupHardwareInfoMock(t *testing.T, info HardwareInfo) {
+	t.Cleanup(func() { hardwareInfo = HardwareInfo{} })
+
+	hardwareInfo = info
+}
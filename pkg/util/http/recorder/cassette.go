@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// cassetteVersion is the version of the cassette file format
+const cassetteVersion = 1
+
+// Request is a recorded HTTP request. Headers are not recorded since they may contain credentials, the URL and
+// the body are scrubbed.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   []byte `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response, with scrubbed headers and body
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// Interaction is a request and the response it got
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette holds the interactions recorded in record mode, and served in replay mode
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+
+	mu sync.Mutex
+	// cursors holds the index of the next interaction to replay for each request key
+	cursors map[string]int
+}
+
+// NewCassette returns an empty cassette
+func NewCassette() *Cassette {
+	return &Cassette{Version: cassetteVersion}
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewCassette()
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s, expected %d", c.Version, path, cassetteVersion)
+	}
+	return c, nil
+}
+
+// Save writes the cassette to a file, readable by the owner only since responses may contain sensitive data
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func (c *Cassette) add(interaction Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, interaction)
+}
+
+// find returns the response recorded for a request. When the same request was recorded several times, the
+// responses are served in the recorded order, and the last one is served again once they are all used: checks
+// are usually run more times in replay mode than in record mode, e.g. with `--check-rate`.
+func (c *Cassette) find(req Request) (Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := requestKey(req)
+	if c.cursors == nil {
+		c.cursors = make(map[string]int)
+	}
+
+	var last *Response
+	seen := 0
+	for i := range c.Interactions {
+		if requestKey(c.Interactions[i].Request) != key {
+			continue
+		}
+		last = &c.Interactions[i].Response
+		if seen == c.cursors[key] {
+			c.cursors[key]++
+			return *last, true
+		}
+		seen++
+	}
+	if last == nil {
+		return Response{}, false
+	}
+	return *last, true
+}
+
+func requestKey(req Request) string {
+	return req.Method + " " + req.URL + "\n" + string(req.Body)
+}
@@ -0,0 +1,280 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const certificateValidity = 24 * time.Hour
+
+// headers only meaningful between the client and the proxy
+var proxyHeaders = []string{"Proxy-Authorization", "Proxy-Connection"}
+
+// Proxy is an HTTP proxy sending the requests it receives through a RoundTripper.  HTTPS requests tunneled with
+// CONNECT are intercepted with certificates signed by an ephemeral certificate authority, which clients must
+// trust, see CACertificatePEM.
+type Proxy struct {
+	roundTripper http.RoundTripper
+	listener     net.Listener
+	server       *http.Server
+
+	caCert *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	caPEM  []byte
+
+	certificatesMutex sync.Mutex
+	certificates      map[string]*tls.Certificate
+}
+
+// NewProxy returns a proxy listening on a random local port
+func NewProxy(roundTripper http.RoundTripper) (*Proxy, error) {
+	caCert, caKey, caPEM, err := newCertificateAuthority()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the proxy certificate authority: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Proxy{
+		roundTripper: roundTripper,
+		listener:     listener,
+		caCert:       caCert,
+		caKey:        caKey,
+		caPEM:        caPEM,
+		certificates: make(map[string]*tls.Certificate),
+	}
+	p.server = &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return p, nil
+}
+
+// Start serves the requests in the background
+func (p *Proxy) Start() {
+	go p.server.Serve(p.listener) //nolint:errcheck
+}
+
+// Stop stops the proxy
+func (p *Proxy) Stop() error {
+	return p.server.Close()
+}
+
+// URL returns the URL of the proxy, to be used as both the HTTP and HTTPS proxy
+func (p *Proxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// CACertificatePEM returns the PEM encoded certificate of the authority signing the intercepted connections
+func (p *Proxy) CACertificatePEM() []byte {
+	return p.caPEM
+}
+
+// ServeHTTP implements http.Handler
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodConnect {
+		p.intercept(w, req)
+		return
+	}
+	if !req.URL.IsAbs() {
+		http.Error(w, "this proxy only serves absolute URLs", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := p.forward(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body) //nolint:errcheck
+}
+
+func (p *Proxy) forward(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.RequestURI = ""
+	for _, header := range proxyHeaders {
+		out.Header.Del(header)
+	}
+	return p.roundTripper.RoundTrip(out)
+}
+
+// intercept terminates the TLS connection tunneled with CONNECT and serves the requests sent over it
+func (p *Proxy) intercept(w http.ResponseWriter, req *http.Request) {
+	host := req.URL.Host
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		return
+	}
+
+	tlsConn := tls.Server(conn, &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return p.certificate(hostname)
+		},
+	})
+	defer tlsConn.Close()
+
+	reader := bufio.NewReader(tlsConn)
+	for {
+		tunneledReq, err := http.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+		tunneledReq.URL.Scheme = "https"
+		tunneledReq.URL.Host = host
+		// the default port is omitted so that the recorded URLs match the ones of direct requests
+		if _, port, err := net.SplitHostPort(host); err == nil && port == "443" {
+			tunneledReq.URL.Host = hostname
+		}
+
+		if err := p.writeResponse(tlsConn, tunneledReq); err != nil || tunneledReq.Close {
+			return
+		}
+	}
+}
+
+// writeResponse forwards a tunneled request and writes its response with an explicit length, for the client to
+// be able to send further requests over the connection
+func (p *Proxy) writeResponse(w io.Writer, req *http.Request) error {
+	var status int
+	var header http.Header
+	var body []byte
+
+	resp, err := p.forward(req)
+	if err == nil {
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		status, header = resp.StatusCode, resp.Header
+	}
+	if err != nil {
+		status, header, body = http.StatusBadGateway, nil, []byte(err.Error())
+	}
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Del("Transfer-Encoding")
+
+	out := &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+	return out.Write(w)
+}
+
+// certificate returns a certificate for the host signed by the proxy certificate authority
+func (p *Proxy) certificate(host string) (*tls.Certificate, error) {
+	p.certificatesMutex.Lock()
+	defer p.certificatesMutex.Unlock()
+
+	if cert, ok := p.certificates[host]; ok {
+		return cert, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: newSerialNumber(),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, p.caCert, &key.PublicKey, p.caKey)
+	if err != nil {
+		return nil, err
+	}
+	cert := &tls.Certificate{
+		Certificate: [][]byte{der, p.caCert.Raw},
+		PrivateKey:  key,
+	}
+	p.certificates[host] = cert
+	return cert, nil
+}
+
+func newCertificateAuthority() (*x509.Certificate, *ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          newSerialNumber(),
+		Subject:               pkix.Name{CommonName: "Datadog Agent check recorder"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(certificateValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func newSerialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return serial
+}
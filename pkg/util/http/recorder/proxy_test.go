@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package recorder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy(t *testing.T) {
+	httpServer, _ := newCountingServer(t)
+	httpsServer := httptest.NewTLSServer(httpServer.Config.Handler)
+	t.Cleanup(httpsServer.Close)
+
+	// the recorder trusts the test server, the client trusts the proxy
+	upstream := httpsServer.Client().Transport.(*http.Transport).Clone()
+	recorder := NewRecorder(upstream)
+
+	proxy, err := NewProxy(recorder)
+	require.NoError(t, err)
+	proxy.Start()
+	t.Cleanup(func() { proxy.Stop() })
+
+	proxyURL, err := url.Parse(proxy.URL())
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(proxy.CACertificatePEM()))
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+	}}
+
+	resp, body := doRequest(t, client, http.MethodGet, httpServer.URL+"/plain", "")
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "GET /plain ", body)
+
+	for i := 0; i < 2; i++ {
+		resp, body = doRequest(t, client, http.MethodPost, httpsServer.URL+"/tls", "payload")
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, "POST /tls payload", body)
+	}
+
+	interactions := recorder.Cassette().Interactions
+	require.Len(t, interactions, 3)
+	assert.Equal(t, httpServer.URL+"/plain", interactions[0].Request.URL)
+	assert.Equal(t, httpsServer.URL+"/tls", interactions[1].Request.URL)
+	assert.Equal(t, "payload", string(interactions[1].Request.Body))
+}
+
+func TestProxyUpstreamError(t *testing.T) {
+	proxy, err := NewProxy(NewReplayer(NewCassette()))
+	require.NoError(t, err)
+	proxy.Start()
+	t.Cleanup(func() { proxy.Stop() })
+
+	proxyURL, err := url.Parse(proxy.URL())
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	resp, body := doRequest(t, client, http.MethodGet, "http://example.com/missing", "")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Contains(t, body, "no recorded response for GET http://example.com/missing")
+}
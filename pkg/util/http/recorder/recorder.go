@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package recorder records the HTTP requests made by the Agent and their responses to a cassette file, and
+// replays them from that file without reaching the network.
+//
+// Go code using the transports created by pkg/util/http is covered by installing the Recorder as a transport
+// hook, other clients, such as Python checks, can be pointed to a Proxy serving the requests through the Recorder.
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
+)
+
+// Mode is the mode of a Recorder
+type Mode int
+
+const (
+	// ModeRecord sends the requests upstream and records them with their responses
+	ModeRecord Mode = iota
+	// ModeReplay serves the requests from the recorded responses
+	ModeReplay
+)
+
+// headers not recorded, either because they are recomputed when replaying or because they may contain credentials
+var ignoredResponseHeaders = []string{"Connection", "Content-Length", "Keep-Alive", "Set-Cookie", "Transfer-Encoding"}
+
+// Recorder is an http.RoundTripper recording or replaying requests.
+//
+// Credentials are scrubbed from the recorded URLs, bodies and response headers with the default scrubber.
+// Requests are scrubbed the same way in replay mode before being looked up, so that they match the recorded ones.
+type Recorder struct {
+	mode     Mode
+	upstream http.RoundTripper
+	cassette *Cassette
+}
+
+// NewRecorder returns a Recorder sending the requests to upstream and recording them in a new cassette
+func NewRecorder(upstream http.RoundTripper) *Recorder {
+	return &Recorder{
+		mode:     ModeRecord,
+		upstream: upstream,
+		cassette: NewCassette(),
+	}
+}
+
+// NewReplayer returns a Recorder serving the requests from the cassette. Requests that were not recorded fail.
+func NewReplayer(cassette *Cassette) *Recorder {
+	return &Recorder{
+		mode:     ModeReplay,
+		cassette: cassette,
+	}
+}
+
+// Mode returns the mode of the recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Cassette returns the cassette the requests are recorded in or replayed from
+func (r *Recorder) Cassette() *Cassette {
+	return r.cassette
+}
+
+// Hook installs the recorder on a transport for the http and https schemes, it is meant to be registered with
+// pkg/util/http.SetTransportHook.  The proxy settings of the transport are not used once the recorder is
+// installed, the requests being sent by the upstream transport of the recorder.
+func (r *Recorder) Hook(transport *http.Transport) {
+	transport.RegisterProtocol("http", r)
+	transport.RegisterProtocol("https", r)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recordedReq, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		resp, ok := r.cassette.find(recordedReq)
+		if !ok {
+			return nil, fmt.Errorf("no recorded response for %s %s", recordedReq.Method, recordedReq.URL)
+		}
+		return resp.toHTTP(req), nil
+	}
+
+	upstreamResp, err := r.upstream.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer upstreamResp.Body.Close()
+
+	body, err := io.ReadAll(upstreamResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp := Response{
+		StatusCode: upstreamResp.StatusCode,
+		Header:     upstreamResp.Header.Clone(),
+		Body:       scrubBody(body),
+	}
+	for _, header := range ignoredResponseHeaders {
+		resp.Header.Del(header)
+	}
+	for _, values := range resp.Header {
+		for i, value := range values {
+			values[i] = scrubber.ScrubLine(value)
+		}
+	}
+	r.cassette.add(Interaction{Request: recordedReq, Response: resp})
+
+	upstreamResp.Body = io.NopCloser(bytes.NewReader(body))
+	return upstreamResp, nil
+}
+
+// newRequest reads the body of an HTTP request, and restores it so that the request can still be sent
+func newRequest(req *http.Request) (Request, error) {
+	recorded := Request{
+		Method: req.Method,
+		URL:    scrubber.ScrubLine(req.URL.String()),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	recorded.Body = scrubBody(body)
+	return recorded, nil
+}
+
+// scrubBody scrubs credentials from a text body. Binary bodies, such as compressed ones, are kept as is since
+// the scrubber would corrupt them.
+func scrubBody(body []byte) []byte {
+	if len(body) == 0 || !utf8.Valid(body) {
+		return body
+	}
+	scrubbed, err := scrubber.ScrubBytes(body)
+	if err != nil {
+		return body
+	}
+	return scrubbed
+}
+
+func (resp Response) toHTTP(req *http.Request) *http.Response {
+	header := resp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}
+}
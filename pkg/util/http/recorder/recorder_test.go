@@ -0,0 +1,145 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := count.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Count", strings.Repeat("i", int(n)))
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+	}))
+	t.Cleanup(server.Close)
+	return server, &count
+}
+
+func doRequest(t *testing.T, client *http.Client, method string, url string, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(respBody)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server, count := newCountingServer(t)
+
+	// record
+	transport := &http.Transport{}
+	recorder := NewRecorder(http.DefaultTransport)
+	recorder.Hook(transport)
+	client := &http.Client{Transport: transport}
+
+	resp, body := doRequest(t, client, http.MethodGet, server.URL+"/status", "")
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "GET /status ", body)
+	doRequest(t, client, http.MethodGet, server.URL+"/status", "")
+	doRequest(t, client, http.MethodPost, server.URL+"/query", "select 1")
+	assert.EqualValues(t, 3, count.Load())
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, recorder.Cassette().Save(path))
+
+	// replay
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, cassette.Interactions, 3)
+	assert.Empty(t, cassette.Interactions[0].Response.Header.Get("Set-Cookie"))
+
+	transport = &http.Transport{}
+	NewReplayer(cassette).Hook(transport)
+	client = &http.Client{Transport: transport}
+
+	// identical requests are served in the recorded order, the last response being served again
+	for _, expected := range []string{"i", "ii", "ii"} {
+		resp, body = doRequest(t, client, http.MethodGet, server.URL+"/status", "")
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, expected, resp.Header.Get("X-Count"))
+		assert.Equal(t, "GET /status ", body)
+	}
+	_, body = doRequest(t, client, http.MethodPost, server.URL+"/query", "select 1")
+	assert.Equal(t, "POST /query select 1", body)
+	assert.EqualValues(t, 3, count.Load())
+
+	// requests are matched on their body
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/query", strings.NewReader("select 2"))
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.ErrorContains(t, err, "no recorded response for POST "+server.URL+"/query")
+}
+
+func TestLoadCassetteErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadCassette(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	c := NewCassette()
+	c.Version = 42
+	path := filepath.Join(dir, "cassette.json")
+	require.NoError(t, c.Save(path))
+	_, err = LoadCassette(path)
+	assert.ErrorContains(t, err, "unsupported cassette version 42")
+}
+
+func TestRecordScrubsCredentials(t *testing.T) {
+	apiKey := "aaaaaaaaaaaaaaaaaaaaaaaaaaaabbbb"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Api-Key", apiKey)
+		w.Write([]byte("password: hunter2\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	// record
+	transport := &http.Transport{}
+	recorder := NewRecorder(http.DefaultTransport)
+	recorder.Hook(transport)
+	client := &http.Client{Transport: transport}
+
+	url := server.URL + "/validate?api_key=" + apiKey
+	_, body := doRequest(t, client, http.MethodPost, url, "password: hunter2")
+	// the client still gets the actual response
+	assert.Equal(t, "password: hunter2\n", body)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, recorder.Cassette().Save(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), apiKey)
+	assert.NotContains(t, string(data), "hunter2")
+
+	// replay, the requests being scrubbed the same way to be found
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	transport = &http.Transport{}
+	NewReplayer(cassette).Hook(transport)
+	client = &http.Client{Transport: transport}
+
+	resp, body := doRequest(t, client, http.MethodPost, url, "password: hunter2")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotContains(t, body, "hunter2")
+	assert.NotContains(t, resp.Header.Get("X-Api-Key"), apiKey)
+}
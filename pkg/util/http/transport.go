@@ -26,8 +26,19 @@ import (
 var (
 	keyLogWriterInit sync.Once
 	keyLogWriter     io.Writer
+
+	transportHookMutex sync.RWMutex
+	transportHook      func(*http.Transport)
 )
 
+// SetTransportHook registers a function called with every transport created by CreateHTTPTransport, for
+// instance to record or replay the requests made with it. Passing nil removes the hook.
+func SetTransportHook(hook func(*http.Transport)) {
+	transportHookMutex.Lock()
+	defer transportHookMutex.Unlock()
+	transportHook = hook
+}
+
 func logSafeURLString(url *url.URL) string {
 	if url == nil {
 		return ""
@@ -125,6 +136,12 @@ func CreateHTTPTransport(cfg pkgconfigmodel.Reader) *http.Transport {
 		transport.Proxy = GetProxyTransportFunc(proxies, cfg)
 	}
 
+	transportHookMutex.RLock()
+	defer transportHookMutex.RUnlock()
+	if transportHook != nil {
+		transportHook(transport)
+	}
+
 	return transport
 }
 
@@ -193,6 +193,21 @@ func TestCreateHTTPTransport(t *testing.T) {
 	assert.Equal(t, transport.TLSHandshakeTimeout, time.Second)
 }
 
+func TestCreateHTTPTransportHook(t *testing.T) {
+	c := pkgconfigmodel.NewConfig("test", "DD", strings.NewReplacer(".", "_"))
+
+	var hooked []*http.Transport
+	SetTransportHook(func(transport *http.Transport) {
+		hooked = append(hooked, transport)
+	})
+	transport := CreateHTTPTransport(c)
+	assert.Equal(t, []*http.Transport{transport}, hooked)
+
+	SetTransportHook(nil)
+	CreateHTTPTransport(c)
+	assert.Len(t, hooked, 1)
+}
+
 func TestNoProxyWarningMap(t *testing.T) {
 	setupTest(t)
 
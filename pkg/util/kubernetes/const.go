@@ -25,6 +25,8 @@ const (
 	KubeAppPartOfLabelKey = "app.kubernetes.io/part-of"
 	// KubeAppManagedByLabelKey is the label key of the tool being used to manage the operation of an application
 	KubeAppManagedByLabelKey = "app.kubernetes.io/managed-by"
+	// ControllerRevisionHashLabelKey is the label key set by stateful set and daemon set controllers on their pods
+	ControllerRevisionHashLabelKey = "controller-revision-hash"
 
 	// RcIDAnnotKey is the key of the RC ID annotation
 	RcIDAnnotKey = "admission.datadoghq.com/rc.id"